@@ -154,3 +154,114 @@ func (c *Client) GetAccountSellers(ctx context.Context, ids []string, skip uint6
 
 	return sellers, nil
 }
+
+func (c *Client) GetAccountBuyers(ctx context.Context, ids []string) ([]Buyer, error) {
+	r, err := c.service.GetAccountBuyers(
+		ctx,
+		&pb.GetAccountBuyersRequest{Ids: ids},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	buyers := []Buyer{}
+	for _, a := range r.Accounts {
+		buyers = append(buyers, Buyer{
+			ID: a.Id,
+			BaseInfo: BaseInfo{
+				FirstName: a.BaseInfo.FirstName,
+				LastName:  a.BaseInfo.LastName,
+				Phone:     a.BaseInfo.Phone,
+				Address:   a.BaseInfo.Address,
+			},
+		})
+	}
+
+	return buyers, nil
+}
+
+func (c *Client) GetNotificationPreferences(ctx context.Context, accountID string) (*NotificationPreference, error) {
+	r, err := c.service.GetNotificationPreferences(
+		ctx,
+		&pb.GetAccountRequest{Id: accountID},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &NotificationPreference{
+		AccountID:      r.AccountId,
+		AccountCreated: r.AccountCreated,
+		OrderPlaced:    r.OrderPlaced,
+		OrderShipped:   r.OrderShipped,
+	}, nil
+}
+
+func (c *Client) UpdateNotificationPreferences(ctx context.Context, p NotificationPreference) (*NotificationPreference, error) {
+	_, err := c.service.UpdateNotificationPreferences(ctx, &pb.NotificationPreferences{
+		AccountId:      p.AccountID,
+		AccountCreated: p.AccountCreated,
+		OrderPlaced:    p.OrderPlaced,
+		OrderShipped:   p.OrderShipped,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+func (c *Client) AddWishlistItem(ctx context.Context, buyerID, productID string, savedPrice float64) (*WishlistItem, error) {
+	r, err := c.service.AddWishlistItem(ctx, &pb.AddWishlistItemRequest{
+		BuyerId:    buyerID,
+		ProductId:  productID,
+		SavedPrice: savedPrice,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return wishlistItemFromProto(r), nil
+}
+
+func (c *Client) RemoveWishlistItem(ctx context.Context, buyerID, productID string) error {
+	_, err := c.service.RemoveWishlistItem(ctx, &pb.RemoveWishlistItemRequest{
+		BuyerId:   buyerID,
+		ProductId: productID,
+	})
+	return err
+}
+
+func (c *Client) GetWishlist(ctx context.Context, buyerID string, skip uint64, take uint64) ([]WishlistItem, error) {
+	r, err := c.service.GetWishlist(ctx, &pb.GetWishlistRequest{
+		BuyerId: buyerID,
+		Skip:    skip,
+		Take:    take,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := []WishlistItem{}
+	for _, item := range r.Items {
+		items = append(items, *wishlistItemFromProto(item))
+	}
+	return items, nil
+}
+
+func (c *Client) DeleteAccount(ctx context.Context, id string) error {
+	_, err := c.service.DeleteAccount(ctx, &pb.DeleteAccountRequest{Id: id})
+	return err
+}
+
+func wishlistItemFromProto(r *pb.WishlistItem) *WishlistItem {
+	item := &WishlistItem{
+		BuyerID:    r.BuyerId,
+		ProductID:  r.ProductId,
+		SavedPrice: r.SavedPrice,
+	}
+	if err := item.CreatedAt.UnmarshalBinary(r.CreatedAt); err != nil {
+		log.Println("error unmarshal timestamp", err)
+	}
+	return item
+}
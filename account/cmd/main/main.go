@@ -5,12 +5,17 @@ import (
 	"time"
 
 	"github.com/231031/ecom-mcs-grpc/account"
+	"github.com/231031/ecom-mcs-grpc/pkg/auth"
 	"github.com/kelseyhightower/envconfig"
 	"github.com/tinrab/retry"
 )
 
 type Config struct {
 	DatabaseURl string `envconfig:"DATABASE_URL"`
+
+	PublicKeyPath      string `envconfig:"FILE_PUB_PATH"`
+	JWKSURL            string `envconfig:"JWKS_URL"`
+	InternalAuthSecret string `envconfig:"INTERNAL_AUTH_SECRET"`
 }
 
 func main() {
@@ -31,6 +36,15 @@ func main() {
 	defer r.Close()
 	log.Println("Listening on port")
 
+	keys, err := auth.NewKeySource(cfg.JWKSURL, cfg.PublicKeyPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	authn, err := auth.NewAuthenticator("account", keys, cfg.InternalAuthSecret)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	s := account.NewService(r)
-	log.Fatal(account.ListenGRPC(s, 50001))
+	log.Fatal(account.ListenGRPC(s, authn, 50001))
 }
@@ -23,3 +23,31 @@ type Seller struct {
 	CreatedAt time.Time `gorm:"autoCreateTime"`
 	UpdatedAt time.Time `gorm:"autoUpdateTime"`
 }
+
+// NotificationPreference holds which events an account is emailed about,
+// an account that never saved one gets every event.
+type NotificationPreference struct {
+	AccountID      string    `gorm:"primaryKey;type:varchar(27);" json:"account_id"`
+	AccountCreated bool      `gorm:"not null;" json:"account_created"`
+	OrderPlaced    bool      `gorm:"not null;" json:"order_placed"`
+	OrderShipped   bool      `gorm:"not null;" json:"order_shipped"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime"`
+}
+
+func defaultNotificationPreference(accountID string) *NotificationPreference {
+	return &NotificationPreference{
+		AccountID:      accountID,
+		AccountCreated: true,
+		OrderPlaced:    true,
+		OrderShipped:   true,
+	}
+}
+
+// WishlistItem is a product a buyer saved for later, SavedPrice is the
+// catalog price at the time it was saved.
+type WishlistItem struct {
+	BuyerID    string    `gorm:"primaryKey;type:varchar(27);" json:"buyer_id"`
+	ProductID  string    `gorm:"primaryKey;type:varchar(27);" json:"product_id"`
+	SavedPrice float64   `gorm:"type:numeric;not null;" json:"saved_price"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+}
@@ -593,6 +593,570 @@ func (x *GetAccountSellersResponse) GetAccounts() []*AccountSeller {
 	return nil
 }
 
+type GetAccountBuyersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAccountBuyersRequest) Reset() {
+	*x = GetAccountBuyersRequest{}
+	mi := &file_account_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAccountBuyersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAccountBuyersRequest) ProtoMessage() {}
+
+func (x *GetAccountBuyersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_account_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAccountBuyersRequest.ProtoReflect.Descriptor instead.
+func (*GetAccountBuyersRequest) Descriptor() ([]byte, []int) {
+	return file_account_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetAccountBuyersRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type GetAccountBuyersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Accounts      []*AccountBuyer        `protobuf:"bytes,1,rep,name=accounts,proto3" json:"accounts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAccountBuyersResponse) Reset() {
+	*x = GetAccountBuyersResponse{}
+	mi := &file_account_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAccountBuyersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAccountBuyersResponse) ProtoMessage() {}
+
+func (x *GetAccountBuyersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_account_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAccountBuyersResponse.ProtoReflect.Descriptor instead.
+func (*GetAccountBuyersResponse) Descriptor() ([]byte, []int) {
+	return file_account_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetAccountBuyersResponse) GetAccounts() []*AccountBuyer {
+	if x != nil {
+		return x.Accounts
+	}
+	return nil
+}
+
+type NotificationPreferences struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	AccountId      string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	AccountCreated bool                   `protobuf:"varint,2,opt,name=account_created,json=accountCreated,proto3" json:"account_created,omitempty"`
+	OrderPlaced    bool                   `protobuf:"varint,3,opt,name=order_placed,json=orderPlaced,proto3" json:"order_placed,omitempty"`
+	OrderShipped   bool                   `protobuf:"varint,4,opt,name=order_shipped,json=orderShipped,proto3" json:"order_shipped,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *NotificationPreferences) Reset() {
+	*x = NotificationPreferences{}
+	mi := &file_account_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotificationPreferences) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotificationPreferences) ProtoMessage() {}
+
+func (x *NotificationPreferences) ProtoReflect() protoreflect.Message {
+	mi := &file_account_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotificationPreferences.ProtoReflect.Descriptor instead.
+func (*NotificationPreferences) Descriptor() ([]byte, []int) {
+	return file_account_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *NotificationPreferences) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *NotificationPreferences) GetAccountCreated() bool {
+	if x != nil {
+		return x.AccountCreated
+	}
+	return false
+}
+
+func (x *NotificationPreferences) GetOrderPlaced() bool {
+	if x != nil {
+		return x.OrderPlaced
+	}
+	return false
+}
+
+func (x *NotificationPreferences) GetOrderShipped() bool {
+	if x != nil {
+		return x.OrderShipped
+	}
+	return false
+}
+
+type WishlistItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BuyerId       string                 `protobuf:"bytes,1,opt,name=buyer_id,json=buyerId,proto3" json:"buyer_id,omitempty"`
+	ProductId     string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	SavedPrice    float64                `protobuf:"fixed64,3,opt,name=saved_price,json=savedPrice,proto3" json:"saved_price,omitempty"`
+	CreatedAt     []byte                 `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WishlistItem) Reset() {
+	*x = WishlistItem{}
+	mi := &file_account_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WishlistItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WishlistItem) ProtoMessage() {}
+
+func (x *WishlistItem) ProtoReflect() protoreflect.Message {
+	mi := &file_account_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WishlistItem.ProtoReflect.Descriptor instead.
+func (*WishlistItem) Descriptor() ([]byte, []int) {
+	return file_account_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *WishlistItem) GetBuyerId() string {
+	if x != nil {
+		return x.BuyerId
+	}
+	return ""
+}
+
+func (x *WishlistItem) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *WishlistItem) GetSavedPrice() float64 {
+	if x != nil {
+		return x.SavedPrice
+	}
+	return 0
+}
+
+func (x *WishlistItem) GetCreatedAt() []byte {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type AddWishlistItemRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BuyerId       string                 `protobuf:"bytes,1,opt,name=buyer_id,json=buyerId,proto3" json:"buyer_id,omitempty"`
+	ProductId     string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	SavedPrice    float64                `protobuf:"fixed64,3,opt,name=saved_price,json=savedPrice,proto3" json:"saved_price,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddWishlistItemRequest) Reset() {
+	*x = AddWishlistItemRequest{}
+	mi := &file_account_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddWishlistItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddWishlistItemRequest) ProtoMessage() {}
+
+func (x *AddWishlistItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_account_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddWishlistItemRequest.ProtoReflect.Descriptor instead.
+func (*AddWishlistItemRequest) Descriptor() ([]byte, []int) {
+	return file_account_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *AddWishlistItemRequest) GetBuyerId() string {
+	if x != nil {
+		return x.BuyerId
+	}
+	return ""
+}
+
+func (x *AddWishlistItemRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *AddWishlistItemRequest) GetSavedPrice() float64 {
+	if x != nil {
+		return x.SavedPrice
+	}
+	return 0
+}
+
+type RemoveWishlistItemRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BuyerId       string                 `protobuf:"bytes,1,opt,name=buyer_id,json=buyerId,proto3" json:"buyer_id,omitempty"`
+	ProductId     string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveWishlistItemRequest) Reset() {
+	*x = RemoveWishlistItemRequest{}
+	mi := &file_account_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveWishlistItemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveWishlistItemRequest) ProtoMessage() {}
+
+func (x *RemoveWishlistItemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_account_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveWishlistItemRequest.ProtoReflect.Descriptor instead.
+func (*RemoveWishlistItemRequest) Descriptor() ([]byte, []int) {
+	return file_account_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RemoveWishlistItemRequest) GetBuyerId() string {
+	if x != nil {
+		return x.BuyerId
+	}
+	return ""
+}
+
+func (x *RemoveWishlistItemRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+type RemoveWishlistItemResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveWishlistItemResponse) Reset() {
+	*x = RemoveWishlistItemResponse{}
+	mi := &file_account_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveWishlistItemResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveWishlistItemResponse) ProtoMessage() {}
+
+func (x *RemoveWishlistItemResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_account_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveWishlistItemResponse.ProtoReflect.Descriptor instead.
+func (*RemoveWishlistItemResponse) Descriptor() ([]byte, []int) {
+	return file_account_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *RemoveWishlistItemResponse) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+type GetWishlistRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BuyerId       string                 `protobuf:"bytes,1,opt,name=buyer_id,json=buyerId,proto3" json:"buyer_id,omitempty"`
+	Skip          uint64                 `protobuf:"varint,2,opt,name=skip,proto3" json:"skip,omitempty"`
+	Take          uint64                 `protobuf:"varint,3,opt,name=take,proto3" json:"take,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetWishlistRequest) Reset() {
+	*x = GetWishlistRequest{}
+	mi := &file_account_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetWishlistRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWishlistRequest) ProtoMessage() {}
+
+func (x *GetWishlistRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_account_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWishlistRequest.ProtoReflect.Descriptor instead.
+func (*GetWishlistRequest) Descriptor() ([]byte, []int) {
+	return file_account_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetWishlistRequest) GetBuyerId() string {
+	if x != nil {
+		return x.BuyerId
+	}
+	return ""
+}
+
+func (x *GetWishlistRequest) GetSkip() uint64 {
+	if x != nil {
+		return x.Skip
+	}
+	return 0
+}
+
+func (x *GetWishlistRequest) GetTake() uint64 {
+	if x != nil {
+		return x.Take
+	}
+	return 0
+}
+
+type GetWishlistResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*WishlistItem        `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetWishlistResponse) Reset() {
+	*x = GetWishlistResponse{}
+	mi := &file_account_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetWishlistResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWishlistResponse) ProtoMessage() {}
+
+func (x *GetWishlistResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_account_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWishlistResponse.ProtoReflect.Descriptor instead.
+func (*GetWishlistResponse) Descriptor() ([]byte, []int) {
+	return file_account_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetWishlistResponse) GetItems() []*WishlistItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type DeleteAccountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAccountRequest) Reset() {
+	*x = DeleteAccountRequest{}
+	mi := &file_account_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAccountRequest) ProtoMessage() {}
+
+func (x *DeleteAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_account_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAccountRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAccountRequest) Descriptor() ([]byte, []int) {
+	return file_account_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *DeleteAccountRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteAccountResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAccountResponse) Reset() {
+	*x = DeleteAccountResponse{}
+	mi := &file_account_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAccountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAccountResponse) ProtoMessage() {}
+
+func (x *DeleteAccountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_account_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAccountResponse.ProtoReflect.Descriptor instead.
+func (*DeleteAccountResponse) Descriptor() ([]byte, []int) {
+	return file_account_proto_rawDescGZIP(), []int{21}
+}
+
 var File_account_proto protoreflect.FileDescriptor
 
 const file_account_proto_rawDesc = "" +
@@ -633,7 +1197,47 @@ const file_account_proto_rawDesc = "" +
 	"\x04take\x18\x02 \x01(\x04R\x04take\x12\x10\n" +
 	"\x03ids\x18\x03 \x03(\tR\x03ids\"M\n" +
 	"\x19GetAccountSellersResponse\x120\n" +
-	"\baccounts\x18\x01 \x03(\v2\x14.proto.AccountSellerR\baccounts2\xac\x04\n" +
+	"\baccounts\x18\x01 \x03(\v2\x14.proto.AccountSellerR\baccounts\"+\n" +
+	"\x17GetAccountBuyersRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\"K\n" +
+	"\x18GetAccountBuyersResponse\x12/\n" +
+	"\baccounts\x18\x01 \x03(\v2\x13.proto.AccountBuyerR\baccounts\"\xa9\x01\n" +
+	"\x17NotificationPreferences\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12'\n" +
+	"\x0faccount_created\x18\x02 \x01(\bR\x0eaccountCreated\x12!\n" +
+	"\forder_placed\x18\x03 \x01(\bR\vorderPlaced\x12#\n" +
+	"\rorder_shipped\x18\x04 \x01(\bR\forderShipped\"\x88\x01\n" +
+	"\fWishlistItem\x12\x19\n" +
+	"\bbuyer_id\x18\x01 \x01(\tR\abuyerId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\tR\tproductId\x12\x1f\n" +
+	"\vsaved_price\x18\x03 \x01(\x01R\n" +
+	"savedPrice\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\fR\tcreatedAt\"s\n" +
+	"\x16AddWishlistItemRequest\x12\x19\n" +
+	"\bbuyer_id\x18\x01 \x01(\tR\abuyerId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\tR\tproductId\x12\x1f\n" +
+	"\vsaved_price\x18\x03 \x01(\x01R\n" +
+	"savedPrice\"U\n" +
+	"\x19RemoveWishlistItemRequest\x12\x19\n" +
+	"\bbuyer_id\x18\x01 \x01(\tR\abuyerId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\tR\tproductId\";\n" +
+	"\x1aRemoveWishlistItemResponse\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\"W\n" +
+	"\x12GetWishlistRequest\x12\x19\n" +
+	"\bbuyer_id\x18\x01 \x01(\tR\abuyerId\x12\x12\n" +
+	"\x04skip\x18\x02 \x01(\x04R\x04skip\x12\x12\n" +
+	"\x04take\x18\x03 \x01(\x04R\x04take\"@\n" +
+	"\x13GetWishlistResponse\x12)\n" +
+	"\x05items\x18\x01 \x03(\v2\x13.proto.WishlistItemR\x05items\"&\n" +
+	"\x14DeleteAccountRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x17\n" +
+	"\x15DeleteAccountResponse2\xfc\b\n" +
 	"\x0eAccountService\x12U\n" +
 	"\x10PostAccountBuyer\x12\x1e.proto.PostAccountBuyerRequest\x1a\x1f.proto.PostAccountBuyerResponse\"\x00\x12X\n" +
 	"\x11PostAccountSeller\x12\x1f.proto.PostAccountSellerRequest\x1a .proto.PostAccountSellerResponse\"\x00\x12C\n" +
@@ -641,7 +1245,14 @@ const file_account_proto_rawDesc = "" +
 	"\x12UpdateAccountBuyer\x12\x13.proto.AccountBuyer\x1a\x13.proto.AccountBuyer\"\x00\x12B\n" +
 	"\x0fGetAccountBuyer\x12\x18.proto.GetAccountRequest\x1a\x13.proto.AccountBuyer\"\x00\x12D\n" +
 	"\x10GetAccountSeller\x12\x18.proto.GetAccountRequest\x1a\x14.proto.AccountSeller\"\x00\x12X\n" +
-	"\x11GetAccountSellers\x12\x1f.proto.GetAccountSellersRequest\x1a .proto.GetAccountSellersResponse\"\x00B\x06Z\x04./pbb\x06proto3"
+	"\x11GetAccountSellers\x12\x1f.proto.GetAccountSellersRequest\x1a .proto.GetAccountSellersResponse\"\x00\x12U\n" +
+	"\x10GetAccountBuyers\x12\x1e.proto.GetAccountBuyersRequest\x1a\x1f.proto.GetAccountBuyersResponse\"\x00\x12X\n" +
+	"\x1aGetNotificationPreferences\x12\x18.proto.GetAccountRequest\x1a\x1e.proto.NotificationPreferences\"\x00\x12a\n" +
+	"\x1dUpdateNotificationPreferences\x12\x1e.proto.NotificationPreferences\x1a\x1e.proto.NotificationPreferences\"\x00\x12G\n" +
+	"\x0fAddWishlistItem\x12\x1d.proto.AddWishlistItemRequest\x1a\x13.proto.WishlistItem\"\x00\x12[\n" +
+	"\x12RemoveWishlistItem\x12 .proto.RemoveWishlistItemRequest\x1a!.proto.RemoveWishlistItemResponse\"\x00\x12F\n" +
+	"\vGetWishlist\x12\x19.proto.GetWishlistRequest\x1a\x1a.proto.GetWishlistResponse\"\x00\x12L\n" +
+	"\rDeleteAccount\x12\x1b.proto.DeleteAccountRequest\x1a\x1c.proto.DeleteAccountResponse\"\x00B\x06Z\x04./pbb\x06proto3"
 
 var (
 	file_account_proto_rawDescOnce sync.Once
@@ -655,19 +1266,30 @@ func file_account_proto_rawDescGZIP() []byte {
 	return file_account_proto_rawDescData
 }
 
-var file_account_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_account_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
 var file_account_proto_goTypes = []any{
-	(*AccountBuyer)(nil),              // 0: proto.AccountBuyer
-	(*AccountSeller)(nil),             // 1: proto.AccountSeller
-	(*BaseInfo)(nil),                  // 2: proto.BaseInfo
-	(*PostAccountBuyerRequest)(nil),   // 3: proto.PostAccountBuyerRequest
-	(*PostAccountBuyerResponse)(nil),  // 4: proto.PostAccountBuyerResponse
-	(*PostAccountSellerRequest)(nil),  // 5: proto.PostAccountSellerRequest
-	(*PostAccountSellerResponse)(nil), // 6: proto.PostAccountSellerResponse
-	(*GetAccountRequest)(nil),         // 7: proto.GetAccountRequest
-	(*GetAccountSellerResponse)(nil),  // 8: proto.GetAccountSellerResponse
-	(*GetAccountSellersRequest)(nil),  // 9: proto.GetAccountSellersRequest
-	(*GetAccountSellersResponse)(nil), // 10: proto.GetAccountSellersResponse
+	(*AccountBuyer)(nil),               // 0: proto.AccountBuyer
+	(*AccountSeller)(nil),              // 1: proto.AccountSeller
+	(*BaseInfo)(nil),                   // 2: proto.BaseInfo
+	(*PostAccountBuyerRequest)(nil),    // 3: proto.PostAccountBuyerRequest
+	(*PostAccountBuyerResponse)(nil),   // 4: proto.PostAccountBuyerResponse
+	(*PostAccountSellerRequest)(nil),   // 5: proto.PostAccountSellerRequest
+	(*PostAccountSellerResponse)(nil),  // 6: proto.PostAccountSellerResponse
+	(*GetAccountRequest)(nil),          // 7: proto.GetAccountRequest
+	(*GetAccountSellerResponse)(nil),   // 8: proto.GetAccountSellerResponse
+	(*GetAccountSellersRequest)(nil),   // 9: proto.GetAccountSellersRequest
+	(*GetAccountSellersResponse)(nil),  // 10: proto.GetAccountSellersResponse
+	(*GetAccountBuyersRequest)(nil),    // 11: proto.GetAccountBuyersRequest
+	(*GetAccountBuyersResponse)(nil),   // 12: proto.GetAccountBuyersResponse
+	(*NotificationPreferences)(nil),    // 13: proto.NotificationPreferences
+	(*WishlistItem)(nil),               // 14: proto.WishlistItem
+	(*AddWishlistItemRequest)(nil),     // 15: proto.AddWishlistItemRequest
+	(*RemoveWishlistItemRequest)(nil),  // 16: proto.RemoveWishlistItemRequest
+	(*RemoveWishlistItemResponse)(nil), // 17: proto.RemoveWishlistItemResponse
+	(*GetWishlistRequest)(nil),         // 18: proto.GetWishlistRequest
+	(*GetWishlistResponse)(nil),        // 19: proto.GetWishlistResponse
+	(*DeleteAccountRequest)(nil),       // 20: proto.DeleteAccountRequest
+	(*DeleteAccountResponse)(nil),      // 21: proto.DeleteAccountResponse
 }
 var file_account_proto_depIdxs = []int32{
 	2,  // 0: proto.AccountBuyer.base_info:type_name -> proto.BaseInfo
@@ -678,25 +1300,41 @@ var file_account_proto_depIdxs = []int32{
 	1,  // 5: proto.PostAccountSellerResponse.account:type_name -> proto.AccountSeller
 	1,  // 6: proto.GetAccountSellerResponse.account:type_name -> proto.AccountSeller
 	1,  // 7: proto.GetAccountSellersResponse.accounts:type_name -> proto.AccountSeller
-	3,  // 8: proto.AccountService.PostAccountBuyer:input_type -> proto.PostAccountBuyerRequest
-	5,  // 9: proto.AccountService.PostAccountSeller:input_type -> proto.PostAccountSellerRequest
-	1,  // 10: proto.AccountService.UpdateAccountSeller:input_type -> proto.AccountSeller
-	0,  // 11: proto.AccountService.UpdateAccountBuyer:input_type -> proto.AccountBuyer
-	7,  // 12: proto.AccountService.GetAccountBuyer:input_type -> proto.GetAccountRequest
-	7,  // 13: proto.AccountService.GetAccountSeller:input_type -> proto.GetAccountRequest
-	9,  // 14: proto.AccountService.GetAccountSellers:input_type -> proto.GetAccountSellersRequest
-	4,  // 15: proto.AccountService.PostAccountBuyer:output_type -> proto.PostAccountBuyerResponse
-	6,  // 16: proto.AccountService.PostAccountSeller:output_type -> proto.PostAccountSellerResponse
-	1,  // 17: proto.AccountService.UpdateAccountSeller:output_type -> proto.AccountSeller
-	0,  // 18: proto.AccountService.UpdateAccountBuyer:output_type -> proto.AccountBuyer
-	0,  // 19: proto.AccountService.GetAccountBuyer:output_type -> proto.AccountBuyer
-	1,  // 20: proto.AccountService.GetAccountSeller:output_type -> proto.AccountSeller
-	10, // 21: proto.AccountService.GetAccountSellers:output_type -> proto.GetAccountSellersResponse
-	15, // [15:22] is the sub-list for method output_type
-	8,  // [8:15] is the sub-list for method input_type
-	8,  // [8:8] is the sub-list for extension type_name
-	8,  // [8:8] is the sub-list for extension extendee
-	0,  // [0:8] is the sub-list for field type_name
+	0,  // 8: proto.GetAccountBuyersResponse.accounts:type_name -> proto.AccountBuyer
+	14, // 9: proto.GetWishlistResponse.items:type_name -> proto.WishlistItem
+	3,  // 10: proto.AccountService.PostAccountBuyer:input_type -> proto.PostAccountBuyerRequest
+	5,  // 11: proto.AccountService.PostAccountSeller:input_type -> proto.PostAccountSellerRequest
+	1,  // 12: proto.AccountService.UpdateAccountSeller:input_type -> proto.AccountSeller
+	0,  // 13: proto.AccountService.UpdateAccountBuyer:input_type -> proto.AccountBuyer
+	7,  // 14: proto.AccountService.GetAccountBuyer:input_type -> proto.GetAccountRequest
+	7,  // 15: proto.AccountService.GetAccountSeller:input_type -> proto.GetAccountRequest
+	9,  // 16: proto.AccountService.GetAccountSellers:input_type -> proto.GetAccountSellersRequest
+	11, // 17: proto.AccountService.GetAccountBuyers:input_type -> proto.GetAccountBuyersRequest
+	7,  // 18: proto.AccountService.GetNotificationPreferences:input_type -> proto.GetAccountRequest
+	13, // 19: proto.AccountService.UpdateNotificationPreferences:input_type -> proto.NotificationPreferences
+	15, // 20: proto.AccountService.AddWishlistItem:input_type -> proto.AddWishlistItemRequest
+	16, // 21: proto.AccountService.RemoveWishlistItem:input_type -> proto.RemoveWishlistItemRequest
+	18, // 22: proto.AccountService.GetWishlist:input_type -> proto.GetWishlistRequest
+	20, // 23: proto.AccountService.DeleteAccount:input_type -> proto.DeleteAccountRequest
+	4,  // 24: proto.AccountService.PostAccountBuyer:output_type -> proto.PostAccountBuyerResponse
+	6,  // 25: proto.AccountService.PostAccountSeller:output_type -> proto.PostAccountSellerResponse
+	1,  // 26: proto.AccountService.UpdateAccountSeller:output_type -> proto.AccountSeller
+	0,  // 27: proto.AccountService.UpdateAccountBuyer:output_type -> proto.AccountBuyer
+	0,  // 28: proto.AccountService.GetAccountBuyer:output_type -> proto.AccountBuyer
+	1,  // 29: proto.AccountService.GetAccountSeller:output_type -> proto.AccountSeller
+	10, // 30: proto.AccountService.GetAccountSellers:output_type -> proto.GetAccountSellersResponse
+	12, // 31: proto.AccountService.GetAccountBuyers:output_type -> proto.GetAccountBuyersResponse
+	13, // 32: proto.AccountService.GetNotificationPreferences:output_type -> proto.NotificationPreferences
+	13, // 33: proto.AccountService.UpdateNotificationPreferences:output_type -> proto.NotificationPreferences
+	14, // 34: proto.AccountService.AddWishlistItem:output_type -> proto.WishlistItem
+	17, // 35: proto.AccountService.RemoveWishlistItem:output_type -> proto.RemoveWishlistItemResponse
+	19, // 36: proto.AccountService.GetWishlist:output_type -> proto.GetWishlistResponse
+	21, // 37: proto.AccountService.DeleteAccount:output_type -> proto.DeleteAccountResponse
+	24, // [24:38] is the sub-list for method output_type
+	10, // [10:24] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_account_proto_init() }
@@ -710,7 +1348,7 @@ func file_account_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_account_proto_rawDesc), len(file_account_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   11,
+			NumMessages:   22,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
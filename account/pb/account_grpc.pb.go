@@ -19,13 +19,20 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	AccountService_PostAccountBuyer_FullMethodName    = "/proto.AccountService/PostAccountBuyer"
-	AccountService_PostAccountSeller_FullMethodName   = "/proto.AccountService/PostAccountSeller"
-	AccountService_UpdateAccountSeller_FullMethodName = "/proto.AccountService/UpdateAccountSeller"
-	AccountService_UpdateAccountBuyer_FullMethodName  = "/proto.AccountService/UpdateAccountBuyer"
-	AccountService_GetAccountBuyer_FullMethodName     = "/proto.AccountService/GetAccountBuyer"
-	AccountService_GetAccountSeller_FullMethodName    = "/proto.AccountService/GetAccountSeller"
-	AccountService_GetAccountSellers_FullMethodName   = "/proto.AccountService/GetAccountSellers"
+	AccountService_PostAccountBuyer_FullMethodName              = "/proto.AccountService/PostAccountBuyer"
+	AccountService_PostAccountSeller_FullMethodName             = "/proto.AccountService/PostAccountSeller"
+	AccountService_UpdateAccountSeller_FullMethodName           = "/proto.AccountService/UpdateAccountSeller"
+	AccountService_UpdateAccountBuyer_FullMethodName            = "/proto.AccountService/UpdateAccountBuyer"
+	AccountService_GetAccountBuyer_FullMethodName               = "/proto.AccountService/GetAccountBuyer"
+	AccountService_GetAccountSeller_FullMethodName              = "/proto.AccountService/GetAccountSeller"
+	AccountService_GetAccountSellers_FullMethodName             = "/proto.AccountService/GetAccountSellers"
+	AccountService_GetAccountBuyers_FullMethodName              = "/proto.AccountService/GetAccountBuyers"
+	AccountService_GetNotificationPreferences_FullMethodName    = "/proto.AccountService/GetNotificationPreferences"
+	AccountService_UpdateNotificationPreferences_FullMethodName = "/proto.AccountService/UpdateNotificationPreferences"
+	AccountService_AddWishlistItem_FullMethodName               = "/proto.AccountService/AddWishlistItem"
+	AccountService_RemoveWishlistItem_FullMethodName            = "/proto.AccountService/RemoveWishlistItem"
+	AccountService_GetWishlist_FullMethodName                   = "/proto.AccountService/GetWishlist"
+	AccountService_DeleteAccount_FullMethodName                 = "/proto.AccountService/DeleteAccount"
 )
 
 // AccountServiceClient is the client API for AccountService service.
@@ -39,6 +46,13 @@ type AccountServiceClient interface {
 	GetAccountBuyer(ctx context.Context, in *GetAccountRequest, opts ...grpc.CallOption) (*AccountBuyer, error)
 	GetAccountSeller(ctx context.Context, in *GetAccountRequest, opts ...grpc.CallOption) (*AccountSeller, error)
 	GetAccountSellers(ctx context.Context, in *GetAccountSellersRequest, opts ...grpc.CallOption) (*GetAccountSellersResponse, error)
+	GetAccountBuyers(ctx context.Context, in *GetAccountBuyersRequest, opts ...grpc.CallOption) (*GetAccountBuyersResponse, error)
+	GetNotificationPreferences(ctx context.Context, in *GetAccountRequest, opts ...grpc.CallOption) (*NotificationPreferences, error)
+	UpdateNotificationPreferences(ctx context.Context, in *NotificationPreferences, opts ...grpc.CallOption) (*NotificationPreferences, error)
+	AddWishlistItem(ctx context.Context, in *AddWishlistItemRequest, opts ...grpc.CallOption) (*WishlistItem, error)
+	RemoveWishlistItem(ctx context.Context, in *RemoveWishlistItemRequest, opts ...grpc.CallOption) (*RemoveWishlistItemResponse, error)
+	GetWishlist(ctx context.Context, in *GetWishlistRequest, opts ...grpc.CallOption) (*GetWishlistResponse, error)
+	DeleteAccount(ctx context.Context, in *DeleteAccountRequest, opts ...grpc.CallOption) (*DeleteAccountResponse, error)
 }
 
 type accountServiceClient struct {
@@ -119,6 +133,76 @@ func (c *accountServiceClient) GetAccountSellers(ctx context.Context, in *GetAcc
 	return out, nil
 }
 
+func (c *accountServiceClient) GetAccountBuyers(ctx context.Context, in *GetAccountBuyersRequest, opts ...grpc.CallOption) (*GetAccountBuyersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAccountBuyersResponse)
+	err := c.cc.Invoke(ctx, AccountService_GetAccountBuyers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) GetNotificationPreferences(ctx context.Context, in *GetAccountRequest, opts ...grpc.CallOption) (*NotificationPreferences, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NotificationPreferences)
+	err := c.cc.Invoke(ctx, AccountService_GetNotificationPreferences_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) UpdateNotificationPreferences(ctx context.Context, in *NotificationPreferences, opts ...grpc.CallOption) (*NotificationPreferences, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NotificationPreferences)
+	err := c.cc.Invoke(ctx, AccountService_UpdateNotificationPreferences_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) AddWishlistItem(ctx context.Context, in *AddWishlistItemRequest, opts ...grpc.CallOption) (*WishlistItem, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WishlistItem)
+	err := c.cc.Invoke(ctx, AccountService_AddWishlistItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) RemoveWishlistItem(ctx context.Context, in *RemoveWishlistItemRequest, opts ...grpc.CallOption) (*RemoveWishlistItemResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveWishlistItemResponse)
+	err := c.cc.Invoke(ctx, AccountService_RemoveWishlistItem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) GetWishlist(ctx context.Context, in *GetWishlistRequest, opts ...grpc.CallOption) (*GetWishlistResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetWishlistResponse)
+	err := c.cc.Invoke(ctx, AccountService_GetWishlist_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountServiceClient) DeleteAccount(ctx context.Context, in *DeleteAccountRequest, opts ...grpc.CallOption) (*DeleteAccountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteAccountResponse)
+	err := c.cc.Invoke(ctx, AccountService_DeleteAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AccountServiceServer is the server API for AccountService service.
 // All implementations must embed UnimplementedAccountServiceServer
 // for forward compatibility.
@@ -130,6 +214,13 @@ type AccountServiceServer interface {
 	GetAccountBuyer(context.Context, *GetAccountRequest) (*AccountBuyer, error)
 	GetAccountSeller(context.Context, *GetAccountRequest) (*AccountSeller, error)
 	GetAccountSellers(context.Context, *GetAccountSellersRequest) (*GetAccountSellersResponse, error)
+	GetAccountBuyers(context.Context, *GetAccountBuyersRequest) (*GetAccountBuyersResponse, error)
+	GetNotificationPreferences(context.Context, *GetAccountRequest) (*NotificationPreferences, error)
+	UpdateNotificationPreferences(context.Context, *NotificationPreferences) (*NotificationPreferences, error)
+	AddWishlistItem(context.Context, *AddWishlistItemRequest) (*WishlistItem, error)
+	RemoveWishlistItem(context.Context, *RemoveWishlistItemRequest) (*RemoveWishlistItemResponse, error)
+	GetWishlist(context.Context, *GetWishlistRequest) (*GetWishlistResponse, error)
+	DeleteAccount(context.Context, *DeleteAccountRequest) (*DeleteAccountResponse, error)
 	mustEmbedUnimplementedAccountServiceServer()
 }
 
@@ -161,6 +252,27 @@ func (UnimplementedAccountServiceServer) GetAccountSeller(context.Context, *GetA
 func (UnimplementedAccountServiceServer) GetAccountSellers(context.Context, *GetAccountSellersRequest) (*GetAccountSellersResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAccountSellers not implemented")
 }
+func (UnimplementedAccountServiceServer) GetAccountBuyers(context.Context, *GetAccountBuyersRequest) (*GetAccountBuyersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccountBuyers not implemented")
+}
+func (UnimplementedAccountServiceServer) GetNotificationPreferences(context.Context, *GetAccountRequest) (*NotificationPreferences, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNotificationPreferences not implemented")
+}
+func (UnimplementedAccountServiceServer) UpdateNotificationPreferences(context.Context, *NotificationPreferences) (*NotificationPreferences, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateNotificationPreferences not implemented")
+}
+func (UnimplementedAccountServiceServer) AddWishlistItem(context.Context, *AddWishlistItemRequest) (*WishlistItem, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddWishlistItem not implemented")
+}
+func (UnimplementedAccountServiceServer) RemoveWishlistItem(context.Context, *RemoveWishlistItemRequest) (*RemoveWishlistItemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveWishlistItem not implemented")
+}
+func (UnimplementedAccountServiceServer) GetWishlist(context.Context, *GetWishlistRequest) (*GetWishlistResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWishlist not implemented")
+}
+func (UnimplementedAccountServiceServer) DeleteAccount(context.Context, *DeleteAccountRequest) (*DeleteAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAccount not implemented")
+}
 func (UnimplementedAccountServiceServer) mustEmbedUnimplementedAccountServiceServer() {}
 func (UnimplementedAccountServiceServer) testEmbeddedByValue()                        {}
 
@@ -308,6 +420,132 @@ func _AccountService_GetAccountSellers_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AccountService_GetAccountBuyers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountBuyersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).GetAccountBuyers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_GetAccountBuyers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).GetAccountBuyers(ctx, req.(*GetAccountBuyersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_GetNotificationPreferences_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).GetNotificationPreferences(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_GetNotificationPreferences_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).GetNotificationPreferences(ctx, req.(*GetAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_UpdateNotificationPreferences_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NotificationPreferences)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).UpdateNotificationPreferences(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_UpdateNotificationPreferences_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).UpdateNotificationPreferences(ctx, req.(*NotificationPreferences))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_AddWishlistItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddWishlistItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).AddWishlistItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_AddWishlistItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).AddWishlistItem(ctx, req.(*AddWishlistItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_RemoveWishlistItem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveWishlistItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).RemoveWishlistItem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_RemoveWishlistItem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).RemoveWishlistItem(ctx, req.(*RemoveWishlistItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_GetWishlist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWishlistRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).GetWishlist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_GetWishlist_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).GetWishlist(ctx, req.(*GetWishlistRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountService_DeleteAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountServiceServer).DeleteAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountService_DeleteAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountServiceServer).DeleteAccount(ctx, req.(*DeleteAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AccountService_ServiceDesc is the grpc.ServiceDesc for AccountService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -343,6 +581,34 @@ var AccountService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetAccountSellers",
 			Handler:    _AccountService_GetAccountSellers_Handler,
 		},
+		{
+			MethodName: "GetAccountBuyers",
+			Handler:    _AccountService_GetAccountBuyers_Handler,
+		},
+		{
+			MethodName: "GetNotificationPreferences",
+			Handler:    _AccountService_GetNotificationPreferences_Handler,
+		},
+		{
+			MethodName: "UpdateNotificationPreferences",
+			Handler:    _AccountService_UpdateNotificationPreferences_Handler,
+		},
+		{
+			MethodName: "AddWishlistItem",
+			Handler:    _AccountService_AddWishlistItem_Handler,
+		},
+		{
+			MethodName: "RemoveWishlistItem",
+			Handler:    _AccountService_RemoveWishlistItem_Handler,
+		},
+		{
+			MethodName: "GetWishlist",
+			Handler:    _AccountService_GetWishlist_Handler,
+		},
+		{
+			MethodName: "DeleteAccount",
+			Handler:    _AccountService_DeleteAccount_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "account.proto",
@@ -0,0 +1,115 @@
+package pb
+
+import "github.com/231031/ecom-mcs-grpc/pkg/validate"
+
+// Validation rules of the account requests, enforced by the service and the
+// gateway through the validate interceptors.
+
+const (
+	maxNameLen    = 100
+	maxAddressLen = 500
+)
+
+// Validate checks the format of the fields that are set, the requests
+// creating an account also require them.
+func (m *BaseInfo) Validate() error {
+	return validate.Check(
+		validate.MaxLen("first_name", m.GetFirstName(), maxNameLen),
+		validate.MaxLen("last_name", m.GetLastName(), maxNameLen),
+		validate.Phone("phone", m.GetPhone()),
+		validate.MaxLen("address", m.GetAddress(), maxAddressLen),
+	)
+}
+
+func requiredBaseInfo(m *BaseInfo) []validate.Rule {
+	return []validate.Rule{
+		validate.Present("base_info", m != nil),
+		validate.Required("base_info.first_name", m.GetFirstName()),
+		validate.Required("base_info.last_name", m.GetLastName()),
+		validate.Required("base_info.phone", m.GetPhone()),
+		validate.Required("base_info.address", m.GetAddress()),
+		validate.Nested("base_info", m),
+	}
+}
+
+func (m *PostAccountBuyerRequest) Validate() error {
+	rules := []validate.Rule{validate.Required("id", m.GetId())}
+	return validate.Check(append(rules, requiredBaseInfo(m.GetBaseInfo())...)...)
+}
+
+func (m *PostAccountSellerRequest) Validate() error {
+	rules := []validate.Rule{
+		validate.Required("id", m.GetId()),
+		validate.Required("store_name", m.GetStoreName()),
+		validate.MaxLen("store_name", m.GetStoreName(), maxNameLen),
+	}
+	return validate.Check(append(rules, requiredBaseInfo(m.GetBaseInfo())...)...)
+}
+
+func (m *AccountBuyer) Validate() error {
+	return validate.Check(
+		validate.Required("id", m.GetId()),
+		validate.Present("base_info", m.GetBaseInfo() != nil),
+		validate.Nested("base_info", m.GetBaseInfo()),
+	)
+}
+
+func (m *AccountSeller) Validate() error {
+	return validate.Check(
+		validate.Required("id", m.GetId()),
+		validate.MaxLen("store_name", m.GetStoreName(), maxNameLen),
+		validate.Present("base_info", m.GetBaseInfo() != nil),
+		validate.Nested("base_info", m.GetBaseInfo()),
+	)
+}
+
+func (m *GetAccountRequest) Validate() error {
+	return validate.Check(
+		validate.Required("id", m.GetId()),
+	)
+}
+
+func (m *GetAccountSellersRequest) Validate() error {
+	return validate.Check(
+		validate.EachRequired("ids", m.GetIds()),
+	)
+}
+
+func (m *GetAccountBuyersRequest) Validate() error {
+	return validate.Check(
+		validate.EachRequired("ids", m.GetIds()),
+	)
+}
+
+func (m *NotificationPreferences) Validate() error {
+	return validate.Check(
+		validate.Required("account_id", m.GetAccountId()),
+	)
+}
+
+func (m *AddWishlistItemRequest) Validate() error {
+	return validate.Check(
+		validate.Required("buyer_id", m.GetBuyerId()),
+		validate.Required("product_id", m.GetProductId()),
+		validate.NonNegative("saved_price", m.GetSavedPrice()),
+	)
+}
+
+func (m *RemoveWishlistItemRequest) Validate() error {
+	return validate.Check(
+		validate.Required("buyer_id", m.GetBuyerId()),
+		validate.Required("product_id", m.GetProductId()),
+	)
+}
+
+func (m *GetWishlistRequest) Validate() error {
+	return validate.Check(
+		validate.Required("buyer_id", m.GetBuyerId()),
+	)
+}
+
+func (m *DeleteAccountRequest) Validate() error {
+	return validate.Check(
+		validate.Required("id", m.GetId()),
+	)
+}
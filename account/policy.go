@@ -0,0 +1,56 @@
+package account
+
+import (
+	"github.com/231031/ecom-mcs-grpc/account/pb"
+	"github.com/231031/ecom-mcs-grpc/pkg/auth"
+)
+
+// policies says who may call each method of the service.
+var policies = auth.Policies{
+	pb.AccountService_PostAccountBuyer_FullMethodName: auth.OwnedBy(func(r *pb.PostAccountBuyerRequest) string {
+		return r.GetId()
+	}, auth.RoleBuyer),
+	pb.AccountService_PostAccountSeller_FullMethodName: auth.OwnedBy(func(r *pb.PostAccountSellerRequest) string {
+		return r.GetId()
+	}, auth.RoleSeller),
+
+	pb.AccountService_UpdateAccountSeller_FullMethodName: auth.OwnedBy(func(r *pb.AccountSeller) string {
+		return r.GetId()
+	}, auth.RoleSeller),
+	pb.AccountService_UpdateAccountBuyer_FullMethodName: auth.OwnedBy(func(r *pb.AccountBuyer) string {
+		return r.GetId()
+	}, auth.RoleBuyer),
+
+	// a buyer only sees their own profile, sellers reach the buyers of their
+	// orders through the order service and admins see the buyer of any order
+	pb.AccountService_GetAccountBuyer_FullMethodName: auth.AnyOf(
+		auth.OwnedBy(func(r *pb.GetAccountRequest) string { return r.GetId() }, auth.RoleBuyer),
+		auth.Roles(auth.RoleAdmin),
+	),
+	pb.AccountService_GetAccountBuyers_FullMethodName: auth.AnyOf(
+		auth.OwnedByAll(func(r *pb.GetAccountBuyersRequest) []string { return r.GetIds() }, auth.RoleBuyer),
+		auth.Roles(auth.RoleAdmin),
+	),
+	pb.AccountService_GetAccountSeller_FullMethodName:  auth.Authenticated(),
+	pb.AccountService_GetAccountSellers_FullMethodName: auth.Authenticated(),
+
+	pb.AccountService_GetNotificationPreferences_FullMethodName: auth.OwnedBy(func(r *pb.GetAccountRequest) string {
+		return r.GetId()
+	}, auth.RoleBuyer, auth.RoleSeller),
+	pb.AccountService_UpdateNotificationPreferences_FullMethodName: auth.OwnedBy(func(r *pb.NotificationPreferences) string {
+		return r.GetAccountId()
+	}, auth.RoleBuyer, auth.RoleSeller),
+
+	pb.AccountService_AddWishlistItem_FullMethodName: auth.OwnedBy(func(r *pb.AddWishlistItemRequest) string {
+		return r.GetBuyerId()
+	}, auth.RoleBuyer),
+	pb.AccountService_RemoveWishlistItem_FullMethodName: auth.OwnedBy(func(r *pb.RemoveWishlistItemRequest) string {
+		return r.GetBuyerId()
+	}, auth.RoleBuyer),
+	pb.AccountService_GetWishlist_FullMethodName: auth.OwnedBy(func(r *pb.GetWishlistRequest) string {
+		return r.GetBuyerId()
+	}, auth.RoleBuyer),
+
+	// the authentication service erases the account once its deletion is due
+	pb.AccountService_DeleteAccount_FullMethodName: auth.Internal(),
+}
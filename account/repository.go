@@ -8,10 +8,13 @@ import (
 	_ "github.com/lib/pq"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 var (
-	ErrNotFound = errors.New("account not found")
+	ErrNotFound             = errors.New("account not found")
+	ErrAlreadyExists        = errors.New("account already exists")
+	ErrWishlistItemNotFound = errors.New("wishlist item not found")
 )
 
 type Repository interface {
@@ -27,6 +30,16 @@ type Repository interface {
 
 	ListAccountSellers(ctx context.Context, skip uint64, take uint64) ([]Seller, error)
 	ListAccountSellersByID(ctx context.Context, ids []string) ([]Seller, error)
+	ListAccountBuyersByID(ctx context.Context, ids []string) ([]Buyer, error)
+
+	GetNotificationPreference(ctx context.Context, accountID string) (*NotificationPreference, error)
+	PutNotificationPreference(ctx context.Context, p NotificationPreference) error
+
+	PutWishlistItem(ctx context.Context, item WishlistItem) (*WishlistItem, error)
+	DeleteWishlistItem(ctx context.Context, buyerID, productID string) error
+	ListWishlistItems(ctx context.Context, buyerID string, skip uint64, take uint64) ([]WishlistItem, error)
+
+	DeleteAccount(ctx context.Context, id string) error
 }
 
 type postgresRepository struct {
@@ -35,7 +48,7 @@ type postgresRepository struct {
 
 func NewPostgresRepository(url string) (Repository, error) {
 
-	db, err := gorm.Open(postgres.Open(url), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(url), &gorm.Config{TranslateError: true})
 
 	if err != nil {
 		log.Fatalln(err)
@@ -63,6 +76,9 @@ func (r *postgresRepository) Ping() error {
 func (r *postgresRepository) CreateAccountSeller(ctx context.Context, a Seller) error {
 	result := r.db.WithContext(ctx).Create(&a)
 	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return ErrAlreadyExists
+		}
 		return result.Error
 	}
 
@@ -72,6 +88,9 @@ func (r *postgresRepository) CreateAccountSeller(ctx context.Context, a Seller)
 func (r *postgresRepository) CreateAccountBuyer(ctx context.Context, a Buyer) error {
 	result := r.db.WithContext(ctx).Create(&a)
 	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return ErrAlreadyExists
+		}
 		return result.Error
 	}
 
@@ -148,3 +167,100 @@ func (r *postgresRepository) ListAccountSellersByID(ctx context.Context, ids []s
 
 	return sellers, nil
 }
+
+func (r *postgresRepository) ListAccountBuyersByID(ctx context.Context, ids []string) ([]Buyer, error) {
+	var buyers = []Buyer{}
+	result := r.db.WithContext(ctx).Where("(id) IN (?)", ids).Find(&buyers)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return buyers, nil
+}
+
+func (r *postgresRepository) GetNotificationPreference(ctx context.Context, accountID string) (*NotificationPreference, error) {
+	var preference = NotificationPreference{AccountID: accountID}
+	result := r.db.WithContext(ctx).First(&preference)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return defaultNotificationPreference(accountID), nil
+		}
+		return nil, result.Error
+	}
+
+	return &preference, nil
+}
+
+func (r *postgresRepository) PutNotificationPreference(ctx context.Context, p NotificationPreference) error {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(&p)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return nil
+}
+
+// PutWishlistItem saves a product once, saving it again keeps the price of
+// the first save so a price drop is measured from there.
+func (r *postgresRepository) PutWishlistItem(ctx context.Context, item WishlistItem) (*WishlistItem, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&item)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	var saved WishlistItem
+	result = r.db.WithContext(ctx).
+		Where("buyer_id = ? AND product_id = ?", item.BuyerID, item.ProductID).
+		First(&saved)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &saved, nil
+}
+
+func (r *postgresRepository) DeleteWishlistItem(ctx context.Context, buyerID, productID string) error {
+	result := r.db.WithContext(ctx).
+		Where("buyer_id = ? AND product_id = ?", buyerID, productID).
+		Delete(&WishlistItem{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrWishlistItemNotFound
+	}
+
+	return nil
+}
+
+func (r *postgresRepository) ListWishlistItems(ctx context.Context, buyerID string, skip uint64, take uint64) ([]WishlistItem, error) {
+	var items = []WishlistItem{}
+	result := r.db.WithContext(ctx).
+		Where("buyer_id = ?", buyerID).
+		Order("created_at DESC").
+		Limit(int(take)).Offset(int(skip)).
+		Find(&items)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return items, nil
+}
+
+// DeleteAccount erases the profile of the account with its wishlist and
+// notification preferences, deleting an account that is gone already is not
+// an error.
+func (r *postgresRepository) DeleteAccount(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("buyer_id = ?", id).Delete(&WishlistItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("id = ?", id).Delete(&Buyer{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("id = ?", id).Delete(&Seller{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("account_id = ?", id).Delete(&NotificationPreference{}).Error
+	})
+}
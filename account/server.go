@@ -3,24 +3,40 @@ package account
 import (
 	"context"
 	"fmt"
+	"log"
 	"net"
 
 	"github.com/231031/ecom-mcs-grpc/account/pb"
+	"github.com/231031/ecom-mcs-grpc/pkg/auth"
+	"github.com/231031/ecom-mcs-grpc/pkg/grpcerr"
+	"github.com/231031/ecom-mcs-grpc/pkg/validate"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 )
 
+var errorMappings = []grpcerr.Mapping{
+	grpcerr.NotFound(ErrNotFound),
+	grpcerr.AlreadyExists(ErrAlreadyExists),
+	grpcerr.NotFound(ErrWishlistItemNotFound),
+}
+
 type grpcServer struct {
 	service Service
 	pb.UnimplementedAccountServiceServer
 }
 
-func ListenGRPC(s Service, port int) error {
+func ListenGRPC(s Service, authn *auth.Authenticator, port int) error {
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
 	if err != nil {
 		return err
 	}
-	serv := grpc.NewServer()
+	serv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			grpcerr.UnaryServerInterceptor(errorMappings...),
+			authn.UnaryServerInterceptor(policies),
+			validate.UnaryServerInterceptor(),
+		),
+	)
 	pb.RegisterAccountServiceServer(
 		serv,
 		&grpcServer{
@@ -183,3 +199,109 @@ func (s *grpcServer) GetAccountSellers(ctx context.Context, r *pb.GetAccountSell
 	}
 	return &pb.GetAccountSellersResponse{Accounts: accounts}, nil
 }
+
+func (s *grpcServer) GetAccountBuyers(ctx context.Context, r *pb.GetAccountBuyersRequest) (*pb.GetAccountBuyersResponse, error) {
+	a, err := s.service.GetAccountBuyers(ctx, r.Ids)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := []*pb.AccountBuyer{}
+	for _, p := range a {
+		accounts = append(
+			accounts,
+			&pb.AccountBuyer{
+				Id: p.ID,
+				BaseInfo: &pb.BaseInfo{
+					FirstName: p.FirstName,
+					LastName:  p.LastName,
+					Phone:     p.Phone,
+					Address:   p.Address,
+				},
+			},
+		)
+	}
+	return &pb.GetAccountBuyersResponse{Accounts: accounts}, nil
+}
+
+func (s *grpcServer) GetNotificationPreferences(ctx context.Context, r *pb.GetAccountRequest) (*pb.NotificationPreferences, error) {
+	p, err := s.service.GetNotificationPreferences(ctx, r.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.NotificationPreferences{
+		AccountId:      p.AccountID,
+		AccountCreated: p.AccountCreated,
+		OrderPlaced:    p.OrderPlaced,
+		OrderShipped:   p.OrderShipped,
+	}, nil
+}
+
+func (s *grpcServer) UpdateNotificationPreferences(ctx context.Context, r *pb.NotificationPreferences) (*pb.NotificationPreferences, error) {
+	_, err := s.service.UpdateNotificationPreferences(ctx, NotificationPreference{
+		AccountID:      r.AccountId,
+		AccountCreated: r.AccountCreated,
+		OrderPlaced:    r.OrderPlaced,
+		OrderShipped:   r.OrderShipped,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (s *grpcServer) AddWishlistItem(ctx context.Context, r *pb.AddWishlistItemRequest) (*pb.WishlistItem, error) {
+	item, err := s.service.AddWishlistItem(ctx, r.BuyerId, r.ProductId, r.SavedPrice)
+	if err != nil {
+		return nil, err
+	}
+
+	return wishlistItemToProto(*item), nil
+}
+
+func (s *grpcServer) RemoveWishlistItem(ctx context.Context, r *pb.RemoveWishlistItemRequest) (*pb.RemoveWishlistItemResponse, error) {
+	err := s.service.RemoveWishlistItem(ctx, r.BuyerId, r.ProductId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.RemoveWishlistItemResponse{ProductId: r.ProductId}, nil
+}
+
+func (s *grpcServer) GetWishlist(ctx context.Context, r *pb.GetWishlistRequest) (*pb.GetWishlistResponse, error) {
+	items, err := s.service.GetWishlist(ctx, r.BuyerId, r.Skip, r.Take)
+	if err != nil {
+		return nil, err
+	}
+
+	itemsProto := []*pb.WishlistItem{}
+	for _, item := range items {
+		itemsProto = append(itemsProto, wishlistItemToProto(item))
+	}
+	return &pb.GetWishlistResponse{Items: itemsProto}, nil
+}
+
+func wishlistItemToProto(item WishlistItem) *pb.WishlistItem {
+	createdAt, err := item.CreatedAt.MarshalBinary()
+	if err != nil {
+		log.Println("error marshal timestamp", err)
+	}
+
+	return &pb.WishlistItem{
+		BuyerId:    item.BuyerID,
+		ProductId:  item.ProductID,
+		SavedPrice: item.SavedPrice,
+		CreatedAt:  createdAt,
+	}
+}
+
+func (s *grpcServer) DeleteAccount(ctx context.Context, r *pb.DeleteAccountRequest) (*pb.DeleteAccountResponse, error) {
+	err := s.service.DeleteAccount(ctx, r.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.DeleteAccountResponse{}, nil
+}
@@ -14,6 +14,16 @@ type Service interface {
 	GetAccountSellerByID(ctx context.Context, id string) (*Seller, error)
 	GetAccountBuyerByID(ctx context.Context, id string) (*Buyer, error)
 	GetAccountSellers(ctx context.Context, ids []string, skip uint64, take uint64) ([]Seller, error)
+	GetAccountBuyers(ctx context.Context, ids []string) ([]Buyer, error)
+
+	GetNotificationPreferences(ctx context.Context, accountID string) (*NotificationPreference, error)
+	UpdateNotificationPreferences(ctx context.Context, p NotificationPreference) (*NotificationPreference, error)
+
+	AddWishlistItem(ctx context.Context, buyerID, productID string, savedPrice float64) (*WishlistItem, error)
+	RemoveWishlistItem(ctx context.Context, buyerID, productID string) error
+	GetWishlist(ctx context.Context, buyerID string, skip uint64, take uint64) ([]WishlistItem, error)
+
+	DeleteAccount(ctx context.Context, id string) error
 }
 
 type AccountService struct {
@@ -75,3 +85,48 @@ func (s *AccountService) GetAccountSellers(ctx context.Context, ids []string, sk
 		return s.repository.ListAccountSellers(ctx, skip, take)
 	}
 }
+
+func (s *AccountService) GetAccountBuyers(ctx context.Context, ids []string) ([]Buyer, error) {
+	if len(ids) == 0 {
+		return []Buyer{}, nil
+	}
+	return s.repository.ListAccountBuyersByID(ctx, ids)
+}
+
+func (s *AccountService) GetNotificationPreferences(ctx context.Context, accountID string) (*NotificationPreference, error) {
+	return s.repository.GetNotificationPreference(ctx, accountID)
+}
+
+func (s *AccountService) UpdateNotificationPreferences(ctx context.Context, p NotificationPreference) (*NotificationPreference, error) {
+	if err := s.repository.PutNotificationPreference(ctx, p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (s *AccountService) AddWishlistItem(ctx context.Context, buyerID, productID string, savedPrice float64) (*WishlistItem, error) {
+	if _, err := s.repository.GetBuyerByID(ctx, buyerID); err != nil {
+		return nil, err
+	}
+
+	return s.repository.PutWishlistItem(ctx, WishlistItem{
+		BuyerID:    buyerID,
+		ProductID:  productID,
+		SavedPrice: savedPrice,
+	})
+}
+
+func (s *AccountService) RemoveWishlistItem(ctx context.Context, buyerID, productID string) error {
+	return s.repository.DeleteWishlistItem(ctx, buyerID, productID)
+}
+
+func (s *AccountService) GetWishlist(ctx context.Context, buyerID string, skip uint64, take uint64) ([]WishlistItem, error) {
+	if take > 100 || (skip == 0 && take == 0) {
+		take = 100
+	}
+	return s.repository.ListWishlistItems(ctx, buyerID, skip, take)
+}
+
+func (s *AccountService) DeleteAccount(ctx context.Context, id string) error {
+	return s.repository.DeleteAccount(ctx, id)
+}
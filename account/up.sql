@@ -26,6 +26,22 @@ CREATE TABLE IF NOT EXISTS buyers (
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL
 );
 
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    account_id VARCHAR(27) PRIMARY KEY,
+    account_created BOOLEAN NOT NULL DEFAULT TRUE,
+    order_placed BOOLEAN NOT NULL DEFAULT TRUE,
+    order_shipped BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS wishlist_items (
+    buyer_id VARCHAR(27) NOT NULL REFERENCES buyers (id) ON DELETE CASCADE,
+    product_id VARCHAR(27) NOT NULL,
+    saved_price NUMERIC NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    PRIMARY KEY (buyer_id, product_id)
+);
+
 CREATE TRIGGER update_sellers_updated_at
 BEFORE UPDATE ON sellers
 FOR EACH ROW
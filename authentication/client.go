@@ -2,8 +2,10 @@ package authentication
 
 import (
 	"context"
+	"time"
 
 	"github.com/231031/ecom-mcs-grpc/authentication/pb"
+	"github.com/231031/ecom-mcs-grpc/pkg/auth"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
@@ -59,3 +61,218 @@ func (c *Client) RefreshTokenUser(ctx context.Context, in *pb.RefreshTokenReques
 	}
 	return token, nil
 }
+
+func (c *Client) VerifyMFA(ctx context.Context, in *pb.VerifyMFARequest) (*pb.LoginUserResponse, error) {
+	u, err := c.service.VerifyMFA(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func (c *Client) StartOIDCLogin(ctx context.Context, provider string) (*pb.StartOIDCLoginResponse, error) {
+	r, err := c.service.StartOIDCLogin(ctx, &pb.StartOIDCLoginRequest{Provider: provider})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (c *Client) CompleteOIDCLogin(ctx context.Context, in *pb.CompleteOIDCLoginRequest) (*pb.LoginUserResponse, error) {
+	u, err := c.service.CompleteOIDCLogin(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func (c *Client) EnrollTOTP(ctx context.Context, userID string) (*pb.EnrollTOTPResponse, error) {
+	r, err := c.service.EnrollTOTP(ctx, &pb.EnrollTOTPRequest{UserId: userID})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (c *Client) EnableTOTP(ctx context.Context, userID string, code string) error {
+	_, err := c.service.EnableTOTP(ctx, &pb.EnableTOTPRequest{
+		UserId: userID,
+		Code:   code,
+	})
+	return err
+}
+
+func (c *Client) DisableTOTP(ctx context.Context, userID string, code string) error {
+	_, err := c.service.DisableTOTP(ctx, &pb.DisableTOTPRequest{
+		UserId: userID,
+		Code:   code,
+	})
+	return err
+}
+
+func (c *Client) ChangePassword(ctx context.Context, userID string, sessionID string, current string, password string) error {
+	_, err := c.service.ChangePassword(ctx, &pb.ChangePasswordRequest{
+		UserId:          userID,
+		SessionId:       sessionID,
+		CurrentPassword: current,
+		NewPassword:     password,
+	})
+	return err
+}
+
+func (c *Client) ChangeEmail(ctx context.Context, userID string, password string, email string) error {
+	_, err := c.service.ChangeEmail(ctx, &pb.ChangeEmailRequest{
+		UserId:   userID,
+		Password: password,
+		Email:    email,
+	})
+	return err
+}
+
+// DeleteUser asks for the account to be deleted and returns when it is
+// erased, a login before then keeps it.
+func (c *Client) DeleteUser(ctx context.Context, userID string, password string) (time.Time, error) {
+	r, err := c.service.DeleteUser(ctx, &pb.DeleteUserRequest{
+		UserId:   userID,
+		Password: password,
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	deleteAt := time.Time{}
+	if err := deleteAt.UnmarshalBinary(r.GetDeleteAt()); err != nil {
+		return time.Time{}, err
+	}
+	return deleteAt, nil
+}
+
+func (c *Client) VerifyEmail(ctx context.Context, token string) (string, error) {
+	r, err := c.service.VerifyEmail(ctx, &pb.VerifyEmailRequest{Token: token})
+	if err != nil {
+		return "", err
+	}
+	return r.GetEmail(), nil
+}
+
+func (c *Client) RequestEmailVerification(ctx context.Context, email string) error {
+	_, err := c.service.RequestEmailVerification(ctx, &pb.RequestEmailVerificationRequest{Email: email})
+	return err
+}
+
+func (c *Client) RequestPasswordReset(ctx context.Context, email string) error {
+	_, err := c.service.RequestPasswordReset(ctx, &pb.RequestPasswordResetRequest{Email: email})
+	return err
+}
+
+func (c *Client) ResetPassword(ctx context.Context, token string, password string) error {
+	_, err := c.service.ResetPassword(ctx, &pb.ResetPasswordRequest{
+		Token:    token,
+		Password: password,
+	})
+	return err
+}
+
+func (c *Client) Logout(ctx context.Context, userID string, sessionID string) error {
+	_, err := c.service.Logout(ctx, &pb.LogoutRequest{
+		UserId:    userID,
+		SessionId: sessionID,
+	})
+	return err
+}
+
+func (c *Client) LogoutAllSessions(ctx context.Context, userID string) (uint32, error) {
+	r, err := c.service.LogoutAllSessions(ctx, &pb.LogoutAllSessionsRequest{UserId: userID})
+	if err != nil {
+		return 0, err
+	}
+	return r.GetRevoked(), nil
+}
+
+func (c *Client) ListSessions(ctx context.Context, userID string) ([]*pb.Session, error) {
+	r, err := c.service.ListSessions(ctx, &pb.ListSessionsRequest{UserId: userID})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetSessions(), nil
+}
+
+func (c *Client) GetJWKS(ctx context.Context) (*auth.JWKS, error) {
+	r, err := c.service.GetJWKS(ctx, &pb.GetJWKSRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	set := &auth.JWKS{}
+	for _, k := range r.GetKeys() {
+		set.Keys = append(set.Keys, auth.JWK{
+			Kty: k.GetKty(),
+			Kid: k.GetKid(),
+			Use: k.GetUse(),
+			Alg: k.GetAlg(),
+			N:   k.GetN(),
+			E:   k.GetE(),
+		})
+	}
+	return set, nil
+}
+
+func (c *Client) ListUsers(ctx context.Context, in *pb.ListUsersRequest) ([]*pb.User, error) {
+	r, err := c.service.ListUsers(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	return r.GetUsers(), nil
+}
+
+func (c *Client) SetUserSuspended(ctx context.Context, id string, suspended bool, reason string) (*pb.User, error) {
+	u, err := c.service.SetUserSuspended(ctx, &pb.SetUserSuspendedRequest{
+		Id:        id,
+		Suspended: suspended,
+		Reason:    reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func (c *Client) UnlockUser(ctx context.Context, id string) (*pb.User, error) {
+	u, err := c.service.UnlockUser(ctx, &pb.UnlockUserRequest{Id: id})
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func (c *Client) GetUser(ctx context.Context, id string) (*pb.User, error) {
+	u, err := c.service.GetUser(ctx, &pb.GetUserRequest{Id: id})
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func (c *Client) AddRole(ctx context.Context, userID string, role int32) (*pb.User, error) {
+	u, err := c.service.AddRole(ctx, &pb.AddRoleRequest{
+		UserId: userID,
+		Role:   role,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// SwitchRole returns the new token pair of the session, acting as the role.
+func (c *Client) SwitchRole(ctx context.Context, userID string, sessionID string, role int32) (*pb.LoginUserResponse, error) {
+	u, err := c.service.SwitchRole(ctx, &pb.SwitchRoleRequest{
+		UserId:    userID,
+		SessionId: sessionID,
+		Role:      role,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
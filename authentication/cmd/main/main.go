@@ -1,13 +1,23 @@
 package main
 
 import (
+	"context"
 	"log"
+	"time"
 
+	"github.com/231031/ecom-mcs-grpc/account"
 	"github.com/231031/ecom-mcs-grpc/authentication"
 	"github.com/231031/ecom-mcs-grpc/authentication/model"
 	"github.com/231031/ecom-mcs-grpc/authentication/repository"
 	"github.com/231031/ecom-mcs-grpc/authentication/service"
 	"github.com/231031/ecom-mcs-grpc/authentication/utils"
+	"github.com/231031/ecom-mcs-grpc/catalog"
+	"github.com/231031/ecom-mcs-grpc/notification"
+	"github.com/231031/ecom-mcs-grpc/order"
+	"github.com/231031/ecom-mcs-grpc/pkg/audit"
+	"github.com/231031/ecom-mcs-grpc/pkg/auth"
+	"github.com/231031/ecom-mcs-grpc/pkg/idempotency"
+	"github.com/231031/ecom-mcs-grpc/review"
 	"github.com/kelseyhightower/envconfig"
 )
 
@@ -17,6 +27,9 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	if err := cfg.Argon2.Validate(); err != nil {
+		log.Fatal(err)
+	}
 
 	db, err := authentication.ConnectPostgres(cfg.DatabaseURl)
 	if err != nil {
@@ -33,7 +46,78 @@ func main() {
 	log.Println("Listening on port")
 
 	tokenCfg := utils.ConfigGenerateKey(&cfg)
-	tokenService := service.NewTokenService(r, tokenCfg)
-	s := service.NewService(r, tokenService)
-	log.Fatal(authentication.ListenGRPC(s, 50004))
+	keyService := service.NewKeyService(r, tokenCfg, cfg.SecretKey, cfg.KeyRotationInterval)
+	if err := keyService.Load(context.Background()); err != nil {
+		log.Fatal(err)
+	}
+	// adds the first key when there is none yet
+	if err := keyService.Rotate(context.Background()); err != nil {
+		log.Fatal(err)
+	}
+	go keyService.Run(context.Background(), time.Minute)
+	go func() {
+		log.Fatal(authentication.ListenJWKS(keyService, cfg.JWKSPort))
+	}()
+
+	tokenService := service.NewTokenService(r, keyService, tokenCfg)
+	providers := []*service.OIDCProvider{}
+	if cfg.OIDC.Issuer != "" {
+		providers = append(providers, service.NewOIDCProvider(cfg.OIDC))
+	}
+	s := service.NewService(r, tokenService, cfg.SecretKey, cfg.TOTPIssuer, cfg.DeletionGracePeriod, providers...)
+	idempotencyStore := idempotency.NewRedisStore(redisClient)
+
+	auditStore, err := audit.NewPostgresStore(cfg.DatabaseURl)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer auditStore.Close()
+
+	if cfg.AdminEmail != "" {
+		if err := s.EnsureAdmin(context.Background(), cfg.AdminEmail, cfg.AdminPassword); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	authn, err := auth.NewAuthenticator("authentication", keyService, cfg.InternalAuthSecret)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	accountClient, err := account.NewClient(cfg.AccountURL, authn.DialOption())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer accountClient.Close()
+	catalogClient, err := catalog.NewClient(cfg.CatalogURL, authn.DialOption())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer catalogClient.Close()
+	orderClient, err := order.NewClient(cfg.OrderURL, authn.DialOption())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer orderClient.Close()
+	reviewClient, err := review.NewClient(cfg.ReviewURL, authn.DialOption())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer reviewClient.Close()
+	notificationClient, err := notification.NewClient(cfg.NotificationURL, authn.DialOption())
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer notificationClient.Close()
+
+	// the profile goes last, the other services may still look it up
+	go authentication.RunPurge(context.Background(), s, time.Hour,
+		authentication.Eraser{Service: "order", Erase: orderClient.EraseAccount},
+		authentication.Eraser{Service: "review", Erase: reviewClient.EraseAccount},
+		authentication.Eraser{Service: "catalog", Erase: catalogClient.EraseAccount},
+		authentication.Eraser{Service: "notification", Erase: notificationClient.EraseAccount},
+		authentication.Eraser{Service: "account", Erase: accountClient.DeleteAccount},
+	)
+
+	log.Fatal(authentication.ListenGRPC(s, keyService, authn, idempotencyStore, auditStore, cfg.NotificationURL, cfg.AppURL, 50004))
 }
@@ -0,0 +1,24 @@
+package authentication
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/231031/ecom-mcs-grpc/authentication/service"
+)
+
+// ListenJWKS serves the public signing keys at /.well-known/jwks.json for the
+// services that verify access tokens.
+func ListenJWKS(keys service.KeyService, port int) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/jwks.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		if err := json.NewEncoder(w).Encode(keys.JWKS()); err != nil {
+			log.Println("error writing jwks", err)
+		}
+	})
+	return http.ListenAndServe(fmt.Sprintf(":%d", port), mux)
+}
@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type Config struct {
 	DatabaseURl   string `envconfig:"DATABASE_URL"`
 	RedisAddr     string `envconfig:"REDIS_ADDR"`
@@ -7,4 +9,47 @@ type Config struct {
 	SecretKey     string `envconfig:"SECRET_KEY"`
 	FilePriPath   string `envconfig:"FILE_PRI_PATH"`
 	FilePubPath   string `envconfig:"FILE_PUB_PATH"`
+
+	// signing keys are rotated on this interval, the key of FILE_PRI_PATH is
+	// only used as the first one
+	KeyRotationInterval time.Duration `envconfig:"KEY_ROTATION_INTERVAL" default:"720h"`
+	JWKSPort            int           `envconfig:"JWKS_PORT" default:"8081"`
+
+	InternalAuthSecret string `envconfig:"INTERNAL_AUTH_SECRET"`
+
+	// the cost of the password hashes
+	Argon2 Argon2Params `envconfig:"ARGON2"`
+
+	NotificationURL string `envconfig:"NOTIFICATION_SERVICE_URL"`
+	// the links of the verification and password reset mails point there
+	AppURL string `envconfig:"APP_URL"`
+	// the profile of a deleted user is erased there too, and what these
+	// services keep of them
+	AccountURL string `envconfig:"ACCOUNT_SERVICE_URL"`
+	CatalogURL string `envconfig:"CATALOG_SERVICE_URL"`
+	OrderURL   string `envconfig:"ORDER_SERVICE_URL"`
+	ReviewURL  string `envconfig:"REVIEW_SERVICE_URL"`
+	// how long a deleted account can still be restored by logging in
+	DeletionGracePeriod time.Duration `envconfig:"DELETION_GRACE_PERIOD" default:"720h"`
+	// the issuer authenticator apps list the TOTP codes under
+	TOTPIssuer string `envconfig:"TOTP_ISSUER" default:"ecom"`
+
+	// the OIDC provider users can log in with, none when the issuer is empty
+	OIDC OIDCProviderConfig `envconfig:"OIDC"`
+
+	// the admin account created on start when it does not exist yet
+	AdminEmail    string `envconfig:"ADMIN_EMAIL"`
+	AdminPassword string `envconfig:"ADMIN_PASSWORD"`
+}
+
+// OIDCProviderConfig is any OpenID Connect provider, its endpoints are
+// discovered from the issuer. The redirect URL is the callback of the
+// gateway, registered at the provider.
+type OIDCProviderConfig struct {
+	Name         string   `envconfig:"NAME"`
+	Issuer       string   `envconfig:"ISSUER"`
+	ClientID     string   `envconfig:"CLIENT_ID"`
+	ClientSecret string   `envconfig:"CLIENT_SECRET"`
+	RedirectURL  string   `envconfig:"REDIRECT_URL"`
+	Scopes       []string `envconfig:"SCOPES" default:"openid,email,profile"`
 }
@@ -5,11 +5,14 @@ type RoleType int
 const (
 	BUYER RoleType = iota
 	SELLER
+	// ADMIN runs the back office, it cannot be chosen on sign up
+	ADMIN
 )
 
 var RoleTypeLabel = map[RoleType]string{
 	BUYER:  "buyer",
 	SELLER: "seller",
+	ADMIN:  "admin",
 }
 
 func (c RoleType) String() string {
@@ -2,30 +2,125 @@ package model
 
 import (
 	"crypto/rsa"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt"
 )
 
 type User struct {
-	ID        string    `json:"id" gorm:"primaryKey"`
-	Email     string    `json:"email" gorm:"uniqueIndex"`
-	Password  string    `json:"password"`
-	Role      int32     `json:"role"`
+	ID       string `json:"id" gorm:"primaryKey"`
+	Email    string `json:"email" gorm:"uniqueIndex"`
+	Password string `json:"password"`
+	// Role is the role of sign up, a login starts acting as it
+	Role int32 `json:"role"`
+	// Roles are all the roles the user holds, Role included
+	Roles []UserRole `json:"roles" gorm:"foreignKey:UserID"`
+	// users cannot log in before they verified their email
+	EmailVerified bool `json:"email_verified"`
+	// Suspended users cannot log in or refresh their tokens
+	Suspended       bool   `json:"suspended"`
+	SuspendedReason string `json:"suspended_reason"`
+	// the TOTP secret, sealed with the secret key of the service. It is set on
+	// enrollment and only asked for on login once TOTPEnabled
+	TOTPSecret  string `json:"-" gorm:"column:totp_secret"`
+	TOTPEnabled bool   `json:"totp_enabled" gorm:"column:totp_enabled"`
+	// set when the user asked for their account to be deleted, it is erased
+	// once the grace period passed unless they log in again before
+	DeletionRequestedAt *time.Time `json:"deletion_requested_at"`
+	CreatedAt           time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt           time.Time  `gorm:"autoUpdateTime"`
+}
+
+// RoleList returns the roles the user holds, the role of sign up when they
+// were not loaded.
+func (u *User) RoleList() []int32 {
+	if len(u.Roles) == 0 {
+		return []int32{u.Role}
+	}
+	roles := []int32{}
+	for _, r := range u.Roles {
+		roles = append(roles, r.Role)
+	}
+	return roles
+}
+
+func (u *User) HasRole(role int32) bool {
+	for _, r := range u.RoleList() {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// UserRole is a role the user holds, a user can buy and sell with one login.
+type UserRole struct {
+	UserID    string    `gorm:"primaryKey"`
+	Role      int32     `gorm:"primaryKey;autoIncrement:false"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// RecoveryCode stands in for a TOTP code once, when the authenticator is
+// lost. Only the hash of the code is kept.
+type RecoveryCode struct {
+	UserID    string    `gorm:"primaryKey"`
+	CodeHash  string    `gorm:"primaryKey"`
 	CreatedAt time.Time `gorm:"autoCreateTime"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime"`
 }
 
+// ExternalIdentity links the account of a user at an OIDC provider, the
+// subject of its ID tokens, to the user.
+type ExternalIdentity struct {
+	Provider  string `gorm:"primaryKey"`
+	Subject   string `gorm:"primaryKey"`
+	UserID    string
+	Email     string
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// OIDCState is what an OIDC login started with, kept until the provider
+// redirects back with the code.
+type OIDCState struct {
+	Provider string
+	// the PKCE code verifier, the provider got its challenge
+	Verifier string
+	Nonce    string
+}
+
+// TOTPEnrollment is what the user adds to their authenticator app, the
+// recovery codes are only shown this once.
+type TOTPEnrollment struct {
+	Secret        string
+	URI           string
+	RecoveryCodes []string
+}
+
+// UserFilter narrows the users listed in the back office.
+type UserFilter struct {
+	Query         string
+	Roles         []int32
+	SuspendedOnly bool
+}
+
+// UserAuth is the user of an access token, acting as Role out of the Roles
+// they hold.
 type UserAuth struct {
-	ID    string `json:"id" gorm:"primaryKey"`
-	Email string `json:"email" gorm:"uniqueIndex"`
-	Role  int32  `json:"role"`
+	ID    string  `json:"id" gorm:"primaryKey"`
+	Email string  `json:"email" gorm:"uniqueIndex"`
+	Role  int32   `json:"role"`
+	Roles []int32 `json:"roles" gorm:"-"`
 }
 
 type UserInfo struct {
 	Email     string        `json:"email"`
 	Role      int32         `json:"role"`
+	Roles     []int32       `json:"roles"`
 	TokenPair TokenResponse `json:"token_pair"`
+	// MFAToken is set instead of the pair when the user has 2FA, it is
+	// exchanged for the pair with a TOTP or recovery code
+	MFAToken string `json:"mfa_token"`
 }
 type TokenResponse struct {
 	AccessToken  string `json:"access_token"`
@@ -35,30 +130,115 @@ type TokenResponse struct {
 type Argon2Configuration struct {
 	HashRaw    []byte
 	Salt       []byte
+	Version    int
 	TimeCost   uint32
 	MemoryCost uint32
 	Threads    uint8
 	KeyLength  uint32
 }
 
+// Argon2Params is the cost new password hashes are made with, a stored hash
+// of a lower cost is made again on the next login. MemoryCost is in KiB.
+type Argon2Params struct {
+	TimeCost   uint32 `envconfig:"TIME_COST" default:"2"`
+	MemoryCost uint32 `envconfig:"MEMORY_COST" default:"65536"`
+	Threads    uint8  `envconfig:"THREADS" default:"4"`
+	KeyLength  uint32 `envconfig:"KEY_LENGTH" default:"32"`
+}
+
+var ErrWeakArgon2Params = errors.New("argon2 cost below the minimum")
+
+// the lowest cost accepted, the minimum argon2id configuration OWASP lists
+const (
+	minArgon2TimeCost   = 2
+	minArgon2MemoryCost = 19 * 1024
+	minArgon2Threads    = 1
+	minArgon2KeyLength  = 16
+)
+
+// Validate refuses a cost too low to protect the passwords, and zero threads
+// argon2 cannot run with.
+func (p Argon2Params) Validate() error {
+	switch {
+	case p.TimeCost < minArgon2TimeCost:
+		return fmt.Errorf("%w: ARGON2_TIME_COST must be at least %d", ErrWeakArgon2Params, minArgon2TimeCost)
+	case p.MemoryCost < minArgon2MemoryCost:
+		return fmt.Errorf("%w: ARGON2_MEMORY_COST must be at least %d", ErrWeakArgon2Params, minArgon2MemoryCost)
+	case p.Threads < minArgon2Threads:
+		return fmt.Errorf("%w: ARGON2_THREADS must be at least %d", ErrWeakArgon2Params, minArgon2Threads)
+	case p.KeyLength < minArgon2KeyLength:
+		return fmt.Errorf("%w: ARGON2_KEY_LENGTH must be at least %d", ErrWeakArgon2Params, minArgon2KeyLength)
+	}
+	return nil
+}
+
+// TokenConfig holds the key of FILE_PRI_PATH, only used as the first
+// signing key, and the lifetimes of the tokens.
 type TokenConfig struct {
 	PrivateKey            *rsa.PrivateKey
 	PublicKey             *rsa.PublicKey
 	RefreshSecret         string
 	TokenIDExpirationSecs int64
 	RefreshExpirationSecs int64
+	PasswordHash          Argon2Params
 }
 
 type TokenClaims struct {
-	User UserAuth `json:"user"`
+	User      UserAuth `json:"user"`
+	SessionID string   `json:"sid"`
 	jwt.StandardClaims
 }
 
 type RefreshTokenClaims struct {
-	ID string `json:"id"`
+	ID        string `json:"id"`
+	SessionID string `json:"sid"`
 	jwt.StandardClaims
 }
 
+// Session is a login and the family of refresh tokens rotated from it. Only
+// the latest token of the family is valid, TokenID is its id.
+type Session struct {
+	ID         string
+	UserID     string
+	Device     string
+	IP         string
+	TokenID    string
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+	// the role the session acts as, -1 for sessions opened before it was
+	// kept
+	Role int32
+	// the latest access token of the session, the session stays denied
+	// until it expires
+	AccessTokenID        string
+	AccessTokenExpiresAt time.Time
+}
+
+// ClientInfo is the device and address a login or refresh comes from.
+type ClientInfo struct {
+	Device string
+	IP     string
+}
+
+// SigningKey is an RSA key access tokens are signed with, the ID is the kid
+// of the tokens. A key is published from its creation, signs from ActiveFrom
+// until the next key takes over, and is published until RetireAt so the
+// tokens it signed last can still be verified.
+type SigningKey struct {
+	ID string `gorm:"primaryKey"`
+	// the PEM of the private key, sealed with the secret key of the service
+	PrivateKey string
+	ActiveFrom time.Time
+	RetireAt   *time.Time
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+}
+
+type AccessTokenData struct {
+	SS        string
+	ID        string
+	ExpiresAt time.Time
+}
+
 type RefreshTokenData struct {
 	SS        string
 	ID        string
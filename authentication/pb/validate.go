@@ -0,0 +1,239 @@
+package pb
+
+import "github.com/231031/ecom-mcs-grpc/pkg/validate"
+
+// Validation rules of the authentication requests, enforced by the service
+// and the gateway through the validate interceptors.
+
+const (
+	maxEmailLen    = 254
+	minPasswordLen = 8
+	maxPasswordLen = 128
+
+	// the roles a user can sign up with, buyer and seller, admins are
+	// created on start
+	maxRole = 1
+	// every role of the authentication model, admin included
+	maxAnyRole = 2
+
+	maxQueryLen  = 254
+	maxReasonLen = 500
+
+	// the user agent and address a session is listed with
+	maxDeviceLen = 256
+	maxIPLen     = 64
+
+	// the tokens of the verification and reset links, and of the MFA
+	// challenges
+	maxTokenLen = 64
+	// a TOTP code or a recovery code
+	maxCodeLen = 16
+
+	// the name of an OIDC provider, and the code and state of its callback
+	maxProviderLen  = 64
+	maxAuthCodeLen  = 2048
+	maxOIDCStateLen = 64
+)
+
+func (m *CreateUserRequest) Validate() error {
+	return validate.Check(
+		validate.Required("email", m.GetEmail()),
+		validate.MaxLen("email", m.GetEmail(), maxEmailLen),
+		validate.Email("email", m.GetEmail()),
+		validate.MinLen("password", m.GetPassword(), minPasswordLen),
+		validate.MaxLen("password", m.GetPassword(), maxPasswordLen),
+		validate.Between("role", m.GetRole(), 0, maxRole),
+	)
+}
+
+// Validate only requires the credentials, the password rules are not given
+// away on login.
+func (m *LoginUserRequest) Validate() error {
+	return validate.Check(
+		validate.Required("email", m.GetEmail()),
+		validate.Required("password", m.GetPassword()),
+		validate.MaxLen("device", m.GetDevice(), maxDeviceLen),
+		validate.MaxLen("ip", m.GetIp(), maxIPLen),
+	)
+}
+
+func (m *RefreshTokenRequest) Validate() error {
+	return validate.Check(
+		validate.Required("refresh_token", m.GetRefreshToken()),
+		validate.MaxLen("device", m.GetDevice(), maxDeviceLen),
+		validate.MaxLen("ip", m.GetIp(), maxIPLen),
+	)
+}
+
+func (m *VerifyMFARequest) Validate() error {
+	return validate.Check(
+		validate.Required("mfa_token", m.GetMfaToken()),
+		validate.MaxLen("mfa_token", m.GetMfaToken(), maxTokenLen),
+		validate.Required("code", m.GetCode()),
+		validate.MaxLen("code", m.GetCode(), maxCodeLen),
+		validate.MaxLen("device", m.GetDevice(), maxDeviceLen),
+		validate.MaxLen("ip", m.GetIp(), maxIPLen),
+	)
+}
+
+func (m *StartOIDCLoginRequest) Validate() error {
+	return validate.Check(
+		validate.Required("provider", m.GetProvider()),
+		validate.MaxLen("provider", m.GetProvider(), maxProviderLen),
+	)
+}
+
+func (m *CompleteOIDCLoginRequest) Validate() error {
+	return validate.Check(
+		validate.Required("provider", m.GetProvider()),
+		validate.MaxLen("provider", m.GetProvider(), maxProviderLen),
+		validate.Required("code", m.GetCode()),
+		validate.MaxLen("code", m.GetCode(), maxAuthCodeLen),
+		validate.Required("state", m.GetState()),
+		validate.MaxLen("state", m.GetState(), maxOIDCStateLen),
+		validate.MaxLen("device", m.GetDevice(), maxDeviceLen),
+		validate.MaxLen("ip", m.GetIp(), maxIPLen),
+	)
+}
+
+func (m *EnrollTOTPRequest) Validate() error {
+	return validate.Check(
+		validate.Required("user_id", m.GetUserId()),
+	)
+}
+
+func (m *EnableTOTPRequest) Validate() error {
+	return validate.Check(
+		validate.Required("user_id", m.GetUserId()),
+		validate.Required("code", m.GetCode()),
+		validate.MaxLen("code", m.GetCode(), maxCodeLen),
+	)
+}
+
+func (m *DisableTOTPRequest) Validate() error {
+	return validate.Check(
+		validate.Required("user_id", m.GetUserId()),
+		validate.Required("code", m.GetCode()),
+		validate.MaxLen("code", m.GetCode(), maxCodeLen),
+	)
+}
+
+func (m *VerifyEmailRequest) Validate() error {
+	return validate.Check(
+		validate.Required("token", m.GetToken()),
+		validate.MaxLen("token", m.GetToken(), maxTokenLen),
+	)
+}
+
+func (m *RequestEmailVerificationRequest) Validate() error {
+	return validate.Check(
+		validate.Required("email", m.GetEmail()),
+		validate.MaxLen("email", m.GetEmail(), maxEmailLen),
+	)
+}
+
+func (m *RequestPasswordResetRequest) Validate() error {
+	return validate.Check(
+		validate.Required("email", m.GetEmail()),
+		validate.MaxLen("email", m.GetEmail(), maxEmailLen),
+	)
+}
+
+func (m *ResetPasswordRequest) Validate() error {
+	return validate.Check(
+		validate.Required("token", m.GetToken()),
+		validate.MaxLen("token", m.GetToken(), maxTokenLen),
+		validate.MinLen("password", m.GetPassword(), minPasswordLen),
+		validate.MaxLen("password", m.GetPassword(), maxPasswordLen),
+	)
+}
+
+func (m *ChangePasswordRequest) Validate() error {
+	return validate.Check(
+		validate.Required("user_id", m.GetUserId()),
+		validate.Required("current_password", m.GetCurrentPassword()),
+		validate.MaxLen("current_password", m.GetCurrentPassword(), maxPasswordLen),
+		validate.MinLen("new_password", m.GetNewPassword(), minPasswordLen),
+		validate.MaxLen("new_password", m.GetNewPassword(), maxPasswordLen),
+	)
+}
+
+func (m *ChangeEmailRequest) Validate() error {
+	return validate.Check(
+		validate.Required("user_id", m.GetUserId()),
+		validate.Required("password", m.GetPassword()),
+		validate.MaxLen("password", m.GetPassword(), maxPasswordLen),
+		validate.Required("email", m.GetEmail()),
+		validate.MaxLen("email", m.GetEmail(), maxEmailLen),
+		validate.Email("email", m.GetEmail()),
+	)
+}
+
+func (m *DeleteUserRequest) Validate() error {
+	return validate.Check(
+		validate.Required("user_id", m.GetUserId()),
+		validate.Required("password", m.GetPassword()),
+		validate.MaxLen("password", m.GetPassword(), maxPasswordLen),
+	)
+}
+
+func (m *LogoutRequest) Validate() error {
+	return validate.Check(
+		validate.Required("user_id", m.GetUserId()),
+		validate.Required("session_id", m.GetSessionId()),
+	)
+}
+
+func (m *LogoutAllSessionsRequest) Validate() error {
+	return validate.Check(
+		validate.Required("user_id", m.GetUserId()),
+	)
+}
+
+func (m *ListSessionsRequest) Validate() error {
+	return validate.Check(
+		validate.Required("user_id", m.GetUserId()),
+	)
+}
+
+func (m *ListUsersRequest) Validate() error {
+	return validate.Check(
+		validate.MaxLen("query", m.GetQuery(), maxQueryLen),
+		validate.MaxItems("roles", len(m.GetRoles()), maxAnyRole+1),
+		validate.EachBetween("roles", m.GetRoles(), 0, maxAnyRole),
+	)
+}
+
+func (m *SetUserSuspendedRequest) Validate() error {
+	return validate.Check(
+		validate.Required("id", m.GetId()),
+		validate.MaxLen("reason", m.GetReason(), maxReasonLen),
+	)
+}
+
+func (m *UnlockUserRequest) Validate() error {
+	return validate.Check(
+		validate.Required("id", m.GetId()),
+	)
+}
+
+func (m *GetUserRequest) Validate() error {
+	return validate.Check(
+		validate.Required("id", m.GetId()),
+	)
+}
+
+func (m *AddRoleRequest) Validate() error {
+	return validate.Check(
+		validate.Required("user_id", m.GetUserId()),
+		validate.Between("role", m.GetRole(), 0, maxRole),
+	)
+}
+
+func (m *SwitchRoleRequest) Validate() error {
+	return validate.Check(
+		validate.Required("user_id", m.GetUserId()),
+		validate.Required("session_id", m.GetSessionId()),
+		validate.Between("role", m.GetRole(), 0, maxAnyRole),
+	)
+}
@@ -0,0 +1,68 @@
+package authentication
+
+import (
+	"github.com/231031/ecom-mcs-grpc/authentication/pb"
+	"github.com/231031/ecom-mcs-grpc/pkg/auth"
+)
+
+// policies says who may call each method of the service.
+var policies = auth.Policies{
+	pb.AuthenticationService_CreateUser_FullMethodName:       auth.Public(),
+	pb.AuthenticationService_LoginUser_FullMethodName:        auth.Public(),
+	pb.AuthenticationService_RefreshTokenUser_FullMethodName: auth.Public(),
+	pb.AuthenticationService_GetJWKS_FullMethodName:          auth.Public(),
+	pb.AuthenticationService_VerifyMFA_FullMethodName:        auth.Public(),
+
+	pb.AuthenticationService_StartOIDCLogin_FullMethodName:    auth.Public(),
+	pb.AuthenticationService_CompleteOIDCLogin_FullMethodName: auth.Public(),
+
+	pb.AuthenticationService_VerifyEmail_FullMethodName:              auth.Public(),
+	pb.AuthenticationService_RequestEmailVerification_FullMethodName: auth.Public(),
+	pb.AuthenticationService_RequestPasswordReset_FullMethodName:     auth.Public(),
+	pb.AuthenticationService_ResetPassword_FullMethodName:            auth.Public(),
+
+	pb.AuthenticationService_Logout_FullMethodName: auth.OwnedBy(func(r *pb.LogoutRequest) string {
+		return r.GetUserId()
+	}, auth.RoleBuyer, auth.RoleSeller, auth.RoleAdmin),
+	pb.AuthenticationService_LogoutAllSessions_FullMethodName: auth.OwnedBy(func(r *pb.LogoutAllSessionsRequest) string {
+		return r.GetUserId()
+	}, auth.RoleBuyer, auth.RoleSeller, auth.RoleAdmin),
+	pb.AuthenticationService_ListSessions_FullMethodName: auth.OwnedBy(func(r *pb.ListSessionsRequest) string {
+		return r.GetUserId()
+	}, auth.RoleBuyer, auth.RoleSeller, auth.RoleAdmin),
+	pb.AuthenticationService_EnrollTOTP_FullMethodName: auth.OwnedBy(func(r *pb.EnrollTOTPRequest) string {
+		return r.GetUserId()
+	}, auth.RoleBuyer, auth.RoleSeller, auth.RoleAdmin),
+	pb.AuthenticationService_EnableTOTP_FullMethodName: auth.OwnedBy(func(r *pb.EnableTOTPRequest) string {
+		return r.GetUserId()
+	}, auth.RoleBuyer, auth.RoleSeller, auth.RoleAdmin),
+	pb.AuthenticationService_DisableTOTP_FullMethodName: auth.OwnedBy(func(r *pb.DisableTOTPRequest) string {
+		return r.GetUserId()
+	}, auth.RoleBuyer, auth.RoleSeller, auth.RoleAdmin),
+	pb.AuthenticationService_ChangePassword_FullMethodName: auth.OwnedBy(func(r *pb.ChangePasswordRequest) string {
+		return r.GetUserId()
+	}, auth.RoleBuyer, auth.RoleSeller, auth.RoleAdmin),
+	pb.AuthenticationService_ChangeEmail_FullMethodName: auth.OwnedBy(func(r *pb.ChangeEmailRequest) string {
+		return r.GetUserId()
+	}, auth.RoleBuyer, auth.RoleSeller, auth.RoleAdmin),
+	pb.AuthenticationService_DeleteUser_FullMethodName: auth.OwnedBy(func(r *pb.DeleteUserRequest) string {
+		return r.GetUserId()
+	}, auth.RoleBuyer, auth.RoleSeller, auth.RoleAdmin),
+
+	pb.AuthenticationService_GetUser_FullMethodName: auth.AnyOf(
+		auth.OwnedBy(func(r *pb.GetUserRequest) string {
+			return r.GetId()
+		}, auth.RoleBuyer, auth.RoleSeller, auth.RoleAdmin),
+		auth.Roles(auth.RoleAdmin),
+	),
+	pb.AuthenticationService_AddRole_FullMethodName: auth.OwnedBy(func(r *pb.AddRoleRequest) string {
+		return r.GetUserId()
+	}, auth.RoleBuyer, auth.RoleSeller),
+	pb.AuthenticationService_SwitchRole_FullMethodName: auth.OwnedBy(func(r *pb.SwitchRoleRequest) string {
+		return r.GetUserId()
+	}, auth.RoleBuyer, auth.RoleSeller, auth.RoleAdmin),
+
+	pb.AuthenticationService_ListUsers_FullMethodName:        auth.Roles(auth.RoleAdmin),
+	pb.AuthenticationService_SetUserSuspended_FullMethodName: auth.Roles(auth.RoleAdmin),
+	pb.AuthenticationService_UnlockUser_FullMethodName:       auth.Roles(auth.RoleAdmin),
+}
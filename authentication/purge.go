@@ -0,0 +1,76 @@
+package authentication
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/231031/ecom-mcs-grpc/authentication/service"
+)
+
+// how many accounts one run of the purge erases
+const purgeBatch = 100
+
+// Eraser erases what a service keeps of a user, erasing twice is harmless.
+type Eraser struct {
+	Service string
+	Erase   func(ctx context.Context, userID string) error
+}
+
+// RunPurge erases the accounts whose deletion grace period is over, on every
+// tick until ctx is done. Every service erases what it keeps of the user
+// first, the user is only deleted once all of them did and is tried again on
+// the next tick otherwise.
+func RunPurge(ctx context.Context, s service.Service, every time.Duration, erasers ...Eraser) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purge(ctx, s, erasers)
+		}
+	}
+}
+
+func purge(ctx context.Context, s service.Service, erasers []Eraser) {
+	users, err := s.DueForDeletion(ctx, purgeBatch)
+	if err != nil {
+		log.Println("error listing accounts due for deletion", err)
+		return
+	}
+
+	for _, u := range users {
+		// the user may have logged in since the list was taken
+		due, err := s.IsDueForDeletion(ctx, u.ID)
+		if err != nil {
+			log.Println("error reading user due for deletion", u.ID, err)
+			continue
+		}
+		if !due || !eraseEverywhere(ctx, u.ID, erasers) {
+			continue
+		}
+		deleted, err := s.PurgeUser(ctx, u.ID)
+		if err != nil {
+			log.Println("error deleting user", u.ID, err)
+			continue
+		}
+		if !deleted {
+			log.Println("deletion of user", u.ID, "cancelled during the purge")
+			continue
+		}
+		log.Println("deleted user", u.ID)
+	}
+}
+
+func eraseEverywhere(ctx context.Context, userID string, erasers []Eraser) bool {
+	for _, e := range erasers {
+		if err := e.Erase(ctx, userID); err != nil {
+			log.Println("error erasing user", userID, "in the", e.Service, "service", err)
+			return false
+		}
+	}
+	return true
+}
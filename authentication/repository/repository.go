@@ -2,19 +2,60 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/231031/ecom-mcs-grpc/authentication/model"
 	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrEmailTaken      = errors.New("email is already registered")
+	ErrUserNotFound    = errors.New("user not found")
+	ErrSessionNotFound = errors.New("session not found")
 )
 
 type Repository interface {
 	Close() error
 	CreateUser(ctx context.Context, u *model.User) error
 	GetUserByEmail(ctx context.Context, email string) (*model.User, error)
+	GetUserByID(ctx context.Context, id string) (*model.User, error)
+	ListUsers(ctx context.Context, filter model.UserFilter, skip uint64, take uint64) ([]model.User, error)
+	SetUserSuspended(ctx context.Context, id string, suspended bool, reason string) (*model.User, error)
+	AddUserRole(ctx context.Context, id string, role int32) (*model.User, error)
+	SetEmailVerified(ctx context.Context, id string) (*model.User, error)
+	UpdatePassword(ctx context.Context, id string, password string) error
+	UpdateEmail(ctx context.Context, id string, email string) (*model.User, error)
+	SetDeletionRequested(ctx context.Context, id string, at *time.Time) error
+	ListUsersDueForDeletion(ctx context.Context, before time.Time, limit int) ([]model.User, error)
+	DeleteUser(ctx context.Context, id string, requestedBefore time.Time) (bool, error)
+	StoreOneTimeToken(ctx context.Context, key string, userID string, exp time.Duration) error
+	TakeOneTimeToken(ctx context.Context, key string) (string, error)
+	GetOneTimeToken(ctx context.Context, key string) (string, error)
+	SetTOTP(ctx context.Context, id string, secret string, enabled bool) error
+	ReplaceRecoveryCodes(ctx context.Context, userID string, hashes []string) error
+	UseRecoveryCode(ctx context.Context, userID string, hash string) (bool, error)
+	MarkTOTPUsed(ctx context.Context, userID string, step uint64, exp time.Duration) (bool, error)
+	GetExternalIdentity(ctx context.Context, provider string, subject string) (*model.ExternalIdentity, error)
+	CreateExternalIdentity(ctx context.Context, identity *model.ExternalIdentity) error
+	StoreOIDCState(ctx context.Context, key string, state *model.OIDCState, exp time.Duration) error
+	TakeOIDCState(ctx context.Context, key string) (*model.OIDCState, error)
 	StoreRefreshToken(ctx context.Context, key string, value string, exp time.Duration) error
 	GetAndDelRefreshToken(ctx context.Context, key string) (string, error)
+	SaveSession(ctx context.Context, session *model.Session, exp time.Duration) error
+	GetSession(ctx context.Context, id string) (*model.Session, error)
+	ListSessions(ctx context.Context, userID string) ([]model.Session, error)
+	DeleteSessions(ctx context.Context, userID string, ids ...string) error
+	LoginLockTTL(ctx context.Context, subject string) (time.Duration, error)
+	RecordLoginFailure(ctx context.Context, subject string, window time.Duration) (int64, error)
+	LockLogin(ctx context.Context, subject string, d time.Duration) error
+	ClearLoginFailures(ctx context.Context, subject string) error
+	ListSigningKeys(ctx context.Context) ([]model.SigningKey, error)
+	RotateSigningKey(ctx context.Context, key *model.SigningKey, previousID string, retireAt time.Time) (bool, error)
 }
 
 type repository struct {
@@ -41,6 +82,9 @@ func (r *repository) Close() error {
 
 func (r *repository) CreateUser(ctx context.Context, u *model.User) error {
 	if err := r.db.WithContext(ctx).Create(u).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return ErrEmailTaken
+		}
 		return err
 	}
 	return nil
@@ -48,7 +92,7 @@ func (r *repository) CreateUser(ctx context.Context, u *model.User) error {
 
 func (r *repository) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
 	u := &model.User{}
-	if err := r.db.WithContext(ctx).Where("email = ?", email).First(u).Error; err != nil {
+	if err := r.db.WithContext(ctx).Preload("Roles").Where("email = ?", email).First(u).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, nil
 		}
@@ -57,6 +101,401 @@ func (r *repository) GetUserByEmail(ctx context.Context, email string) (*model.U
 	return u, nil
 }
 
+func (r *repository) GetUserByID(ctx context.Context, id string) (*model.User, error) {
+	u := &model.User{}
+	if err := r.db.WithContext(ctx).Preload("Roles").Where("id = ?", id).First(u).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return u, nil
+}
+
+func (r *repository) ListUsers(ctx context.Context, filter model.UserFilter, skip uint64, take uint64) ([]model.User, error) {
+	query := r.db.WithContext(ctx).Model(&model.User{}).Preload("Roles")
+	if filter.Query != "" {
+		query = query.Where("email ILIKE ?", "%"+filter.Query+"%")
+	}
+	if len(filter.Roles) > 0 {
+		query = query.Where("id IN (SELECT user_id FROM user_roles WHERE role IN ?)", filter.Roles)
+	}
+	if filter.SuspendedOnly {
+		query = query.Where("suspended")
+	}
+
+	users := []model.User{}
+	err := query.Order("created_at DESC").Offset(int(skip)).Limit(int(take)).Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *repository) SetUserSuspended(ctx context.Context, id string, suspended bool, reason string) (*model.User, error) {
+	if !suspended {
+		reason = ""
+	}
+
+	res := r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"suspended":        suspended,
+		"suspended_reason": reason,
+	})
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, ErrUserNotFound
+	}
+	return r.GetUserByID(ctx, id)
+}
+
+// AddUserRole lets the user hold the role too, adding a role they hold
+// already changes nothing.
+func (r *repository) AddUserRole(ctx context.Context, id string, role int32) (*model.User, error) {
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.UserRole{UserID: id, Role: role}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := r.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, ErrUserNotFound
+	}
+	return u, nil
+}
+
+func (r *repository) SetEmailVerified(ctx context.Context, id string) (*model.User, error) {
+	res := r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", id).Update("email_verified", true)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, ErrUserNotFound
+	}
+	return r.GetUserByID(ctx, id)
+}
+
+func (r *repository) UpdatePassword(ctx context.Context, id string, password string) error {
+	res := r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", id).Update("password", password)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// UpdateEmail changes the email of the user, it was verified by the link
+// sent to it. The unique email decides when another user took it since the
+// link was sent. The linked identities follow the new email.
+func (r *repository) UpdateEmail(ctx context.Context, id string, email string) (*model.User, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"email":          email,
+			"email_verified": true,
+		})
+		if res.Error != nil {
+			if errors.Is(res.Error, gorm.ErrDuplicatedKey) {
+				return ErrEmailTaken
+			}
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrUserNotFound
+		}
+
+		return tx.Model(&model.ExternalIdentity{}).Where("user_id = ?", id).Update("email", email).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.GetUserByID(ctx, id)
+}
+
+// SetDeletionRequested marks the user for deletion at the time, nil cancels
+// the request.
+func (r *repository) SetDeletionRequested(ctx context.Context, id string, at *time.Time) error {
+	res := r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", id).Update("deletion_requested_at", at)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// ListUsersDueForDeletion returns the users who asked to be deleted before
+// the time, the oldest requests first.
+func (r *repository) ListUsersDueForDeletion(ctx context.Context, before time.Time, limit int) ([]model.User, error) {
+	users := []model.User{}
+	err := r.db.WithContext(ctx).
+		Where("deletion_requested_at IS NOT NULL AND deletion_requested_at <= ?", before).
+		Order("deletion_requested_at").
+		Limit(limit).
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// DeleteUser erases the user if they still asked to be deleted before the
+// time, the recovery codes and linked identities go with it. It returns
+// whether the user was erased.
+func (r *repository) DeleteUser(ctx context.Context, id string, requestedBefore time.Time) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Where("id = ? AND deletion_requested_at IS NOT NULL AND deletion_requested_at <= ?", id, requestedBefore).
+		Delete(&model.User{})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// StoreOneTimeToken keeps the user a verification or reset token was issued
+// for, under a key made of the hash of the token.
+func (r *repository) StoreOneTimeToken(ctx context.Context, key string, userID string, exp time.Duration) error {
+	return r.redisClient.Set(ctx, key, userID, exp).Err()
+}
+
+// TakeOneTimeToken returns the user of the token and deletes it, an empty id
+// when the token is unknown, used or expired.
+func (r *repository) TakeOneTimeToken(ctx context.Context, key string) (string, error) {
+	userID, err := r.redisClient.GetDel(ctx, key).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return userID, nil
+}
+
+// GetOneTimeToken returns the user of the token without using it up.
+func (r *repository) GetOneTimeToken(ctx context.Context, key string) (string, error) {
+	userID, err := r.redisClient.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return userID, nil
+}
+
+// SetTOTP stores the sealed TOTP secret of the user, an empty secret turns
+// 2FA off.
+func (r *repository) SetTOTP(ctx context.Context, id string, secret string, enabled bool) error {
+	res := r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"totp_secret":  secret,
+		"totp_enabled": enabled,
+	})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// ReplaceRecoveryCodes drops the recovery codes of the user for the new
+// ones, none removes them all.
+func (r *repository) ReplaceRecoveryCodes(ctx context.Context, userID string, hashes []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(hashes) == 0 {
+			return nil
+		}
+
+		codes := []model.RecoveryCode{}
+		for _, hash := range hashes {
+			codes = append(codes, model.RecoveryCode{UserID: userID, CodeHash: hash})
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// UseRecoveryCode deletes the code and tells whether it was there, a code
+// works once.
+func (r *repository) UseRecoveryCode(ctx context.Context, userID string, hash string) (bool, error) {
+	res := r.db.WithContext(ctx).Where("user_id = ? AND code_hash = ?", userID, hash).Delete(&model.RecoveryCode{})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// MarkTOTPUsed records the step of a TOTP code the user logged in with and
+// returns false when it was already used, a code seen once is not replayed.
+func (r *repository) MarkTOTPUsed(ctx context.Context, userID string, step uint64, exp time.Duration) (bool, error) {
+	key := fmt.Sprintf("totp_used:%s:%d", userID, step)
+	return r.redisClient.SetNX(ctx, key, 1, exp).Result()
+}
+
+// GetExternalIdentity returns nil when the account at the provider is not
+// linked to a user yet.
+func (r *repository) GetExternalIdentity(ctx context.Context, provider string, subject string) (*model.ExternalIdentity, error) {
+	identity := &model.ExternalIdentity{}
+	err := r.db.WithContext(ctx).Where("provider = ? AND subject = ?", provider, subject).First(identity).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return identity, nil
+}
+
+func (r *repository) CreateExternalIdentity(ctx context.Context, identity *model.ExternalIdentity) error {
+	return r.db.WithContext(ctx).Create(identity).Error
+}
+
+// OIDC state operations, the state of a login is a hash at oidc_state:<hash
+// of the state parameter>, used once.
+func (r *repository) StoreOIDCState(ctx context.Context, key string, state *model.OIDCState, exp time.Duration) error {
+	_, err := r.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, map[string]interface{}{
+			"provider": state.Provider,
+			"verifier": state.Verifier,
+			"nonce":    state.Nonce,
+		})
+		pipe.Expire(ctx, key, exp)
+		return nil
+	})
+	return err
+}
+
+// TakeOIDCState returns the state and deletes it, nil when it is unknown,
+// used or expired.
+func (r *repository) TakeOIDCState(ctx context.Context, key string) (*model.OIDCState, error) {
+	var fields *redis.MapStringStringCmd
+	_, err := r.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		fields = pipe.HGetAll(ctx, key)
+		pipe.Del(ctx, key)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(fields.Val()) == 0 {
+		return nil, nil
+	}
+
+	return &model.OIDCState{
+		Provider: fields.Val()["provider"],
+		Verifier: fields.Val()["verifier"],
+		Nonce:    fields.Val()["nonce"],
+	}, nil
+}
+
+// Login attempt operations, the subject is an email or an address. The
+// failures are counted at login_failures:<subject> and the subject is locked
+// while login_lock:<subject> exists.
+func loginFailuresKey(subject string) string {
+	return "login_failures:" + subject
+}
+
+func loginLockKey(subject string) string {
+	return "login_lock:" + subject
+}
+
+// LoginLockTTL returns how long the subject stays locked, zero when it is not.
+func (r *repository) LoginLockTTL(ctx context.Context, subject string) (time.Duration, error) {
+	ttl, err := r.redisClient.PTTL(ctx, loginLockKey(subject)).Result()
+	if err != nil {
+		return 0, err
+	}
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
+
+// RecordLoginFailure counts a failure and returns the failures in a row, the
+// count is forgotten after window without failures.
+func (r *repository) RecordLoginFailure(ctx context.Context, subject string, window time.Duration) (int64, error) {
+	var incr *redis.IntCmd
+	_, err := r.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, loginFailuresKey(subject))
+		pipe.Expire(ctx, loginFailuresKey(subject), window)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+func (r *repository) LockLogin(ctx context.Context, subject string, d time.Duration) error {
+	return r.redisClient.Set(ctx, loginLockKey(subject), 1, d).Err()
+}
+
+func (r *repository) ClearLoginFailures(ctx context.Context, subject string) error {
+	return r.redisClient.Del(ctx, loginFailuresKey(subject), loginLockKey(subject)).Err()
+}
+
+// ListSigningKeys returns the keys that are not retired yet, oldest first.
+func (r *repository) ListSigningKeys(ctx context.Context) ([]model.SigningKey, error) {
+	keys := []model.SigningKey{}
+	err := r.db.WithContext(ctx).
+		Where("retire_at IS NULL OR retire_at > ?", time.Now()).
+		Order("active_from ASC").
+		Find(&keys).Error
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// signingKeyLock is the advisory lock replicas take to rotate the keys.
+const signingKeyLock = 7_263_012
+
+// RotateSigningKey adds the key and retires the previous one at retireAt. It
+// adds nothing and returns false when the latest key is not previousID
+// anymore, another replica rotated first.
+func (r *repository) RotateSigningKey(ctx context.Context, key *model.SigningKey, previousID string, retireAt time.Time) (bool, error) {
+	added := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", signingKeyLock).Error; err != nil {
+			return err
+		}
+
+		latest := &model.SigningKey{}
+		err := tx.Where("retire_at IS NULL OR retire_at > ?", time.Now()).
+			Order("active_from DESC").
+			First(latest).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if latest.ID != previousID {
+			return nil
+		}
+		if latest.ID != "" {
+			if err := tx.Model(latest).Update("retire_at", retireAt).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Create(key).Error; err != nil {
+			return err
+		}
+		added = true
+		return nil
+	})
+	return added, err
+}
+
 // Refresh Token operations
 func (r *repository) StoreRefreshToken(ctx context.Context, key string, value string, exp time.Duration) error {
 	if err := r.redisClient.Set(ctx, key, value, exp).Err(); err != nil {
@@ -65,10 +504,158 @@ func (r *repository) StoreRefreshToken(ctx context.Context, key string, value st
 	return nil
 }
 
+// GetAndDelRefreshToken returns an empty value when the token was already
+// used or is expired.
 func (r *repository) GetAndDelRefreshToken(ctx context.Context, key string) (string, error) {
 	val, err := r.redisClient.GetDel(ctx, key).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
 	if err != nil {
 		return "", err
 	}
 	return val, nil
 }
+
+// Session operations, a session is a hash at session:<id> and the ids of the
+// sessions of a user are kept in the set user_sessions:<user id>
+func sessionKey(id string) string {
+	return "session:" + id
+}
+
+func userSessionsKey(userID string) string {
+	return "user_sessions:" + userID
+}
+
+// SaveSession creates or updates the session, both keys expire with the
+// latest refresh token of the session.
+func (r *repository) SaveSession(ctx context.Context, session *model.Session, exp time.Duration) error {
+	_, err := r.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, sessionKey(session.ID), map[string]interface{}{
+			"user_id":      session.UserID,
+			"device":       session.Device,
+			"ip":           session.IP,
+			"token_id":     session.TokenID,
+			"created_at":   session.CreatedAt.Unix(),
+			"last_used_at": session.LastUsedAt.Unix(),
+			"role":         session.Role,
+
+			"access_token_id":         session.AccessTokenID,
+			"access_token_expires_at": session.AccessTokenExpiresAt.Unix(),
+		})
+		pipe.Expire(ctx, sessionKey(session.ID), exp)
+		pipe.SAdd(ctx, userSessionsKey(session.UserID), session.ID)
+		pipe.Expire(ctx, userSessionsKey(session.UserID), exp)
+		return nil
+	})
+	return err
+}
+
+func (r *repository) GetSession(ctx context.Context, id string) (*model.Session, error) {
+	fields, err := r.redisClient.HGetAll(ctx, sessionKey(id)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return sessionFromHash(id, fields), nil
+}
+
+// ListSessions returns the live sessions of the user, the ids of the expired
+// ones are dropped from the set on the way.
+func (r *repository) ListSessions(ctx context.Context, userID string) ([]model.Session, error) {
+	ids, err := r.redisClient.SMembers(ctx, userSessionsKey(userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := []model.Session{}
+	expired := []interface{}{}
+	for _, id := range ids {
+		s, err := r.GetSession(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if s == nil {
+			expired = append(expired, id)
+			continue
+		}
+		sessions = append(sessions, *s)
+	}
+
+	if len(expired) > 0 {
+		if err := r.redisClient.SRem(ctx, userSessionsKey(userID), expired...).Err(); err != nil {
+			return nil, err
+		}
+	}
+	return sessions, nil
+}
+
+// DeleteSessions revokes the sessions of the user along with their current
+// refresh tokens, and denies every access token issued for them until the
+// latest one expires.
+func (r *repository) DeleteSessions(ctx context.Context, userID string, ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	keys := []string{}
+	members := []interface{}{}
+	denied := []*model.Session{}
+	for _, id := range ids {
+		s, err := r.GetSession(ctx, id)
+		if err != nil {
+			return err
+		}
+		if s != nil {
+			keys = append(keys, fmt.Sprintf("refresh_token:%s", s.TokenID))
+			// the latest token of a session is the last to expire
+			if time.Until(s.AccessTokenExpiresAt) > 0 {
+				denied = append(denied, s)
+			}
+		}
+		keys = append(keys, sessionKey(id))
+		members = append(members, id)
+	}
+
+	_, err := r.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, keys...)
+		pipe.SRem(ctx, userSessionsKey(userID), members...)
+		for _, s := range denied {
+			pipe.Set(ctx, RevokedSessionKey(s.ID), userID, time.Until(s.AccessTokenExpiresAt))
+		}
+		return nil
+	})
+	return err
+}
+
+// RevokedSessionKey is the denylist entry of a session, the gateway refuses
+// the tokens carrying the session in their sid claim while it exists.
+func RevokedSessionKey(sessionID string) string {
+	return "revoked_session:" + sessionID
+}
+
+func sessionFromHash(id string, fields map[string]string) *model.Session {
+	createdAt, _ := strconv.ParseInt(fields["created_at"], 10, 64)
+	lastUsedAt, _ := strconv.ParseInt(fields["last_used_at"], 10, 64)
+	accessExpiresAt, _ := strconv.ParseInt(fields["access_token_expires_at"], 10, 64)
+	role, err := strconv.ParseInt(fields["role"], 10, 32)
+	if err != nil {
+		role = -1
+	}
+
+	return &model.Session{
+		ID:         id,
+		UserID:     fields["user_id"],
+		Device:     fields["device"],
+		IP:         fields["ip"],
+		TokenID:    fields["token_id"],
+		CreatedAt:  time.Unix(createdAt, 0).UTC(),
+		LastUsedAt: time.Unix(lastUsedAt, 0).UTC(),
+		Role:       int32(role),
+
+		AccessTokenID:        fields["access_token_id"],
+		AccessTokenExpiresAt: time.Unix(accessExpiresAt, 0).UTC(),
+	}
+}
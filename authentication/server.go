@@ -3,30 +3,96 @@ package authentication
 import (
 	"context"
 	"fmt"
+	"log"
 	"net"
+	"net/url"
+	"time"
 
 	"github.com/231031/ecom-mcs-grpc/authentication/model"
 	"github.com/231031/ecom-mcs-grpc/authentication/pb"
+	"github.com/231031/ecom-mcs-grpc/authentication/repository"
 	"github.com/231031/ecom-mcs-grpc/authentication/service"
+	"github.com/231031/ecom-mcs-grpc/notification"
+	"github.com/231031/ecom-mcs-grpc/pkg/audit"
+	"github.com/231031/ecom-mcs-grpc/pkg/auth"
+	"github.com/231031/ecom-mcs-grpc/pkg/grpcerr"
+	"github.com/231031/ecom-mcs-grpc/pkg/idempotency"
+	"github.com/231031/ecom-mcs-grpc/pkg/validate"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 )
 
+var errorMappings = []grpcerr.Mapping{
+	grpcerr.AlreadyExists(repository.ErrEmailTaken).OnField("email"),
+	grpcerr.Unauthorized(service.ErrInvalidCredentials),
+	grpcerr.Unauthorized(service.ErrUnauth),
+	grpcerr.Unauthorized(service.ErrExpired),
+	grpcerr.Unauthorized(service.ErrTokenReused),
+	grpcerr.Forbidden(service.ErrSuspended),
+	grpcerr.Forbidden(service.ErrEmailNotVerified),
+	grpcerr.RateLimited(service.ErrTooManyAttempts),
+	grpcerr.Validation(service.ErrInvalidToken, "token"),
+	grpcerr.Validation(service.ErrInvalidCode, "code"),
+	grpcerr.Conflict(service.ErrTOTPEnabled),
+	grpcerr.Conflict(service.ErrTOTPNotEnrolled),
+	grpcerr.Conflict(service.ErrTOTPNotEnabled),
+	grpcerr.NotFound(service.ErrUnknownProvider),
+	grpcerr.Validation(service.ErrOIDCState, "state"),
+	grpcerr.Unauthorized(service.ErrOIDCFailed),
+	grpcerr.AlreadyExists(service.ErrOIDCEmailUnverified).OnField("email"),
+	grpcerr.Conflict(service.ErrSuspendAdmin),
+	grpcerr.Validation(service.ErrWrongPassword, "password"),
+	grpcerr.Conflict(service.ErrDeleteAdmin),
+	grpcerr.Validation(service.ErrRoleNotAllowed, "role"),
+	grpcerr.Forbidden(service.ErrRoleNotHeld),
+	grpcerr.NotFound(repository.ErrUserNotFound),
+	grpcerr.NotFound(repository.ErrSessionNotFound),
+}
+
 type grpcServer struct {
-	service service.Service
+	service            service.Service
+	keys               service.KeyService
+	notificationClient *notification.Client
+	appURL             string
 	pb.UnimplementedAuthenticationServiceServer
 }
 
-func ListenGRPC(s service.Service, port int) error {
+func ListenGRPC(s service.Service, keys service.KeyService, authn *auth.Authenticator, idempotencyStore idempotency.Store, auditStore audit.Store, notificationURL string, appURL string, port int) error {
+	notificationClient, err := notification.NewClient(notificationURL, authn.DialOption())
+	if err != nil {
+		return err
+	}
+
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
 	if err != nil {
+		notificationClient.Close()
 		return err
 	}
-	serv := grpc.NewServer()
+	serv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			grpcerr.UnaryServerInterceptor(errorMappings...),
+			authn.UnaryServerInterceptor(policies),
+			validate.UnaryServerInterceptor(),
+			idempotency.UnaryServerInterceptor(
+				idempotencyStore,
+				idempotency.DefaultTTL,
+				pb.AuthenticationService_CreateUser_FullMethodName,
+			),
+			audit.UnaryServerInterceptor(
+				auditStore,
+				pb.AuthenticationService_ListUsers_FullMethodName,
+				pb.AuthenticationService_SetUserSuspended_FullMethodName,
+				pb.AuthenticationService_UnlockUser_FullMethodName,
+			),
+		),
+	)
 	pb.RegisterAuthenticationServiceServer(
 		serv,
 		&grpcServer{
-			service: s,
+			service:            s,
+			keys:               keys,
+			notificationClient: notificationClient,
+			appURL:             appURL,
 		},
 	)
 	reflection.Register(serv)
@@ -39,35 +105,190 @@ func (s *grpcServer) CreateUser(ctx context.Context, in *pb.CreateUserRequest) (
 		Password: in.GetPassword(),
 		Role:     in.GetRole(),
 	}
-	_, err := s.service.CreateUser(ctx, u)
+	user, err := s.service.CreateUser(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := s.service.NewEmailVerification(ctx, user.ID)
 	if err != nil {
 		return nil, err
 	}
+	s.notify(notification.EventEmailVerification, user, map[string]string{
+		"link": s.link("/verify-email", token),
+	})
+
 	return &pb.CreateUserResponse{}, nil
 }
 
+func (s *grpcServer) VerifyEmail(ctx context.Context, in *pb.VerifyEmailRequest) (*pb.VerifyEmailResponse, error) {
+	user, changed, err := s.service.VerifyEmail(ctx, in.GetToken())
+	if err != nil {
+		return nil, err
+	}
+
+	// the welcome mail is only sent for the email of sign up
+	if !changed {
+		s.notify(notification.EventAccountCreated, user, nil)
+	}
+	return &pb.VerifyEmailResponse{Email: user.Email}, nil
+}
+
+func (s *grpcServer) RequestEmailVerification(ctx context.Context, in *pb.RequestEmailVerificationRequest) (*pb.RequestEmailVerificationResponse, error) {
+	user, token, err := s.service.RequestEmailVerification(ctx, in.GetEmail())
+	if err != nil {
+		return nil, err
+	}
+
+	if user != nil {
+		s.notify(notification.EventEmailVerification, user, map[string]string{
+			"link": s.link("/verify-email", token),
+		})
+	}
+	return &pb.RequestEmailVerificationResponse{}, nil
+}
+
+func (s *grpcServer) RequestPasswordReset(ctx context.Context, in *pb.RequestPasswordResetRequest) (*pb.RequestPasswordResetResponse, error) {
+	user, token, err := s.service.RequestPasswordReset(ctx, in.GetEmail())
+	if err != nil {
+		return nil, err
+	}
+
+	if user != nil {
+		s.notify(notification.EventPasswordReset, user, map[string]string{
+			"link": s.link("/reset-password", token),
+		})
+	}
+	return &pb.RequestPasswordResetResponse{}, nil
+}
+
+func (s *grpcServer) ResetPassword(ctx context.Context, in *pb.ResetPasswordRequest) (*pb.ResetPasswordResponse, error) {
+	err := s.service.ResetPassword(ctx, in.GetToken(), in.GetPassword())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ResetPasswordResponse{}, nil
+}
+
+// notify sends the mail in the background, it must not hold up or fail the
+// request.
+func (s *grpcServer) notify(event int32, user *model.User, data map[string]string) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		_, err := s.notificationClient.Notify(ctx, event, user.ID, user.Email, data)
+		if err != nil {
+			log.Println("error sending notification", err)
+		}
+	}()
+}
+
+// link is the page of the app a mail sends the user to with the token.
+func (s *grpcServer) link(path string, token string) string {
+	return s.appURL + path + "?token=" + url.QueryEscape(token)
+}
+
 func (s *grpcServer) LoginUser(ctx context.Context, in *pb.LoginUserRequest) (*pb.LoginUserResponse, error) {
-	u, err := s.service.LoginUser(ctx, in.GetEmail(), in.GetPassword())
+	client := model.ClientInfo{Device: in.GetDevice(), IP: in.GetIp()}
+	u, err := s.service.LoginUser(ctx, in.GetEmail(), in.GetPassword(), client)
 	if err != nil {
 		return nil, err
 	}
+	return loginResponse(u), nil
+}
 
-	tokenResp := &pb.TokenResponse{
-		Token:        u.TokenPair.AccessToken,
-		RefreshToken: u.TokenPair.RefreshToken,
+func (s *grpcServer) VerifyMFA(ctx context.Context, in *pb.VerifyMFARequest) (*pb.LoginUserResponse, error) {
+	client := model.ClientInfo{Device: in.GetDevice(), IP: in.GetIp()}
+	u, err := s.service.VerifyMFA(ctx, in.GetMfaToken(), in.GetCode(), client)
+	if err != nil {
+		return nil, err
+	}
+	return loginResponse(u), nil
+}
+
+func (s *grpcServer) StartOIDCLogin(ctx context.Context, in *pb.StartOIDCLoginRequest) (*pb.StartOIDCLoginResponse, error) {
+	authURL, state, err := s.service.StartOIDCLogin(ctx, in.GetProvider())
+	if err != nil {
+		return nil, err
 	}
+	return &pb.StartOIDCLoginResponse{AuthorizationUrl: authURL, State: state}, nil
+}
 
-	userResponse := &pb.LoginUserResponse{
-		Email:         in.GetEmail(),
-		Role:          u.Role,
-		TokenResponse: tokenResp,
+func (s *grpcServer) CompleteOIDCLogin(ctx context.Context, in *pb.CompleteOIDCLoginRequest) (*pb.LoginUserResponse, error) {
+	client := model.ClientInfo{Device: in.GetDevice(), IP: in.GetIp()}
+	u, err := s.service.CompleteOIDCLogin(ctx, in.GetProvider(), in.GetCode(), in.GetState(), client)
+	if err != nil {
+		return nil, err
 	}
+	return loginResponse(u), nil
+}
 
-	return userResponse, nil
+func (s *grpcServer) EnrollTOTP(ctx context.Context, in *pb.EnrollTOTPRequest) (*pb.EnrollTOTPResponse, error) {
+	enrollment, err := s.service.EnrollTOTP(ctx, in.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.EnrollTOTPResponse{
+		Secret:        enrollment.Secret,
+		OtpauthUri:    enrollment.URI,
+		RecoveryCodes: enrollment.RecoveryCodes,
+	}, nil
+}
+
+func (s *grpcServer) EnableTOTP(ctx context.Context, in *pb.EnableTOTPRequest) (*pb.EnableTOTPResponse, error) {
+	err := s.service.EnableTOTP(ctx, in.GetUserId(), in.GetCode())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.EnableTOTPResponse{}, nil
+}
+
+func (s *grpcServer) DisableTOTP(ctx context.Context, in *pb.DisableTOTPRequest) (*pb.DisableTOTPResponse, error) {
+	err := s.service.DisableTOTP(ctx, in.GetUserId(), in.GetCode())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.DisableTOTPResponse{}, nil
+}
+
+func (s *grpcServer) ChangePassword(ctx context.Context, in *pb.ChangePasswordRequest) (*pb.ChangePasswordResponse, error) {
+	err := s.service.ChangePassword(ctx, in.GetUserId(), in.GetSessionId(), in.GetCurrentPassword(), in.GetNewPassword())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ChangePasswordResponse{}, nil
+}
+
+func (s *grpcServer) ChangeEmail(ctx context.Context, in *pb.ChangeEmailRequest) (*pb.ChangeEmailResponse, error) {
+	user, token, err := s.service.ChangeEmail(ctx, in.GetUserId(), in.GetPassword(), in.GetEmail())
+	if err != nil {
+		return nil, err
+	}
+
+	// the link goes to the new email, it proves the user owns it
+	s.notify(notification.EventEmailVerification, &model.User{ID: user.ID, Email: in.GetEmail()}, map[string]string{
+		"link": s.link("/verify-email", token),
+	})
+	return &pb.ChangeEmailResponse{}, nil
+}
+
+func (s *grpcServer) DeleteUser(ctx context.Context, in *pb.DeleteUserRequest) (*pb.DeleteUserResponse, error) {
+	deleteAt, err := s.service.RequestDeletion(ctx, in.GetUserId(), in.GetPassword())
+	if err != nil {
+		return nil, err
+	}
+
+	deleteAtProto, err := deleteAt.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &pb.DeleteUserResponse{DeleteAt: deleteAtProto}, nil
 }
 
 func (s *grpcServer) RefreshTokenUser(ctx context.Context, in *pb.RefreshTokenRequest) (*pb.TokenResponse, error) {
-	token, err := s.service.RefreshTokenUser(ctx, in.GetRefreshToken())
+	client := model.ClientInfo{Device: in.GetDevice(), IP: in.GetIp()}
+	token, err := s.service.RefreshTokenUser(ctx, in.GetRefreshToken(), client)
 	if err != nil {
 		return nil, err
 	}
@@ -78,3 +299,164 @@ func (s *grpcServer) RefreshTokenUser(ctx context.Context, in *pb.RefreshTokenRe
 	}
 	return tokenResp, nil
 }
+
+func (s *grpcServer) Logout(ctx context.Context, in *pb.LogoutRequest) (*pb.LogoutResponse, error) {
+	err := s.service.Logout(ctx, in.GetUserId(), in.GetSessionId())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.LogoutResponse{}, nil
+}
+
+func (s *grpcServer) LogoutAllSessions(ctx context.Context, in *pb.LogoutAllSessionsRequest) (*pb.LogoutAllSessionsResponse, error) {
+	revoked, err := s.service.LogoutAllSessions(ctx, in.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	return &pb.LogoutAllSessionsResponse{Revoked: uint32(revoked)}, nil
+}
+
+func (s *grpcServer) ListSessions(ctx context.Context, in *pb.ListSessionsRequest) (*pb.ListSessionsResponse, error) {
+	sessions, err := s.service.ListSessions(ctx, in.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	sessionsProto := []*pb.Session{}
+	for _, session := range sessions {
+		createdAt, err := session.CreatedAt.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		lastUsedAt, err := session.LastUsedAt.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+
+		sessionsProto = append(sessionsProto, &pb.Session{
+			Id:         session.ID,
+			Device:     session.Device,
+			Ip:         session.IP,
+			CreatedAt:  createdAt,
+			LastUsedAt: lastUsedAt,
+		})
+	}
+	return &pb.ListSessionsResponse{Sessions: sessionsProto}, nil
+}
+
+func (s *grpcServer) GetJWKS(ctx context.Context, in *pb.GetJWKSRequest) (*pb.JWKS, error) {
+	set := &pb.JWKS{}
+	for _, k := range s.keys.JWKS().Keys {
+		set.Keys = append(set.Keys, &pb.JWK{
+			Kty: k.Kty,
+			Kid: k.Kid,
+			Use: k.Use,
+			Alg: k.Alg,
+			N:   k.N,
+			E:   k.E,
+		})
+	}
+	return set, nil
+}
+
+func (s *grpcServer) ListUsers(ctx context.Context, in *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
+	filter := model.UserFilter{
+		Query:         in.GetQuery(),
+		Roles:         in.GetRoles(),
+		SuspendedOnly: in.GetSuspendedOnly(),
+	}
+	users, err := s.service.ListUsers(ctx, filter, in.GetSkip(), in.GetTake())
+	if err != nil {
+		return nil, err
+	}
+
+	usersProto := []*pb.User{}
+	for _, u := range users {
+		userProto, err := userToProto(&u)
+		if err != nil {
+			return nil, err
+		}
+		usersProto = append(usersProto, userProto)
+	}
+	return &pb.ListUsersResponse{Users: usersProto}, nil
+}
+
+func (s *grpcServer) SetUserSuspended(ctx context.Context, in *pb.SetUserSuspendedRequest) (*pb.User, error) {
+	u, err := s.service.SetUserSuspended(ctx, in.GetId(), in.GetSuspended(), in.GetReason())
+	if err != nil {
+		return nil, err
+	}
+	return userToProto(u)
+}
+
+func (s *grpcServer) UnlockUser(ctx context.Context, in *pb.UnlockUserRequest) (*pb.User, error) {
+	u, err := s.service.UnlockUser(ctx, in.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return userToProto(u)
+}
+
+func (s *grpcServer) GetUser(ctx context.Context, in *pb.GetUserRequest) (*pb.User, error) {
+	u, err := s.service.GetUser(ctx, in.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return userToProto(u)
+}
+
+func (s *grpcServer) AddRole(ctx context.Context, in *pb.AddRoleRequest) (*pb.User, error) {
+	u, err := s.service.AddRole(ctx, in.GetUserId(), in.GetRole())
+	if err != nil {
+		return nil, err
+	}
+	return userToProto(u)
+}
+
+func (s *grpcServer) SwitchRole(ctx context.Context, in *pb.SwitchRoleRequest) (*pb.LoginUserResponse, error) {
+	u, err := s.service.SwitchRole(ctx, in.GetUserId(), in.GetSessionId(), in.GetRole())
+	if err != nil {
+		return nil, err
+	}
+	return loginResponse(u), nil
+}
+
+// loginResponse carries the token pair, or the MFA challenge when the user
+// still has to give their second factor.
+func loginResponse(u *model.UserInfo) *pb.LoginUserResponse {
+	if u.MFAToken != "" {
+		return &pb.LoginUserResponse{
+			Email:    u.Email,
+			Role:     u.Role,
+			MfaToken: u.MFAToken,
+		}
+	}
+
+	return &pb.LoginUserResponse{
+		Email: u.Email,
+		Role:  u.Role,
+		Roles: u.Roles,
+		TokenResponse: &pb.TokenResponse{
+			Token:        u.TokenPair.AccessToken,
+			RefreshToken: u.TokenPair.RefreshToken,
+		},
+	}
+}
+
+func userToProto(u *model.User) (*pb.User, error) {
+	createdAt, err := u.CreatedAt.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.User{
+		Id:              u.ID,
+		Email:           u.Email,
+		Role:            u.Role,
+		Suspended:       u.Suspended,
+		SuspendedReason: u.SuspendedReason,
+		TotpEnabled:     u.TOTPEnabled,
+		Roles:           u.RoleList(),
+		CreatedAt:       createdAt,
+	}, nil
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/231031/ecom-mcs-grpc/authentication/model"
+	"github.com/231031/ecom-mcs-grpc/authentication/repository"
+)
+
+var (
+	ErrWrongPassword = errors.New("the password is wrong")
+	ErrDeleteAdmin   = errors.New("admin accounts cannot be deleted")
+)
+
+// the link that confirms a new email waits as long as the one of sign up
+const emailChangeTTL = emailVerificationTTL
+
+// ChangePassword sets the new password once the current one checks out, and
+// signs the user out of every session but the one they changed it from.
+func (s *authService) ChangePassword(ctx context.Context, userID string, sessionID string, current string, password string) error {
+	u, err := s.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkPassword(ctx, u, current); err != nil {
+		return err
+	}
+
+	hashed, err := s.tokenService.hashPassword(password)
+	if err != nil {
+		return err
+	}
+	if err := s.repository.UpdatePassword(ctx, u.ID, hashed); err != nil {
+		return err
+	}
+
+	_, err = s.tokenService.RevokeOtherSessions(ctx, u.ID, sessionID)
+	return err
+}
+
+// ChangeEmail issues the token of the link sent to the new email, the email
+// only changes once the link is opened with VerifyEmail.
+func (s *authService) ChangeEmail(ctx context.Context, userID string, password string, email string) (*model.User, string, error) {
+	u, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := s.checkPassword(ctx, u, password); err != nil {
+		return nil, "", err
+	}
+
+	taken, err := s.repository.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, "", err
+	}
+	if taken != nil {
+		return nil, "", repository.ErrEmailTaken
+	}
+
+	token, hash, err := newOneTimeToken()
+	if err != nil {
+		return nil, "", err
+	}
+	err = s.repository.StoreOneTimeToken(ctx, emailChangeKey(hash), u.ID+":"+email, emailChangeTTL)
+	if err != nil {
+		return nil, "", err
+	}
+	return u, token, nil
+}
+
+// RequestDeletion signs the user out everywhere and marks the account for
+// deletion, it is erased after the grace period unless the user logs in
+// again before. It returns when the account is erased.
+func (s *authService) RequestDeletion(ctx context.Context, userID string, password string) (time.Time, error) {
+	u, err := s.getUser(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if u.HasRole(int32(model.ADMIN)) {
+		return time.Time{}, ErrDeleteAdmin
+	}
+	if err := s.checkPassword(ctx, u, password); err != nil {
+		return time.Time{}, err
+	}
+
+	now := time.Now()
+	if err := s.repository.SetDeletionRequested(ctx, u.ID, &now); err != nil {
+		return time.Time{}, err
+	}
+	if _, err := s.tokenService.RevokeAllSessions(ctx, u.ID); err != nil {
+		return time.Time{}, err
+	}
+	return now.Add(s.deletionGracePeriod), nil
+}
+
+// DueForDeletion returns the users whose grace period is over.
+func (s *authService) DueForDeletion(ctx context.Context, limit int) ([]model.User, error) {
+	return s.repository.ListUsersDueForDeletion(ctx, s.deletionCutoff(), limit)
+}
+
+// IsDueForDeletion reads the user again, a login since the list was taken
+// cancels the deletion.
+func (s *authService) IsDueForDeletion(ctx context.Context, userID string) (bool, error) {
+	u, err := s.repository.GetUserByID(ctx, userID)
+	if err != nil || u == nil {
+		return false, err
+	}
+	return u.DeletionRequestedAt != nil && !u.DeletionRequestedAt.After(s.deletionCutoff()), nil
+}
+
+// PurgeUser erases the user for good, once the other services erased what
+// they keep of them. It returns false when the deletion was cancelled in the
+// meantime and the user is kept.
+func (s *authService) PurgeUser(ctx context.Context, userID string) (bool, error) {
+	u, err := s.repository.GetUserByID(ctx, userID)
+	if err != nil || u == nil {
+		return false, err
+	}
+
+	deleted, err := s.repository.DeleteUser(ctx, u.ID, s.deletionCutoff())
+	if err != nil || !deleted {
+		return false, err
+	}
+	if _, err := s.tokenService.RevokeAllSessions(ctx, u.ID); err != nil {
+		return true, err
+	}
+	return true, s.repository.ClearLoginFailures(ctx, emailSubject(u.Email).key)
+}
+
+func (s *authService) deletionCutoff() time.Time {
+	return time.Now().Add(-s.deletionGracePeriod)
+}
+
+// startSession logs the user in, a login during the grace period keeps the
+// account.
+func (s *authService) startSession(ctx context.Context, u *model.User, client model.ClientInfo) (*model.UserInfo, error) {
+	if u.DeletionRequestedAt != nil {
+		if err := s.repository.SetDeletionRequested(ctx, u.ID, nil); err != nil {
+			return nil, err
+		}
+		log.Println("deletion of user", u.ID, "cancelled by login")
+	}
+
+	userAuth := newUserAuth(u, u.Role)
+	tokenPair, err := s.tokenService.StartSession(ctx, userAuth, client)
+	if err != nil {
+		return nil, err
+	}
+	return &model.UserInfo{Email: u.Email, Role: u.Role, Roles: userAuth.Roles, TokenPair: *tokenPair}, nil
+}
+
+// checkPassword confirms a change to the account with the password of the
+// user, wrong guesses count towards the lockout of the email like on login.
+func (s *authService) checkPassword(ctx context.Context, u *model.User, password string) error {
+	subjects := []loginSubject{emailSubject(u.Email)}
+	if err := s.checkLoginLock(ctx, subjects); err != nil {
+		return err
+	}
+
+	isValid, _, err := s.tokenService.verifyPasswordSecure(u.Password, password)
+	if err != nil {
+		return err
+	}
+	if !isValid {
+		if err := s.recordLoginFailure(ctx, subjects); err != nil {
+			return err
+		}
+		return ErrWrongPassword
+	}
+	return nil
+}
+
+// takeEmailChange returns the user and the new email of a change token, an
+// empty id when the token is not one.
+func (s *authService) takeEmailChange(ctx context.Context, token string) (string, string, error) {
+	value, err := s.repository.TakeOneTimeToken(ctx, emailChangeKey(hashToken(token)))
+	if err != nil || value == "" {
+		return "", "", err
+	}
+	// ids have no colon, the email may
+	userID, email, _ := strings.Cut(value, ":")
+	return userID, email, nil
+}
+
+func emailChangeKey(hash string) string {
+	return "email_change:" + hash
+}
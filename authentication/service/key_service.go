@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/231031/ecom-mcs-grpc/authentication/model"
+	"github.com/231031/ecom-mcs-grpc/authentication/repository"
+	"github.com/231031/ecom-mcs-grpc/authentication/utils"
+	"github.com/231031/ecom-mcs-grpc/pkg/auth"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNoSigningKey = errors.New("no signing key is active")
+)
+
+const (
+	signingKeyBits = 2048
+
+	// publishAhead is how long a new key is published before it signs, the
+	// key sets cached by the gateway and the services know it by then.
+	publishAhead = 2 * auth.DefaultKeySetTTL
+	// retireMargin keeps a replaced key published a little past the expiry
+	// of the last token it signed, for clock skew.
+	retireMargin = 5 * time.Minute
+)
+
+// KeyService manages the keys access tokens are signed with. The keys are
+// kept in the database so every replica signs with the same one, and are
+// rotated on an interval with an overlap: the next key is published before it
+// signs and the previous one stays published until its tokens expired.
+type KeyService interface {
+	auth.KeySource
+	SigningKey() (string, *rsa.PrivateKey, error)
+	JWKS() auth.JWKS
+	Load(ctx context.Context) error
+	Rotate(ctx context.Context) error
+	Run(ctx context.Context, every time.Duration)
+}
+
+type signingKey struct {
+	id         string
+	activeFrom time.Time
+	retireAt   *time.Time
+	private    *rsa.PrivateKey
+}
+
+type keyService struct {
+	repository repository.Repository
+	secret     string
+	// the key of FILE_PRI_PATH, the first key when there is none yet
+	initialKey       *rsa.PrivateKey
+	rotationInterval time.Duration
+	tokenLifetime    time.Duration
+
+	mu   sync.RWMutex
+	keys []signingKey
+}
+
+func NewKeyService(r repository.Repository, cfg *model.TokenConfig, secret string, rotationInterval time.Duration) KeyService {
+	return &keyService{
+		repository:       r,
+		secret:           secret,
+		initialKey:       cfg.PrivateKey,
+		rotationInterval: rotationInterval,
+		tokenLifetime:    time.Duration(cfg.TokenIDExpirationSecs) * time.Second,
+	}
+}
+
+// SigningKey returns the newest key that is active.
+func (s *keyService) SigningKey() (string, *rsa.PrivateKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	for i := len(s.keys) - 1; i >= 0; i-- {
+		k := s.keys[i]
+		if !k.activeFrom.After(now) {
+			return k.id, k.private, nil
+		}
+	}
+	return "", nil, ErrNoSigningKey
+}
+
+func (s *keyService) PublicKey(kid string) (*rsa.PublicKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, k := range s.keys {
+		if k.id == kid && k.published(time.Now()) {
+			return &k.private.PublicKey, nil
+		}
+	}
+	return nil, auth.ErrUnknownKey
+}
+
+// JWKS returns the published keys, the upcoming one included.
+func (s *keyService) JWKS() auth.JWKS {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	set := auth.JWKS{Keys: []auth.JWK{}}
+	for _, k := range s.keys {
+		if k.published(time.Now()) {
+			set.Keys = append(set.Keys, auth.NewJWK(k.id, &k.private.PublicKey))
+		}
+	}
+	return set
+}
+
+// Load reads the keys from the database, picking up the ones other replicas
+// rotated in.
+func (s *keyService) Load(ctx context.Context) error {
+	stored, err := s.repository.ListSigningKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	keys := []signingKey{}
+	for _, k := range stored {
+		private, err := s.openKey(k.PrivateKey)
+		if err != nil {
+			log.Println("error opening signing key", k.ID, err)
+			continue
+		}
+		keys = append(keys, signingKey{
+			id:         k.ID,
+			activeFrom: k.ActiveFrom,
+			retireAt:   k.RetireAt,
+			private:    private,
+		})
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.mu.Unlock()
+	return nil
+}
+
+// Rotate adds the next key once the latest one is due for rotation. The
+// first key signs right away, the next ones after publishAhead.
+func (s *keyService) Rotate(ctx context.Context) error {
+	s.mu.RLock()
+	var latest *signingKey
+	if len(s.keys) > 0 {
+		latest = &s.keys[len(s.keys)-1]
+	}
+	s.mu.RUnlock()
+
+	now := time.Now()
+	if latest != nil && now.Before(latest.activeFrom.Add(s.rotationInterval-publishAhead)) {
+		return nil
+	}
+
+	private := s.initialKey
+	activeFrom := now
+	previousID := ""
+	if latest != nil {
+		private = nil
+		activeFrom = now.Add(publishAhead)
+		previousID = latest.id
+	}
+	if private == nil {
+		var err error
+		private, err = rsa.GenerateKey(rand.Reader, signingKeyBits)
+		if err != nil {
+			return err
+		}
+	}
+
+	sealed, err := s.sealKey(private)
+	if err != nil {
+		return err
+	}
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return err
+	}
+
+	key := &model.SigningKey{
+		ID:         id.String(),
+		PrivateKey: sealed,
+		ActiveFrom: activeFrom,
+	}
+	retireAt := activeFrom.Add(s.tokenLifetime + retireMargin)
+	added, err := s.repository.RotateSigningKey(ctx, key, previousID, retireAt)
+	if err != nil {
+		return err
+	}
+	if added {
+		log.Println("signing key", key.ID, "added, active from", activeFrom)
+	}
+	return s.Load(ctx)
+}
+
+// Run rotates the keys and reloads them on the interval until ctx is done.
+func (s *keyService) Run(ctx context.Context, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Rotate(ctx); err != nil {
+				log.Println("error rotating signing keys", err)
+			}
+		}
+	}
+}
+
+func (s *keyService) sealKey(key *rsa.PrivateKey) (string, error) {
+	block := &pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	}
+	return utils.SealKey(pem.EncodeToMemory(block), s.secret)
+}
+
+func (s *keyService) openKey(sealed string) (*rsa.PrivateKey, error) {
+	data, err := utils.OpenKey(sealed, s.secret)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid signing key pem")
+	}
+	return x509.ParsePKCS1PrivateKey(block.Bytes)
+}
+
+func (k signingKey) published(now time.Time) bool {
+	return k.retireAt == nil || k.retireAt.After(now)
+}
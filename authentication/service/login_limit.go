@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/231031/ecom-mcs-grpc/pkg/grpcerr"
+)
+
+var (
+	ErrTooManyAttempts = errors.New("too many failed logins, try again later")
+)
+
+// loginLimit backs off the logins of a subject, an email or an address,
+// after failures in a row: free failures, then a delay doubling with every
+// failure, and a lockout once lockoutAfter is reached.
+type loginLimit struct {
+	free         int64
+	lockoutAfter int64
+	base         time.Duration
+	lockout      time.Duration
+}
+
+var (
+	emailLoginLimit = loginLimit{free: 3, lockoutAfter: 10, base: time.Second, lockout: 30 * time.Minute}
+	// the users behind a NAT share an address, it gets more room
+	ipLoginLimit = loginLimit{free: 20, lockoutAfter: 100, base: time.Second, lockout: 30 * time.Minute}
+)
+
+// loginFailureWindow is how long failures are remembered after the last one.
+const loginFailureWindow = time.Hour
+
+func (l loginLimit) delay(failures int64) time.Duration {
+	if failures >= l.lockoutAfter {
+		return l.lockout
+	}
+	if failures <= l.free {
+		return 0
+	}
+
+	shift := failures - l.free - 1
+	if shift > 30 {
+		return l.lockout
+	}
+	return min(l.base<<shift, l.lockout)
+}
+
+type loginSubject struct {
+	key   string
+	limit loginLimit
+}
+
+func emailSubject(email string) loginSubject {
+	return loginSubject{key: "email:" + strings.ToLower(email), limit: emailLoginLimit}
+}
+
+func loginSubjects(email string, ip string) []loginSubject {
+	subjects := []loginSubject{emailSubject(email)}
+	if ip != "" {
+		subjects = append(subjects, loginSubject{key: "ip:" + ip, limit: ipLoginLimit})
+	}
+	return subjects
+}
+
+// checkLoginLock refuses the login while one of the subjects is locked,
+// telling the caller when to try again.
+func (s *authService) checkLoginLock(ctx context.Context, subjects []loginSubject) error {
+	for _, subject := range subjects {
+		ttl, err := s.repository.LoginLockTTL(ctx, subject.key)
+		if err != nil {
+			return err
+		}
+		if ttl > 0 {
+			return grpcerr.WithRetryAfter(ErrTooManyAttempts, ttl)
+		}
+	}
+	return nil
+}
+
+// recordLoginFailure counts a wrong password or second factor against the
+// subjects and locks those that reached their backoff.
+func (s *authService) recordLoginFailure(ctx context.Context, subjects []loginSubject) error {
+	for _, subject := range subjects {
+		failures, err := s.repository.RecordLoginFailure(ctx, subject.key, loginFailureWindow)
+		if err != nil {
+			return err
+		}
+		if d := subject.limit.delay(failures); d > 0 {
+			if err := s.repository.LockLogin(ctx, subject.key, d); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
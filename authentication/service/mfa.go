@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/231031/ecom-mcs-grpc/authentication/model"
+	"github.com/231031/ecom-mcs-grpc/authentication/repository"
+	"github.com/231031/ecom-mcs-grpc/authentication/utils"
+)
+
+var (
+	ErrInvalidCode     = errors.New("the code is invalid")
+	ErrTOTPEnabled     = errors.New("two-factor authentication is already enabled")
+	ErrTOTPNotEnrolled = errors.New("two-factor authentication is not enrolled")
+	ErrTOTPNotEnabled  = errors.New("two-factor authentication is not enabled")
+)
+
+const (
+	// how long the password step of a login waits for the second factor
+	mfaChallengeTTL = 5 * time.Minute
+	// a used TOTP code is remembered while it is accepted
+	totpUsedTTL = 3 * utils.TOTPPeriod
+
+	recoveryCodeCount = 10
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// EnrollTOTP issues a new secret and recovery codes. 2FA stays off until the
+// user confirmed the app works with EnableTOTP, enrolling again replaces what
+// was not confirmed.
+func (s *authService) EnrollTOTP(ctx context.Context, userID string) (*model.TOTPEnrollment, error) {
+	u, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if u.TOTPEnabled {
+		return nil, ErrTOTPEnabled
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := utils.SealKey([]byte(secret), s.secret)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repository.SetTOTP(ctx, u.ID, sealed, false); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repository.ReplaceRecoveryCodes(ctx, u.ID, hashes); err != nil {
+		return nil, err
+	}
+
+	return &model.TOTPEnrollment{
+		Secret:        secret,
+		URI:           utils.TOTPURI(s.totpIssuer, u.Email, secret),
+		RecoveryCodes: codes,
+	}, nil
+}
+
+// EnableTOTP turns 2FA on once a code of the enrolled secret checks out.
+func (s *authService) EnableTOTP(ctx context.Context, userID string, code string) error {
+	u, err := s.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if u.TOTPEnabled {
+		return ErrTOTPEnabled
+	}
+	if u.TOTPSecret == "" {
+		return ErrTOTPNotEnrolled
+	}
+
+	ok, err := s.checkTOTP(ctx, u, strings.TrimSpace(code))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidCode
+	}
+	return s.repository.SetTOTP(ctx, u.ID, u.TOTPSecret, true)
+}
+
+// DisableTOTP turns 2FA off, asking for a TOTP or recovery code so a stolen
+// session alone cannot.
+func (s *authService) DisableTOTP(ctx context.Context, userID string, code string) error {
+	u, err := s.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !u.TOTPEnabled {
+		return ErrTOTPNotEnabled
+	}
+
+	ok, err := s.checkSecondFactor(ctx, u, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidCode
+	}
+
+	if err := s.repository.SetTOTP(ctx, u.ID, "", false); err != nil {
+		return err
+	}
+	return s.repository.ReplaceRecoveryCodes(ctx, u.ID, nil)
+}
+
+// VerifyMFA completes a login with the second factor. A wrong code counts as
+// a failed login and leaves the challenge usable until it expires.
+func (s *authService) VerifyMFA(ctx context.Context, mfaToken string, code string, client model.ClientInfo) (*model.UserInfo, error) {
+	key := mfaChallengeKey(hashToken(mfaToken))
+	userID, err := s.repository.GetOneTimeToken(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if userID == "" {
+		return nil, ErrInvalidToken
+	}
+	u, err := s.repository.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil || !u.TOTPEnabled {
+		return nil, ErrInvalidToken
+	}
+
+	subjects := loginSubjects(u.Email, client.IP)
+	if err := s.checkLoginLock(ctx, subjects); err != nil {
+		return nil, err
+	}
+	ok, err := s.checkSecondFactor(ctx, u, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if err := s.recordLoginFailure(ctx, subjects); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidCode
+	}
+
+	// another request may have completed the challenge in the meantime
+	userID, err = s.repository.TakeOneTimeToken(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if userID == "" {
+		return nil, ErrInvalidToken
+	}
+	if err := s.repository.ClearLoginFailures(ctx, emailSubject(u.Email).key); err != nil {
+		return nil, err
+	}
+	if u.Suspended {
+		return nil, ErrSuspended
+	}
+
+	return s.startSession(ctx, u, client)
+}
+
+// newMFAChallenge answers the password step of a user with 2FA, the token
+// stands for the checked password until the second factor comes.
+func (s *authService) newMFAChallenge(ctx context.Context, u *model.User) (*model.UserInfo, error) {
+	token, hash, err := newOneTimeToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repository.StoreOneTimeToken(ctx, mfaChallengeKey(hash), u.ID, mfaChallengeTTL); err != nil {
+		return nil, err
+	}
+	return &model.UserInfo{Email: u.Email, Role: u.Role, MFAToken: token}, nil
+}
+
+// checkSecondFactor accepts a TOTP code or one of the recovery codes.
+func (s *authService) checkSecondFactor(ctx context.Context, u *model.User, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+	if utils.IsTOTPCode(code) {
+		return s.checkTOTP(ctx, u, code)
+	}
+	return s.repository.UseRecoveryCode(ctx, u.ID, hashToken(normalizeRecoveryCode(code)))
+}
+
+func (s *authService) checkTOTP(ctx context.Context, u *model.User, code string) (bool, error) {
+	secret, err := utils.OpenKey(u.TOTPSecret, s.secret)
+	if err != nil {
+		return false, err
+	}
+
+	step, ok, err := utils.ValidateTOTP(string(secret), code, time.Now())
+	if err != nil || !ok {
+		return false, err
+	}
+	return s.repository.MarkTOTPUsed(ctx, u.ID, step, totpUsedTTL)
+}
+
+func (s *authService) getUser(ctx context.Context, id string) (*model.User, error) {
+	u, err := s.repository.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, repository.ErrUserNotFound
+	}
+	return u, nil
+}
+
+// newRecoveryCodes returns the codes shown to the user, as xxxxx-xxxxx, and
+// the hashes stored for them.
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := []string{}
+	hashes := []string{}
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, 6)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(recoveryCodeEncoding.EncodeToString(b))[:10]
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		hashes = append(hashes, hashToken(raw))
+	}
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode accepts a code typed without the dash or in capitals.
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(code, "-", ""))
+}
+
+func mfaChallengeKey(hash string) string {
+	return "mfa_challenge:" + hash
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/231031/ecom-mcs-grpc/authentication/model"
+)
+
+var (
+	ErrUnknownProvider     = errors.New("unknown identity provider")
+	ErrOIDCState           = errors.New("the login is invalid or expired, start it again")
+	ErrOIDCFailed          = errors.New("the identity provider did not confirm the login")
+	ErrOIDCEmailUnverified = errors.New("the email is already registered, verify it at the identity provider to link the accounts")
+)
+
+// how long a login started at a provider waits for its callback
+const oidcStateTTL = 10 * time.Minute
+
+// StartOIDCLogin returns the URL the browser logs in at, and the state the
+// provider sends back with the code.
+func (s *authService) StartOIDCLogin(ctx context.Context, provider string) (string, string, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return "", "", ErrUnknownProvider
+	}
+
+	state, stateHash, err := newOneTimeToken()
+	if err != nil {
+		return "", "", err
+	}
+	verifier, err := randomToken()
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := randomToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	authURL, err := p.AuthURL(ctx, state, verifier, nonce)
+	if err != nil {
+		log.Println("error discovering oidc provider", provider, err)
+		return "", "", ErrOIDCFailed
+	}
+
+	err = s.repository.StoreOIDCState(ctx, oidcStateKey(stateHash), &model.OIDCState{
+		Provider: provider,
+		Verifier: verifier,
+		Nonce:    nonce,
+	}, oidcStateTTL)
+	if err != nil {
+		return "", "", err
+	}
+	return authURL, state, nil
+}
+
+// CompleteOIDCLogin finishes the login with the code of the callback. The
+// account at the provider logs in as the user it is linked to, and is linked
+// on its first login to the user of its email, or to a new buyer. The
+// provider must have verified the email.
+func (s *authService) CompleteOIDCLogin(ctx context.Context, provider string, code string, state string, client model.ClientInfo) (*model.UserInfo, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+
+	st, err := s.repository.TakeOIDCState(ctx, oidcStateKey(hashToken(state)))
+	if err != nil {
+		return nil, err
+	}
+	if st == nil || st.Provider != provider {
+		return nil, ErrOIDCState
+	}
+
+	idToken, err := p.Exchange(ctx, code, st.Verifier)
+	if err != nil {
+		log.Println("error exchanging oidc code", provider, err)
+		return nil, ErrOIDCFailed
+	}
+	claims, err := p.VerifyIDToken(ctx, idToken, st.Nonce)
+	if err != nil {
+		log.Println("error verifying oidc id token", provider, err)
+		return nil, ErrOIDCFailed
+	}
+
+	u, err := s.userForIdentity(ctx, provider, claims)
+	if err != nil {
+		return nil, err
+	}
+	if u.Suspended {
+		return nil, ErrSuspended
+	}
+	if !u.EmailVerified {
+		return nil, ErrEmailNotVerified
+	}
+	if u.TOTPEnabled {
+		return s.newMFAChallenge(ctx, u)
+	}
+
+	return s.startSession(ctx, u, client)
+}
+
+func (s *authService) userForIdentity(ctx context.Context, provider string, claims *idTokenClaims) (*model.User, error) {
+	identity, err := s.repository.GetExternalIdentity(ctx, provider, claims.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if identity != nil {
+		return s.getUser(ctx, identity.UserID)
+	}
+
+	if claims.Email == "" {
+		log.Println("oidc provider", provider, "shared no email for", claims.Subject)
+		return nil, ErrOIDCFailed
+	}
+	u, err := s.repository.GetUserByEmail(ctx, claims.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	// anyone can claim an email at a provider that does not verify it, such
+	// an account is neither created nor linked
+	if !claims.EmailVerified {
+		return nil, ErrOIDCEmailUnverified
+	}
+
+	switch {
+	case u == nil:
+		// the user logs in with the provider only, the password is random
+		// until they reset it
+		password, err := randomToken()
+		if err != nil {
+			return nil, err
+		}
+		u, err = s.CreateUser(ctx, &model.User{
+			Email:         claims.Email,
+			Password:      password,
+			Role:          int32(model.BUYER),
+			EmailVerified: true,
+		})
+		if err != nil {
+			return nil, err
+		}
+	case !u.EmailVerified:
+		// whoever registered the unverified email may not own it, their
+		// password and sessions go before the owner's identity is linked
+		if err := s.takeOverUnverified(ctx, u.ID); err != nil {
+			return nil, err
+		}
+		u, err = s.repository.SetEmailVerified(ctx, u.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.repository.CreateExternalIdentity(ctx, &model.ExternalIdentity{
+		Provider: provider,
+		Subject:  claims.Subject,
+		UserID:   u.ID,
+		Email:    claims.Email,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// takeOverUnverified replaces the password of the user with a random one and
+// signs them out everywhere.
+func (s *authService) takeOverUnverified(ctx context.Context, userID string) error {
+	password, err := randomToken()
+	if err != nil {
+		return err
+	}
+	hashed, err := s.tokenService.hashPassword(password)
+	if err != nil {
+		return err
+	}
+	if err := s.repository.UpdatePassword(ctx, userID, hashed); err != nil {
+		return err
+	}
+	_, err = s.tokenService.RevokeAllSessions(ctx, userID)
+	return err
+}
+
+// randomToken returns 32 random bytes, base64url encoded, the length PKCE
+// asks of a code verifier.
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func oidcStateKey(hash string) string {
+	return "oidc_state:" + hash
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/231031/ecom-mcs-grpc/authentication/model"
+	"github.com/231031/ecom-mcs-grpc/pkg/auth"
+	"github.com/golang-jwt/jwt"
+)
+
+// idTokenLeeway is the clock skew allowed on the times of an ID token.
+const idTokenLeeway = time.Minute
+
+// OIDCProvider runs the authorization code flow, with PKCE, against an
+// OpenID Connect provider. The endpoints and keys are discovered from the
+// issuer on first use.
+type OIDCProvider struct {
+	cfg    model.OIDCProviderConfig
+	client *http.Client
+
+	mu        sync.Mutex
+	discovery *oidcDiscovery
+	keys      *auth.KeySet
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// idTokenClaims are the claims of an ID token the login needs. The audience
+// is a string or a list, the claims of jwt only take the string.
+type idTokenClaims struct {
+	Issuer        string   `json:"iss"`
+	Subject       string   `json:"sub"`
+	Audience      audience `json:"aud"`
+	ExpiresAt     int64    `json:"exp"`
+	IssuedAt      int64    `json:"iat"`
+	Nonce         string   `json:"nonce"`
+	Email         string   `json:"email"`
+	EmailVerified bool     `json:"email_verified"`
+}
+
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*a = audience{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+func (c *idTokenClaims) Valid() error {
+	now := time.Now()
+	if now.After(time.Unix(c.ExpiresAt, 0).Add(idTokenLeeway)) {
+		return errors.New("id token is expired")
+	}
+	if c.IssuedAt != 0 && now.Add(idTokenLeeway).Before(time.Unix(c.IssuedAt, 0)) {
+		return errors.New("id token is issued in the future")
+	}
+	return nil
+}
+
+func NewOIDCProvider(cfg model.OIDCProviderConfig) *OIDCProvider {
+	return &OIDCProvider{
+		cfg:    cfg,
+		client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (p *OIDCProvider) Name() string {
+	return p.cfg.Name
+}
+
+// AuthURL is where the browser is sent to log in at the provider.
+func (p *OIDCProvider) AuthURL(ctx context.Context, state string, verifier string, nonce string) (string, error) {
+	d, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	challenge := sha256.Sum256([]byte(verifier))
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", p.cfg.ClientID)
+	query.Set("redirect_uri", p.cfg.RedirectURL)
+	query.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	query.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(d.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return d.AuthorizationEndpoint + sep + query.Encode(), nil
+}
+
+// Exchange trades the code for the ID token of the user.
+func (p *OIDCProvider) Exchange(ctx context.Context, code string, verifier string) (string, error) {
+	d, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.cfg.RedirectURL)
+	form.Set("client_id", p.cfg.ClientID)
+	form.Set("client_secret", p.cfg.ClientSecret)
+	form.Set("code_verifier", verifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body := struct {
+		IDToken     string `json:"id_token"`
+		Error       string `json:"error"`
+		Description string `json:"error_description"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("decoding token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("exchanging code: %s %s %s", resp.Status, body.Error, body.Description)
+	}
+	if body.IDToken == "" {
+		return "", errors.New("token response has no id token")
+	}
+	return body.IDToken, nil
+}
+
+// VerifyIDToken checks the signature of the ID token with the keys of the
+// provider, and that it was issued by the provider, to this client, for the
+// login that sent the nonce.
+func (p *OIDCProvider) VerifyIDToken(ctx context.Context, raw string, nonce string) (*idTokenClaims, error) {
+	d, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := &idTokenClaims{}
+	_, err = jwt.ParseWithClaims(raw, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, auth.ErrUnexpectedSigning
+		}
+		kid, _ := token.Header["kid"].(string)
+		return p.keys.PublicKey(kid)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Issuer != d.Issuer {
+		return nil, fmt.Errorf("id token issuer %q is not %q", claims.Issuer, d.Issuer)
+	}
+	if !claims.Audience.contains(p.cfg.ClientID) {
+		return nil, errors.New("id token is not issued to the client")
+	}
+	if claims.Nonce != nonce {
+		return nil, errors.New("id token nonce does not match")
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("id token has no subject")
+	}
+	return claims, nil
+}
+
+// discover reads the configuration of the provider once, it is read again
+// on the next login when it failed.
+func (p *OIDCProvider) discover(ctx context.Context) (*oidcDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	u := strings.TrimSuffix(p.cfg.Issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("discovering %s: %s", p.cfg.Issuer, resp.Status)
+	}
+	d := &oidcDiscovery{}
+	if err := json.NewDecoder(resp.Body).Decode(d); err != nil {
+		return nil, err
+	}
+	if d.Issuer != p.cfg.Issuer {
+		return nil, fmt.Errorf("discovered issuer %q is not %q", d.Issuer, p.cfg.Issuer)
+	}
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return nil, fmt.Errorf("discovery of %s is missing endpoints", p.cfg.Issuer)
+	}
+
+	p.discovery = d
+	p.keys = auth.NewKeySet(auth.FetchHTTP(d.JWKSURI), auth.DefaultKeySetTTL)
+	return d, nil
+}
+
+func (a audience) contains(clientID string) bool {
+	for _, aud := range a {
+		if aud == clientID {
+			return true
+		}
+	}
+	return false
+}
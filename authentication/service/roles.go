@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/231031/ecom-mcs-grpc/authentication/model"
+)
+
+var (
+	ErrRoleNotAllowed = errors.New("only the buyer and seller roles can be added")
+	ErrRoleNotHeld    = errors.New("the user does not hold the role")
+)
+
+func (s *authService) GetUser(ctx context.Context, id string) (*model.User, error) {
+	return s.getUser(ctx, id)
+}
+
+// AddRole lets a buyer sell, or a seller buy, with the same login. The
+// sessions keep acting as their role until SwitchRole.
+func (s *authService) AddRole(ctx context.Context, userID string, role int32) (*model.User, error) {
+	if role != int32(model.BUYER) && role != int32(model.SELLER) {
+		return nil, ErrRoleNotAllowed
+	}
+	u, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if u.Suspended {
+		return nil, ErrSuspended
+	}
+	return s.repository.AddUserRole(ctx, u.ID, role)
+}
+
+// SwitchRole makes the session act as another role the user holds, with a
+// new token pair in place of the one of the session.
+func (s *authService) SwitchRole(ctx context.Context, userID string, sessionID string, role int32) (*model.UserInfo, error) {
+	u, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if u.Suspended {
+		return nil, ErrSuspended
+	}
+	if !u.HasRole(role) {
+		return nil, ErrRoleNotHeld
+	}
+
+	userAuth := newUserAuth(u, role)
+	tokenPair, err := s.tokenService.SwitchSessionRole(ctx, userAuth, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return &model.UserInfo{Email: u.Email, Role: role, Roles: userAuth.Roles, TokenPair: *tokenPair}, nil
+}
+
+// newUserAuth is the user of the access tokens, acting as role.
+func newUserAuth(u *model.User, role int32) *model.UserAuth {
+	return &model.UserAuth{ID: u.ID, Email: u.Email, Role: role, Roles: u.RoleList()}
+}
+
+// sessionRole is the role a session goes on acting as, the role of sign up
+// when the user does not hold it anymore.
+func sessionRole(u *model.User, session *model.Session) int32 {
+	if session.Role >= 0 && u.HasRole(session.Role) {
+		return session.Role
+	}
+	return u.Role
+}
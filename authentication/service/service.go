@@ -2,7 +2,13 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"log"
+	"time"
 
 	"github.com/231031/ecom-mcs-grpc/authentication/model"
 	"github.com/231031/ecom-mcs-grpc/authentication/repository"
@@ -11,21 +17,80 @@ import (
 
 var (
 	ErrInvalidCredentials = errors.New("failed to login, invalid email or password")
+	ErrSuspended          = errors.New("the account is suspended")
+	ErrSuspendAdmin       = errors.New("admin accounts cannot be suspended")
+	ErrEmailNotVerified   = errors.New("the email is not verified yet")
+	ErrInvalidToken       = errors.New("the token is invalid or expired")
+)
+
+const (
+	emailVerificationTTL = 24 * time.Hour
+	passwordResetTTL     = time.Hour
 )
 
 type Service interface {
 	CreateUser(ctx context.Context, u *model.User) (*model.User, error)
-	LoginUser(ctx context.Context, email string, password string) (*model.UserInfo, error)
-	RefreshTokenUser(ctx context.Context, refreshToken string) (*model.TokenResponse, error)
+	LoginUser(ctx context.Context, email string, password string, client model.ClientInfo) (*model.UserInfo, error)
+	RefreshTokenUser(ctx context.Context, refreshToken string, client model.ClientInfo) (*model.TokenResponse, error)
+	Logout(ctx context.Context, userID string, sessionID string) error
+	LogoutAllSessions(ctx context.Context, userID string) (int, error)
+	ListSessions(ctx context.Context, userID string) ([]model.Session, error)
+
+	EnsureAdmin(ctx context.Context, email string, password string) error
+	ListUsers(ctx context.Context, filter model.UserFilter, skip uint64, take uint64) ([]model.User, error)
+	SetUserSuspended(ctx context.Context, id string, suspended bool, reason string) (*model.User, error)
+
+	NewEmailVerification(ctx context.Context, userID string) (string, error)
+	RequestEmailVerification(ctx context.Context, email string) (*model.User, string, error)
+	VerifyEmail(ctx context.Context, token string) (*model.User, bool, error)
+	RequestPasswordReset(ctx context.Context, email string) (*model.User, string, error)
+	ResetPassword(ctx context.Context, token string, password string) error
+	UnlockUser(ctx context.Context, id string) (*model.User, error)
+
+	GetUser(ctx context.Context, id string) (*model.User, error)
+	AddRole(ctx context.Context, userID string, role int32) (*model.User, error)
+	SwitchRole(ctx context.Context, userID string, sessionID string, role int32) (*model.UserInfo, error)
+
+	ChangePassword(ctx context.Context, userID string, sessionID string, current string, password string) error
+	ChangeEmail(ctx context.Context, userID string, password string, email string) (*model.User, string, error)
+	RequestDeletion(ctx context.Context, userID string, password string) (time.Time, error)
+	DueForDeletion(ctx context.Context, limit int) ([]model.User, error)
+	IsDueForDeletion(ctx context.Context, userID string) (bool, error)
+	PurgeUser(ctx context.Context, userID string) (bool, error)
+
+	EnrollTOTP(ctx context.Context, userID string) (*model.TOTPEnrollment, error)
+	EnableTOTP(ctx context.Context, userID string, code string) error
+	DisableTOTP(ctx context.Context, userID string, code string) error
+	VerifyMFA(ctx context.Context, mfaToken string, code string, client model.ClientInfo) (*model.UserInfo, error)
+
+	StartOIDCLogin(ctx context.Context, provider string) (string, string, error)
+	CompleteOIDCLogin(ctx context.Context, provider string, code string, state string, client model.ClientInfo) (*model.UserInfo, error)
 }
 
 type authService struct {
 	repository   repository.Repository
 	tokenService TokenService
+	// seals the TOTP secrets
+	secret     string
+	totpIssuer string
+	providers  map[string]*OIDCProvider
+	// how long a deleted account waits before it is erased
+	deletionGracePeriod time.Duration
 }
 
-func NewService(r repository.Repository, s TokenService) Service {
-	return &authService{repository: r, tokenService: s}
+func NewService(r repository.Repository, s TokenService, secret string, totpIssuer string, deletionGracePeriod time.Duration, providers ...*OIDCProvider) Service {
+	byName := map[string]*OIDCProvider{}
+	for _, p := range providers {
+		byName[p.Name()] = p
+	}
+	return &authService{
+		repository:          r,
+		tokenService:        s,
+		secret:              secret,
+		totpIssuer:          totpIssuer,
+		providers:           byName,
+		deletionGracePeriod: deletionGracePeriod,
+	}
 }
 
 func (s *authService) CreateUser(ctx context.Context, u *model.User) (*model.User, error) {
@@ -37,44 +102,296 @@ func (s *authService) CreateUser(ctx context.Context, u *model.User) (*model.Use
 	}
 
 	u.Password = hashed
+	u.Roles = []model.UserRole{{Role: u.Role}}
 	if err := s.repository.CreateUser(ctx, u); err != nil {
 		return nil, err
 	}
 	return u, nil
 }
 
-func (s *authService) LoginUser(ctx context.Context, email string, password string) (*model.UserInfo, error) {
+func (s *authService) LoginUser(ctx context.Context, email string, password string, client model.ClientInfo) (*model.UserInfo, error) {
+	subjects := loginSubjects(email, client.IP)
+	if err := s.checkLoginLock(ctx, subjects); err != nil {
+		return nil, err
+	}
+
 	u, err := s.repository.GetUserByEmail(ctx, email)
 	if err != nil {
 		return nil, err
 	}
 	if u == nil {
+		if err := s.recordLoginFailure(ctx, subjects); err != nil {
+			return nil, err
+		}
 		return nil, ErrInvalidCredentials
 	}
 
-	isValid, err := s.tokenService.verifyPasswordSecure(u.Password, password)
+	isValid, rehash, err := s.tokenService.verifyPasswordSecure(u.Password, password)
 	if err != nil {
 		return nil, err
 	}
 	if !isValid {
+		if err := s.recordLoginFailure(ctx, subjects); err != nil {
+			return nil, err
+		}
 		return nil, ErrInvalidCredentials
 	}
+	if rehash {
+		s.upgradePasswordHash(ctx, u.ID, password)
+	}
+	// with 2FA the password alone is half a login, the failures of the code
+	// count until VerifyMFA clears them
+	if !u.TOTPEnabled {
+		if err := s.repository.ClearLoginFailures(ctx, emailSubject(email).key); err != nil {
+			return nil, err
+		}
+	}
+	if u.Suspended {
+		return nil, ErrSuspended
+	}
+	if !u.EmailVerified {
+		return nil, ErrEmailNotVerified
+	}
+	if u.TOTPEnabled {
+		return s.newMFAChallenge(ctx, u)
+	}
+	return s.startSession(ctx, u, client)
+}
+
+func (s *authService) RefreshTokenUser(ctx context.Context, refreshToken string, client model.ClientInfo) (*model.TokenResponse, error) {
+	tokenPair, err := s.tokenService.HandleRefreshToken(ctx, refreshToken, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return tokenPair, nil
+}
+
+func (s *authService) Logout(ctx context.Context, userID string, sessionID string) error {
+	return s.tokenService.RevokeSession(ctx, userID, sessionID)
+}
+
+func (s *authService) LogoutAllSessions(ctx context.Context, userID string) (int, error) {
+	return s.tokenService.RevokeAllSessions(ctx, userID)
+}
+
+func (s *authService) ListSessions(ctx context.Context, userID string) ([]model.Session, error) {
+	return s.tokenService.ListSessions(ctx, userID)
+}
+
+// upgradePasswordHash stores the password again with the current policy, the
+// login goes on with the old hash in place when it fails.
+func (s *authService) upgradePasswordHash(ctx context.Context, userID string, password string) {
+	hashed, err := s.tokenService.hashPassword(password)
+	if err != nil {
+		log.Println("error rehashing the password of user", userID, err)
+		return
+	}
+	if err := s.repository.UpdatePassword(ctx, userID, hashed); err != nil {
+		log.Println("error storing the rehashed password of user", userID, err)
+	}
+}
+
+// EnsureAdmin creates the admin account on start, an account already
+// registered with the email is left as it is.
+func (s *authService) EnsureAdmin(ctx context.Context, email string, password string) error {
+	u, err := s.repository.GetUserByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	if u != nil {
+		return nil
+	}
+
+	_, err = s.CreateUser(ctx, &model.User{
+		Email:         email,
+		Password:      password,
+		Role:          int32(model.ADMIN),
+		EmailVerified: true,
+	})
+	if errors.Is(err, repository.ErrEmailTaken) {
+		return nil
+	}
+	return err
+}
+
+func (s *authService) ListUsers(ctx context.Context, filter model.UserFilter, skip uint64, take uint64) ([]model.User, error) {
+	if take > 100 || (skip == 0 && take == 0) {
+		take = 100
+	}
+	return s.repository.ListUsers(ctx, filter, skip, take)
+}
 
-	userAuth := &model.UserAuth{ID: u.ID, Email: u.Email, Role: u.Role}
-	tokenPair, err := s.tokenService.GenerateNewPairToken(ctx, userAuth, "")
+func (s *authService) SetUserSuspended(ctx context.Context, id string, suspended bool, reason string) (*model.User, error) {
+	u, err := s.repository.GetUserByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if u == nil {
+		return nil, repository.ErrUserNotFound
+	}
+	if u.HasRole(int32(model.ADMIN)) {
+		return nil, ErrSuspendAdmin
+	}
 
-	return &model.UserInfo{Email: u.Email, Role: u.Role, TokenPair: *tokenPair}, nil
+	u, err = s.repository.SetUserSuspended(ctx, id, suspended, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	// a suspended user is signed out of every device right away
+	if suspended {
+		if _, err := s.tokenService.RevokeAllSessions(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	return u, nil
 }
 
-func (s *authService) RefreshTokenUser(ctx context.Context, refreshToken string) (*model.TokenResponse, error) {
-	userAuth := &model.UserAuth{}
-	tokenPair, err := s.tokenService.GenerateNewPairToken(ctx, userAuth, refreshToken)
+// UnlockUser lifts the lockout of the email of the user, the addresses the
+// failures came from stay locked.
+func (s *authService) UnlockUser(ctx context.Context, id string) (*model.User, error) {
+	u, err := s.repository.GetUserByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if u == nil {
+		return nil, repository.ErrUserNotFound
+	}
 
-	return tokenPair, nil
+	if err := s.repository.ClearLoginFailures(ctx, emailSubject(u.Email).key); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// NewEmailVerification issues the token of the verification mail sent on
+// sign up.
+func (s *authService) NewEmailVerification(ctx context.Context, userID string) (string, error) {
+	token, hash, err := newOneTimeToken()
+	if err != nil {
+		return "", err
+	}
+
+	err = s.repository.StoreOneTimeToken(ctx, emailVerificationKey(hash), userID, emailVerificationTTL)
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// RequestEmailVerification issues a new verification token. Nothing is
+// issued, and no error returned, for an unknown or verified email, so the
+// answer does not tell which emails are registered.
+func (s *authService) RequestEmailVerification(ctx context.Context, email string) (*model.User, string, error) {
+	u, err := s.repository.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, "", err
+	}
+	if u == nil || u.EmailVerified {
+		return nil, "", nil
+	}
+
+	token, err := s.NewEmailVerification(ctx, u.ID)
+	if err != nil {
+		return nil, "", err
+	}
+	return u, token, nil
+}
+
+// VerifyEmail verifies the email of sign up, or the new email of a change
+// by ChangeEmail, and tells which of the two it was.
+func (s *authService) VerifyEmail(ctx context.Context, token string) (*model.User, bool, error) {
+	userID, err := s.repository.TakeOneTimeToken(ctx, emailVerificationKey(hashToken(token)))
+	if err != nil {
+		return nil, false, err
+	}
+	if userID != "" {
+		u, err := s.repository.SetEmailVerified(ctx, userID)
+		return u, false, err
+	}
+
+	userID, email, err := s.takeEmailChange(ctx, token)
+	if err != nil {
+		return nil, false, err
+	}
+	if userID == "" {
+		return nil, false, ErrInvalidToken
+	}
+	u, err := s.repository.UpdateEmail(ctx, userID, email)
+	return u, true, err
+}
+
+// RequestPasswordReset issues a reset token, like RequestEmailVerification
+// it answers the same whether the email is registered or not.
+func (s *authService) RequestPasswordReset(ctx context.Context, email string) (*model.User, string, error) {
+	u, err := s.repository.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil, "", err
+	}
+	if u == nil || u.Suspended {
+		return nil, "", nil
+	}
+
+	token, hash, err := newOneTimeToken()
+	if err != nil {
+		return nil, "", err
+	}
+	err = s.repository.StoreOneTimeToken(ctx, passwordResetKey(hash), u.ID, passwordResetTTL)
+	if err != nil {
+		return nil, "", err
+	}
+	return u, token, nil
+}
+
+// ResetPassword sets the new password and signs the user out everywhere, the
+// old password may be what leaked.
+func (s *authService) ResetPassword(ctx context.Context, token string, password string) error {
+	userID, err := s.repository.TakeOneTimeToken(ctx, passwordResetKey(hashToken(token)))
+	if err != nil {
+		return err
+	}
+	if userID == "" {
+		return ErrInvalidToken
+	}
+
+	hashed, err := s.tokenService.hashPassword(password)
+	if err != nil {
+		return err
+	}
+	if err := s.repository.UpdatePassword(ctx, userID, hashed); err != nil {
+		return err
+	}
+
+	// the reset link went to the inbox, so the email is verified too
+	if _, err := s.repository.SetEmailVerified(ctx, userID); err != nil {
+		return err
+	}
+
+	_, err = s.tokenService.RevokeAllSessions(ctx, userID)
+	return err
+}
+
+// newOneTimeToken returns a random token for a mail link and its hash, only
+// the hash is stored.
+func newOneTimeToken() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	return token, hashToken(token), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func emailVerificationKey(hash string) string {
+	return "email_verification:" + hash
+}
+
+func passwordResetKey(hash string) string {
+	return "password_reset:" + hash
 }
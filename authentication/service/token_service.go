@@ -2,13 +2,11 @@ package service
 
 import (
 	"context"
-	"crypto/rsa"
 	"crypto/subtle"
 	"encoding/base64"
 	"errors"
 	"fmt"
-	"strconv"
-	"strings"
+	"log"
 	"time"
 
 	"github.com/231031/ecom-mcs-grpc/authentication/model"
@@ -17,87 +15,100 @@ import (
 	"github.com/golang-jwt/jwt"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
 )
 
 var (
-	ErrUnauth  = errors.New("the token is invalid")
-	ErrExpired = errors.New("the refresh token is expired")
+	ErrUnauth      = errors.New("the token is invalid")
+	ErrExpired     = errors.New("the refresh token is expired")
+	ErrTokenReused = errors.New("the refresh token was already used, the session is revoked")
 )
 
 type TokenService interface {
-	GenerateNewPairToken(ctx context.Context, user *model.UserAuth, prevToken string) (*model.TokenResponse, error)
+	StartSession(ctx context.Context, user *model.UserAuth, client model.ClientInfo) (*model.TokenResponse, error)
 	ValidateRefreshToken(refreshTokenStr string) (*model.RefreshTokenClaims, error)
-	generateIDToken(user *model.UserAuth, key *rsa.PrivateKey, exp int64) (string, error)
-	generateRefreshToken(id string, key string, exp int64) (*model.RefreshTokenData, error)
-	HandleRefreshToken(ctx context.Context, refreshToken string) (*model.TokenResponse, error)
+	generateIDToken(user *model.UserAuth, sessionID string, exp int64) (*model.AccessTokenData, error)
+	generateRefreshToken(id string, sessionID string, key string, exp int64) (*model.RefreshTokenData, error)
+	HandleRefreshToken(ctx context.Context, refreshToken string, client model.ClientInfo) (*model.TokenResponse, error)
+	ListSessions(ctx context.Context, userID string) ([]model.Session, error)
+	SwitchSessionRole(ctx context.Context, userAuth *model.UserAuth, sessionID string) (*model.TokenResponse, error)
+	RevokeSession(ctx context.Context, userID string, sessionID string) error
+	RevokeAllSessions(ctx context.Context, userID string) (int, error)
+	RevokeOtherSessions(ctx context.Context, userID string, keep string) (int, error)
 	hashPassword(password string) (string, error)
-	verifyPasswordSecure(storedHash, providedPassword string) (bool, error)
+	verifyPasswordSecure(storedHash, providedPassword string) (bool, bool, error)
 }
 
 type tokenService struct {
 	AuthRepository        repository.Repository
-	PrivateKey            *rsa.PrivateKey
-	PublicKey             *rsa.PublicKey
+	Keys                  KeyService
 	RefreshSecret         string
 	TokenIDExpirationSecs int64
 	RefreshExpirationSecs int64
+	PasswordHash          model.Argon2Params
 }
 
-func NewTokenService(repo repository.Repository, cfg *model.TokenConfig) TokenService {
+func NewTokenService(repo repository.Repository, keys KeyService, cfg *model.TokenConfig) TokenService {
 	return &tokenService{
 		AuthRepository:        repo,
-		PrivateKey:            cfg.PrivateKey,
-		PublicKey:             cfg.PublicKey,
+		Keys:                  keys,
 		RefreshSecret:         cfg.RefreshSecret,
 		TokenIDExpirationSecs: cfg.TokenIDExpirationSecs,
 		RefreshExpirationSecs: cfg.RefreshExpirationSecs,
+		PasswordHash:          cfg.PasswordHash,
 	}
 }
 
-func (s *tokenService) GenerateNewPairToken(ctx context.Context, userAuth *model.UserAuth, prevToken string) (*model.TokenResponse, error) {
-	if prevToken != "" {
-		key := fmt.Sprintf("refresh_token:%s", prevToken)
-		value, err := s.AuthRepository.GetAndDelRefreshToken(ctx, key)
-		if err != nil {
-			return nil, ErrExpired
-		}
-
-		identical := strings.Split(value, ":")
-		if len(identical) < 3 {
-			return nil, ErrExpired
-		}
-		userAuth.ID = identical[0]
-		userAuth.Email = identical[1]
+// StartSession opens a new session on login, the refresh tokens rotated from
+// the one returned here all belong to it.
+func (s *tokenService) StartSession(ctx context.Context, userAuth *model.UserAuth, client model.ClientInfo) (*model.TokenResponse, error) {
+	sessionID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
 
-		roleType, err := strconv.ParseInt(identical[2], 10, 32)
-		if err != nil {
-			fmt.Println("error converting string to int32:", err)
-			return nil, ErrUnauth
-		}
-		userAuth.Role = int32(roleType)
+	now := time.Now().UTC()
+	session := &model.Session{
+		ID:        sessionID.String(),
+		UserID:    userAuth.ID,
+		Device:    client.Device,
+		IP:        client.IP,
+		CreatedAt: now,
+		Role:      userAuth.Role,
+	}
+	return s.issueTokens(ctx, userAuth, session)
+}
 
+// issueTokens generates a new pair for the session and makes the refresh
+// token the only valid one of the session.
+func (s *tokenService) issueTokens(ctx context.Context, userAuth *model.UserAuth, session *model.Session) (*model.TokenResponse, error) {
+	newToken, err := s.generateIDToken(userAuth, session.ID, s.TokenIDExpirationSecs)
+	if err != nil {
+		return nil, err
 	}
 
-	// generate new token - login, refresh token
-	newToken, err := s.generateIDToken(userAuth, s.PrivateKey, s.TokenIDExpirationSecs)
+	newRefresh, err := s.generateRefreshToken(userAuth.ID, session.ID, s.RefreshSecret, s.RefreshExpirationSecs)
 	if err != nil {
 		return nil, err
 	}
 
-	newRefresh, err := s.generateRefreshToken(userAuth.ID, s.RefreshSecret, s.RefreshExpirationSecs)
+	session.TokenID = newRefresh.ID
+	session.AccessTokenID = newToken.ID
+	session.AccessTokenExpiresAt = newToken.ExpiresAt
+	session.LastUsedAt = time.Now().UTC()
+	err = s.AuthRepository.SaveSession(ctx, session, newRefresh.ExpiresIn)
 	if err != nil {
 		return nil, err
 	}
 
-	key := fmt.Sprintf("refresh_token:%s", newRefresh.SS)
-	val := fmt.Sprintf("%s:%s:%s", userAuth.ID, userAuth.Email, strconv.Itoa(int(userAuth.Role)))
-	err = s.AuthRepository.StoreRefreshToken(ctx, key, val, newRefresh.ExpiresIn)
+	key := fmt.Sprintf("refresh_token:%s", newRefresh.ID)
+	err = s.AuthRepository.StoreRefreshToken(ctx, key, session.ID, newRefresh.ExpiresIn)
 	if err != nil {
 		return nil, err
 	}
 
 	return &model.TokenResponse{
-		AccessToken:  newToken,
+		AccessToken:  newToken.SS,
 		RefreshToken: newRefresh.SS,
 	}, nil
 }
@@ -126,29 +137,46 @@ func (s *tokenService) ValidateRefreshToken(refreshTokenStr string) (*model.Refr
 	return claims, nil
 }
 
-func (s *tokenService) generateIDToken(user *model.UserAuth, key *rsa.PrivateKey, exp int64) (string, error) {
+func (s *tokenService) generateIDToken(user *model.UserAuth, sessionID string, exp int64) (*model.AccessTokenData, error) {
+	kid, key, err := s.Keys.SigningKey()
+	if err != nil {
+		return nil, err
+	}
+
 	curTime := time.Now()
 	tokenExp := curTime.Unix() + exp
+	tokenID, err := uuid.NewRandom()
+	if err != nil {
+		log.Println("failed to generate uuid", err)
+		return nil, err
+	}
 
 	claims := model.TokenClaims{
-		User: *user,
+		User:      *user,
+		SessionID: sessionID,
 		StandardClaims: jwt.StandardClaims{
 			IssuedAt:  curTime.Unix(),
 			ExpiresAt: tokenExp,
+			Id:        tokenID.String(),
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
 	ss, err := token.SignedString(key)
 	if err != nil {
 		fmt.Sprintln("failed to signed string of token", err)
-		return "", err
+		return nil, err
 	}
 
-	return ss, nil
+	return &model.AccessTokenData{
+		SS:        ss,
+		ID:        tokenID.String(),
+		ExpiresAt: time.Unix(tokenExp, 0),
+	}, nil
 }
 
-func (s *tokenService) generateRefreshToken(id string, key string, exp int64) (*model.RefreshTokenData, error) {
+func (s *tokenService) generateRefreshToken(id string, sessionID string, key string, exp int64) (*model.RefreshTokenData, error) {
 	curTime := time.Now()
 	tokenExp := curTime.Add(time.Duration(exp) * time.Second)
 	tokenID, err := uuid.NewRandom()
@@ -158,7 +186,8 @@ func (s *tokenService) generateRefreshToken(id string, key string, exp int64) (*
 	}
 
 	claims := model.RefreshTokenClaims{
-		ID: id,
+		ID:        id,
+		SessionID: sessionID,
 		StandardClaims: jwt.StandardClaims{
 			IssuedAt:  curTime.Unix(),
 			ExpiresAt: tokenExp.Unix(),
@@ -180,33 +209,134 @@ func (s *tokenService) generateRefreshToken(id string, key string, exp int64) (*
 	}, nil
 }
 
-func (s *tokenService) HandleRefreshToken(ctx context.Context, refreshToken string) (*model.TokenResponse, error) {
+// HandleRefreshToken rotates the refresh token of a session. Each token can be
+// used once, a token used again means it leaked and the whole session is
+// revoked, the legitimate holder included.
+func (s *tokenService) HandleRefreshToken(ctx context.Context, refreshToken string, client model.ClientInfo) (*model.TokenResponse, error) {
 	claims, err := s.ValidateRefreshToken(refreshToken)
+	if err != nil {
+		return nil, ErrUnauth
+	}
+	if claims.SessionID == "" {
+		return nil, ErrUnauth
+	}
+
+	key := fmt.Sprintf("refresh_token:%s", claims.Id)
+	sessionID, err := s.AuthRepository.GetAndDelRefreshToken(ctx, key)
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = uuid.Parse(claims.Id)
+	session, err := s.AuthRepository.GetSession(ctx, claims.SessionID)
 	if err != nil {
-		fmt.Sprintln("failed to parse claims token uuid", err)
 		return nil, err
 	}
+	if session == nil {
+		return nil, ErrExpired
+	}
+	if session.UserID != claims.ID {
+		return nil, ErrUnauth
+	}
+	if sessionID == "" {
+		log.Println("refresh token reused, revoking session", session.ID, "of user", session.UserID)
+		if err := s.AuthRepository.DeleteSessions(ctx, session.UserID, session.ID); err != nil {
+			return nil, err
+		}
+		return nil, ErrTokenReused
+	}
+	if sessionID != session.ID {
+		return nil, ErrUnauth
+	}
 
-	userAuth := &model.UserAuth{}
-	tokenPair, err := s.GenerateNewPairToken(ctx, userAuth, refreshToken)
+	u, err := s.AuthRepository.GetUserByID(ctx, session.UserID)
 	if err != nil {
 		return nil, err
 	}
+	if u == nil {
+		return nil, ErrUnauth
+	}
+	if u.Suspended {
+		return nil, ErrSuspended
+	}
+
+	if client.Device != "" {
+		session.Device = client.Device
+	}
+	if client.IP != "" {
+		session.IP = client.IP
+	}
+
+	userAuth := newUserAuth(u, sessionRole(u, session))
+	session.Role = userAuth.Role
+	return s.issueTokens(ctx, userAuth, session)
+}
+
+// SwitchSessionRole issues the session a pair acting as the role of
+// userAuth, the refresh token of the session is used up.
+func (s *tokenService) SwitchSessionRole(ctx context.Context, userAuth *model.UserAuth, sessionID string) (*model.TokenResponse, error) {
+	session, err := s.AuthRepository.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil || session.UserID != userAuth.ID {
+		return nil, repository.ErrSessionNotFound
+	}
+
+	key := fmt.Sprintf("refresh_token:%s", session.TokenID)
+	if _, err := s.AuthRepository.GetAndDelRefreshToken(ctx, key); err != nil {
+		return nil, err
+	}
+	session.Role = userAuth.Role
+	return s.issueTokens(ctx, userAuth, session)
+}
 
-	return tokenPair, nil
+func (s *tokenService) ListSessions(ctx context.Context, userID string) ([]model.Session, error) {
+	return s.AuthRepository.ListSessions(ctx, userID)
+}
+
+func (s *tokenService) RevokeSession(ctx context.Context, userID string, sessionID string) error {
+	session, err := s.AuthRepository.GetSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if session == nil || session.UserID != userID {
+		return repository.ErrSessionNotFound
+	}
+	return s.AuthRepository.DeleteSessions(ctx, userID, sessionID)
+}
+
+// RevokeAllSessions signs the user out everywhere and returns how many
+// sessions were revoked.
+func (s *tokenService) RevokeAllSessions(ctx context.Context, userID string) (int, error) {
+	return s.RevokeOtherSessions(ctx, userID, "")
+}
+
+// RevokeOtherSessions signs the user out of every session but keep, the one
+// the request came from.
+func (s *tokenService) RevokeOtherSessions(ctx context.Context, userID string, keep string) (int, error) {
+	sessions, err := s.AuthRepository.ListSessions(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	ids := []string{}
+	for _, session := range sessions {
+		if session.ID != keep {
+			ids = append(ids, session.ID)
+		}
+	}
+	if err := s.AuthRepository.DeleteSessions(ctx, userID, ids...); err != nil {
+		return 0, err
+	}
+	return len(ids), nil
 }
 
 func (s *tokenService) hashPassword(password string) (string, error) {
 	cfg := &model.Argon2Configuration{
-		TimeCost:   2,
-		MemoryCost: 64 * 1024,
-		Threads:    4,
-		KeyLength:  32,
+		TimeCost:   s.PasswordHash.TimeCost,
+		MemoryCost: s.PasswordHash.MemoryCost,
+		Threads:    s.PasswordHash.Threads,
+		KeyLength:  s.PasswordHash.KeyLength,
 	}
 
 	salt, err := utils.GenerateSalt(cfg.KeyLength)
@@ -233,12 +363,27 @@ func (s *tokenService) hashPassword(password string) (string, error) {
 	return encodedHash, nil
 }
 
-func (s *tokenService) verifyPasswordSecure(storedHash, providedPassword string) (bool, error) {
+// verifyPasswordSecure checks the password against the stored hash, and
+// tells whether the hash should be made again with the current policy: it is
+// the bcrypt hash of an imported user, or an argon2 hash of a lower cost.
+func (s *tokenService) verifyPasswordSecure(storedHash, providedPassword string) (bool, bool, error) {
+	if utils.IsBcryptHash(storedHash) {
+		err := bcrypt.CompareHashAndPassword([]byte(storedHash), []byte(providedPassword))
+		// bcrypt only hashed 72 bytes, a longer password is not the one stored
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) || errors.Is(err, bcrypt.ErrPasswordTooLong) {
+			return false, false, nil
+		}
+		if err != nil {
+			return false, false, err
+		}
+		return true, true, nil
+	}
+
 	// Parse stored hash parameters
 	config, err := utils.ParseArgon2Hash(storedHash)
 	if err != nil {
-		fmt.Sprintln("error parsing stored hash:", err)
-		return false, err
+		log.Println("error parsing stored hash:", err)
+		return false, false, err
 	}
 
 	// Generate hash using identical parameters
@@ -253,5 +398,14 @@ func (s *tokenService) verifyPasswordSecure(storedHash, providedPassword string)
 
 	// Perform constant-time comparison to prevent timing attacks
 	match := subtle.ConstantTimeCompare(config.HashRaw, computedHash) == 1
-	return match, nil
+	return match, match && s.weakerThanPolicy(config), nil
+}
+
+// weakerThanPolicy tells whether a hash was made with an older argon2 or a
+// lower cost than the one of the policy.
+func (s *tokenService) weakerThanPolicy(config *model.Argon2Configuration) bool {
+	return config.Version < argon2.Version ||
+		config.TimeCost < s.PasswordHash.TimeCost ||
+		config.MemoryCost < s.PasswordHash.MemoryCost ||
+		uint32(len(config.HashRaw)) < s.PasswordHash.KeyLength
 }
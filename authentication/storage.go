@@ -11,7 +11,7 @@ import (
 )
 
 func ConnectPostgres(url string) (*gorm.DB, error) {
-	db, err := gorm.Open(postgres.Open(url), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(url), &gorm.Config{TranslateError: true})
 	if err != nil {
 		log.Fatalln(err)
 	}
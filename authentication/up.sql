@@ -10,12 +10,70 @@ CREATE TABLE IF NOT EXISTS users (
     email VARCHAR(127) UNIQUE NOT NULL,
     password VARCHAR(255) NOT NULL,
     role INT NOT NULL,
+    email_verified BOOLEAN NOT NULL DEFAULT FALSE,
+    suspended BOOLEAN NOT NULL DEFAULT FALSE,
+    suspended_reason TEXT NOT NULL DEFAULT '',
+    totp_secret TEXT NOT NULL DEFAULT '',
+    totp_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    deletion_requested_at TIMESTAMP WITH TIME ZONE,
     created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL
 );
 
+CREATE INDEX IF NOT EXISTS users_deletion_requested_at_idx ON users (deletion_requested_at)
+WHERE deletion_requested_at IS NOT NULL;
+
 CREATE TRIGGER update_users_updated_at
 BEFORE UPDATE ON users
 FOR EACH ROW
 EXECUTE PROCEDURE set_updated_at();
 
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id VARCHAR(27) NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role INT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    PRIMARY KEY (user_id, role)
+);
+
+-- the users of before hold the role they signed up with
+INSERT INTO user_roles (user_id, role)
+SELECT id, role FROM users
+ON CONFLICT DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    user_id VARCHAR(27) NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    PRIMARY KEY (user_id, code_hash)
+);
+
+CREATE TABLE IF NOT EXISTS external_identities (
+    provider VARCHAR(64) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    user_id VARCHAR(27) NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    email VARCHAR(127) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    PRIMARY KEY (provider, subject)
+);
+
+CREATE INDEX IF NOT EXISTS external_identities_user_id_idx ON external_identities (user_id);
+
+CREATE TABLE IF NOT EXISTS signing_keys (
+    id VARCHAR(36) PRIMARY KEY,
+    private_key TEXT NOT NULL,
+    active_from TIMESTAMP WITH TIME ZONE NOT NULL,
+    retire_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    id VARCHAR(27) PRIMARY KEY,
+    actor_id VARCHAR(27) NOT NULL,
+    actor_email VARCHAR(127) NOT NULL,
+    method VARCHAR(255) NOT NULL,
+    request TEXT NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_actor_id_idx ON audit_log (actor_id, created_at);
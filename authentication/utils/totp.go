@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TOTP of RFC 6238 with the parameters every authenticator app supports,
+// HMAC-SHA1, 6 digits and 30 second steps.
+const (
+	TOTPDigits = 6
+	TOTPPeriod = 30 * time.Second
+
+	// the steps before and after the current one a code is still accepted
+	// for, the clock of the phone may drift
+	totpSkew      = 1
+	totpSecretLen = 20
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateTOTPSecret returns a random secret, base32 encoded as the apps
+// expect it.
+func GenerateTOTPSecret() (string, error) {
+	b := make([]byte, totpSecretLen)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return totpEncoding.EncodeToString(b), nil
+}
+
+// TOTPCode returns the code of the secret at the step.
+func TOTPCode(secret string, step uint64) (string, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", err
+	}
+
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, step)
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", TOTPDigits, value%1_000_000), nil
+}
+
+// ValidateTOTP checks the code at t and returns the step it was made for, so
+// the caller can refuse a code that was already used.
+func ValidateTOTP(secret string, code string, t time.Time) (uint64, bool, error) {
+	if !IsTOTPCode(code) {
+		return 0, false, nil
+	}
+
+	current := uint64(t.Unix()) / uint64(TOTPPeriod/time.Second)
+	for i := -totpSkew; i <= totpSkew; i++ {
+		step := current + uint64(i)
+		expected, err := TOTPCode(secret, step)
+		if err != nil {
+			return 0, false, err
+		}
+		if hmac.Equal([]byte(expected), []byte(code)) {
+			return step, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// IsTOTPCode tells a code of the app from a recovery code.
+func IsTOTPCode(code string) bool {
+	if len(code) != TOTPDigits {
+		return false
+	}
+	_, err := strconv.ParseUint(code, 10, 32)
+	return err == nil
+}
+
+// TOTPURI returns the otpauth URI apps scan, as a QR code, to add the account.
+func TOTPURI(issuer string, account string, secret string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", strconv.Itoa(TOTPDigits))
+	query.Set("period", strconv.Itoa(int(TOTPPeriod/time.Second)))
+
+	label := url.PathEscape(issuer + ":" + account)
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
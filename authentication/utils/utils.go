@@ -1,7 +1,10 @@
 package utils
 
 import (
+	"crypto/aes"
+	"crypto/cipher"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
 	"errors"
 	"fmt"
@@ -24,13 +27,17 @@ func ParseArgon2Hash(encodedHash string) (*model.Argon2Configuration, error) {
 	}
 
 	// Extract version information
-	var version int
-	fmt.Sscanf(components[2], "v=%d", &version)
+	config := &model.Argon2Configuration{}
+	if _, err := fmt.Sscanf(components[2], "v=%d", &config.Version); err != nil {
+		return nil, errors.New("invalid hash version")
+	}
 
 	// Parse configuration parameters
-	config := &model.Argon2Configuration{}
-	fmt.Sscanf(components[3], "m=%d,t=%d,p=%d",
+	n, _ := fmt.Sscanf(components[3], "m=%d,t=%d,p=%d",
 		&config.MemoryCost, &config.TimeCost, &config.Threads)
+	if n != 3 || config.TimeCost == 0 || config.Threads == 0 {
+		return nil, errors.New("invalid hash parameters")
+	}
 
 	// Decode salt component
 	salt, err := base64.RawStdEncoding.DecodeString(components[4])
@@ -52,6 +59,17 @@ func ParseArgon2Hash(encodedHash string) (*model.Argon2Configuration, error) {
 	return config, nil
 }
 
+// IsBcryptHash tells the bcrypt hashes of imported users from the argon2
+// ones.
+func IsBcryptHash(encodedHash string) bool {
+	for _, prefix := range []string{"$2a$", "$2b$", "$2y$"} {
+		if strings.HasPrefix(encodedHash, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // generateSalt creates a cryptographically secure random salt
 func GenerateSalt(saltLength uint32) ([]byte, error) {
 	salt := make([]byte, saltLength)
@@ -62,11 +80,54 @@ func GenerateSalt(saltLength uint32) ([]byte, error) {
 	return salt, nil
 }
 
+// SealKey encrypts a private key with the secret, so the keys stored in the
+// database are of no use without it.
+func SealKey(key []byte, secret string) (string, error) {
+	gcm, err := newKeyCipher(secret)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := gcm.Seal(nonce, nonce, key, nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func OpenKey(sealed string, secret string) ([]byte, error) {
+	gcm, err := newKeyCipher(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("sealed key is too short")
+	}
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
+
+func newKeyCipher(secret string) (cipher.AEAD, error) {
+	sum := sha256.Sum256([]byte(secret))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
 func ConfigGenerateKey(cfg *model.Config) *model.TokenConfig {
 	tokenCfg := &model.TokenConfig{
 		TokenIDExpirationSecs: 10 * 60,
 		RefreshExpirationSecs: 48 * 3600,
 		RefreshSecret:         cfg.SecretKey,
+		PasswordHash:          cfg.Argon2,
 	}
 
 	priv, err := ioutil.ReadFile(cfg.FilePriPath)
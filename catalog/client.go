@@ -3,6 +3,8 @@ package catalog
 import (
 	"context"
 	"errors"
+	"log"
+	"time"
 
 	"github.com/231031/ecom-mcs-grpc/catalog/pb"
 	"google.golang.org/grpc"
@@ -37,25 +39,31 @@ func (c *Client) Close() {
 	c.conn.Close()
 }
 
-func (c *Client) PostProduct(ctx context.Context, name, description, seller_id string, price float64, quantity uint32) (*Product, error) {
+func (c *Client) PostProduct(ctx context.Context, name, description, seller_id string, price float64, quantity, lowStockThreshold uint32) (*Product, error) {
 	r, err := c.service.PostProduct(ctx, &pb.PostProductRequest{
-		Name:        name,
-		Description: description,
-		Price:       price,
-		Quantity:    quantity,
-		SellerId:    seller_id,
+		Name:              name,
+		Description:       description,
+		Price:             price,
+		Quantity:          quantity,
+		SellerId:          seller_id,
+		LowStockThreshold: lowStockThreshold,
 	})
 	if err != nil {
 		return nil, err
 	}
 
 	return &Product{
-		ID:          r.Product.Id,
-		Name:        r.Product.Name,
-		Description: r.Product.Description,
-		Price:       r.Product.Price,
-		Quantity:    r.Product.Quantity,
-		SellerID:    r.Product.SellerId,
+		ID:                r.Product.Id,
+		Name:              r.Product.Name,
+		Description:       r.Product.Description,
+		Price:             r.Product.Price,
+		Quantity:          r.Product.Quantity,
+		LowStockThreshold: r.Product.LowStockThreshold,
+		AverageRating:     r.Product.AverageRating,
+		ReviewCount:       r.Product.ReviewCount,
+		SellerID:          r.Product.SellerId,
+		Unpublished:       r.Product.Unpublished,
+		ModerationNote:    r.Product.ModerationNote,
 	}, nil
 }
 
@@ -68,22 +76,30 @@ func (c *Client) GetProduct(ctx context.Context, id string) (*Product, error) {
 	}
 
 	return &Product{
-		ID:          r.Product.Id,
-		Name:        r.Product.Name,
-		Description: r.Product.Description,
-		Price:       r.Product.Price,
-		Quantity:    r.Product.Quantity,
+		ID:                r.Product.Id,
+		Name:              r.Product.Name,
+		Description:       r.Product.Description,
+		Price:             r.Product.Price,
+		Quantity:          r.Product.Quantity,
+		LowStockThreshold: r.Product.LowStockThreshold,
+		AverageRating:     r.Product.AverageRating,
+		ReviewCount:       r.Product.ReviewCount,
+		SellerID:          r.Product.SellerId,
+		Unpublished:       r.Product.Unpublished,
+		ModerationNote:    r.Product.ModerationNote,
 	}, nil
 }
 
-func (c *Client) GetProducts(ctx context.Context, skip uint64, take uint64, ids []string, query string) ([]Product, error) {
+func (c *Client) GetProducts(ctx context.Context, skip uint64, take uint64, ids []string, query string, filter ProductFilter) ([]Product, error) {
 	r, err := c.service.GetProducts(
 		ctx,
 		&pb.GetProductsRequest{
-			Skip:  skip,
-			Take:  take,
-			Query: query,
-			Ids:   ids,
+			Skip:         skip,
+			Take:         take,
+			Query:        query,
+			Ids:          ids,
+			MinRating:    filter.MinRating,
+			SortByRating: filter.SortByRating,
 		},
 	)
 	if err != nil {
@@ -93,17 +109,35 @@ func (c *Client) GetProducts(ctx context.Context, skip uint64, take uint64, ids
 	products := []Product{}
 	for _, p := range r.Products {
 		products = append(products, Product{
-			ID:          p.Id,
-			Description: p.Description,
-			Name:        p.Name,
-			Price:       p.Price,
-			Quantity:    p.Quantity,
+			ID:                p.Id,
+			Description:       p.Description,
+			Name:              p.Name,
+			Price:             p.Price,
+			Quantity:          p.Quantity,
+			LowStockThreshold: p.LowStockThreshold,
+			AverageRating:     p.AverageRating,
+			ReviewCount:       p.ReviewCount,
+			SellerID:          p.SellerId,
+			Unpublished:       p.Unpublished,
+			ModerationNote:    p.ModerationNote,
 		})
 	}
 
 	return products, nil
 }
 
+func (c *Client) UpdateRating(ctx context.Context, productID string, averageRating float64, reviewCount uint32) error {
+	_, err := c.service.UpdateRating(
+		ctx,
+		&pb.UpdateRatingRequest{
+			ProductId:     productID,
+			AverageRating: averageRating,
+			ReviewCount:   reviewCount,
+		},
+	)
+	return err
+}
+
 func (c *Client) UpdateQuantity(ctx context.Context, ids []string, quantity []uint32) ([]string, error) {
 	if len(ids) == 0 || len(quantity) == 0 {
 		return nil, ErrNotHaveProductsInfo
@@ -123,23 +157,256 @@ func (c *Client) UpdateQuantity(ctx context.Context, ids []string, quantity []ui
 	return resp.Ids, nil
 }
 
-func (c *Client) UpdateProduct(ctx context.Context, id, name, description string, price float64, quantity uint32) (*Product, error) {
+func (c *Client) UpdateProduct(ctx context.Context, id, name, description string, price float64, quantity, lowStockThreshold uint32) (*Product, error) {
 	p, err := c.service.UpdateProduct(ctx, &pb.Product{
-		Id:          id,
-		Name:        name,
-		Price:       price,
-		Description: description,
-		Quantity:    quantity,
+		Id:                id,
+		Name:              name,
+		Price:             price,
+		Description:       description,
+		Quantity:          quantity,
+		LowStockThreshold: lowStockThreshold,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Product{
+		ID:                p.Id,
+		Name:              p.Name,
+		Description:       p.Description,
+		Price:             p.Price,
+		Quantity:          p.Quantity,
+		LowStockThreshold: p.LowStockThreshold,
+	}, nil
+}
+
+func (c *Client) Restock(ctx context.Context, productID string, quantity uint32, reference string, note string) (*StockLevel, error) {
+	r, err := c.service.Restock(ctx, &pb.RestockRequest{
+		ProductId: productID,
+		Quantity:  quantity,
+		Reference: reference,
+		Note:      note,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stockLevelFromProto(r.StockLevel), nil
+}
+
+func (c *Client) AdjustStock(ctx context.Context, productID string, delta int32, note string) (*StockLevel, error) {
+	r, err := c.service.AdjustStock(ctx, &pb.AdjustStockRequest{
+		ProductId: productID,
+		Delta:     delta,
+		Note:      note,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stockLevelFromProto(r.StockLevel), nil
+}
+
+func (c *Client) ReserveStock(ctx context.Context, items []ReservationItem, ttl time.Duration) (*Reservation, error) {
+	itemsProto := []*pb.Reservation_Item{}
+	for _, it := range items {
+		itemsProto = append(itemsProto, &pb.Reservation_Item{
+			ProductId: it.ProductID,
+			Quantity:  it.Quantity,
+		})
+	}
+
+	r, err := c.service.ReserveStock(ctx, &pb.ReserveStockRequest{
+		Items:      itemsProto,
+		TtlSeconds: uint32(ttl.Seconds()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reservationFromProto(r.Reservation), nil
+}
+
+func (c *Client) CommitReservation(ctx context.Context, id, reference string) (*Reservation, error) {
+	r, err := c.service.CommitReservation(ctx, &pb.CommitReservationRequest{
+		Id:        id,
+		Reference: reference,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reservationFromProto(r.Reservation), nil
+}
+
+func (c *Client) ReleaseReservation(ctx context.Context, id string) (*Reservation, error) {
+	r, err := c.service.ReleaseReservation(ctx, &pb.ReleaseReservationRequest{
+		Id: id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reservationFromProto(r.Reservation), nil
+}
+
+func (c *Client) GetStockMovements(ctx context.Context, productID string, skip uint64, take uint64) ([]StockMovement, error) {
+	r, err := c.service.GetStockMovements(ctx, &pb.GetStockMovementsRequest{
+		ProductId: productID,
+		Skip:      skip,
+		Take:      take,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	movements := []StockMovement{}
+	for _, m := range r.Movements {
+		movement := StockMovement{
+			ID:            m.Id,
+			ProductID:     m.ProductId,
+			Kind:          m.Kind,
+			OnHandDelta:   m.OnHandDelta,
+			ReservedDelta: m.ReservedDelta,
+			ReservationID: m.ReservationId,
+			Reference:     m.Reference,
+			Note:          m.Note,
+		}
+		if err := movement.CreatedAt.UnmarshalBinary(m.CreatedAt); err != nil {
+			log.Println("error unmarshalling timestamp", err)
+		}
+		movements = append(movements, movement)
+	}
+
+	return movements, nil
+}
+
+func stockLevelFromProto(l *pb.StockLevel) *StockLevel {
+	return &StockLevel{
+		ProductID: l.ProductId,
+		OnHand:    l.OnHand,
+		Reserved:  l.Reserved,
+	}
+}
+
+func reservationFromProto(rp *pb.Reservation) *Reservation {
+	r := &Reservation{
+		ID:        rp.Id,
+		Status:    rp.Status,
+		Reference: rp.Reference,
+		Items:     []ReservationItem{},
+	}
+	for _, it := range rp.Items {
+		r.Items = append(r.Items, ReservationItem{
+			ProductID: it.ProductId,
+			Quantity:  it.Quantity,
+		})
+	}
+
+	if err := r.ExpiresAt.UnmarshalBinary(rp.ExpiresAt); err != nil {
+		log.Println("error unmarshalling timestamp", err)
+	}
+	if err := r.CreatedAt.UnmarshalBinary(rp.CreatedAt); err != nil {
+		log.Println("error unmarshalling timestamp", err)
+	}
+	if err := r.UpdatedAt.UnmarshalBinary(rp.UpdatedAt); err != nil {
+		log.Println("error unmarshalling timestamp", err)
+	}
+
+	return r
+}
+
+func (c *Client) SubscribeBackInStock(ctx context.Context, productID, accountID string) error {
+	_, err := c.service.SubscribeBackInStock(ctx, &pb.StockSubscriptionRequest{
+		ProductId: productID,
+		AccountId: accountID,
+	})
+	return err
+}
+
+func (c *Client) UnsubscribeBackInStock(ctx context.Context, productID, accountID string) error {
+	_, err := c.service.UnsubscribeBackInStock(ctx, &pb.StockSubscriptionRequest{
+		ProductId: productID,
+		AccountId: accountID,
+	})
+	return err
+}
+
+func (c *Client) GetStockNotifications(ctx context.Context, recipientID string, unreadOnly bool, skip uint64, take uint64) ([]StockNotification, error) {
+	r, err := c.service.GetStockNotifications(ctx, &pb.GetStockNotificationsRequest{
+		RecipientId: recipientID,
+		UnreadOnly:  unreadOnly,
+		Skip:        skip,
+		Take:        take,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	notifications := []StockNotification{}
+	for _, n := range r.Notifications {
+		notification := StockNotification{
+			ID:          n.Id,
+			RecipientID: n.RecipientId,
+			ProductID:   n.ProductId,
+			Kind:        n.Kind,
+			Quantity:    n.Quantity,
+			Threshold:   n.Threshold,
+		}
+		if err := notification.CreatedAt.UnmarshalBinary(n.CreatedAt); err != nil {
+			log.Println("error unmarshalling timestamp", err)
+		}
+		if len(n.ReadAt) > 0 {
+			readAt := time.Time{}
+			if err := readAt.UnmarshalBinary(n.ReadAt); err != nil {
+				log.Println("error unmarshalling timestamp", err)
+			}
+			notification.ReadAt = &readAt
+		}
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, nil
+}
+
+func (c *Client) MarkStockNotificationsRead(ctx context.Context, recipientID string, ids []string) ([]string, error) {
+	r, err := c.service.MarkStockNotificationsRead(ctx, &pb.MarkStockNotificationsReadRequest{
+		RecipientId: recipientID,
+		Ids:         ids,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Ids, nil
+}
+
+func (c *Client) EraseAccount(ctx context.Context, accountID string) error {
+	_, err := c.service.EraseAccount(ctx, &pb.EraseAccountRequest{AccountId: accountID})
+	return err
+}
+
+func (c *Client) ModerateProduct(ctx context.Context, productID string, unpublished bool, note string) (*Product, error) {
+	p, err := c.service.ModerateProduct(ctx, &pb.ModerateProductRequest{
+		ProductId:   productID,
+		Unpublished: unpublished,
+		Note:        note,
 	})
 	if err != nil {
 		return nil, err
 	}
 
 	return &Product{
-		ID:          p.Id,
-		Name:        p.Name,
-		Description: p.Description,
-		Price:       p.Price,
-		Quantity:    p.Quantity,
+		ID:                p.Id,
+		Name:              p.Name,
+		Description:       p.Description,
+		Price:             p.Price,
+		Quantity:          p.Quantity,
+		LowStockThreshold: p.LowStockThreshold,
+		AverageRating:     p.AverageRating,
+		ReviewCount:       p.ReviewCount,
+		SellerID:          p.SellerId,
+		Unpublished:       p.Unpublished,
+		ModerationNote:    p.ModerationNote,
 	}, nil
 }
@@ -1,10 +1,13 @@
 package main
 
 import (
+	"context"
 	"log"
 	"time"
 
 	"github.com/231031/ecom-mcs-grpc/catalog"
+	"github.com/231031/ecom-mcs-grpc/pkg/audit"
+	"github.com/231031/ecom-mcs-grpc/pkg/auth"
 	"github.com/kelseyhightower/envconfig"
 	"github.com/tinrab/retry"
 )
@@ -14,6 +17,11 @@ type Config struct {
 	ElasticUsername string `envconfig:"ELASTIC_USERNAME"`
 	ElasticPassword string `envconfig:"ELASTIC_PASSWORD"`
 	AccountURL      string `envconfig:"ACCOUNT_SERVICE_URL"`
+	InventoryURL    string `envconfig:"INVENTORY_DATABASE_URL"`
+
+	PublicKeyPath      string `envconfig:"FILE_PUB_PATH"`
+	JWKSURL            string `envconfig:"JWKS_URL"`
+	InternalAuthSecret string `envconfig:"INTERNAL_AUTH_SECRET"`
 }
 
 func main() {
@@ -33,8 +41,41 @@ func main() {
 		return nil
 	})
 	defer r.Close()
+
+	var inv catalog.InventoryRepository
+	retry.ForeverSleep(2*time.Second, func(_ int) (err error) {
+		inv, err = catalog.NewPostgresInventoryRepository(cfg.InventoryURL)
+		if err != nil {
+			log.Println(err)
+		}
+		return
+	})
+	defer inv.Close()
+
+	idempotencyStore, err := catalog.NewElasticIdempotencyStore(cfg.DatabaseURl, cfg.ElasticUsername, cfg.ElasticPassword)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer idempotencyStore.Close()
+
+	auditStore, err := audit.NewPostgresStore(cfg.InventoryURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer auditStore.Close()
 	log.Println("Listening on port")
 
-	s := catalog.NewService(r)
-	log.Fatal(catalog.ListenGRPC(s, 50002, cfg.AccountURL))
+	keys, err := auth.NewKeySource(cfg.JWKSURL, cfg.PublicKeyPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	authn, err := auth.NewAuthenticator("catalog", keys, cfg.InternalAuthSecret)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	s := catalog.NewService(r, inv)
+	go catalog.RunStockBackfill(context.Background(), s, 30*time.Second)
+	go catalog.RunReservationSweeper(context.Background(), s, 30*time.Second)
+	log.Fatal(catalog.ListenGRPC(s, authn, idempotencyStore, auditStore, 50002, cfg.AccountURL))
 }
@@ -0,0 +1,180 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/231031/ecom-mcs-grpc/pkg/idempotency"
+	"github.com/elastic/go-elasticsearch/v8"
+)
+
+const idempotencyIndex = "idempotency_keys"
+
+var ErrPutIdempotencyKey = errors.New("failed to put idempotency key")
+
+// elasticIdempotencyStore keeps idempotency records next to the products, one
+// document per key, so catalog needs no other database for them.
+type elasticIdempotencyStore struct {
+	client *elasticsearch.Client
+}
+
+func NewElasticIdempotencyStore(url, username, password string) (idempotency.Store, error) {
+	client, err := elasticsearch.NewClient(elasticsearch.Config{
+		Addresses: []string{
+			url,
+		},
+		Username: username,
+		Password: password,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &elasticIdempotencyStore{client: client}, nil
+}
+
+func (s *elasticIdempotencyStore) Close() {
+}
+
+func (s *elasticIdempotencyStore) Reserve(ctx context.Context, key, requestHash string, lock time.Duration) (*idempotency.Record, error) {
+	docJson, err := json.Marshal(idempotencyDocument{
+		RequestHash: requestHash,
+		ExpiresAt:   time.Now().UTC().Add(lock),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// create fails with a conflict when the key is already held
+	resp, err := s.client.Create(
+		idempotencyIndex,
+		key,
+		bytes.NewReader(docJson),
+		s.client.Create.WithContext(ctx),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusCreated {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusConflict {
+		log.Println(resp.String())
+		return nil, ErrPutIdempotencyKey
+	}
+
+	stored, err := s.get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if !stored.Found {
+		return s.Reserve(ctx, key, requestHash, lock)
+	}
+
+	if stored.Source.ExpiresAt.Before(time.Now().UTC()) {
+		// take the expired key over, unless another call got there first
+		resp, err := s.client.Index(
+			idempotencyIndex,
+			bytes.NewReader(docJson),
+			s.client.Index.WithDocumentID(key),
+			s.client.Index.WithIfSeqNo(stored.SeqNo),
+			s.client.Index.WithIfPrimaryTerm(stored.PrimaryTerm),
+			s.client.Index.WithContext(ctx),
+		)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode == http.StatusOK {
+			return nil, nil
+		}
+		return &idempotency.Record{RequestHash: requestHash}, nil
+	}
+
+	return &idempotency.Record{
+		RequestHash: stored.Source.RequestHash,
+		Response:    stored.Source.Response,
+	}, nil
+}
+
+func (s *elasticIdempotencyStore) Complete(ctx context.Context, key string, response []byte, ttl time.Duration) error {
+	stored, err := s.get(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	docJson, err := json.Marshal(idempotencyDocument{
+		RequestHash: stored.Source.RequestHash,
+		Response:    response,
+		ExpiresAt:   time.Now().UTC().Add(ttl),
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Index(
+		idempotencyIndex,
+		bytes.NewReader(docJson),
+		s.client.Index.WithDocumentID(key),
+		s.client.Index.WithContext(ctx),
+	)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.IsError() {
+		log.Println(resp.String())
+		return ErrPutIdempotencyKey
+	}
+	return nil
+}
+
+func (s *elasticIdempotencyStore) Release(ctx context.Context, key string) error {
+	resp, err := s.client.Delete(
+		idempotencyIndex,
+		key,
+		s.client.Delete.WithContext(ctx),
+	)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+func (s *elasticIdempotencyStore) get(ctx context.Context, key string) (*idempotencyResp, error) {
+	resp, err := s.client.Get(
+		idempotencyIndex,
+		key,
+		s.client.Get.WithContext(ctx),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := &idempotencyResp{}
+	if resp.StatusCode == http.StatusNotFound {
+		return stored, nil
+	}
+	if err := json.Unmarshal(body, stored); err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
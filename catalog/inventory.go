@@ -0,0 +1,351 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/segmentio/ksuid"
+)
+
+const (
+	MovementRestock     = 0
+	MovementSale        = 1
+	MovementReservation = 2
+	MovementRelease     = 3
+	MovementAdjustment  = 4
+)
+
+const (
+	ReservationActive    = 0
+	ReservationCommitted = 1
+	ReservationReleased  = 2
+	ReservationExpired   = 3
+)
+
+const (
+	DefaultReservationTTL = 15 * time.Minute
+	MaxReservationTTL     = 2 * time.Hour
+
+	// products read from the index at a time by the stock backfill
+	backfillBatch = 100
+)
+
+var (
+	ErrOutOfStock            = errors.New("not enough stock")
+	ErrInvalidStockQuantity  = errors.New("invalid stock quantity")
+	ErrReservationNotFound   = errors.New("reservation not found")
+	ErrReservationNotActive  = errors.New("reservation is no longer active")
+	ErrInvalidReservationTTL = errors.New("invalid reservation ttl")
+	ErrEmptyReservation      = errors.New("reservation has no items")
+)
+
+// StockLevel is the inventory balance of a product. Available is what can
+// still be sold and is the quantity mirrored on the product document.
+type StockLevel struct {
+	ProductID string `json:"product_id"`
+	OnHand    uint32 `json:"on_hand"`
+	Reserved  uint32 `json:"reserved"`
+}
+
+func (l StockLevel) Available() uint32 {
+	return l.OnHand - l.Reserved
+}
+
+// StockMovement is an entry of the append-only inventory ledger.
+type StockMovement struct {
+	ID            int64     `json:"id"`
+	ProductID     string    `json:"product_id"`
+	Kind          int32     `json:"kind"`
+	OnHandDelta   int32     `json:"on_hand_delta"`
+	ReservedDelta int32     `json:"reserved_delta"`
+	ReservationID string    `json:"reservation_id"`
+	Reference     string    `json:"reference"`
+	Note          string    `json:"note"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type ReservationItem struct {
+	ProductID string `json:"product_id"`
+	Quantity  uint32 `json:"quantity"`
+}
+
+// Reservation holds stock for a checkout until it is committed as a sale,
+// released, or expires.
+type Reservation struct {
+	ID        string            `json:"id"`
+	Status    int32             `json:"status"`
+	Reference string            `json:"reference"`
+	Items     []ReservationItem `json:"items"`
+	ExpiresAt time.Time         `json:"expires_at"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// Restock puts items back on hand, a restock with the reference of one
+// already applied to the product changes nothing.
+func (s *catalogService) Restock(ctx context.Context, productID string, quantity uint32, reference string, note string) (*StockLevel, error) {
+	if quantity == 0 {
+		return nil, ErrInvalidStockQuantity
+	}
+
+	level, err := s.inventory.ApplyMovement(ctx, StockMovement{
+		ProductID:   productID,
+		Kind:        MovementRestock,
+		OnHandDelta: int32(quantity),
+		Reference:   reference,
+		Note:        note,
+		CreatedAt:   time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.syncQuantity(ctx, []StockLevel{*level})
+	return level, nil
+}
+
+func (s *catalogService) AdjustStock(ctx context.Context, productID string, delta int32, note string) (*StockLevel, error) {
+	if delta == 0 {
+		return nil, ErrInvalidStockQuantity
+	}
+
+	level, err := s.inventory.ApplyMovement(ctx, StockMovement{
+		ProductID:   productID,
+		Kind:        MovementAdjustment,
+		OnHandDelta: delta,
+		Note:        note,
+		CreatedAt:   time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.syncQuantity(ctx, []StockLevel{*level})
+	return level, nil
+}
+
+func (s *catalogService) ReserveStock(ctx context.Context, items []ReservationItem, ttl time.Duration) (*Reservation, error) {
+	if ttl == 0 {
+		ttl = DefaultReservationTTL
+	}
+	if ttl < 0 || ttl > MaxReservationTTL {
+		return nil, ErrInvalidReservationTTL
+	}
+
+	merged, err := mergeReservationItems(items)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	r := &Reservation{
+		ID:        ksuid.New().String(),
+		Status:    ReservationActive,
+		Items:     merged,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	levels, err := s.inventory.PutReservation(ctx, *r)
+	if err != nil {
+		return nil, err
+	}
+
+	s.syncQuantity(ctx, levels)
+	return r, nil
+}
+
+func (s *catalogService) CommitReservation(ctx context.Context, id, reference string) (*Reservation, error) {
+	r, levels, err := s.inventory.CloseReservation(ctx, id, ReservationCommitted, reference, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	s.syncQuantity(ctx, levels)
+	return r, nil
+}
+
+func (s *catalogService) ReleaseReservation(ctx context.Context, id string) (*Reservation, error) {
+	r, levels, err := s.inventory.CloseReservation(ctx, id, ReservationReleased, "", time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	s.syncQuantity(ctx, levels)
+	return r, nil
+}
+
+// ReleaseExpiredReservations gives the stock of lapsed reservations back and
+// returns how many were released.
+func (s *catalogService) ReleaseExpiredReservations(ctx context.Context) (int, error) {
+	now := time.Now().UTC()
+	ids, err := s.inventory.GetExpiredReservationIDs(ctx, now, 100)
+	if err != nil {
+		return 0, err
+	}
+
+	released := 0
+	for _, id := range ids {
+		_, levels, err := s.inventory.CloseReservation(ctx, id, ReservationExpired, "", now)
+		if err != nil {
+			// committed or released by someone else in the meantime
+			if errors.Is(err, ErrReservationNotActive) {
+				continue
+			}
+			return released, err
+		}
+
+		s.syncQuantity(ctx, levels)
+		released++
+	}
+	return released, nil
+}
+
+func (s *catalogService) GetStockMovements(ctx context.Context, productID string, skip uint64, take uint64) ([]StockMovement, error) {
+	if take > 100 || (skip == 0 && take == 0) {
+		take = 100
+	}
+	return s.inventory.GetStockMovements(ctx, productID, skip, take)
+}
+
+// setAvailable adjusts the stock on hand so the available quantity of each
+// product matches the given one, all products in one go.
+func (s *catalogService) setAvailable(ctx context.Context, ids []string, quantity []uint32) error {
+	levels, err := s.inventory.SetAvailable(ctx, ids, quantity, "quantity set", time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	s.syncQuantity(ctx, levels)
+	return nil
+}
+
+// BackfillStock brings the products indexed before the inventory ledger into
+// it, with a restock of the quantity their document holds. It returns how
+// many products were brought in.
+func (s *catalogService) BackfillStock(ctx context.Context) (int, error) {
+	filled := 0
+	for skip := uint64(0); ; skip += backfillBatch {
+		products, err := s.repository.ListProducts(ctx, skip, backfillBatch, ProductFilter{IncludeUnpublished: true})
+		if err != nil {
+			return filled, err
+		}
+
+		ids := []string{}
+		quantity := []uint32{}
+		for _, p := range products {
+			ids = append(ids, p.ID)
+			quantity = append(quantity, p.Quantity)
+		}
+		levels, err := s.inventory.InitStock(ctx, ids, quantity, "stock before the ledger", time.Now().UTC())
+		if err != nil {
+			return filled, err
+		}
+		filled += len(levels)
+
+		if len(products) < backfillBatch {
+			return filled, nil
+		}
+	}
+}
+
+// syncQuantity mirrors the available stock onto the product documents and
+// raises stock notifications. The ledger stays the source of truth, so a
+// failed sync is only logged.
+func (s *catalogService) syncQuantity(ctx context.Context, levels []StockLevel) {
+	if len(levels) == 0 {
+		return
+	}
+
+	ids := []string{}
+	quantity := []uint32{}
+	for _, l := range levels {
+		ids = append(ids, l.ProductID)
+		quantity = append(quantity, l.Available())
+	}
+
+	// the documents still hold the quantity from before this change
+	before, err := s.repository.ListProductsWithIDs(ctx, ids)
+	if err != nil {
+		log.Println("error getting products", err)
+	}
+
+	if err := s.repository.UpdateQuantity(ctx, ids, quantity); err != nil {
+		log.Println("error syncing product quantity", err)
+		return
+	}
+	s.watchStock(ctx, before, levels)
+}
+
+// mergeReservationItems sums duplicate products and sorts the items, so
+// concurrent reservations lock inventory rows in the same order.
+func mergeReservationItems(items []ReservationItem) ([]ReservationItem, error) {
+	if len(items) == 0 {
+		return nil, ErrEmptyReservation
+	}
+
+	index := map[string]int{}
+	merged := []ReservationItem{}
+	for _, it := range items {
+		if it.Quantity == 0 {
+			return nil, ErrInvalidStockQuantity
+		}
+		if i, ok := index[it.ProductID]; ok {
+			merged[i].Quantity += it.Quantity
+			continue
+		}
+		index[it.ProductID] = len(merged)
+		merged = append(merged, it)
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].ProductID < merged[j].ProductID
+	})
+	return merged, nil
+}
+
+// RunStockBackfill retries the backfill until it went through once.
+func RunStockBackfill(ctx context.Context, s Service, interval time.Duration) {
+	for {
+		filled, err := s.BackfillStock(ctx)
+		if err == nil {
+			if filled > 0 {
+				log.Println("backfilled stock of products", filled)
+			}
+			return
+		}
+		log.Println("error backfilling stock", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// RunReservationSweeper releases expired reservations every interval until
+// the context is done.
+func RunReservationSweeper(ctx context.Context, s Service, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			released, err := s.ReleaseExpiredReservations(ctx)
+			if err != nil {
+				log.Println("error releasing expired reservations", err)
+			}
+			if released > 0 {
+				log.Println("released expired reservations", released)
+			}
+		}
+	}
+}
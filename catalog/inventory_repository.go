@@ -0,0 +1,549 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// pgCheckViolation is raised when a movement would take stock below zero or
+// reserve more than is on hand.
+const pgCheckViolation = "23514"
+
+type InventoryRepository interface {
+	Close()
+	ApplyMovement(ctx context.Context, m StockMovement) (*StockLevel, error)
+	SetAvailable(ctx context.Context, ids []string, quantity []uint32, note string, at time.Time) ([]StockLevel, error)
+	InitStock(ctx context.Context, ids []string, quantity []uint32, note string, at time.Time) ([]StockLevel, error)
+	GetStockLevels(ctx context.Context, productIDs []string) ([]StockLevel, error)
+	GetStockMovements(ctx context.Context, productID string, skip uint64, take uint64) ([]StockMovement, error)
+
+	PutReservation(ctx context.Context, r Reservation) ([]StockLevel, error)
+	CloseReservation(ctx context.Context, id string, status int32, reference string, at time.Time) (*Reservation, []StockLevel, error)
+	GetExpiredReservationIDs(ctx context.Context, now time.Time, limit int) ([]string, error)
+
+	PutStockSubscription(ctx context.Context, productID, accountID string, at time.Time) error
+	DeleteStockSubscription(ctx context.Context, productID, accountID string) error
+	PopStockSubscribers(ctx context.Context, productID string) ([]string, error)
+	PutStockNotifications(ctx context.Context, notifications []StockNotification) error
+	GetStockNotifications(ctx context.Context, recipientID string, unreadOnly bool, skip uint64, take uint64) ([]StockNotification, error)
+	MarkStockNotificationsRead(ctx context.Context, recipientID string, ids []string, at time.Time) ([]string, error)
+	DeleteAccountStockAlerts(ctx context.Context, accountID string) error
+}
+
+type postgresInventoryRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresInventoryRepository(url string) (InventoryRepository, error) {
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Ping()
+	if err != nil {
+		return nil, err
+	}
+	return &postgresInventoryRepository{db}, nil
+}
+
+func (r *postgresInventoryRepository) Close() {
+	r.db.Close()
+}
+
+func (r *postgresInventoryRepository) ApplyMovement(ctx context.Context, m StockMovement) (level *StockLevel, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	// a movement with a reference is applied once, the caller may retry it
+	if m.Reference != "" {
+		level, err = appliedMovement(ctx, tx, m)
+		if err != nil || level != nil {
+			return level, err
+		}
+	}
+	return applyMovement(ctx, tx, m)
+}
+
+// SetAvailable adjusts the stock on hand so the available quantity of each
+// product matches the given one. The rows are locked before the deltas are
+// worked out, a sale at the same time waits instead of being overwritten.
+func (r *postgresInventoryRepository) SetAvailable(ctx context.Context, ids []string, quantity []uint32, note string, at time.Time) (levels []StockLevel, err error) {
+	if len(ids) != len(quantity) {
+		return nil, ErrQuantityMismatch
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	_, err = tx.ExecContext(
+		ctx,
+		"INSERT INTO inventory_items(product_id) SELECT unnest($1::varchar[]) ON CONFLICT (product_id) DO NOTHING",
+		pq.Array(ids),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// lock in a fixed order so two updates of the same products cannot deadlock
+	rows, err := tx.QueryContext(
+		ctx,
+		`SELECT product_id, on_hand, reserved FROM inventory_items
+		WHERE product_id = ANY($1)
+		ORDER BY product_id
+		FOR UPDATE`,
+		pq.Array(ids),
+	)
+	if err != nil {
+		return nil, err
+	}
+	current := map[string]StockLevel{}
+	for rows.Next() {
+		l := StockLevel{}
+		if err = rows.Scan(&l.ProductID, &l.OnHand, &l.Reserved); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		current[l.ProductID] = l
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	levels = []StockLevel{}
+	for i, id := range ids {
+		delta := int32(quantity[i]) - int32(current[id].Available())
+		if delta == 0 {
+			continue
+		}
+
+		var level *StockLevel
+		level, err = applyMovement(ctx, tx, StockMovement{
+			ProductID:   id,
+			Kind:        MovementAdjustment,
+			OnHandDelta: delta,
+			Note:        note,
+			CreatedAt:   at,
+		})
+		if err != nil {
+			return nil, err
+		}
+		current[id] = *level
+		levels = append(levels, *level)
+	}
+
+	return levels, nil
+}
+
+// InitStock opens the ledger of the products that are not in it yet with a
+// restock of the given quantity, the products already in it are left alone.
+func (r *postgresInventoryRepository) InitStock(ctx context.Context, ids []string, quantity []uint32, note string, at time.Time) (levels []StockLevel, err error) {
+	if len(ids) != len(quantity) {
+		return nil, ErrQuantityMismatch
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	levels = []StockLevel{}
+	for i, id := range ids {
+		var created string
+		err = tx.QueryRowContext(
+			ctx,
+			"INSERT INTO inventory_items(product_id) VALUES($1) ON CONFLICT (product_id) DO NOTHING RETURNING product_id",
+			id,
+		).Scan(&created)
+		if errors.Is(err, sql.ErrNoRows) {
+			err = nil
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if quantity[i] == 0 {
+			levels = append(levels, StockLevel{ProductID: id})
+			continue
+		}
+
+		var level *StockLevel
+		level, err = applyMovement(ctx, tx, StockMovement{
+			ProductID:   id,
+			Kind:        MovementRestock,
+			OnHandDelta: int32(quantity[i]),
+			Note:        note,
+			CreatedAt:   at,
+		})
+		if err != nil {
+			return nil, err
+		}
+		levels = append(levels, *level)
+	}
+
+	return levels, nil
+}
+
+func (r *postgresInventoryRepository) GetStockLevels(ctx context.Context, productIDs []string) ([]StockLevel, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		"SELECT product_id, on_hand, reserved FROM inventory_items WHERE product_id = ANY($1)",
+		pq.Array(productIDs),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	levels := []StockLevel{}
+	for rows.Next() {
+		l := StockLevel{}
+		if err = rows.Scan(&l.ProductID, &l.OnHand, &l.Reserved); err != nil {
+			return nil, err
+		}
+		levels = append(levels, l)
+	}
+
+	return levels, rows.Err()
+}
+
+func (r *postgresInventoryRepository) GetStockMovements(ctx context.Context, productID string, skip uint64, take uint64) ([]StockMovement, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT id, product_id, kind, on_hand_delta, reserved_delta, COALESCE(reservation_id, ''), reference, note, created_at
+		FROM stock_movements
+		WHERE product_id = $1
+		ORDER BY id DESC
+		LIMIT $2 OFFSET $3`,
+		productID, take, skip,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	movements := []StockMovement{}
+	for rows.Next() {
+		m := StockMovement{}
+		if err = rows.Scan(
+			&m.ID,
+			&m.ProductID,
+			&m.Kind,
+			&m.OnHandDelta,
+			&m.ReservedDelta,
+			&m.ReservationID,
+			&m.Reference,
+			&m.Note,
+			&m.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		movements = append(movements, m)
+	}
+
+	return movements, rows.Err()
+}
+
+func (r *postgresInventoryRepository) PutReservation(ctx context.Context, res Reservation) (levels []StockLevel, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	_, err = tx.ExecContext(
+		ctx,
+		`INSERT INTO stock_reservations(id, status, reference, expires_at, created_at, updated_at)
+		VALUES($1, $2, $3, $4, $5, $6)`,
+		res.ID, res.Status, res.Reference, res.ExpiresAt, res.CreatedAt, res.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, it := range res.Items {
+		_, err = tx.ExecContext(
+			ctx,
+			"INSERT INTO stock_reservation_items(reservation_id, product_id, quantity) VALUES($1, $2, $3)",
+			res.ID, it.ProductID, it.Quantity,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		var level *StockLevel
+		level, err = applyMovement(ctx, tx, StockMovement{
+			ProductID:     it.ProductID,
+			Kind:          MovementReservation,
+			ReservedDelta: int32(it.Quantity),
+			ReservationID: res.ID,
+			CreatedAt:     res.CreatedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+		levels = append(levels, *level)
+	}
+
+	return levels, nil
+}
+
+// CloseReservation ends an active reservation. A commit turns the held stock
+// into a sale, any other status gives it back.
+func (r *postgresInventoryRepository) CloseReservation(ctx context.Context, id string, status int32, reference string, at time.Time) (res *Reservation, levels []StockLevel, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	res = &Reservation{}
+	err = tx.QueryRowContext(
+		ctx,
+		`SELECT id, status, reference, expires_at, created_at, updated_at
+		FROM stock_reservations
+		WHERE id = $1
+		FOR UPDATE`,
+		id,
+	).Scan(&res.ID, &res.Status, &res.Reference, &res.ExpiresAt, &res.CreatedAt, &res.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, ErrReservationNotFound
+		}
+		return nil, nil, err
+	}
+	// a commit retried with the same reference gets the committed reservation
+	if status == ReservationCommitted && res.Status == ReservationCommitted && reference != "" && res.Reference == reference {
+		res.Items, err = getReservationItems(ctx, tx, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		return res, nil, nil
+	}
+	if res.Status != ReservationActive {
+		return nil, nil, ErrReservationNotActive
+	}
+	// a lapsed reservation can only be given back, the sweeper will get to it
+	if status == ReservationCommitted && res.ExpiresAt.Before(at) {
+		return nil, nil, ErrReservationNotActive
+	}
+
+	res.Items, err = getReservationItems(ctx, tx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res.Status = status
+	if reference != "" {
+		res.Reference = reference
+	}
+	res.UpdatedAt = at
+
+	_, err = tx.ExecContext(
+		ctx,
+		"UPDATE stock_reservations SET status = $2, reference = $3, updated_at = $4 WHERE id = $1",
+		res.ID, res.Status, res.Reference, res.UpdatedAt,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, it := range res.Items {
+		m := StockMovement{
+			ProductID:     it.ProductID,
+			Kind:          MovementRelease,
+			ReservedDelta: -int32(it.Quantity),
+			ReservationID: res.ID,
+			Reference:     res.Reference,
+			CreatedAt:     at,
+		}
+		if status == ReservationCommitted {
+			m.Kind = MovementSale
+			m.OnHandDelta = -int32(it.Quantity)
+		}
+
+		var level *StockLevel
+		level, err = applyMovement(ctx, tx, m)
+		if err != nil {
+			return nil, nil, err
+		}
+		levels = append(levels, *level)
+	}
+
+	return res, levels, nil
+}
+
+func (r *postgresInventoryRepository) GetExpiredReservationIDs(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT id FROM stock_reservations
+		WHERE status = $1 AND expires_at < $2
+		ORDER BY expires_at
+		LIMIT $3`,
+		ReservationActive, now, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// applyMovement appends m to the ledger and moves the product balance by the
+// same amounts, creating the balance on the first movement of a product.
+func applyMovement(ctx context.Context, tx *sql.Tx, m StockMovement) (*StockLevel, error) {
+	_, err := tx.ExecContext(
+		ctx,
+		"INSERT INTO inventory_items(product_id) VALUES($1) ON CONFLICT (product_id) DO NOTHING",
+		m.ProductID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	level := &StockLevel{}
+	err = tx.QueryRowContext(
+		ctx,
+		`UPDATE inventory_items
+		SET on_hand = on_hand + $2, reserved = reserved + $3, updated_at = $4
+		WHERE product_id = $1
+		RETURNING product_id, on_hand, reserved`,
+		m.ProductID, m.OnHandDelta, m.ReservedDelta, m.CreatedAt,
+	).Scan(&level.ProductID, &level.OnHand, &level.Reserved)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == pgCheckViolation {
+			return nil, ErrOutOfStock
+		}
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		`INSERT INTO stock_movements(product_id, kind, on_hand_delta, reserved_delta, reservation_id, reference, note, created_at)
+		VALUES($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8)`,
+		m.ProductID, m.Kind, m.OnHandDelta, m.ReservedDelta, m.ReservationID, m.Reference, m.Note, m.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return level, nil
+}
+
+// appliedMovement returns the stock level of the product when a movement of
+// the same kind and reference was already applied, nil otherwise. It locks the
+// row of the product so that retries running at once see each other.
+func appliedMovement(ctx context.Context, tx *sql.Tx, m StockMovement) (*StockLevel, error) {
+	_, err := tx.ExecContext(
+		ctx,
+		"INSERT INTO inventory_items(product_id) VALUES($1) ON CONFLICT (product_id) DO NOTHING",
+		m.ProductID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	level := &StockLevel{}
+	err = tx.QueryRowContext(
+		ctx,
+		"SELECT product_id, on_hand, reserved FROM inventory_items WHERE product_id = $1 FOR UPDATE",
+		m.ProductID,
+	).Scan(&level.ProductID, &level.OnHand, &level.Reserved)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied bool
+	err = tx.QueryRowContext(
+		ctx,
+		"SELECT EXISTS(SELECT 1 FROM stock_movements WHERE product_id = $1 AND kind = $2 AND reference = $3)",
+		m.ProductID, m.Kind, m.Reference,
+	).Scan(&applied)
+	if err != nil || !applied {
+		return nil, err
+	}
+	return level, nil
+}
+
+func getReservationItems(ctx context.Context, tx *sql.Tx, reservationID string) ([]ReservationItem, error) {
+	rows, err := tx.QueryContext(
+		ctx,
+		`SELECT product_id, quantity FROM stock_reservation_items
+		WHERE reservation_id = $1
+		ORDER BY product_id`,
+		reservationID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []ReservationItem{}
+	for rows.Next() {
+		it := ReservationItem{}
+		if err = rows.Scan(&it.ProductID, &it.Quantity); err != nil {
+			return nil, err
+		}
+		items = append(items, it)
+	}
+
+	return items, rows.Err()
+}
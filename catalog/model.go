@@ -1,5 +1,7 @@
 package catalog
 
+import "time"
+
 type mGetResp struct {
 	Hits []productResp `json:"docs"`
 }
@@ -19,11 +21,16 @@ type productResp struct {
 }
 
 type productDocument struct {
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
-	Quantity    uint32  `json:"quantity"`
-	SellerID    string  `json:"seller_id"`
+	Name              string  `json:"name"`
+	Description       string  `json:"description"`
+	Price             float64 `json:"price"`
+	Quantity          uint32  `json:"quantity"`
+	SellerID          string  `json:"seller_id"`
+	LowStockThreshold uint32  `json:"low_stock_threshold"`
+	AverageRating     float64 `json:"average_rating"`
+	ReviewCount       uint32  `json:"review_count"`
+	Unpublished       bool    `json:"unpublished"`
+	ModerationNote    string  `json:"moderation_note"`
 }
 
 type Product struct {
@@ -33,4 +40,36 @@ type Product struct {
 	Price       float64 `json:"price"`
 	Quantity    uint32  `json:"quantity"`
 	SellerID    string  `json:"seller_id"`
+	// LowStockThreshold alerts the seller once the available quantity falls
+	// to it, zero only alerts when the product runs out.
+	LowStockThreshold uint32 `json:"low_stock_threshold"`
+	// AverageRating and ReviewCount are kept up to date by the review
+	// service, sellers cannot set them.
+	AverageRating float64 `json:"average_rating"`
+	ReviewCount   uint32  `json:"review_count"`
+	// Unpublished products were taken down by an admin, they are left out
+	// of listings and searches and cannot be ordered.
+	Unpublished    bool   `json:"unpublished"`
+	ModerationNote string `json:"moderation_note"`
+}
+
+// ProductFilter narrows and orders a product listing.
+type ProductFilter struct {
+	MinRating    float64
+	SortByRating bool
+	// IncludeUnpublished is only set by the service itself, never by callers
+	IncludeUnpublished bool
+}
+
+type idempotencyResp struct {
+	SeqNo       int                 `json:"_seq_no"`
+	PrimaryTerm int                 `json:"_primary_term"`
+	Found       bool                `json:"found"`
+	Source      idempotencyDocument `json:"_source"`
+}
+
+type idempotencyDocument struct {
+	RequestHash string    `json:"request_hash"`
+	Response    []byte    `json:"response"`
+	ExpiresAt   time.Time `json:"expires_at"`
 }
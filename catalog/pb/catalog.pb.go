@@ -1,7 +1,7 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.36.4
-// 	protoc        v5.29.2
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.0
 // source: catalog.proto
 
 package pb
@@ -22,15 +22,20 @@ const (
 )
 
 type Product struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	Price         float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
-	Quantity      uint32                 `protobuf:"varint,5,opt,name=quantity,proto3" json:"quantity,omitempty"`
-	SellerId      string                 `protobuf:"bytes,6,opt,name=seller_id,json=sellerId,proto3" json:"seller_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name              string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description       string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Price             float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
+	Quantity          uint32                 `protobuf:"varint,5,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	SellerId          string                 `protobuf:"bytes,6,opt,name=seller_id,json=sellerId,proto3" json:"seller_id,omitempty"`
+	LowStockThreshold uint32                 `protobuf:"varint,7,opt,name=low_stock_threshold,json=lowStockThreshold,proto3" json:"low_stock_threshold,omitempty"`
+	AverageRating     float64                `protobuf:"fixed64,8,opt,name=average_rating,json=averageRating,proto3" json:"average_rating,omitempty"`
+	ReviewCount       uint32                 `protobuf:"varint,9,opt,name=review_count,json=reviewCount,proto3" json:"review_count,omitempty"`
+	Unpublished       bool                   `protobuf:"varint,10,opt,name=unpublished,proto3" json:"unpublished,omitempty"`
+	ModerationNote    string                 `protobuf:"bytes,11,opt,name=moderation_note,json=moderationNote,proto3" json:"moderation_note,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *Product) Reset() {
@@ -105,15 +110,51 @@ func (x *Product) GetSellerId() string {
 	return ""
 }
 
+func (x *Product) GetLowStockThreshold() uint32 {
+	if x != nil {
+		return x.LowStockThreshold
+	}
+	return 0
+}
+
+func (x *Product) GetAverageRating() float64 {
+	if x != nil {
+		return x.AverageRating
+	}
+	return 0
+}
+
+func (x *Product) GetReviewCount() uint32 {
+	if x != nil {
+		return x.ReviewCount
+	}
+	return 0
+}
+
+func (x *Product) GetUnpublished() bool {
+	if x != nil {
+		return x.Unpublished
+	}
+	return false
+}
+
+func (x *Product) GetModerationNote() string {
+	if x != nil {
+		return x.ModerationNote
+	}
+	return ""
+}
+
 type PostProductRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
-	Price         float64                `protobuf:"fixed64,3,opt,name=price,proto3" json:"price,omitempty"`
-	Quantity      uint32                 `protobuf:"varint,4,opt,name=quantity,proto3" json:"quantity,omitempty"`
-	SellerId      string                 `protobuf:"bytes,5,opt,name=seller_id,json=sellerId,proto3" json:"seller_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Name              string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description       string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Price             float64                `protobuf:"fixed64,3,opt,name=price,proto3" json:"price,omitempty"`
+	Quantity          uint32                 `protobuf:"varint,4,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	SellerId          string                 `protobuf:"bytes,5,opt,name=seller_id,json=sellerId,proto3" json:"seller_id,omitempty"`
+	LowStockThreshold uint32                 `protobuf:"varint,6,opt,name=low_stock_threshold,json=lowStockThreshold,proto3" json:"low_stock_threshold,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *PostProductRequest) Reset() {
@@ -181,6 +222,13 @@ func (x *PostProductRequest) GetSellerId() string {
 	return ""
 }
 
+func (x *PostProductRequest) GetLowStockThreshold() uint32 {
+	if x != nil {
+		return x.LowStockThreshold
+	}
+	return 0
+}
+
 type PostProductResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Product       *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
@@ -319,6 +367,8 @@ type GetProductsRequest struct {
 	Take          uint64                 `protobuf:"varint,2,opt,name=take,proto3" json:"take,omitempty"`
 	Ids           []string               `protobuf:"bytes,3,rep,name=ids,proto3" json:"ids,omitempty"`
 	Query         string                 `protobuf:"bytes,4,opt,name=query,proto3" json:"query,omitempty"`
+	MinRating     float64                `protobuf:"fixed64,5,opt,name=min_rating,json=minRating,proto3" json:"min_rating,omitempty"`
+	SortByRating  bool                   `protobuf:"varint,6,opt,name=sort_by_rating,json=sortByRating,proto3" json:"sort_by_rating,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -381,6 +431,20 @@ func (x *GetProductsRequest) GetQuery() string {
 	return ""
 }
 
+func (x *GetProductsRequest) GetMinRating() float64 {
+	if x != nil {
+		return x.MinRating
+	}
+	return 0
+}
+
+func (x *GetProductsRequest) GetSortByRating() bool {
+	if x != nil {
+		return x.SortByRating
+	}
+	return false
+}
+
 type GetProductsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Products      []*Product             `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
@@ -609,90 +673,1627 @@ func (x *UpdateQuantityResponse) GetIds() []string {
 	return nil
 }
 
+type UpdateRatingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	AverageRating float64                `protobuf:"fixed64,2,opt,name=average_rating,json=averageRating,proto3" json:"average_rating,omitempty"`
+	ReviewCount   uint32                 `protobuf:"varint,3,opt,name=review_count,json=reviewCount,proto3" json:"review_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateRatingRequest) Reset() {
+	*x = UpdateRatingRequest{}
+	mi := &file_catalog_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateRatingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRatingRequest) ProtoMessage() {}
+
+func (x *UpdateRatingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRatingRequest.ProtoReflect.Descriptor instead.
+func (*UpdateRatingRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *UpdateRatingRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *UpdateRatingRequest) GetAverageRating() float64 {
+	if x != nil {
+		return x.AverageRating
+	}
+	return 0
+}
+
+func (x *UpdateRatingRequest) GetReviewCount() uint32 {
+	if x != nil {
+		return x.ReviewCount
+	}
+	return 0
+}
+
+type UpdateRatingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateRatingResponse) Reset() {
+	*x = UpdateRatingResponse{}
+	mi := &file_catalog_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateRatingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRatingResponse) ProtoMessage() {}
+
+func (x *UpdateRatingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRatingResponse.ProtoReflect.Descriptor instead.
+func (*UpdateRatingResponse) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UpdateRatingResponse) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+type StockLevel struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	OnHand        uint32                 `protobuf:"varint,2,opt,name=on_hand,json=onHand,proto3" json:"on_hand,omitempty"`
+	Reserved      uint32                 `protobuf:"varint,3,opt,name=reserved,proto3" json:"reserved,omitempty"`
+	Available     uint32                 `protobuf:"varint,4,opt,name=available,proto3" json:"available,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockLevel) Reset() {
+	*x = StockLevel{}
+	mi := &file_catalog_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockLevel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockLevel) ProtoMessage() {}
+
+func (x *StockLevel) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockLevel.ProtoReflect.Descriptor instead.
+func (*StockLevel) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *StockLevel) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *StockLevel) GetOnHand() uint32 {
+	if x != nil {
+		return x.OnHand
+	}
+	return 0
+}
+
+func (x *StockLevel) GetReserved() uint32 {
+	if x != nil {
+		return x.Reserved
+	}
+	return 0
+}
+
+func (x *StockLevel) GetAvailable() uint32 {
+	if x != nil {
+		return x.Available
+	}
+	return 0
+}
+
+type StockLevelResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StockLevel    *StockLevel            `protobuf:"bytes,1,opt,name=stock_level,json=stockLevel,proto3" json:"stock_level,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockLevelResponse) Reset() {
+	*x = StockLevelResponse{}
+	mi := &file_catalog_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockLevelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockLevelResponse) ProtoMessage() {}
+
+func (x *StockLevelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockLevelResponse.ProtoReflect.Descriptor instead.
+func (*StockLevelResponse) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *StockLevelResponse) GetStockLevel() *StockLevel {
+	if x != nil {
+		return x.StockLevel
+	}
+	return nil
+}
+
+type RestockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity      uint32                 `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Note          string                 `protobuf:"bytes,3,opt,name=note,proto3" json:"note,omitempty"`
+	Reference     string                 `protobuf:"bytes,4,opt,name=reference,proto3" json:"reference,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestockRequest) Reset() {
+	*x = RestockRequest{}
+	mi := &file_catalog_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestockRequest) ProtoMessage() {}
+
+func (x *RestockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestockRequest.ProtoReflect.Descriptor instead.
+func (*RestockRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RestockRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *RestockRequest) GetQuantity() uint32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *RestockRequest) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *RestockRequest) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+type AdjustStockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Delta         int32                  `protobuf:"varint,2,opt,name=delta,proto3" json:"delta,omitempty"`
+	Note          string                 `protobuf:"bytes,3,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdjustStockRequest) Reset() {
+	*x = AdjustStockRequest{}
+	mi := &file_catalog_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdjustStockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdjustStockRequest) ProtoMessage() {}
+
+func (x *AdjustStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdjustStockRequest.ProtoReflect.Descriptor instead.
+func (*AdjustStockRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *AdjustStockRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *AdjustStockRequest) GetDelta() int32 {
+	if x != nil {
+		return x.Delta
+	}
+	return 0
+}
+
+func (x *AdjustStockRequest) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+type Reservation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status        int32                  `protobuf:"varint,2,opt,name=status,proto3" json:"status,omitempty"`
+	Reference     string                 `protobuf:"bytes,3,opt,name=reference,proto3" json:"reference,omitempty"`
+	Items         []*Reservation_Item    `protobuf:"bytes,4,rep,name=items,proto3" json:"items,omitempty"`
+	ExpiresAt     []byte                 `protobuf:"bytes,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	CreatedAt     []byte                 `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     []byte                 `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Reservation) Reset() {
+	*x = Reservation{}
+	mi := &file_catalog_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Reservation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reservation) ProtoMessage() {}
+
+func (x *Reservation) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reservation.ProtoReflect.Descriptor instead.
+func (*Reservation) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Reservation) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Reservation) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *Reservation) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+func (x *Reservation) GetItems() []*Reservation_Item {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *Reservation) GetExpiresAt() []byte {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *Reservation) GetCreatedAt() []byte {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Reservation) GetUpdatedAt() []byte {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type ReservationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reservation   *Reservation           `protobuf:"bytes,1,opt,name=reservation,proto3" json:"reservation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReservationResponse) Reset() {
+	*x = ReservationResponse{}
+	mi := &file_catalog_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReservationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReservationResponse) ProtoMessage() {}
+
+func (x *ReservationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReservationResponse.ProtoReflect.Descriptor instead.
+func (*ReservationResponse) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ReservationResponse) GetReservation() *Reservation {
+	if x != nil {
+		return x.Reservation
+	}
+	return nil
+}
+
+type ReserveStockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*Reservation_Item    `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	TtlSeconds    uint32                 `protobuf:"varint,2,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveStockRequest) Reset() {
+	*x = ReserveStockRequest{}
+	mi := &file_catalog_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveStockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveStockRequest) ProtoMessage() {}
+
+func (x *ReserveStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveStockRequest.ProtoReflect.Descriptor instead.
+func (*ReserveStockRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ReserveStockRequest) GetItems() []*Reservation_Item {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *ReserveStockRequest) GetTtlSeconds() uint32 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+type CommitReservationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Reference     string                 `protobuf:"bytes,2,opt,name=reference,proto3" json:"reference,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommitReservationRequest) Reset() {
+	*x = CommitReservationRequest{}
+	mi := &file_catalog_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitReservationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitReservationRequest) ProtoMessage() {}
+
+func (x *CommitReservationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitReservationRequest.ProtoReflect.Descriptor instead.
+func (*CommitReservationRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CommitReservationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CommitReservationRequest) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+type ReleaseReservationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseReservationRequest) Reset() {
+	*x = ReleaseReservationRequest{}
+	mi := &file_catalog_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseReservationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseReservationRequest) ProtoMessage() {}
+
+func (x *ReleaseReservationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseReservationRequest.ProtoReflect.Descriptor instead.
+func (*ReleaseReservationRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ReleaseReservationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type StockMovement struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ProductId     string                 `protobuf:"bytes,2,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Kind          int32                  `protobuf:"varint,3,opt,name=kind,proto3" json:"kind,omitempty"`
+	OnHandDelta   int32                  `protobuf:"varint,4,opt,name=on_hand_delta,json=onHandDelta,proto3" json:"on_hand_delta,omitempty"`
+	ReservedDelta int32                  `protobuf:"varint,5,opt,name=reserved_delta,json=reservedDelta,proto3" json:"reserved_delta,omitempty"`
+	ReservationId string                 `protobuf:"bytes,6,opt,name=reservation_id,json=reservationId,proto3" json:"reservation_id,omitempty"`
+	Reference     string                 `protobuf:"bytes,7,opt,name=reference,proto3" json:"reference,omitempty"`
+	Note          string                 `protobuf:"bytes,8,opt,name=note,proto3" json:"note,omitempty"`
+	CreatedAt     []byte                 `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockMovement) Reset() {
+	*x = StockMovement{}
+	mi := &file_catalog_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockMovement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockMovement) ProtoMessage() {}
+
+func (x *StockMovement) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockMovement.ProtoReflect.Descriptor instead.
+func (*StockMovement) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *StockMovement) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *StockMovement) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *StockMovement) GetKind() int32 {
+	if x != nil {
+		return x.Kind
+	}
+	return 0
+}
+
+func (x *StockMovement) GetOnHandDelta() int32 {
+	if x != nil {
+		return x.OnHandDelta
+	}
+	return 0
+}
+
+func (x *StockMovement) GetReservedDelta() int32 {
+	if x != nil {
+		return x.ReservedDelta
+	}
+	return 0
+}
+
+func (x *StockMovement) GetReservationId() string {
+	if x != nil {
+		return x.ReservationId
+	}
+	return ""
+}
+
+func (x *StockMovement) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+func (x *StockMovement) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *StockMovement) GetCreatedAt() []byte {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type GetStockMovementsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Skip          uint64                 `protobuf:"varint,2,opt,name=skip,proto3" json:"skip,omitempty"`
+	Take          uint64                 `protobuf:"varint,3,opt,name=take,proto3" json:"take,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStockMovementsRequest) Reset() {
+	*x = GetStockMovementsRequest{}
+	mi := &file_catalog_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStockMovementsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStockMovementsRequest) ProtoMessage() {}
+
+func (x *GetStockMovementsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStockMovementsRequest.ProtoReflect.Descriptor instead.
+func (*GetStockMovementsRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetStockMovementsRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *GetStockMovementsRequest) GetSkip() uint64 {
+	if x != nil {
+		return x.Skip
+	}
+	return 0
+}
+
+func (x *GetStockMovementsRequest) GetTake() uint64 {
+	if x != nil {
+		return x.Take
+	}
+	return 0
+}
+
+type GetStockMovementsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Movements     []*StockMovement       `protobuf:"bytes,1,rep,name=movements,proto3" json:"movements,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStockMovementsResponse) Reset() {
+	*x = GetStockMovementsResponse{}
+	mi := &file_catalog_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStockMovementsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStockMovementsResponse) ProtoMessage() {}
+
+func (x *GetStockMovementsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStockMovementsResponse.ProtoReflect.Descriptor instead.
+func (*GetStockMovementsResponse) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetStockMovementsResponse) GetMovements() []*StockMovement {
+	if x != nil {
+		return x.Movements
+	}
+	return nil
+}
+
+type StockNotification struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	RecipientId   string                 `protobuf:"bytes,2,opt,name=recipient_id,json=recipientId,proto3" json:"recipient_id,omitempty"`
+	ProductId     string                 `protobuf:"bytes,3,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Kind          int32                  `protobuf:"varint,4,opt,name=kind,proto3" json:"kind,omitempty"`
+	Quantity      uint32                 `protobuf:"varint,5,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Threshold     uint32                 `protobuf:"varint,6,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	ReadAt        []byte                 `protobuf:"bytes,7,opt,name=read_at,json=readAt,proto3" json:"read_at,omitempty"`
+	CreatedAt     []byte                 `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockNotification) Reset() {
+	*x = StockNotification{}
+	mi := &file_catalog_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockNotification) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockNotification) ProtoMessage() {}
+
+func (x *StockNotification) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockNotification.ProtoReflect.Descriptor instead.
+func (*StockNotification) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *StockNotification) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *StockNotification) GetRecipientId() string {
+	if x != nil {
+		return x.RecipientId
+	}
+	return ""
+}
+
+func (x *StockNotification) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *StockNotification) GetKind() int32 {
+	if x != nil {
+		return x.Kind
+	}
+	return 0
+}
+
+func (x *StockNotification) GetQuantity() uint32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *StockNotification) GetThreshold() uint32 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *StockNotification) GetReadAt() []byte {
+	if x != nil {
+		return x.ReadAt
+	}
+	return nil
+}
+
+func (x *StockNotification) GetCreatedAt() []byte {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type StockSubscriptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	AccountId     string                 `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockSubscriptionRequest) Reset() {
+	*x = StockSubscriptionRequest{}
+	mi := &file_catalog_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockSubscriptionRequest) ProtoMessage() {}
+
+func (x *StockSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*StockSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *StockSubscriptionRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *StockSubscriptionRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+type StockSubscriptionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StockSubscriptionResponse) Reset() {
+	*x = StockSubscriptionResponse{}
+	mi := &file_catalog_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StockSubscriptionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StockSubscriptionResponse) ProtoMessage() {}
+
+func (x *StockSubscriptionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StockSubscriptionResponse.ProtoReflect.Descriptor instead.
+func (*StockSubscriptionResponse) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *StockSubscriptionResponse) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+type GetStockNotificationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RecipientId   string                 `protobuf:"bytes,1,opt,name=recipient_id,json=recipientId,proto3" json:"recipient_id,omitempty"`
+	UnreadOnly    bool                   `protobuf:"varint,2,opt,name=unread_only,json=unreadOnly,proto3" json:"unread_only,omitempty"`
+	Skip          uint64                 `protobuf:"varint,3,opt,name=skip,proto3" json:"skip,omitempty"`
+	Take          uint64                 `protobuf:"varint,4,opt,name=take,proto3" json:"take,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStockNotificationsRequest) Reset() {
+	*x = GetStockNotificationsRequest{}
+	mi := &file_catalog_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStockNotificationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStockNotificationsRequest) ProtoMessage() {}
+
+func (x *GetStockNotificationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStockNotificationsRequest.ProtoReflect.Descriptor instead.
+func (*GetStockNotificationsRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *GetStockNotificationsRequest) GetRecipientId() string {
+	if x != nil {
+		return x.RecipientId
+	}
+	return ""
+}
+
+func (x *GetStockNotificationsRequest) GetUnreadOnly() bool {
+	if x != nil {
+		return x.UnreadOnly
+	}
+	return false
+}
+
+func (x *GetStockNotificationsRequest) GetSkip() uint64 {
+	if x != nil {
+		return x.Skip
+	}
+	return 0
+}
+
+func (x *GetStockNotificationsRequest) GetTake() uint64 {
+	if x != nil {
+		return x.Take
+	}
+	return 0
+}
+
+type GetStockNotificationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Notifications []*StockNotification   `protobuf:"bytes,1,rep,name=notifications,proto3" json:"notifications,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStockNotificationsResponse) Reset() {
+	*x = GetStockNotificationsResponse{}
+	mi := &file_catalog_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStockNotificationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStockNotificationsResponse) ProtoMessage() {}
+
+func (x *GetStockNotificationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStockNotificationsResponse.ProtoReflect.Descriptor instead.
+func (*GetStockNotificationsResponse) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *GetStockNotificationsResponse) GetNotifications() []*StockNotification {
+	if x != nil {
+		return x.Notifications
+	}
+	return nil
+}
+
+type MarkStockNotificationsReadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RecipientId   string                 `protobuf:"bytes,1,opt,name=recipient_id,json=recipientId,proto3" json:"recipient_id,omitempty"`
+	Ids           []string               `protobuf:"bytes,2,rep,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MarkStockNotificationsReadRequest) Reset() {
+	*x = MarkStockNotificationsReadRequest{}
+	mi := &file_catalog_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarkStockNotificationsReadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarkStockNotificationsReadRequest) ProtoMessage() {}
+
+func (x *MarkStockNotificationsReadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarkStockNotificationsReadRequest.ProtoReflect.Descriptor instead.
+func (*MarkStockNotificationsReadRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *MarkStockNotificationsReadRequest) GetRecipientId() string {
+	if x != nil {
+		return x.RecipientId
+	}
+	return ""
+}
+
+func (x *MarkStockNotificationsReadRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type MarkStockNotificationsReadResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MarkStockNotificationsReadResponse) Reset() {
+	*x = MarkStockNotificationsReadResponse{}
+	mi := &file_catalog_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarkStockNotificationsReadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarkStockNotificationsReadResponse) ProtoMessage() {}
+
+func (x *MarkStockNotificationsReadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarkStockNotificationsReadResponse.ProtoReflect.Descriptor instead.
+func (*MarkStockNotificationsReadResponse) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *MarkStockNotificationsReadResponse) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type ModerateProductRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Unpublished   bool                   `protobuf:"varint,2,opt,name=unpublished,proto3" json:"unpublished,omitempty"`
+	Note          string                 `protobuf:"bytes,3,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ModerateProductRequest) Reset() {
+	*x = ModerateProductRequest{}
+	mi := &file_catalog_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModerateProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModerateProductRequest) ProtoMessage() {}
+
+func (x *ModerateProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModerateProductRequest.ProtoReflect.Descriptor instead.
+func (*ModerateProductRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ModerateProductRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *ModerateProductRequest) GetUnpublished() bool {
+	if x != nil {
+		return x.Unpublished
+	}
+	return false
+}
+
+func (x *ModerateProductRequest) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+type EraseAccountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EraseAccountRequest) Reset() {
+	*x = EraseAccountRequest{}
+	mi := &file_catalog_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EraseAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EraseAccountRequest) ProtoMessage() {}
+
+func (x *EraseAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EraseAccountRequest.ProtoReflect.Descriptor instead.
+func (*EraseAccountRequest) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *EraseAccountRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+type EraseAccountResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EraseAccountResponse) Reset() {
+	*x = EraseAccountResponse{}
+	mi := &file_catalog_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EraseAccountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EraseAccountResponse) ProtoMessage() {}
+
+func (x *EraseAccountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EraseAccountResponse.ProtoReflect.Descriptor instead.
+func (*EraseAccountResponse) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{34}
+}
+
+type Reservation_Item struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity      uint32                 `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Reservation_Item) Reset() {
+	*x = Reservation_Item{}
+	mi := &file_catalog_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Reservation_Item) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reservation_Item) ProtoMessage() {}
+
+func (x *Reservation_Item) ProtoReflect() protoreflect.Message {
+	mi := &file_catalog_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reservation_Item.ProtoReflect.Descriptor instead.
+func (*Reservation_Item) Descriptor() ([]byte, []int) {
+	return file_catalog_proto_rawDescGZIP(), []int{17, 0}
+}
+
+func (x *Reservation_Item) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *Reservation_Item) GetQuantity() uint32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
 var File_catalog_proto protoreflect.FileDescriptor
 
-var file_catalog_proto_rawDesc = string([]byte{
-	0x0a, 0x0d, 0x63, 0x61, 0x74, 0x61, 0x6c, 0x6f, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
-	0x05, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x9e, 0x01, 0x0a, 0x07, 0x50, 0x72, 0x6f, 0x64, 0x75,
-	0x63, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
-	0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69,
-	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73,
-	0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x69, 0x63,
-	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x12, 0x1a,
-	0x0a, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d,
-	0x52, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x65,
-	0x6c, 0x6c, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73,
-	0x65, 0x6c, 0x6c, 0x65, 0x72, 0x49, 0x64, 0x22, 0x99, 0x01, 0x0a, 0x12, 0x50, 0x6f, 0x73, 0x74,
-	0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12,
-	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
-	0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f,
-	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70,
-	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x01, 0x52, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x71, 0x75,
-	0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x71, 0x75,
-	0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x65, 0x6c, 0x6c, 0x65, 0x72,
-	0x5f, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x65, 0x6c, 0x6c, 0x65,
-	0x72, 0x49, 0x64, 0x22, 0x3f, 0x0a, 0x13, 0x50, 0x6f, 0x73, 0x74, 0x50, 0x72, 0x6f, 0x64, 0x75,
-	0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x07, 0x70, 0x72,
-	0x6f, 0x64, 0x75, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x52, 0x07, 0x70, 0x72, 0x6f,
-	0x64, 0x75, 0x63, 0x74, 0x22, 0x23, 0x0a, 0x11, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x64, 0x75,
-	0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x3e, 0x0a, 0x12, 0x47, 0x65, 0x74,
-	0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x28, 0x0a, 0x07, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x0e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74,
-	0x52, 0x07, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x22, 0x64, 0x0a, 0x12, 0x47, 0x65, 0x74,
-	0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x12, 0x0a, 0x04, 0x73, 0x6b, 0x69, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04, 0x73,
-	0x6b, 0x69, 0x70, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x61, 0x6b, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x04, 0x52, 0x04, 0x74, 0x61, 0x6b, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x69, 0x64, 0x73, 0x18, 0x03,
-	0x20, 0x03, 0x28, 0x09, 0x52, 0x03, 0x69, 0x64, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x71, 0x75, 0x65,
-	0x72, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x22,
-	0x41, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x73, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2a, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63,
-	0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x2e, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63,
-	0x74, 0x73, 0x22, 0x40, 0x0a, 0x14, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x64,
-	0x75, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x28, 0x0a, 0x07, 0x70, 0x72,
-	0x6f, 0x64, 0x75, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x52, 0x07, 0x70, 0x72, 0x6f,
-	0x64, 0x75, 0x63, 0x74, 0x22, 0x27, 0x0a, 0x15, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x72,
-	0x6f, 0x64, 0x75, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a,
-	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x45, 0x0a,
-	0x15, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x51, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x69, 0x64, 0x73, 0x18, 0x01, 0x20,
-	0x03, 0x28, 0x09, 0x52, 0x03, 0x69, 0x64, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x71, 0x75, 0x61, 0x6e,
-	0x74, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x08, 0x71, 0x75, 0x61, 0x6e,
-	0x74, 0x69, 0x74, 0x79, 0x22, 0x2a, 0x0a, 0x16, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x51, 0x75,
-	0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x10,
-	0x0a, 0x03, 0x69, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x03, 0x69, 0x64, 0x73,
-	0x32, 0xe9, 0x02, 0x0a, 0x0e, 0x43, 0x61, 0x74, 0x61, 0x6c, 0x6f, 0x67, 0x53, 0x65, 0x72, 0x76,
-	0x69, 0x63, 0x65, 0x12, 0x46, 0x0a, 0x0b, 0x50, 0x6f, 0x73, 0x74, 0x50, 0x72, 0x6f, 0x64, 0x75,
-	0x63, 0x74, 0x12, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x6f, 0x73, 0x74, 0x50,
-	0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x6f, 0x73, 0x74, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63,
-	0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x43, 0x0a, 0x0a, 0x47,
-	0x65, 0x74, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x12, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x50,
-	0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00,
-	0x12, 0x46, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x73, 0x12,
-	0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x64, 0x75,
-	0x63, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x73, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x31, 0x0a, 0x0d, 0x55, 0x70, 0x64, 0x61,
-	0x74, 0x65, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x12, 0x0e, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2e, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x1a, 0x0e, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x2e, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x22, 0x00, 0x12, 0x4f, 0x0a, 0x0e, 0x55,
-	0x70, 0x64, 0x61, 0x74, 0x65, 0x51, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x12, 0x1c, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x51, 0x75, 0x61, 0x6e,
-	0x74, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x51, 0x75, 0x61, 0x6e, 0x74, 0x69,
-	0x74, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x06, 0x5a, 0x04,
-	0x2e, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
-})
+const file_catalog_proto_rawDesc = "" +
+	"\n" +
+	"\rcatalog.proto\x12\x05proto\"\xe3\x02\n" +
+	"\aProduct\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x14\n" +
+	"\x05price\x18\x04 \x01(\x01R\x05price\x12\x1a\n" +
+	"\bquantity\x18\x05 \x01(\rR\bquantity\x12\x1b\n" +
+	"\tseller_id\x18\x06 \x01(\tR\bsellerId\x12.\n" +
+	"\x13low_stock_threshold\x18\a \x01(\rR\x11lowStockThreshold\x12%\n" +
+	"\x0eaverage_rating\x18\b \x01(\x01R\raverageRating\x12!\n" +
+	"\freview_count\x18\t \x01(\rR\vreviewCount\x12 \n" +
+	"\vunpublished\x18\n" +
+	" \x01(\bR\vunpublished\x12'\n" +
+	"\x0fmoderation_note\x18\v \x01(\tR\x0emoderationNote\"\xc9\x01\n" +
+	"\x12PostProductRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x14\n" +
+	"\x05price\x18\x03 \x01(\x01R\x05price\x12\x1a\n" +
+	"\bquantity\x18\x04 \x01(\rR\bquantity\x12\x1b\n" +
+	"\tseller_id\x18\x05 \x01(\tR\bsellerId\x12.\n" +
+	"\x13low_stock_threshold\x18\x06 \x01(\rR\x11lowStockThreshold\"?\n" +
+	"\x13PostProductResponse\x12(\n" +
+	"\aproduct\x18\x01 \x01(\v2\x0e.proto.ProductR\aproduct\"#\n" +
+	"\x11GetProductRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\">\n" +
+	"\x12GetProductResponse\x12(\n" +
+	"\aproduct\x18\x01 \x01(\v2\x0e.proto.ProductR\aproduct\"\xa9\x01\n" +
+	"\x12GetProductsRequest\x12\x12\n" +
+	"\x04skip\x18\x01 \x01(\x04R\x04skip\x12\x12\n" +
+	"\x04take\x18\x02 \x01(\x04R\x04take\x12\x10\n" +
+	"\x03ids\x18\x03 \x03(\tR\x03ids\x12\x14\n" +
+	"\x05query\x18\x04 \x01(\tR\x05query\x12\x1d\n" +
+	"\n" +
+	"min_rating\x18\x05 \x01(\x01R\tminRating\x12$\n" +
+	"\x0esort_by_rating\x18\x06 \x01(\bR\fsortByRating\"A\n" +
+	"\x13GetProductsResponse\x12*\n" +
+	"\bproducts\x18\x01 \x03(\v2\x0e.proto.ProductR\bproducts\"@\n" +
+	"\x14UpdateProductRequest\x12(\n" +
+	"\aproduct\x18\x01 \x01(\v2\x0e.proto.ProductR\aproduct\"'\n" +
+	"\x15UpdateProductResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"E\n" +
+	"\x15UpdateQuantityRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\x12\x1a\n" +
+	"\bquantity\x18\x02 \x03(\rR\bquantity\"*\n" +
+	"\x16UpdateQuantityResponse\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\"~\n" +
+	"\x13UpdateRatingRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12%\n" +
+	"\x0eaverage_rating\x18\x02 \x01(\x01R\raverageRating\x12!\n" +
+	"\freview_count\x18\x03 \x01(\rR\vreviewCount\"5\n" +
+	"\x14UpdateRatingResponse\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\"~\n" +
+	"\n" +
+	"StockLevel\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x17\n" +
+	"\aon_hand\x18\x02 \x01(\rR\x06onHand\x12\x1a\n" +
+	"\breserved\x18\x03 \x01(\rR\breserved\x12\x1c\n" +
+	"\tavailable\x18\x04 \x01(\rR\tavailable\"H\n" +
+	"\x12StockLevelResponse\x122\n" +
+	"\vstock_level\x18\x01 \x01(\v2\x11.proto.StockLevelR\n" +
+	"stockLevel\"}\n" +
+	"\x0eRestockRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\rR\bquantity\x12\x12\n" +
+	"\x04note\x18\x03 \x01(\tR\x04note\x12\x1c\n" +
+	"\treference\x18\x04 \x01(\tR\treference\"]\n" +
+	"\x12AdjustStockRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x14\n" +
+	"\x05delta\x18\x02 \x01(\x05R\x05delta\x12\x12\n" +
+	"\x04note\x18\x03 \x01(\tR\x04note\"\xa2\x02\n" +
+	"\vReservation\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\x05R\x06status\x12\x1c\n" +
+	"\treference\x18\x03 \x01(\tR\treference\x12-\n" +
+	"\x05items\x18\x04 \x03(\v2\x17.proto.Reservation.ItemR\x05items\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x05 \x01(\fR\texpiresAt\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\fR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\fR\tupdatedAt\x1aA\n" +
+	"\x04Item\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\rR\bquantity\"K\n" +
+	"\x13ReservationResponse\x124\n" +
+	"\vreservation\x18\x01 \x01(\v2\x12.proto.ReservationR\vreservation\"e\n" +
+	"\x13ReserveStockRequest\x12-\n" +
+	"\x05items\x18\x01 \x03(\v2\x17.proto.Reservation.ItemR\x05items\x12\x1f\n" +
+	"\vttl_seconds\x18\x02 \x01(\rR\n" +
+	"ttlSeconds\"H\n" +
+	"\x18CommitReservationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1c\n" +
+	"\treference\x18\x02 \x01(\tR\treference\"+\n" +
+	"\x19ReleaseReservationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x95\x02\n" +
+	"\rStockMovement\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x02 \x01(\tR\tproductId\x12\x12\n" +
+	"\x04kind\x18\x03 \x01(\x05R\x04kind\x12\"\n" +
+	"\ron_hand_delta\x18\x04 \x01(\x05R\vonHandDelta\x12%\n" +
+	"\x0ereserved_delta\x18\x05 \x01(\x05R\rreservedDelta\x12%\n" +
+	"\x0ereservation_id\x18\x06 \x01(\tR\rreservationId\x12\x1c\n" +
+	"\treference\x18\a \x01(\tR\treference\x12\x12\n" +
+	"\x04note\x18\b \x01(\tR\x04note\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\t \x01(\fR\tcreatedAt\"a\n" +
+	"\x18GetStockMovementsRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x12\n" +
+	"\x04skip\x18\x02 \x01(\x04R\x04skip\x12\x12\n" +
+	"\x04take\x18\x03 \x01(\x04R\x04take\"O\n" +
+	"\x19GetStockMovementsResponse\x122\n" +
+	"\tmovements\x18\x01 \x03(\v2\x14.proto.StockMovementR\tmovements\"\xeb\x01\n" +
+	"\x11StockNotification\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12!\n" +
+	"\frecipient_id\x18\x02 \x01(\tR\vrecipientId\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x03 \x01(\tR\tproductId\x12\x12\n" +
+	"\x04kind\x18\x04 \x01(\x05R\x04kind\x12\x1a\n" +
+	"\bquantity\x18\x05 \x01(\rR\bquantity\x12\x1c\n" +
+	"\tthreshold\x18\x06 \x01(\rR\tthreshold\x12\x17\n" +
+	"\aread_at\x18\a \x01(\fR\x06readAt\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\b \x01(\fR\tcreatedAt\"X\n" +
+	"\x18StockSubscriptionRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x02 \x01(\tR\taccountId\":\n" +
+	"\x19StockSubscriptionResponse\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\"\x8a\x01\n" +
+	"\x1cGetStockNotificationsRequest\x12!\n" +
+	"\frecipient_id\x18\x01 \x01(\tR\vrecipientId\x12\x1f\n" +
+	"\vunread_only\x18\x02 \x01(\bR\n" +
+	"unreadOnly\x12\x12\n" +
+	"\x04skip\x18\x03 \x01(\x04R\x04skip\x12\x12\n" +
+	"\x04take\x18\x04 \x01(\x04R\x04take\"_\n" +
+	"\x1dGetStockNotificationsResponse\x12>\n" +
+	"\rnotifications\x18\x01 \x03(\v2\x18.proto.StockNotificationR\rnotifications\"X\n" +
+	"!MarkStockNotificationsReadRequest\x12!\n" +
+	"\frecipient_id\x18\x01 \x01(\tR\vrecipientId\x12\x10\n" +
+	"\x03ids\x18\x02 \x03(\tR\x03ids\"6\n" +
+	"\"MarkStockNotificationsReadResponse\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\"m\n" +
+	"\x16ModerateProductRequest\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12 \n" +
+	"\vunpublished\x18\x02 \x01(\bR\vunpublished\x12\x12\n" +
+	"\x04note\x18\x03 \x01(\tR\x04note\"4\n" +
+	"\x13EraseAccountRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\"\x16\n" +
+	"\x14EraseAccountResponse2\xae\v\n" +
+	"\x0eCatalogService\x12F\n" +
+	"\vPostProduct\x12\x19.proto.PostProductRequest\x1a\x1a.proto.PostProductResponse\"\x00\x12C\n" +
+	"\n" +
+	"GetProduct\x12\x18.proto.GetProductRequest\x1a\x19.proto.GetProductResponse\"\x00\x12F\n" +
+	"\vGetProducts\x12\x19.proto.GetProductsRequest\x1a\x1a.proto.GetProductsResponse\"\x00\x121\n" +
+	"\rUpdateProduct\x12\x0e.proto.Product\x1a\x0e.proto.Product\"\x00\x12O\n" +
+	"\x0eUpdateQuantity\x12\x1c.proto.UpdateQuantityRequest\x1a\x1d.proto.UpdateQuantityResponse\"\x00\x12I\n" +
+	"\fUpdateRating\x12\x1a.proto.UpdateRatingRequest\x1a\x1b.proto.UpdateRatingResponse\"\x00\x12=\n" +
+	"\aRestock\x12\x15.proto.RestockRequest\x1a\x19.proto.StockLevelResponse\"\x00\x12E\n" +
+	"\vAdjustStock\x12\x19.proto.AdjustStockRequest\x1a\x19.proto.StockLevelResponse\"\x00\x12H\n" +
+	"\fReserveStock\x12\x1a.proto.ReserveStockRequest\x1a\x1a.proto.ReservationResponse\"\x00\x12R\n" +
+	"\x11CommitReservation\x12\x1f.proto.CommitReservationRequest\x1a\x1a.proto.ReservationResponse\"\x00\x12T\n" +
+	"\x12ReleaseReservation\x12 .proto.ReleaseReservationRequest\x1a\x1a.proto.ReservationResponse\"\x00\x12X\n" +
+	"\x11GetStockMovements\x12\x1f.proto.GetStockMovementsRequest\x1a .proto.GetStockMovementsResponse\"\x00\x12[\n" +
+	"\x14SubscribeBackInStock\x12\x1f.proto.StockSubscriptionRequest\x1a .proto.StockSubscriptionResponse\"\x00\x12]\n" +
+	"\x16UnsubscribeBackInStock\x12\x1f.proto.StockSubscriptionRequest\x1a .proto.StockSubscriptionResponse\"\x00\x12d\n" +
+	"\x15GetStockNotifications\x12#.proto.GetStockNotificationsRequest\x1a$.proto.GetStockNotificationsResponse\"\x00\x12s\n" +
+	"\x1aMarkStockNotificationsRead\x12(.proto.MarkStockNotificationsReadRequest\x1a).proto.MarkStockNotificationsReadResponse\"\x00\x12I\n" +
+	"\fEraseAccount\x12\x1a.proto.EraseAccountRequest\x1a\x1b.proto.EraseAccountResponse\"\x00\x12B\n" +
+	"\x0fModerateProduct\x12\x1d.proto.ModerateProductRequest\x1a\x0e.proto.Product\"\x00B\x06Z\x04./pbb\x06proto3"
 
 var (
 	file_catalog_proto_rawDescOnce sync.Once
@@ -706,40 +2307,97 @@ func file_catalog_proto_rawDescGZIP() []byte {
 	return file_catalog_proto_rawDescData
 }
 
-var file_catalog_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_catalog_proto_msgTypes = make([]protoimpl.MessageInfo, 36)
 var file_catalog_proto_goTypes = []any{
-	(*Product)(nil),                // 0: proto.Product
-	(*PostProductRequest)(nil),     // 1: proto.PostProductRequest
-	(*PostProductResponse)(nil),    // 2: proto.PostProductResponse
-	(*GetProductRequest)(nil),      // 3: proto.GetProductRequest
-	(*GetProductResponse)(nil),     // 4: proto.GetProductResponse
-	(*GetProductsRequest)(nil),     // 5: proto.GetProductsRequest
-	(*GetProductsResponse)(nil),    // 6: proto.GetProductsResponse
-	(*UpdateProductRequest)(nil),   // 7: proto.UpdateProductRequest
-	(*UpdateProductResponse)(nil),  // 8: proto.UpdateProductResponse
-	(*UpdateQuantityRequest)(nil),  // 9: proto.UpdateQuantityRequest
-	(*UpdateQuantityResponse)(nil), // 10: proto.UpdateQuantityResponse
+	(*Product)(nil),                            // 0: proto.Product
+	(*PostProductRequest)(nil),                 // 1: proto.PostProductRequest
+	(*PostProductResponse)(nil),                // 2: proto.PostProductResponse
+	(*GetProductRequest)(nil),                  // 3: proto.GetProductRequest
+	(*GetProductResponse)(nil),                 // 4: proto.GetProductResponse
+	(*GetProductsRequest)(nil),                 // 5: proto.GetProductsRequest
+	(*GetProductsResponse)(nil),                // 6: proto.GetProductsResponse
+	(*UpdateProductRequest)(nil),               // 7: proto.UpdateProductRequest
+	(*UpdateProductResponse)(nil),              // 8: proto.UpdateProductResponse
+	(*UpdateQuantityRequest)(nil),              // 9: proto.UpdateQuantityRequest
+	(*UpdateQuantityResponse)(nil),             // 10: proto.UpdateQuantityResponse
+	(*UpdateRatingRequest)(nil),                // 11: proto.UpdateRatingRequest
+	(*UpdateRatingResponse)(nil),               // 12: proto.UpdateRatingResponse
+	(*StockLevel)(nil),                         // 13: proto.StockLevel
+	(*StockLevelResponse)(nil),                 // 14: proto.StockLevelResponse
+	(*RestockRequest)(nil),                     // 15: proto.RestockRequest
+	(*AdjustStockRequest)(nil),                 // 16: proto.AdjustStockRequest
+	(*Reservation)(nil),                        // 17: proto.Reservation
+	(*ReservationResponse)(nil),                // 18: proto.ReservationResponse
+	(*ReserveStockRequest)(nil),                // 19: proto.ReserveStockRequest
+	(*CommitReservationRequest)(nil),           // 20: proto.CommitReservationRequest
+	(*ReleaseReservationRequest)(nil),          // 21: proto.ReleaseReservationRequest
+	(*StockMovement)(nil),                      // 22: proto.StockMovement
+	(*GetStockMovementsRequest)(nil),           // 23: proto.GetStockMovementsRequest
+	(*GetStockMovementsResponse)(nil),          // 24: proto.GetStockMovementsResponse
+	(*StockNotification)(nil),                  // 25: proto.StockNotification
+	(*StockSubscriptionRequest)(nil),           // 26: proto.StockSubscriptionRequest
+	(*StockSubscriptionResponse)(nil),          // 27: proto.StockSubscriptionResponse
+	(*GetStockNotificationsRequest)(nil),       // 28: proto.GetStockNotificationsRequest
+	(*GetStockNotificationsResponse)(nil),      // 29: proto.GetStockNotificationsResponse
+	(*MarkStockNotificationsReadRequest)(nil),  // 30: proto.MarkStockNotificationsReadRequest
+	(*MarkStockNotificationsReadResponse)(nil), // 31: proto.MarkStockNotificationsReadResponse
+	(*ModerateProductRequest)(nil),             // 32: proto.ModerateProductRequest
+	(*EraseAccountRequest)(nil),                // 33: proto.EraseAccountRequest
+	(*EraseAccountResponse)(nil),               // 34: proto.EraseAccountResponse
+	(*Reservation_Item)(nil),                   // 35: proto.Reservation.Item
 }
 var file_catalog_proto_depIdxs = []int32{
 	0,  // 0: proto.PostProductResponse.product:type_name -> proto.Product
 	0,  // 1: proto.GetProductResponse.product:type_name -> proto.Product
 	0,  // 2: proto.GetProductsResponse.products:type_name -> proto.Product
 	0,  // 3: proto.UpdateProductRequest.product:type_name -> proto.Product
-	1,  // 4: proto.CatalogService.PostProduct:input_type -> proto.PostProductRequest
-	3,  // 5: proto.CatalogService.GetProduct:input_type -> proto.GetProductRequest
-	5,  // 6: proto.CatalogService.GetProducts:input_type -> proto.GetProductsRequest
-	0,  // 7: proto.CatalogService.UpdateProduct:input_type -> proto.Product
-	9,  // 8: proto.CatalogService.UpdateQuantity:input_type -> proto.UpdateQuantityRequest
-	2,  // 9: proto.CatalogService.PostProduct:output_type -> proto.PostProductResponse
-	4,  // 10: proto.CatalogService.GetProduct:output_type -> proto.GetProductResponse
-	6,  // 11: proto.CatalogService.GetProducts:output_type -> proto.GetProductsResponse
-	0,  // 12: proto.CatalogService.UpdateProduct:output_type -> proto.Product
-	10, // 13: proto.CatalogService.UpdateQuantity:output_type -> proto.UpdateQuantityResponse
-	9,  // [9:14] is the sub-list for method output_type
-	4,  // [4:9] is the sub-list for method input_type
-	4,  // [4:4] is the sub-list for extension type_name
-	4,  // [4:4] is the sub-list for extension extendee
-	0,  // [0:4] is the sub-list for field type_name
+	13, // 4: proto.StockLevelResponse.stock_level:type_name -> proto.StockLevel
+	35, // 5: proto.Reservation.items:type_name -> proto.Reservation.Item
+	17, // 6: proto.ReservationResponse.reservation:type_name -> proto.Reservation
+	35, // 7: proto.ReserveStockRequest.items:type_name -> proto.Reservation.Item
+	22, // 8: proto.GetStockMovementsResponse.movements:type_name -> proto.StockMovement
+	25, // 9: proto.GetStockNotificationsResponse.notifications:type_name -> proto.StockNotification
+	1,  // 10: proto.CatalogService.PostProduct:input_type -> proto.PostProductRequest
+	3,  // 11: proto.CatalogService.GetProduct:input_type -> proto.GetProductRequest
+	5,  // 12: proto.CatalogService.GetProducts:input_type -> proto.GetProductsRequest
+	0,  // 13: proto.CatalogService.UpdateProduct:input_type -> proto.Product
+	9,  // 14: proto.CatalogService.UpdateQuantity:input_type -> proto.UpdateQuantityRequest
+	11, // 15: proto.CatalogService.UpdateRating:input_type -> proto.UpdateRatingRequest
+	15, // 16: proto.CatalogService.Restock:input_type -> proto.RestockRequest
+	16, // 17: proto.CatalogService.AdjustStock:input_type -> proto.AdjustStockRequest
+	19, // 18: proto.CatalogService.ReserveStock:input_type -> proto.ReserveStockRequest
+	20, // 19: proto.CatalogService.CommitReservation:input_type -> proto.CommitReservationRequest
+	21, // 20: proto.CatalogService.ReleaseReservation:input_type -> proto.ReleaseReservationRequest
+	23, // 21: proto.CatalogService.GetStockMovements:input_type -> proto.GetStockMovementsRequest
+	26, // 22: proto.CatalogService.SubscribeBackInStock:input_type -> proto.StockSubscriptionRequest
+	26, // 23: proto.CatalogService.UnsubscribeBackInStock:input_type -> proto.StockSubscriptionRequest
+	28, // 24: proto.CatalogService.GetStockNotifications:input_type -> proto.GetStockNotificationsRequest
+	30, // 25: proto.CatalogService.MarkStockNotificationsRead:input_type -> proto.MarkStockNotificationsReadRequest
+	33, // 26: proto.CatalogService.EraseAccount:input_type -> proto.EraseAccountRequest
+	32, // 27: proto.CatalogService.ModerateProduct:input_type -> proto.ModerateProductRequest
+	2,  // 28: proto.CatalogService.PostProduct:output_type -> proto.PostProductResponse
+	4,  // 29: proto.CatalogService.GetProduct:output_type -> proto.GetProductResponse
+	6,  // 30: proto.CatalogService.GetProducts:output_type -> proto.GetProductsResponse
+	0,  // 31: proto.CatalogService.UpdateProduct:output_type -> proto.Product
+	10, // 32: proto.CatalogService.UpdateQuantity:output_type -> proto.UpdateQuantityResponse
+	12, // 33: proto.CatalogService.UpdateRating:output_type -> proto.UpdateRatingResponse
+	14, // 34: proto.CatalogService.Restock:output_type -> proto.StockLevelResponse
+	14, // 35: proto.CatalogService.AdjustStock:output_type -> proto.StockLevelResponse
+	18, // 36: proto.CatalogService.ReserveStock:output_type -> proto.ReservationResponse
+	18, // 37: proto.CatalogService.CommitReservation:output_type -> proto.ReservationResponse
+	18, // 38: proto.CatalogService.ReleaseReservation:output_type -> proto.ReservationResponse
+	24, // 39: proto.CatalogService.GetStockMovements:output_type -> proto.GetStockMovementsResponse
+	27, // 40: proto.CatalogService.SubscribeBackInStock:output_type -> proto.StockSubscriptionResponse
+	27, // 41: proto.CatalogService.UnsubscribeBackInStock:output_type -> proto.StockSubscriptionResponse
+	29, // 42: proto.CatalogService.GetStockNotifications:output_type -> proto.GetStockNotificationsResponse
+	31, // 43: proto.CatalogService.MarkStockNotificationsRead:output_type -> proto.MarkStockNotificationsReadResponse
+	34, // 44: proto.CatalogService.EraseAccount:output_type -> proto.EraseAccountResponse
+	0,  // 45: proto.CatalogService.ModerateProduct:output_type -> proto.Product
+	28, // [28:46] is the sub-list for method output_type
+	10, // [10:28] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_catalog_proto_init() }
@@ -753,7 +2411,7 @@ func file_catalog_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_catalog_proto_rawDesc), len(file_catalog_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   11,
+			NumMessages:   36,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -1,7 +1,7 @@
 // Code generated by protoc-gen-go-grpc. DO NOT EDIT.
 // versions:
 // - protoc-gen-go-grpc v1.5.1
-// - protoc             v5.29.2
+// - protoc             v6.33.0
 // source: catalog.proto
 
 package pb
@@ -19,11 +19,24 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	CatalogService_PostProduct_FullMethodName    = "/proto.CatalogService/PostProduct"
-	CatalogService_GetProduct_FullMethodName     = "/proto.CatalogService/GetProduct"
-	CatalogService_GetProducts_FullMethodName    = "/proto.CatalogService/GetProducts"
-	CatalogService_UpdateProduct_FullMethodName  = "/proto.CatalogService/UpdateProduct"
-	CatalogService_UpdateQuantity_FullMethodName = "/proto.CatalogService/UpdateQuantity"
+	CatalogService_PostProduct_FullMethodName                = "/proto.CatalogService/PostProduct"
+	CatalogService_GetProduct_FullMethodName                 = "/proto.CatalogService/GetProduct"
+	CatalogService_GetProducts_FullMethodName                = "/proto.CatalogService/GetProducts"
+	CatalogService_UpdateProduct_FullMethodName              = "/proto.CatalogService/UpdateProduct"
+	CatalogService_UpdateQuantity_FullMethodName             = "/proto.CatalogService/UpdateQuantity"
+	CatalogService_UpdateRating_FullMethodName               = "/proto.CatalogService/UpdateRating"
+	CatalogService_Restock_FullMethodName                    = "/proto.CatalogService/Restock"
+	CatalogService_AdjustStock_FullMethodName                = "/proto.CatalogService/AdjustStock"
+	CatalogService_ReserveStock_FullMethodName               = "/proto.CatalogService/ReserveStock"
+	CatalogService_CommitReservation_FullMethodName          = "/proto.CatalogService/CommitReservation"
+	CatalogService_ReleaseReservation_FullMethodName         = "/proto.CatalogService/ReleaseReservation"
+	CatalogService_GetStockMovements_FullMethodName          = "/proto.CatalogService/GetStockMovements"
+	CatalogService_SubscribeBackInStock_FullMethodName       = "/proto.CatalogService/SubscribeBackInStock"
+	CatalogService_UnsubscribeBackInStock_FullMethodName     = "/proto.CatalogService/UnsubscribeBackInStock"
+	CatalogService_GetStockNotifications_FullMethodName      = "/proto.CatalogService/GetStockNotifications"
+	CatalogService_MarkStockNotificationsRead_FullMethodName = "/proto.CatalogService/MarkStockNotificationsRead"
+	CatalogService_EraseAccount_FullMethodName               = "/proto.CatalogService/EraseAccount"
+	CatalogService_ModerateProduct_FullMethodName            = "/proto.CatalogService/ModerateProduct"
 )
 
 // CatalogServiceClient is the client API for CatalogService service.
@@ -35,6 +48,19 @@ type CatalogServiceClient interface {
 	GetProducts(ctx context.Context, in *GetProductsRequest, opts ...grpc.CallOption) (*GetProductsResponse, error)
 	UpdateProduct(ctx context.Context, in *Product, opts ...grpc.CallOption) (*Product, error)
 	UpdateQuantity(ctx context.Context, in *UpdateQuantityRequest, opts ...grpc.CallOption) (*UpdateQuantityResponse, error)
+	UpdateRating(ctx context.Context, in *UpdateRatingRequest, opts ...grpc.CallOption) (*UpdateRatingResponse, error)
+	Restock(ctx context.Context, in *RestockRequest, opts ...grpc.CallOption) (*StockLevelResponse, error)
+	AdjustStock(ctx context.Context, in *AdjustStockRequest, opts ...grpc.CallOption) (*StockLevelResponse, error)
+	ReserveStock(ctx context.Context, in *ReserveStockRequest, opts ...grpc.CallOption) (*ReservationResponse, error)
+	CommitReservation(ctx context.Context, in *CommitReservationRequest, opts ...grpc.CallOption) (*ReservationResponse, error)
+	ReleaseReservation(ctx context.Context, in *ReleaseReservationRequest, opts ...grpc.CallOption) (*ReservationResponse, error)
+	GetStockMovements(ctx context.Context, in *GetStockMovementsRequest, opts ...grpc.CallOption) (*GetStockMovementsResponse, error)
+	SubscribeBackInStock(ctx context.Context, in *StockSubscriptionRequest, opts ...grpc.CallOption) (*StockSubscriptionResponse, error)
+	UnsubscribeBackInStock(ctx context.Context, in *StockSubscriptionRequest, opts ...grpc.CallOption) (*StockSubscriptionResponse, error)
+	GetStockNotifications(ctx context.Context, in *GetStockNotificationsRequest, opts ...grpc.CallOption) (*GetStockNotificationsResponse, error)
+	MarkStockNotificationsRead(ctx context.Context, in *MarkStockNotificationsReadRequest, opts ...grpc.CallOption) (*MarkStockNotificationsReadResponse, error)
+	EraseAccount(ctx context.Context, in *EraseAccountRequest, opts ...grpc.CallOption) (*EraseAccountResponse, error)
+	ModerateProduct(ctx context.Context, in *ModerateProductRequest, opts ...grpc.CallOption) (*Product, error)
 }
 
 type catalogServiceClient struct {
@@ -95,6 +121,136 @@ func (c *catalogServiceClient) UpdateQuantity(ctx context.Context, in *UpdateQua
 	return out, nil
 }
 
+func (c *catalogServiceClient) UpdateRating(ctx context.Context, in *UpdateRatingRequest, opts ...grpc.CallOption) (*UpdateRatingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateRatingResponse)
+	err := c.cc.Invoke(ctx, CatalogService_UpdateRating_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) Restock(ctx context.Context, in *RestockRequest, opts ...grpc.CallOption) (*StockLevelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StockLevelResponse)
+	err := c.cc.Invoke(ctx, CatalogService_Restock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) AdjustStock(ctx context.Context, in *AdjustStockRequest, opts ...grpc.CallOption) (*StockLevelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StockLevelResponse)
+	err := c.cc.Invoke(ctx, CatalogService_AdjustStock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) ReserveStock(ctx context.Context, in *ReserveStockRequest, opts ...grpc.CallOption) (*ReservationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReservationResponse)
+	err := c.cc.Invoke(ctx, CatalogService_ReserveStock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) CommitReservation(ctx context.Context, in *CommitReservationRequest, opts ...grpc.CallOption) (*ReservationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReservationResponse)
+	err := c.cc.Invoke(ctx, CatalogService_CommitReservation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) ReleaseReservation(ctx context.Context, in *ReleaseReservationRequest, opts ...grpc.CallOption) (*ReservationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReservationResponse)
+	err := c.cc.Invoke(ctx, CatalogService_ReleaseReservation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) GetStockMovements(ctx context.Context, in *GetStockMovementsRequest, opts ...grpc.CallOption) (*GetStockMovementsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetStockMovementsResponse)
+	err := c.cc.Invoke(ctx, CatalogService_GetStockMovements_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) SubscribeBackInStock(ctx context.Context, in *StockSubscriptionRequest, opts ...grpc.CallOption) (*StockSubscriptionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StockSubscriptionResponse)
+	err := c.cc.Invoke(ctx, CatalogService_SubscribeBackInStock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) UnsubscribeBackInStock(ctx context.Context, in *StockSubscriptionRequest, opts ...grpc.CallOption) (*StockSubscriptionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StockSubscriptionResponse)
+	err := c.cc.Invoke(ctx, CatalogService_UnsubscribeBackInStock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) GetStockNotifications(ctx context.Context, in *GetStockNotificationsRequest, opts ...grpc.CallOption) (*GetStockNotificationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetStockNotificationsResponse)
+	err := c.cc.Invoke(ctx, CatalogService_GetStockNotifications_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) MarkStockNotificationsRead(ctx context.Context, in *MarkStockNotificationsReadRequest, opts ...grpc.CallOption) (*MarkStockNotificationsReadResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MarkStockNotificationsReadResponse)
+	err := c.cc.Invoke(ctx, CatalogService_MarkStockNotificationsRead_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) EraseAccount(ctx context.Context, in *EraseAccountRequest, opts ...grpc.CallOption) (*EraseAccountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EraseAccountResponse)
+	err := c.cc.Invoke(ctx, CatalogService_EraseAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogServiceClient) ModerateProduct(ctx context.Context, in *ModerateProductRequest, opts ...grpc.CallOption) (*Product, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Product)
+	err := c.cc.Invoke(ctx, CatalogService_ModerateProduct_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CatalogServiceServer is the server API for CatalogService service.
 // All implementations must embed UnimplementedCatalogServiceServer
 // for forward compatibility.
@@ -104,6 +260,19 @@ type CatalogServiceServer interface {
 	GetProducts(context.Context, *GetProductsRequest) (*GetProductsResponse, error)
 	UpdateProduct(context.Context, *Product) (*Product, error)
 	UpdateQuantity(context.Context, *UpdateQuantityRequest) (*UpdateQuantityResponse, error)
+	UpdateRating(context.Context, *UpdateRatingRequest) (*UpdateRatingResponse, error)
+	Restock(context.Context, *RestockRequest) (*StockLevelResponse, error)
+	AdjustStock(context.Context, *AdjustStockRequest) (*StockLevelResponse, error)
+	ReserveStock(context.Context, *ReserveStockRequest) (*ReservationResponse, error)
+	CommitReservation(context.Context, *CommitReservationRequest) (*ReservationResponse, error)
+	ReleaseReservation(context.Context, *ReleaseReservationRequest) (*ReservationResponse, error)
+	GetStockMovements(context.Context, *GetStockMovementsRequest) (*GetStockMovementsResponse, error)
+	SubscribeBackInStock(context.Context, *StockSubscriptionRequest) (*StockSubscriptionResponse, error)
+	UnsubscribeBackInStock(context.Context, *StockSubscriptionRequest) (*StockSubscriptionResponse, error)
+	GetStockNotifications(context.Context, *GetStockNotificationsRequest) (*GetStockNotificationsResponse, error)
+	MarkStockNotificationsRead(context.Context, *MarkStockNotificationsReadRequest) (*MarkStockNotificationsReadResponse, error)
+	EraseAccount(context.Context, *EraseAccountRequest) (*EraseAccountResponse, error)
+	ModerateProduct(context.Context, *ModerateProductRequest) (*Product, error)
 	mustEmbedUnimplementedCatalogServiceServer()
 }
 
@@ -129,6 +298,45 @@ func (UnimplementedCatalogServiceServer) UpdateProduct(context.Context, *Product
 func (UnimplementedCatalogServiceServer) UpdateQuantity(context.Context, *UpdateQuantityRequest) (*UpdateQuantityResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateQuantity not implemented")
 }
+func (UnimplementedCatalogServiceServer) UpdateRating(context.Context, *UpdateRatingRequest) (*UpdateRatingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateRating not implemented")
+}
+func (UnimplementedCatalogServiceServer) Restock(context.Context, *RestockRequest) (*StockLevelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restock not implemented")
+}
+func (UnimplementedCatalogServiceServer) AdjustStock(context.Context, *AdjustStockRequest) (*StockLevelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdjustStock not implemented")
+}
+func (UnimplementedCatalogServiceServer) ReserveStock(context.Context, *ReserveStockRequest) (*ReservationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReserveStock not implemented")
+}
+func (UnimplementedCatalogServiceServer) CommitReservation(context.Context, *CommitReservationRequest) (*ReservationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CommitReservation not implemented")
+}
+func (UnimplementedCatalogServiceServer) ReleaseReservation(context.Context, *ReleaseReservationRequest) (*ReservationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseReservation not implemented")
+}
+func (UnimplementedCatalogServiceServer) GetStockMovements(context.Context, *GetStockMovementsRequest) (*GetStockMovementsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStockMovements not implemented")
+}
+func (UnimplementedCatalogServiceServer) SubscribeBackInStock(context.Context, *StockSubscriptionRequest) (*StockSubscriptionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubscribeBackInStock not implemented")
+}
+func (UnimplementedCatalogServiceServer) UnsubscribeBackInStock(context.Context, *StockSubscriptionRequest) (*StockSubscriptionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UnsubscribeBackInStock not implemented")
+}
+func (UnimplementedCatalogServiceServer) GetStockNotifications(context.Context, *GetStockNotificationsRequest) (*GetStockNotificationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStockNotifications not implemented")
+}
+func (UnimplementedCatalogServiceServer) MarkStockNotificationsRead(context.Context, *MarkStockNotificationsReadRequest) (*MarkStockNotificationsReadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MarkStockNotificationsRead not implemented")
+}
+func (UnimplementedCatalogServiceServer) EraseAccount(context.Context, *EraseAccountRequest) (*EraseAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EraseAccount not implemented")
+}
+func (UnimplementedCatalogServiceServer) ModerateProduct(context.Context, *ModerateProductRequest) (*Product, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ModerateProduct not implemented")
+}
 func (UnimplementedCatalogServiceServer) mustEmbedUnimplementedCatalogServiceServer() {}
 func (UnimplementedCatalogServiceServer) testEmbeddedByValue()                        {}
 
@@ -240,6 +448,240 @@ func _CatalogService_UpdateQuantity_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CatalogService_UpdateRating_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateRatingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).UpdateRating(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_UpdateRating_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).UpdateRating(ctx, req.(*UpdateRatingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_Restock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).Restock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_Restock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).Restock(ctx, req.(*RestockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_AdjustStock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdjustStockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).AdjustStock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_AdjustStock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).AdjustStock(ctx, req.(*AdjustStockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_ReserveStock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReserveStockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).ReserveStock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_ReserveStock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).ReserveStock(ctx, req.(*ReserveStockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_CommitReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommitReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).CommitReservation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_CommitReservation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).CommitReservation(ctx, req.(*CommitReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_ReleaseReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleaseReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).ReleaseReservation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_ReleaseReservation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).ReleaseReservation(ctx, req.(*ReleaseReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_GetStockMovements_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStockMovementsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).GetStockMovements(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_GetStockMovements_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).GetStockMovements(ctx, req.(*GetStockMovementsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_SubscribeBackInStock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StockSubscriptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).SubscribeBackInStock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_SubscribeBackInStock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).SubscribeBackInStock(ctx, req.(*StockSubscriptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_UnsubscribeBackInStock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StockSubscriptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).UnsubscribeBackInStock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_UnsubscribeBackInStock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).UnsubscribeBackInStock(ctx, req.(*StockSubscriptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_GetStockNotifications_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStockNotificationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).GetStockNotifications(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_GetStockNotifications_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).GetStockNotifications(ctx, req.(*GetStockNotificationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_MarkStockNotificationsRead_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MarkStockNotificationsReadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).MarkStockNotificationsRead(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_MarkStockNotificationsRead_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).MarkStockNotificationsRead(ctx, req.(*MarkStockNotificationsReadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_EraseAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EraseAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).EraseAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_EraseAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).EraseAccount(ctx, req.(*EraseAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatalogService_ModerateProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModerateProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServiceServer).ModerateProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatalogService_ModerateProduct_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServiceServer).ModerateProduct(ctx, req.(*ModerateProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CatalogService_ServiceDesc is the grpc.ServiceDesc for CatalogService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -267,6 +709,58 @@ var CatalogService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateQuantity",
 			Handler:    _CatalogService_UpdateQuantity_Handler,
 		},
+		{
+			MethodName: "UpdateRating",
+			Handler:    _CatalogService_UpdateRating_Handler,
+		},
+		{
+			MethodName: "Restock",
+			Handler:    _CatalogService_Restock_Handler,
+		},
+		{
+			MethodName: "AdjustStock",
+			Handler:    _CatalogService_AdjustStock_Handler,
+		},
+		{
+			MethodName: "ReserveStock",
+			Handler:    _CatalogService_ReserveStock_Handler,
+		},
+		{
+			MethodName: "CommitReservation",
+			Handler:    _CatalogService_CommitReservation_Handler,
+		},
+		{
+			MethodName: "ReleaseReservation",
+			Handler:    _CatalogService_ReleaseReservation_Handler,
+		},
+		{
+			MethodName: "GetStockMovements",
+			Handler:    _CatalogService_GetStockMovements_Handler,
+		},
+		{
+			MethodName: "SubscribeBackInStock",
+			Handler:    _CatalogService_SubscribeBackInStock_Handler,
+		},
+		{
+			MethodName: "UnsubscribeBackInStock",
+			Handler:    _CatalogService_UnsubscribeBackInStock_Handler,
+		},
+		{
+			MethodName: "GetStockNotifications",
+			Handler:    _CatalogService_GetStockNotifications_Handler,
+		},
+		{
+			MethodName: "MarkStockNotificationsRead",
+			Handler:    _CatalogService_MarkStockNotificationsRead_Handler,
+		},
+		{
+			MethodName: "EraseAccount",
+			Handler:    _CatalogService_EraseAccount_Handler,
+		},
+		{
+			MethodName: "ModerateProduct",
+			Handler:    _CatalogService_ModerateProduct_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "catalog.proto",
@@ -0,0 +1,138 @@
+package pb
+
+import "github.com/231031/ecom-mcs-grpc/pkg/validate"
+
+// Validation rules of the catalog requests, enforced by the service and the
+// gateway through the validate interceptors.
+
+const (
+	maxNameLen        = 200
+	maxDescriptionLen = 5000
+	maxQueryLen       = 200
+	maxNoteLen        = 500
+	maxRating         = 5.0
+)
+
+func (m *PostProductRequest) Validate() error {
+	return validate.Check(
+		validate.Required("name", m.GetName()),
+		validate.MaxLen("name", m.GetName(), maxNameLen),
+		validate.MaxLen("description", m.GetDescription(), maxDescriptionLen),
+		validate.Positive("price", m.GetPrice()),
+		validate.Required("seller_id", m.GetSellerId()),
+	)
+}
+
+func (m *GetProductRequest) Validate() error {
+	return validate.Check(
+		validate.Required("id", m.GetId()),
+	)
+}
+
+func (m *GetProductsRequest) Validate() error {
+	return validate.Check(
+		validate.EachRequired("ids", m.GetIds()),
+		validate.MaxLen("query", m.GetQuery(), maxQueryLen),
+		validate.Between("min_rating", m.GetMinRating(), 0, maxRating),
+	)
+}
+
+// Validate checks a product update, the fields left at their zero value are
+// not changed so only the id is required.
+func (m *Product) Validate() error {
+	return validate.Check(
+		validate.Required("id", m.GetId()),
+		validate.MaxLen("name", m.GetName(), maxNameLen),
+		validate.MaxLen("description", m.GetDescription(), maxDescriptionLen),
+		validate.NonNegative("price", m.GetPrice()),
+	)
+}
+
+func (m *UpdateQuantityRequest) Validate() error {
+	return validate.Check(
+		validate.EachRequired("ids", m.GetIds()),
+		validate.SameLen("quantity", "ids", len(m.GetQuantity()), len(m.GetIds())),
+	)
+}
+
+func (m *UpdateRatingRequest) Validate() error {
+	return validate.Check(
+		validate.Required("product_id", m.GetProductId()),
+		validate.Between("average_rating", m.GetAverageRating(), 0, maxRating),
+	)
+}
+
+func (m *RestockRequest) Validate() error {
+	return validate.Check(
+		validate.Required("product_id", m.GetProductId()),
+		validate.Positive("quantity", m.GetQuantity()),
+		validate.MaxLen("note", m.GetNote(), maxNoteLen),
+	)
+}
+
+func (m *AdjustStockRequest) Validate() error {
+	return validate.Check(
+		validate.Required("product_id", m.GetProductId()),
+		validate.NonZero("delta", m.GetDelta()),
+		validate.MaxLen("note", m.GetNote(), maxNoteLen),
+	)
+}
+
+func (m *Reservation_Item) Validate() error {
+	return validate.Check(
+		validate.Required("product_id", m.GetProductId()),
+		validate.Positive("quantity", m.GetQuantity()),
+	)
+}
+
+func (m *ReserveStockRequest) Validate() error {
+	return validate.Check(
+		validate.MinItems("items", len(m.GetItems()), 1),
+		validate.Each("items", m.GetItems()),
+	)
+}
+
+func (m *CommitReservationRequest) Validate() error {
+	return validate.Check(
+		validate.Required("id", m.GetId()),
+	)
+}
+
+func (m *ReleaseReservationRequest) Validate() error {
+	return validate.Check(
+		validate.Required("id", m.GetId()),
+	)
+}
+
+func (m *GetStockMovementsRequest) Validate() error {
+	return validate.Check(
+		validate.Required("product_id", m.GetProductId()),
+	)
+}
+
+func (m *StockSubscriptionRequest) Validate() error {
+	return validate.Check(
+		validate.Required("product_id", m.GetProductId()),
+		validate.Required("account_id", m.GetAccountId()),
+	)
+}
+
+func (m *GetStockNotificationsRequest) Validate() error {
+	return validate.Check(
+		validate.Required("recipient_id", m.GetRecipientId()),
+	)
+}
+
+func (m *MarkStockNotificationsReadRequest) Validate() error {
+	return validate.Check(
+		validate.Required("recipient_id", m.GetRecipientId()),
+		validate.EachRequired("ids", m.GetIds()),
+	)
+}
+
+func (m *ModerateProductRequest) Validate() error {
+	return validate.Check(
+		validate.Required("product_id", m.GetProductId()),
+		validate.MaxLen("note", m.GetNote(), maxNoteLen),
+	)
+}
@@ -0,0 +1,46 @@
+package catalog
+
+import (
+	"github.com/231031/ecom-mcs-grpc/catalog/pb"
+	"github.com/231031/ecom-mcs-grpc/pkg/auth"
+)
+
+// policies says who may call each method of the service. The methods on a
+// product of a seller only check the role here, the handler checks that the
+// product is theirs. Admins may adjust the stock of any product.
+var policies = auth.Policies{
+	pb.CatalogService_PostProduct_FullMethodName: auth.OwnedBy(func(r *pb.PostProductRequest) string {
+		return r.GetSellerId()
+	}, auth.RoleSeller),
+
+	pb.CatalogService_GetProduct_FullMethodName:  auth.Public(),
+	pb.CatalogService_GetProducts_FullMethodName: auth.Public(),
+
+	pb.CatalogService_UpdateProduct_FullMethodName:  auth.Roles(auth.RoleSeller),
+	pb.CatalogService_UpdateQuantity_FullMethodName: auth.Internal(),
+	pb.CatalogService_UpdateRating_FullMethodName:   auth.Internal(),
+
+	pb.CatalogService_Restock_FullMethodName:            auth.Roles(auth.RoleSeller),
+	pb.CatalogService_AdjustStock_FullMethodName:        auth.Roles(auth.RoleSeller, auth.RoleAdmin),
+	pb.CatalogService_ReserveStock_FullMethodName:       auth.Internal(),
+	pb.CatalogService_CommitReservation_FullMethodName:  auth.Internal(),
+	pb.CatalogService_ReleaseReservation_FullMethodName: auth.Internal(),
+	pb.CatalogService_GetStockMovements_FullMethodName:  auth.Roles(auth.RoleSeller, auth.RoleAdmin),
+
+	pb.CatalogService_SubscribeBackInStock_FullMethodName: auth.OwnedBy(func(r *pb.StockSubscriptionRequest) string {
+		return r.GetAccountId()
+	}, auth.RoleBuyer),
+	pb.CatalogService_UnsubscribeBackInStock_FullMethodName: auth.OwnedBy(func(r *pb.StockSubscriptionRequest) string {
+		return r.GetAccountId()
+	}, auth.RoleBuyer),
+	pb.CatalogService_GetStockNotifications_FullMethodName: auth.OwnedBy(func(r *pb.GetStockNotificationsRequest) string {
+		return r.GetRecipientId()
+	}, auth.RoleBuyer, auth.RoleSeller),
+	pb.CatalogService_MarkStockNotificationsRead_FullMethodName: auth.OwnedBy(func(r *pb.MarkStockNotificationsReadRequest) string {
+		return r.GetRecipientId()
+	}, auth.RoleBuyer, auth.RoleSeller),
+	// the authentication service erases the account once its deletion is due
+	pb.CatalogService_EraseAccount_FullMethodName: auth.Internal(),
+
+	pb.CatalogService_ModerateProduct_FullMethodName: auth.Roles(auth.RoleAdmin),
+}
@@ -16,20 +16,23 @@ import (
 )
 
 var (
-	ErrNotFound     = errors.New("product not found")
-	ErrAlreadyExist = errors.New("product is already registered")
-	ErrPutProduct   = errors.New("falied to put product")
+	ErrNotFound         = errors.New("product not found")
+	ErrPutProduct       = errors.New("falied to put product")
+	ErrInvalidRating    = errors.New("rating must be between 0 and 5")
+	ErrQuantityMismatch = errors.New("ids and quantity must have the same length")
 )
 
 type Repository interface {
 	Close()
 	PutProduct(ctx context.Context, p Product) error
 	GetProductByID(ctx context.Context, id string) (*Product, error)
-	ListProducts(ctx context.Context, skip uint64, take uint64) ([]Product, error)
+	ListProducts(ctx context.Context, skip uint64, take uint64, filter ProductFilter) ([]Product, error)
 	ListProductsWithIDs(ctx context.Context, ids []string) ([]Product, error)
-	SearchProducts(ctx context.Context, query string, skip uint64, take uint64) ([]Product, error)
+	SearchProducts(ctx context.Context, query string, skip uint64, take uint64, filter ProductFilter) ([]Product, error)
 	UpdateQuantity(ctx context.Context, ids []string, quantity []uint32) error
 	UpdateProduct(ctx context.Context, p map[string]interface{}) error
+	UpdateRating(ctx context.Context, id string, averageRating float64, reviewCount uint32) error
+	ModerateProduct(ctx context.Context, id string, unpublished bool, note string) error
 }
 
 type elasticRepository struct {
@@ -55,22 +58,13 @@ func (r *elasticRepository) Close() {
 }
 
 func (r *elasticRepository) PutProduct(ctx context.Context, p Product) error {
-	query := createQueryCheck(p)
-	pFound, err := r.SearchProducts(ctx, query, 0, 1)
-	if err != nil {
-		return err
-	}
-
-	if len(pFound) != 0 {
-		return ErrAlreadyExist
-	}
-
 	product := productDocument{
-		Name:        p.Name,
-		Description: p.Description,
-		Price:       p.Price,
-		Quantity:    p.Quantity,
-		SellerID:    p.SellerID,
+		Name:              p.Name,
+		Description:       p.Description,
+		Price:             p.Price,
+		Quantity:          p.Quantity,
+		LowStockThreshold: p.LowStockThreshold,
+		SellerID:          p.SellerID,
 	}
 	productJson, err := json.Marshal(product)
 	if err != nil {
@@ -122,40 +116,22 @@ func (r *elasticRepository) GetProductByID(ctx context.Context, id string) (*Pro
 	}
 
 	return &Product{
-		ID:          id,
-		Name:        p.Source.Name,
-		Description: p.Source.Description,
-		Price:       p.Source.Price,
-		Quantity:    p.Source.Quantity,
+		ID:                id,
+		Name:              p.Source.Name,
+		Description:       p.Source.Description,
+		Price:             p.Source.Price,
+		Quantity:          p.Source.Quantity,
+		LowStockThreshold: p.Source.LowStockThreshold,
+		AverageRating:     p.Source.AverageRating,
+		ReviewCount:       p.Source.ReviewCount,
+		SellerID:          p.Source.SellerID,
+		Unpublished:       p.Source.Unpublished,
+		ModerationNote:    p.Source.ModerationNote,
 	}, nil
 }
 
-func (r *elasticRepository) ListProducts(ctx context.Context, skip uint64, take uint64) ([]Product, error) {
-	resp, err := r.client.Search(
-		r.client.Search.WithContext(ctx),
-		r.client.Search.WithIndex("products"),
-		r.client.Search.WithSize(int(take)),
-		r.client.Search.WithFrom(int(skip)),
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	defer resp.Body.Close()
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	listResp := listsProductResp{}
-	err = json.Unmarshal(body, &listResp)
-	if err != nil {
-		return nil, err
-	}
-
-	products := []Product{}
-	mapProductResponse(listResp.Hits.Hits, &products)
-	return products, nil
+func (r *elasticRepository) ListProducts(ctx context.Context, skip uint64, take uint64, filter ProductFilter) ([]Product, error) {
+	return r.searchProducts(ctx, "", skip, take, filter)
 }
 
 func (r *elasticRepository) ListProductsWithIDs(ctx context.Context, ids []string) ([]Product, error) {
@@ -188,11 +164,17 @@ func (r *elasticRepository) ListProductsWithIDs(ctx context.Context, ids []strin
 	for _, p := range listResp.Hits {
 		if p.Found {
 			products = append(products, Product{
-				ID:          p.ID,
-				Name:        p.Source.Name,
-				Description: p.Source.Description,
-				Price:       p.Source.Price,
-				Quantity:    p.Source.Quantity,
+				ID:                p.ID,
+				Name:              p.Source.Name,
+				Description:       p.Source.Description,
+				Price:             p.Source.Price,
+				Quantity:          p.Source.Quantity,
+				LowStockThreshold: p.Source.LowStockThreshold,
+				AverageRating:     p.Source.AverageRating,
+				ReviewCount:       p.Source.ReviewCount,
+				SellerID:          p.Source.SellerID,
+				Unpublished:       p.Source.Unpublished,
+				ModerationNote:    p.Source.ModerationNote,
 			})
 		}
 	}
@@ -200,11 +182,20 @@ func (r *elasticRepository) ListProductsWithIDs(ctx context.Context, ids []strin
 	return products, nil
 }
 
-func (r *elasticRepository) SearchProducts(ctx context.Context, query string, skip uint64, take uint64) ([]Product, error) {
+func (r *elasticRepository) SearchProducts(ctx context.Context, query string, skip uint64, take uint64, filter ProductFilter) ([]Product, error) {
+	return r.searchProducts(ctx, query, skip, take, filter)
+}
+
+func (r *elasticRepository) searchProducts(ctx context.Context, query string, skip uint64, take uint64, filter ProductFilter) ([]Product, error) {
+	searchJson, err := json.Marshal(productSearchBody(query, filter))
+	if err != nil {
+		return nil, err
+	}
+
 	resp, err := r.client.Search(
 		r.client.Search.WithContext(ctx),
 		r.client.Search.WithIndex("products"),
-		r.client.Search.WithQuery(query),
+		r.client.Search.WithBody(bytes.NewReader(searchJson)),
 		r.client.Search.WithFrom(int(skip)),
 		r.client.Search.WithSize(int(take)),
 	)
@@ -230,6 +221,10 @@ func (r *elasticRepository) SearchProducts(ctx context.Context, query string, sk
 }
 
 func (r *elasticRepository) UpdateQuantity(ctx context.Context, ids []string, quantity []uint32) error {
+	if len(ids) != len(quantity) {
+		return ErrQuantityMismatch
+	}
+
 	var builder strings.Builder
 	for i := range ids {
 		builder.WriteString(fmt.Sprintf(`{ "update": { "_index": "products", "_id": "%s" } }%s`, ids[i], "\n"))
@@ -287,3 +282,67 @@ func (r *elasticRepository) UpdateProduct(ctx context.Context, p map[string]inte
 
 	return nil
 }
+
+func (r *elasticRepository) UpdateRating(ctx context.Context, id string, averageRating float64, reviewCount uint32) error {
+	docJson, err := json.Marshal(map[string]interface{}{
+		"doc": map[string]interface{}{
+			"average_rating": averageRating,
+			"review_count":   reviewCount,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.client.Update(
+		"products",
+		id,
+		bytes.NewReader(docJson),
+		r.client.Update.WithContext(ctx),
+	)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.IsError() {
+		log.Println(resp.String())
+		return ErrPutProduct
+	}
+	return nil
+}
+
+func (r *elasticRepository) ModerateProduct(ctx context.Context, id string, unpublished bool, note string) error {
+	docJson, err := json.Marshal(map[string]interface{}{
+		"doc": map[string]interface{}{
+			"unpublished":     unpublished,
+			"moderation_note": note,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.client.Update(
+		"products",
+		id,
+		bytes.NewReader(docJson),
+		r.client.Update.WithContext(ctx),
+	)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.IsError() {
+		log.Println(resp.String())
+		return ErrPutProduct
+	}
+	return nil
+}
@@ -3,22 +3,42 @@ package catalog
 import (
 	"context"
 	"fmt"
+	"log"
 	"net"
+	"time"
 
 	"github.com/231031/ecom-mcs-grpc/account"
 	"github.com/231031/ecom-mcs-grpc/catalog/pb"
+	"github.com/231031/ecom-mcs-grpc/pkg/audit"
+	"github.com/231031/ecom-mcs-grpc/pkg/auth"
+	"github.com/231031/ecom-mcs-grpc/pkg/grpcerr"
+	"github.com/231031/ecom-mcs-grpc/pkg/idempotency"
+	"github.com/231031/ecom-mcs-grpc/pkg/validate"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 )
 
+var errorMappings = []grpcerr.Mapping{
+	grpcerr.NotFound(ErrNotFound),
+	grpcerr.Validation(ErrInvalidRating, "averageRating"),
+	grpcerr.Validation(ErrQuantityMismatch, "quantity"),
+	grpcerr.OutOfStock(ErrOutOfStock),
+	grpcerr.Validation(ErrInvalidStockQuantity, "quantity"),
+	grpcerr.NotFound(ErrReservationNotFound),
+	grpcerr.Conflict(ErrReservationNotActive),
+	grpcerr.Validation(ErrInvalidReservationTTL, "ttlSeconds"),
+	grpcerr.Validation(ErrEmptyReservation, "items"),
+	grpcerr.Forbidden(auth.ErrForbidden),
+}
+
 type grpcServer struct {
 	service       Service
 	accountClient *account.Client
 	pb.UnimplementedCatalogServiceServer
 }
 
-func ListenGRPC(s Service, port int, accountURL string) error {
-	accountClient, err := account.NewClient(accountURL)
+func ListenGRPC(s Service, authn *auth.Authenticator, idempotencyStore idempotency.Store, auditStore audit.Store, port int, accountURL string) error {
+	accountClient, err := account.NewClient(accountURL, authn.DialOption())
 	if err != nil {
 		return err
 	}
@@ -29,7 +49,23 @@ func ListenGRPC(s Service, port int, accountURL string) error {
 		return err
 	}
 
-	serve := grpc.NewServer()
+	serve := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			grpcerr.UnaryServerInterceptor(errorMappings...),
+			authn.UnaryServerInterceptor(policies),
+			validate.UnaryServerInterceptor(),
+			idempotency.UnaryServerInterceptor(
+				idempotencyStore,
+				idempotency.DefaultTTL,
+				pb.CatalogService_PostProduct_FullMethodName,
+			),
+			audit.UnaryServerInterceptor(
+				auditStore,
+				pb.CatalogService_ModerateProduct_FullMethodName,
+				pb.CatalogService_AdjustStock_FullMethodName,
+			),
+		),
+	)
 	grpcServiceServer := &grpcServer{
 		service:       s,
 		accountClient: accountClient,
@@ -47,19 +83,20 @@ func (s *grpcServer) PostProduct(ctx context.Context, r *pb.PostProductRequest)
 		return nil, err
 	}
 
-	p, err := s.service.PostProduct(ctx, r.Name, r.Description, r.SellerId, r.Price, r.Quantity)
+	p, err := s.service.PostProduct(ctx, r.Name, r.Description, r.SellerId, r.Price, r.Quantity, r.LowStockThreshold)
 	if err != nil {
 		return nil, err
 	}
 
 	return &pb.PostProductResponse{
 		Product: &pb.Product{
-			Id:          p.ID,
-			Name:        p.Name,
-			Description: p.Description,
-			Price:       p.Price,
-			Quantity:    p.Quantity,
-			SellerId:    p.SellerID,
+			Id:                p.ID,
+			Name:              p.Name,
+			Description:       p.Description,
+			Price:             p.Price,
+			Quantity:          p.Quantity,
+			LowStockThreshold: p.LowStockThreshold,
+			SellerId:          p.SellerID,
 		},
 	}, nil
 
@@ -69,15 +106,23 @@ func (s *grpcServer) GetProduct(ctx context.Context, r *pb.GetProductRequest) (*
 	if err != nil {
 		return nil, err
 	}
+	if p.Unpublished && !canSeeUnpublished(ctx, p) {
+		return nil, ErrNotFound
+	}
 
 	return &pb.GetProductResponse{
 		Product: &pb.Product{
-			Id:          p.ID,
-			Name:        p.Name,
-			Description: p.Description,
-			Price:       p.Price,
-			Quantity:    p.Quantity,
-			SellerId:    p.SellerID,
+			Id:                p.ID,
+			Name:              p.Name,
+			Description:       p.Description,
+			Price:             p.Price,
+			Quantity:          p.Quantity,
+			LowStockThreshold: p.LowStockThreshold,
+			AverageRating:     p.AverageRating,
+			ReviewCount:       p.ReviewCount,
+			SellerId:          p.SellerID,
+			Unpublished:       p.Unpublished,
+			ModerationNote:    p.ModerationNote,
 		},
 	}, nil
 }
@@ -86,12 +131,13 @@ func (s *grpcServer) GetProducts(ctx context.Context, r *pb.GetProductsRequest)
 	var res []Product
 	var err error
 
+	filter := ProductFilter{MinRating: r.MinRating, SortByRating: r.SortByRating}
 	if r.Query != "" {
-		res, err = s.service.SearchProducts(ctx, r.Query, r.Skip, r.Take)
+		res, err = s.service.SearchProducts(ctx, r.Query, r.Skip, r.Take, filter)
 	} else if len(r.Ids) > 0 {
 		res, err = s.service.GetProductByIDs(ctx, r.Ids)
 	} else {
-		res, err = s.service.GetProducts(ctx, r.Skip, r.Take)
+		res, err = s.service.GetProducts(ctx, r.Skip, r.Take, filter)
 	}
 
 	if err != nil {
@@ -101,11 +147,17 @@ func (s *grpcServer) GetProducts(ctx context.Context, r *pb.GetProductsRequest)
 	products := []*pb.Product{}
 	for _, p := range res {
 		products = append(products, &pb.Product{
-			Id:          p.ID,
-			Name:        p.Name,
-			Description: p.Description,
-			Price:       p.Price,
-			Quantity:    p.Quantity,
+			Id:                p.ID,
+			Name:              p.Name,
+			Description:       p.Description,
+			Price:             p.Price,
+			Quantity:          p.Quantity,
+			LowStockThreshold: p.LowStockThreshold,
+			AverageRating:     p.AverageRating,
+			ReviewCount:       p.ReviewCount,
+			SellerId:          p.SellerID,
+			Unpublished:       p.Unpublished,
+			ModerationNote:    p.ModerationNote,
 		})
 	}
 	return &pb.GetProductsResponse{Products: products}, nil
@@ -124,12 +176,17 @@ func (s *grpcServer) UpdateQuantity(ctx context.Context, req *pb.UpdateQuantityR
 }
 
 func (s *grpcServer) UpdateProduct(ctx context.Context, req *pb.Product) (*pb.Product, error) {
+	if err := s.checkSeller(ctx, req.Id); err != nil {
+		return nil, err
+	}
+
 	p := Product{
-		ID:          req.Id,
-		Name:        req.Name,
-		Price:       req.Price,
-		Description: req.Description,
-		Quantity:    req.Quantity,
+		ID:                req.Id,
+		Name:              req.Name,
+		Price:             req.Price,
+		Description:       req.Description,
+		Quantity:          req.Quantity,
+		LowStockThreshold: req.LowStockThreshold,
 	}
 
 	_, err := s.service.UpdateProduct(ctx, p)
@@ -139,3 +196,261 @@ func (s *grpcServer) UpdateProduct(ctx context.Context, req *pb.Product) (*pb.Pr
 
 	return req, nil
 }
+
+func (s *grpcServer) UpdateRating(ctx context.Context, req *pb.UpdateRatingRequest) (*pb.UpdateRatingResponse, error) {
+	err := s.service.UpdateRating(ctx, req.ProductId, req.AverageRating, req.ReviewCount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.UpdateRatingResponse{ProductId: req.ProductId}, nil
+}
+
+func (s *grpcServer) Restock(ctx context.Context, req *pb.RestockRequest) (*pb.StockLevelResponse, error) {
+	if err := s.checkSeller(ctx, req.ProductId); err != nil {
+		return nil, err
+	}
+
+	level, err := s.service.Restock(ctx, req.ProductId, req.Quantity, req.Reference, req.Note)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.StockLevelResponse{StockLevel: stockLevelToProto(*level)}, nil
+}
+
+func (s *grpcServer) AdjustStock(ctx context.Context, req *pb.AdjustStockRequest) (*pb.StockLevelResponse, error) {
+	if err := s.checkSeller(ctx, req.ProductId); err != nil {
+		return nil, err
+	}
+
+	level, err := s.service.AdjustStock(ctx, req.ProductId, req.Delta, req.Note)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.StockLevelResponse{StockLevel: stockLevelToProto(*level)}, nil
+}
+
+func (s *grpcServer) ReserveStock(ctx context.Context, req *pb.ReserveStockRequest) (*pb.ReservationResponse, error) {
+	items := []ReservationItem{}
+	for _, it := range req.Items {
+		items = append(items, ReservationItem{
+			ProductID: it.ProductId,
+			Quantity:  it.Quantity,
+		})
+	}
+
+	r, err := s.service.ReserveStock(ctx, items, time.Duration(req.TtlSeconds)*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ReservationResponse{Reservation: reservationToProto(*r)}, nil
+}
+
+func (s *grpcServer) CommitReservation(ctx context.Context, req *pb.CommitReservationRequest) (*pb.ReservationResponse, error) {
+	r, err := s.service.CommitReservation(ctx, req.Id, req.Reference)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ReservationResponse{Reservation: reservationToProto(*r)}, nil
+}
+
+func (s *grpcServer) ReleaseReservation(ctx context.Context, req *pb.ReleaseReservationRequest) (*pb.ReservationResponse, error) {
+	r, err := s.service.ReleaseReservation(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.ReservationResponse{Reservation: reservationToProto(*r)}, nil
+}
+
+func (s *grpcServer) GetStockMovements(ctx context.Context, req *pb.GetStockMovementsRequest) (*pb.GetStockMovementsResponse, error) {
+	if err := s.checkSeller(ctx, req.ProductId); err != nil {
+		return nil, err
+	}
+
+	movements, err := s.service.GetStockMovements(ctx, req.ProductId, req.Skip, req.Take)
+	if err != nil {
+		return nil, err
+	}
+
+	movementsProto := []*pb.StockMovement{}
+	for _, m := range movements {
+		createdAt, err := m.CreatedAt.MarshalBinary()
+		if err != nil {
+			log.Println("error marshal timestamp", err)
+		}
+
+		movementsProto = append(movementsProto, &pb.StockMovement{
+			Id:            m.ID,
+			ProductId:     m.ProductID,
+			Kind:          m.Kind,
+			OnHandDelta:   m.OnHandDelta,
+			ReservedDelta: m.ReservedDelta,
+			ReservationId: m.ReservationID,
+			Reference:     m.Reference,
+			Note:          m.Note,
+			CreatedAt:     createdAt,
+		})
+	}
+	return &pb.GetStockMovementsResponse{Movements: movementsProto}, nil
+}
+
+func stockLevelToProto(l StockLevel) *pb.StockLevel {
+	return &pb.StockLevel{
+		ProductId: l.ProductID,
+		OnHand:    l.OnHand,
+		Reserved:  l.Reserved,
+		Available: l.Available(),
+	}
+}
+
+func reservationToProto(r Reservation) *pb.Reservation {
+	reservationProto := &pb.Reservation{
+		Id:        r.ID,
+		Status:    r.Status,
+		Reference: r.Reference,
+		Items:     []*pb.Reservation_Item{},
+	}
+	for _, it := range r.Items {
+		reservationProto.Items = append(reservationProto.Items, &pb.Reservation_Item{
+			ProductId: it.ProductID,
+			Quantity:  it.Quantity,
+		})
+	}
+
+	var err error
+	reservationProto.ExpiresAt, err = r.ExpiresAt.MarshalBinary()
+	if err != nil {
+		log.Println("error marshal timestamp", err)
+	}
+	reservationProto.CreatedAt, err = r.CreatedAt.MarshalBinary()
+	if err != nil {
+		log.Println("error marshal timestamp", err)
+	}
+	reservationProto.UpdatedAt, err = r.UpdatedAt.MarshalBinary()
+	if err != nil {
+		log.Println("error marshal timestamp", err)
+	}
+
+	return reservationProto
+}
+
+func (s *grpcServer) SubscribeBackInStock(ctx context.Context, req *pb.StockSubscriptionRequest) (*pb.StockSubscriptionResponse, error) {
+	err := s.service.SubscribeBackInStock(ctx, req.ProductId, req.AccountId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.StockSubscriptionResponse{ProductId: req.ProductId}, nil
+}
+
+func (s *grpcServer) UnsubscribeBackInStock(ctx context.Context, req *pb.StockSubscriptionRequest) (*pb.StockSubscriptionResponse, error) {
+	err := s.service.UnsubscribeBackInStock(ctx, req.ProductId, req.AccountId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.StockSubscriptionResponse{ProductId: req.ProductId}, nil
+}
+
+func (s *grpcServer) GetStockNotifications(ctx context.Context, req *pb.GetStockNotificationsRequest) (*pb.GetStockNotificationsResponse, error) {
+	notifications, err := s.service.GetStockNotifications(ctx, req.RecipientId, req.UnreadOnly, req.Skip, req.Take)
+	if err != nil {
+		return nil, err
+	}
+
+	notificationsProto := []*pb.StockNotification{}
+	for _, n := range notifications {
+		notificationProto := &pb.StockNotification{
+			Id:          n.ID,
+			RecipientId: n.RecipientID,
+			ProductId:   n.ProductID,
+			Kind:        n.Kind,
+			Quantity:    n.Quantity,
+			Threshold:   n.Threshold,
+		}
+
+		notificationProto.CreatedAt, err = n.CreatedAt.MarshalBinary()
+		if err != nil {
+			log.Println("error marshal timestamp", err)
+		}
+		if n.ReadAt != nil {
+			notificationProto.ReadAt, err = n.ReadAt.MarshalBinary()
+			if err != nil {
+				log.Println("error marshal timestamp", err)
+			}
+		}
+		notificationsProto = append(notificationsProto, notificationProto)
+	}
+	return &pb.GetStockNotificationsResponse{Notifications: notificationsProto}, nil
+}
+
+func (s *grpcServer) MarkStockNotificationsRead(ctx context.Context, req *pb.MarkStockNotificationsReadRequest) (*pb.MarkStockNotificationsReadResponse, error) {
+	ids, err := s.service.MarkStockNotificationsRead(ctx, req.RecipientId, req.Ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.MarkStockNotificationsReadResponse{Ids: ids}, nil
+}
+
+func (s *grpcServer) EraseAccount(ctx context.Context, req *pb.EraseAccountRequest) (*pb.EraseAccountResponse, error) {
+	if err := s.service.EraseAccount(ctx, req.AccountId); err != nil {
+		return nil, err
+	}
+
+	return &pb.EraseAccountResponse{}, nil
+}
+
+func (s *grpcServer) ModerateProduct(ctx context.Context, req *pb.ModerateProductRequest) (*pb.Product, error) {
+	p, err := s.service.ModerateProduct(ctx, req.ProductId, req.Unpublished, req.Note)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.Product{
+		Id:                p.ID,
+		Name:              p.Name,
+		Description:       p.Description,
+		Price:             p.Price,
+		Quantity:          p.Quantity,
+		LowStockThreshold: p.LowStockThreshold,
+		AverageRating:     p.AverageRating,
+		ReviewCount:       p.ReviewCount,
+		SellerId:          p.SellerID,
+		Unpublished:       p.Unpublished,
+		ModerationNote:    p.ModerationNote,
+	}, nil
+}
+
+// checkSeller makes sure a seller calling only touches their own product,
+// other services and admins are trusted with any.
+func (s *grpcServer) checkSeller(ctx context.Context, productID string) error {
+	id, ok := auth.FromContext(ctx)
+	if !ok || id.IsService() || id.HasRole(auth.RoleAdmin) {
+		return nil
+	}
+
+	p, err := s.service.GetProduct(ctx, productID)
+	if err != nil {
+		return err
+	}
+	if p.SellerID != id.UserID {
+		return auth.ErrForbidden
+	}
+	return nil
+}
+
+// canSeeUnpublished tells whether the caller may still look a product up
+// once it is unpublished: other services, admins and the seller owning it.
+func canSeeUnpublished(ctx context.Context, p *Product) bool {
+	id, ok := auth.FromContext(ctx)
+	if !ok {
+		return false
+	}
+	return id.IsService() || id.HasRole(auth.RoleAdmin) || (id.HasRole(auth.RoleSeller) && id.UserID == p.SellerID)
+}
@@ -3,35 +3,55 @@ package catalog
 import (
 	"context"
 	"log"
+	"time"
 
 	"github.com/segmentio/ksuid"
 )
 
 type Service interface {
-	PostProduct(ctx context.Context, name, description, seller_id string, price float64, quantity uint32) (*Product, error)
+	PostProduct(ctx context.Context, name, description, seller_id string, price float64, quantity, lowStockThreshold uint32) (*Product, error)
 	GetProduct(ctx context.Context, id string) (*Product, error)
-	GetProducts(ctx context.Context, skip uint64, take uint64) ([]Product, error)
+	GetProducts(ctx context.Context, skip uint64, take uint64, filter ProductFilter) ([]Product, error)
 	GetProductByIDs(ctx context.Context, ids []string) ([]Product, error)
-	SearchProducts(ctx context.Context, query string, skip uint64, take uint64) ([]Product, error)
+	SearchProducts(ctx context.Context, query string, skip uint64, take uint64, filter ProductFilter) ([]Product, error)
 	UpdateQuantity(ctx context.Context, ids []string, quantity []uint32) ([]string, error)
 	UpdateProduct(ctx context.Context, p Product) (*Product, error)
+	UpdateRating(ctx context.Context, id string, averageRating float64, reviewCount uint32) error
+	ModerateProduct(ctx context.Context, id string, unpublished bool, note string) (*Product, error)
+
+	Restock(ctx context.Context, productID string, quantity uint32, reference string, note string) (*StockLevel, error)
+	AdjustStock(ctx context.Context, productID string, delta int32, note string) (*StockLevel, error)
+	ReserveStock(ctx context.Context, items []ReservationItem, ttl time.Duration) (*Reservation, error)
+	CommitReservation(ctx context.Context, id, reference string) (*Reservation, error)
+	ReleaseReservation(ctx context.Context, id string) (*Reservation, error)
+	ReleaseExpiredReservations(ctx context.Context) (int, error)
+	BackfillStock(ctx context.Context) (int, error)
+	GetStockMovements(ctx context.Context, productID string, skip uint64, take uint64) ([]StockMovement, error)
+
+	SubscribeBackInStock(ctx context.Context, productID, accountID string) error
+	UnsubscribeBackInStock(ctx context.Context, productID, accountID string) error
+	GetStockNotifications(ctx context.Context, recipientID string, unreadOnly bool, skip uint64, take uint64) ([]StockNotification, error)
+	MarkStockNotificationsRead(ctx context.Context, recipientID string, ids []string) ([]string, error)
+	EraseAccount(ctx context.Context, accountID string) error
 }
 
 type catalogService struct {
 	repository Repository
+	inventory  InventoryRepository
 }
 
-func NewService(r Repository) Service {
-	return &catalogService{repository: r}
+func NewService(r Repository, inv InventoryRepository) Service {
+	return &catalogService{repository: r, inventory: inv}
 }
-func (s *catalogService) PostProduct(ctx context.Context, name, description, seller_id string, price float64, quantity uint32) (*Product, error) {
+func (s *catalogService) PostProduct(ctx context.Context, name, description, seller_id string, price float64, quantity, lowStockThreshold uint32) (*Product, error) {
 	p := &Product{
-		Name:        name,
-		Description: description,
-		Price:       price,
-		ID:          ksuid.New().String(),
-		Quantity:    quantity,
-		SellerID:    seller_id,
+		Name:              name,
+		Description:       description,
+		Price:             price,
+		ID:                ksuid.New().String(),
+		Quantity:          quantity,
+		SellerID:          seller_id,
+		LowStockThreshold: lowStockThreshold,
 	}
 
 	if err := s.repository.PutProduct(ctx, *p); err != nil {
@@ -40,6 +60,13 @@ func (s *catalogService) PostProduct(ctx context.Context, name, description, sel
 		return nil, err
 	}
 
+	if quantity > 0 {
+		if _, err := s.Restock(ctx, p.ID, quantity, "", "initial stock"); err != nil {
+			log.Println("service: error stocking product")
+			return nil, err
+		}
+	}
+
 	return p, nil
 }
 
@@ -47,26 +74,26 @@ func (s *catalogService) GetProduct(ctx context.Context, id string) (*Product, e
 	return s.repository.GetProductByID(ctx, id)
 }
 
-func (s *catalogService) GetProducts(ctx context.Context, skip uint64, take uint64) ([]Product, error) {
+func (s *catalogService) GetProducts(ctx context.Context, skip uint64, take uint64, filter ProductFilter) ([]Product, error) {
 	if take > 100 || (skip == 0 && take == 0) {
 		take = 100
 	}
-	return s.repository.ListProducts(ctx, skip, take)
+	return s.repository.ListProducts(ctx, skip, take, filter)
 }
 
 func (s *catalogService) GetProductByIDs(ctx context.Context, ids []string) ([]Product, error) {
 	return s.repository.ListProductsWithIDs(ctx, ids)
 }
 
-func (s *catalogService) SearchProducts(ctx context.Context, query string, skip uint64, take uint64) ([]Product, error) {
+func (s *catalogService) SearchProducts(ctx context.Context, query string, skip uint64, take uint64, filter ProductFilter) ([]Product, error) {
 	if take > 100 || (skip == 0 && take == 0) {
 		take = 100
 	}
-	return s.repository.SearchProducts(ctx, query, skip, take)
+	return s.repository.SearchProducts(ctx, query, skip, take, filter)
 }
 
 func (s *catalogService) UpdateQuantity(ctx context.Context, ids []string, quantity []uint32) ([]string, error) {
-	err := s.repository.UpdateQuantity(ctx, ids, quantity)
+	err := s.setAvailable(ctx, ids, quantity)
 	if err != nil {
 		return nil, err
 	}
@@ -78,9 +105,37 @@ func (s *catalogService) UpdateProduct(ctx context.Context, p Product) (*Product
 		return nil, err
 	}
 
+	// stock only changes through the inventory ledger, the rating through
+	// the review service and the moderation through the admins
+	delete(mappedP, "quantity")
+	delete(mappedP, "average_rating")
+	delete(mappedP, "review_count")
+	delete(mappedP, "unpublished")
+	delete(mappedP, "moderation_note")
+	if err := s.setAvailable(ctx, []string{p.ID}, []uint32{p.Quantity}); err != nil {
+		return nil, err
+	}
+
 	err = s.repository.UpdateProduct(ctx, mappedP)
 	if err != nil {
 		return nil, err
 	}
 	return &p, nil
 }
+
+func (s *catalogService) UpdateRating(ctx context.Context, id string, averageRating float64, reviewCount uint32) error {
+	if averageRating < 0 || averageRating > 5 {
+		return ErrInvalidRating
+	}
+	return s.repository.UpdateRating(ctx, id, averageRating, reviewCount)
+}
+
+func (s *catalogService) ModerateProduct(ctx context.Context, id string, unpublished bool, note string) (*Product, error) {
+	if !unpublished {
+		note = ""
+	}
+	if err := s.repository.ModerateProduct(ctx, id, unpublished, note); err != nil {
+		return nil, err
+	}
+	return s.repository.GetProductByID(ctx, id)
+}
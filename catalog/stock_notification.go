@@ -0,0 +1,118 @@
+package catalog
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/segmentio/ksuid"
+)
+
+const (
+	NotificationLowStock    = 0
+	NotificationOutOfStock  = 1
+	NotificationBackInStock = 2
+)
+
+// StockNotification tells a seller their product is running low or out, or a
+// buyer that a product they wait for can be ordered again.
+type StockNotification struct {
+	ID          string     `json:"id"`
+	RecipientID string     `json:"recipient_id"`
+	ProductID   string     `json:"product_id"`
+	Kind        int32      `json:"kind"`
+	Quantity    uint32     `json:"quantity"`
+	Threshold   uint32     `json:"threshold"`
+	ReadAt      *time.Time `json:"read_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func (s *catalogService) SubscribeBackInStock(ctx context.Context, productID, accountID string) error {
+	if _, err := s.repository.GetProductByID(ctx, productID); err != nil {
+		return err
+	}
+	return s.inventory.PutStockSubscription(ctx, productID, accountID, time.Now().UTC())
+}
+
+func (s *catalogService) UnsubscribeBackInStock(ctx context.Context, productID, accountID string) error {
+	return s.inventory.DeleteStockSubscription(ctx, productID, accountID)
+}
+
+func (s *catalogService) GetStockNotifications(ctx context.Context, recipientID string, unreadOnly bool, skip uint64, take uint64) ([]StockNotification, error) {
+	if take > 100 || (skip == 0 && take == 0) {
+		take = 100
+	}
+	return s.inventory.GetStockNotifications(ctx, recipientID, unreadOnly, skip, take)
+}
+
+func (s *catalogService) MarkStockNotificationsRead(ctx context.Context, recipientID string, ids []string) ([]string, error) {
+	return s.inventory.MarkStockNotificationsRead(ctx, recipientID, ids, time.Now().UTC())
+}
+
+// EraseAccount forgets the stock alerts of a deleted account.
+func (s *catalogService) EraseAccount(ctx context.Context, accountID string) error {
+	return s.inventory.DeleteAccountStockAlerts(ctx, accountID)
+}
+
+// watchStock compares the quantity the products had before a stock change
+// with the new levels and records a notification for every crossing.
+func (s *catalogService) watchStock(ctx context.Context, before []Product, levels []StockLevel) {
+	previous := map[string]Product{}
+	for _, p := range before {
+		previous[p.ID] = p
+	}
+
+	now := time.Now().UTC()
+	notifications := []StockNotification{}
+	for _, l := range levels {
+		p, ok := previous[l.ProductID]
+		if !ok {
+			continue
+		}
+
+		available := l.Available()
+		notification := StockNotification{
+			RecipientID: p.SellerID,
+			ProductID:   p.ID,
+			Quantity:    available,
+			Threshold:   p.LowStockThreshold,
+			CreatedAt:   now,
+		}
+
+		switch {
+		case p.Quantity > 0 && available == 0:
+			notification.Kind = NotificationOutOfStock
+		case p.Quantity > p.LowStockThreshold && available <= p.LowStockThreshold:
+			notification.Kind = NotificationLowStock
+		case p.Quantity == 0 && available > 0:
+			subscribers, err := s.inventory.PopStockSubscribers(ctx, p.ID)
+			if err != nil {
+				log.Println("error getting back in stock subscribers", err)
+				continue
+			}
+			for _, accountID := range subscribers {
+				notifications = append(notifications, StockNotification{
+					ID:          ksuid.New().String(),
+					RecipientID: accountID,
+					ProductID:   p.ID,
+					Kind:        NotificationBackInStock,
+					Quantity:    available,
+					CreatedAt:   now,
+				})
+			}
+			continue
+		default:
+			continue
+		}
+
+		notification.ID = ksuid.New().String()
+		notifications = append(notifications, notification)
+	}
+
+	if len(notifications) == 0 {
+		return
+	}
+	if err := s.inventory.PutStockNotifications(ctx, notifications); err != nil {
+		log.Println("error putting stock notifications", err)
+	}
+}
@@ -0,0 +1,173 @@
+package catalog
+
+import (
+	"context"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func (r *postgresInventoryRepository) PutStockSubscription(ctx context.Context, productID, accountID string, at time.Time) error {
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO stock_subscriptions(product_id, account_id, created_at)
+		VALUES($1, $2, $3)
+		ON CONFLICT (product_id, account_id) DO NOTHING`,
+		productID, accountID, at,
+	)
+	return err
+}
+
+func (r *postgresInventoryRepository) DeleteStockSubscription(ctx context.Context, productID, accountID string) error {
+	_, err := r.db.ExecContext(
+		ctx,
+		"DELETE FROM stock_subscriptions WHERE product_id = $1 AND account_id = $2",
+		productID, accountID,
+	)
+	return err
+}
+
+// DeleteAccountStockAlerts deletes the subscriptions of the account and the
+// notifications it received.
+func (r *postgresInventoryRepository) DeleteAccountStockAlerts(ctx context.Context, accountID string) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	if _, err = tx.ExecContext(ctx, "DELETE FROM stock_subscriptions WHERE account_id = $1", accountID); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "DELETE FROM stock_notifications WHERE recipient_id = $1", accountID)
+	return err
+}
+
+// PopStockSubscribers removes the back-in-stock subscriptions of a product
+// and returns who held them, every subscriber is told once.
+func (r *postgresInventoryRepository) PopStockSubscribers(ctx context.Context, productID string) ([]string, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		"DELETE FROM stock_subscriptions WHERE product_id = $1 RETURNING account_id",
+		productID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accountIDs := []string{}
+	for rows.Next() {
+		var accountID string
+		if err = rows.Scan(&accountID); err != nil {
+			return nil, err
+		}
+		accountIDs = append(accountIDs, accountID)
+	}
+
+	return accountIDs, rows.Err()
+}
+
+func (r *postgresInventoryRepository) PutStockNotifications(ctx context.Context, notifications []StockNotification) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn(
+		"stock_notifications",
+		"id", "recipient_id", "product_id", "kind", "quantity", "threshold", "created_at",
+	))
+	if err != nil {
+		return err
+	}
+
+	for _, n := range notifications {
+		_, err = stmt.ExecContext(ctx, n.ID, n.RecipientID, n.ProductID, n.Kind, n.Quantity, n.Threshold, n.CreatedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = stmt.ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+	return stmt.Close()
+}
+
+func (r *postgresInventoryRepository) GetStockNotifications(ctx context.Context, recipientID string, unreadOnly bool, skip uint64, take uint64) ([]StockNotification, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT id, recipient_id, product_id, kind, quantity, threshold, read_at, created_at
+		FROM stock_notifications
+		WHERE recipient_id = $1 AND (NOT $2 OR read_at IS NULL)
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4`,
+		recipientID, unreadOnly, take, skip,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := []StockNotification{}
+	for rows.Next() {
+		n := StockNotification{}
+		if err = rows.Scan(
+			&n.ID,
+			&n.RecipientID,
+			&n.ProductID,
+			&n.Kind,
+			&n.Quantity,
+			&n.Threshold,
+			&n.ReadAt,
+			&n.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+
+	return notifications, rows.Err()
+}
+
+func (r *postgresInventoryRepository) MarkStockNotificationsRead(ctx context.Context, recipientID string, ids []string, at time.Time) ([]string, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`UPDATE stock_notifications SET read_at = $3
+		WHERE recipient_id = $1 AND id = ANY($2) AND read_at IS NULL
+		RETURNING id`,
+		recipientID, pq.Array(ids), at,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	marked := []string{}
+	for rows.Next() {
+		var id string
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		marked = append(marked, id)
+	}
+
+	return marked, rows.Err()
+}
@@ -0,0 +1,77 @@
+CREATE TABLE IF NOT EXISTS inventory_items (
+    product_id VARCHAR(27) PRIMARY KEY,
+    on_hand INT NOT NULL DEFAULT 0,
+    reserved INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    CHECK (on_hand >= 0),
+    CHECK (reserved >= 0),
+    CHECK (reserved <= on_hand)
+);
+
+CREATE TABLE IF NOT EXISTS stock_reservations (
+    id VARCHAR(27) PRIMARY KEY,
+    status INT NOT NULL DEFAULT 0,
+    reference VARCHAR(64) NOT NULL DEFAULT '',
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS stock_reservations_active_idx ON stock_reservations (expires_at) WHERE status = 0;
+
+CREATE TABLE IF NOT EXISTS stock_reservation_items (
+    reservation_id VARCHAR(27) NOT NULL,
+    product_id VARCHAR(27) NOT NULL,
+    quantity INT NOT NULL,
+    PRIMARY KEY (reservation_id, product_id),
+    FOREIGN KEY (reservation_id) REFERENCES stock_reservations(id) ON DELETE CASCADE
+);
+
+-- append-only ledger, balances in inventory_items are the running sum of it
+CREATE TABLE IF NOT EXISTS stock_movements (
+    id BIGSERIAL PRIMARY KEY,
+    product_id VARCHAR(27) NOT NULL,
+    kind INT NOT NULL,
+    on_hand_delta INT NOT NULL DEFAULT 0,
+    reserved_delta INT NOT NULL DEFAULT 0,
+    reservation_id VARCHAR(27),
+    reference VARCHAR(64) NOT NULL DEFAULT '',
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    FOREIGN KEY (reservation_id) REFERENCES stock_reservations(id)
+);
+
+CREATE INDEX IF NOT EXISTS stock_movements_product_id_idx ON stock_movements (product_id, id);
+CREATE UNIQUE INDEX IF NOT EXISTS stock_movements_restock_reference_idx ON stock_movements (product_id, reference) WHERE kind = 0 AND reference <> '';
+
+CREATE TABLE IF NOT EXISTS stock_subscriptions (
+    product_id VARCHAR(27) NOT NULL,
+    account_id VARCHAR(27) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (product_id, account_id)
+);
+
+CREATE TABLE IF NOT EXISTS stock_notifications (
+    id VARCHAR(27) PRIMARY KEY,
+    recipient_id VARCHAR(27) NOT NULL,
+    product_id VARCHAR(27) NOT NULL,
+    kind INT NOT NULL,
+    quantity INT NOT NULL,
+    threshold INT NOT NULL DEFAULT 0,
+    read_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS stock_notifications_recipient_id_idx ON stock_notifications (recipient_id, created_at);
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    id VARCHAR(27) PRIMARY KEY,
+    actor_id VARCHAR(27) NOT NULL,
+    actor_email VARCHAR(127) NOT NULL,
+    method VARCHAR(255) NOT NULL,
+    request TEXT NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_actor_id_idx ON audit_log (actor_id, created_at);
@@ -2,17 +2,22 @@ package catalog
 
 import (
 	"encoding/json"
-	"fmt"
-	"strconv"
 )
 
 func mapProductResponse(productResp []productResp, products *[]Product) {
 	for _, p := range productResp {
 		*products = append(*products, Product{
-			ID:          p.ID,
-			Name:        p.Source.Name,
-			Description: p.Source.Description,
-			Price:       p.Source.Price,
+			ID:                p.ID,
+			Name:              p.Source.Name,
+			Description:       p.Source.Description,
+			Price:             p.Source.Price,
+			Quantity:          p.Source.Quantity,
+			LowStockThreshold: p.Source.LowStockThreshold,
+			AverageRating:     p.Source.AverageRating,
+			ReviewCount:       p.Source.ReviewCount,
+			SellerID:          p.Source.SellerID,
+			Unpublished:       p.Source.Unpublished,
+			ModerationNote:    p.Source.ModerationNote,
 		})
 	}
 }
@@ -38,11 +43,6 @@ func marshalJsonID(ids []string) ([]byte, error) {
 	return docsJson, nil
 }
 
-func createQueryCheck(p Product) string {
-	priceProduct := strconv.FormatFloat(p.Price, 'f', -1, 64)
-	return fmt.Sprintf("name:%s AND price:%s AND description:%s", p.Name, priceProduct, p.Description)
-}
-
 func convertProductToMap(p Product) (map[string]interface{}, error) {
 	// Marshall the Product struct to JSON
 	productJSON, err := json.Marshal(p)
@@ -59,3 +59,50 @@ func convertProductToMap(p Product) (map[string]interface{}, error) {
 
 	return result, nil
 }
+
+// productSearchBody builds the search request of a product listing, query
+// uses the same query string syntax as the q parameter.
+func productSearchBody(query string, filter ProductFilter) map[string]interface{} {
+	must := []interface{}{}
+	if query != "" {
+		must = append(must, map[string]interface{}{
+			"query_string": map[string]interface{}{"query": query},
+		})
+	}
+
+	filters := []interface{}{}
+	if filter.MinRating > 0 {
+		filters = append(filters, map[string]interface{}{
+			"range": map[string]interface{}{
+				"average_rating": map[string]interface{}{"gte": filter.MinRating},
+			},
+		})
+	}
+
+	// products indexed before moderation have no unpublished field, they match
+	mustNot := []interface{}{}
+	if !filter.IncludeUnpublished {
+		mustNot = append(mustNot, map[string]interface{}{
+			"term": map[string]interface{}{"unpublished": true},
+		})
+	}
+
+	body := map[string]interface{}{
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must":     must,
+				"filter":   filters,
+				"must_not": mustNot,
+			},
+		},
+	}
+	if filter.SortByRating {
+		// products indexed before any review have no rating mapped yet
+		body["sort"] = []interface{}{
+			map[string]interface{}{"average_rating": map[string]interface{}{"order": "desc", "unmapped_type": "float"}},
+			map[string]interface{}{"review_count": map[string]interface{}{"order": "desc", "unmapped_type": "long"}},
+			"_score",
+		}
+	}
+	return body
+}
@@ -0,0 +1,102 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/231031/ecom-mcs-grpc/catalog"
+)
+
+var (
+	ErrPrivateOrders   = errors.New("orders are only visible to their buyer")
+	ErrPrivateWishlist = errors.New("wishlist is only visible to its buyer")
+)
+
+type accountBuyerResolver struct {
+	server *Server
+}
+
+func (r *accountBuyerResolver) Orders(ctx context.Context, obj *AccountBuyer) ([]*Order, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	// accounts returned by login carry no id
+	if obj.ID == "" {
+		return []*Order{}, nil
+	}
+
+	userAuth, err := GetUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if obj.ID != userAuth.ID {
+		return nil, ErrPrivateOrders
+	}
+
+	orderList, err := r.server.orderClient.GetOrdersForAccount(ctx, obj.ID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	orders := []*Order{}
+	for _, o := range orderList {
+		orders = append(orders, MapOrder(o))
+	}
+	return orders, nil
+}
+
+func (r *accountBuyerResolver) Wishlist(ctx context.Context, obj *AccountBuyer, pagination *PaginationInput) ([]*WishlistItem, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	if obj.ID == "" {
+		return []*WishlistItem{}, nil
+	}
+
+	userAuth, err := GetUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if obj.ID != userAuth.ID {
+		return nil, ErrPrivateWishlist
+	}
+
+	skip, take := pagination.bounds()
+	items, err := r.server.accountClient.GetWishlist(ctx, obj.ID, skip, take)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	if len(items) == 0 {
+		return []*WishlistItem{}, nil
+	}
+
+	ids := []string{}
+	for _, item := range items {
+		ids = append(ids, item.ProductID)
+	}
+	products, err := r.server.catalogClient.GetProducts(ctx, 0, 0, ids, "", catalog.ProductFilter{})
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	productMap := map[string]catalog.Product{}
+	for _, p := range products {
+		productMap[p.ID] = p
+	}
+
+	wishlist := []*WishlistItem{}
+	for _, item := range items {
+		// products removed from the catalog drop out of the wishlist
+		p, ok := productMap[item.ProductID]
+		if !ok {
+			continue
+		}
+		wishlist = append(wishlist, MapWishlistItem(item.SavedPrice, item.CreatedAt, p))
+	}
+	return wishlist, nil
+}
@@ -0,0 +1,29 @@
+package graphql
+
+import (
+	"context"
+	"log"
+)
+
+type accountSellerResolver struct {
+	server *Server
+}
+
+func (r *accountSellerResolver) Products(ctx context.Context, obj *AccountSeller) ([]*Product, error) {
+	// accounts returned by login carry no id
+	if obj.ID == "" {
+		return []*Product{}, nil
+	}
+
+	productList, err := r.server.GetLoaders(ctx).SellerProducts.Load(obj.ID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	products := []*Product{}
+	for _, p := range productList {
+		products = append(products, MapProduct(p))
+	}
+	return products, nil
+}
@@ -3,19 +3,43 @@ package main
 import (
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/231031/ecom-mcs-grpc/graphql"
+	"github.com/231031/ecom-mcs-grpc/pkg/auth"
+	gqlgen "github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/kelseyhightower/envconfig"
+	"github.com/vektah/gqlparser/v2/ast"
 )
 
 type AppConfig struct {
-	AuthUrl       string `envconfig:"AUTH_SERVICE_URL"`
-	AccountUrl    string `envconfig:"ACCOUNT_SERVICE_URL"`
-	OrderUrl      string `envconfig:"ORDER_SERVICE_URL"`
-	CatalogUrl    string `envconfig:"CATALOG_SERVICE_URL"`
-	PublicKeyPath string `envconfig:"PUBLIC_KEY_PATH"`
+	AuthUrl    string `envconfig:"AUTH_SERVICE_URL"`
+	AccountUrl string `envconfig:"ACCOUNT_SERVICE_URL"`
+	OrderUrl   string `envconfig:"ORDER_SERVICE_URL"`
+	CatalogUrl string `envconfig:"CATALOG_SERVICE_URL"`
+	ReviewUrl  string `envconfig:"REVIEW_SERVICE_URL"`
+
+	ComplexityLimit    int    `envconfig:"COMPLEXITY_LIMIT" default:"1000"`
+	ComplexityWeights  string `envconfig:"COMPLEXITY_WEIGHTS"`
+	DepthLimit         int    `envconfig:"DEPTH_LIMIT" default:"10"`
+	APQRedisAddr       string `envconfig:"APQ_REDIS_ADDR"`
+	APQRedisPassword   string `envconfig:"APQ_REDIS_PASSWORD"`
+	QueryAllowListPath string `envconfig:"QUERY_ALLOWLIST_PATH"`
+
+	RevocationRedisAddr     string        `envconfig:"REVOCATION_REDIS_ADDR"`
+	RevocationRedisPassword string        `envconfig:"REVOCATION_REDIS_PASSWORD"`
+	RevocationCacheTTL      time.Duration `envconfig:"REVOCATION_CACHE_TTL" default:"5s"`
+
+	// where the browser lands after an OIDC login
+	OIDCAfterLoginURL string `envconfig:"OIDC_AFTER_LOGIN_URL" default:"/"`
+
+	// the proxies in front of the gateway whose X-Forwarded-For is believed
+	TrustedProxies string `envconfig:"TRUSTED_PROXIES"`
 }
 
 func main() {
@@ -25,18 +49,77 @@ func main() {
 		log.Fatal(err)
 	}
 
-	middleware := graphql.NewAuthMiddleware(cfg.PublicKeyPath)
-	s, err := graphql.NewGraphQLServer(cfg.AuthUrl, cfg.AccountUrl, cfg.CatalogUrl, cfg.OrderUrl)
+	// the authentication service denies revoked sessions in its redis, the
+	// gateway reads them from there
+	var revocations *graphql.RevocationList
+	if cfg.RevocationRedisAddr != "" {
+		revocations, err = graphql.NewRevocationList(cfg.RevocationRedisAddr, cfg.RevocationRedisPassword, cfg.RevocationCacheTTL)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	proxies, err := graphql.ParseTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	s, err := graphql.NewGraphQLServer(cfg.AuthUrl, cfg.AccountUrl, cfg.CatalogUrl, cfg.OrderUrl, cfg.ReviewUrl)
+	if err != nil {
+		log.Fatal(err)
+	}
+	keys := auth.NewKeySet(s.FetchJWKS, auth.DefaultKeySetTTL)
+	middleware := graphql.NewAuthMiddleware(keys, revocations)
+
+	weights, err := graphql.ParseComplexityWeights(cfg.ComplexityWeights)
 	if err != nil {
 		log.Fatal(err)
 	}
+	srv := graphql.WithComplexityWeights(s.ToExecutablesSchema(middleware), weights)
 
-	srv := s.ToExecutablesSchema(middleware)
+	// production runs with an allow list, only the queries shipped with the
+	// clients are served and nothing new is persisted
+	var apqCache gqlgen.Cache[string] = lru.New[string](100)
+	var allowList *graphql.AllowList
+	if cfg.QueryAllowListPath != "" {
+		allowList, err = graphql.LoadAllowList(cfg.QueryAllowListPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		apqCache = allowList
+	} else if cfg.APQRedisAddr != "" {
+		apqCache, err = graphql.NewRedisCache(cfg.APQRedisAddr, cfg.APQRedisPassword, "apq:", 24*time.Hour)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
 
-	h := handler.NewDefaultServer(srv)
-	p := playground.Handler("GraphQL", "/graphql")
-	http.Handle("/playground", p)
-	http.Handle("/graphql", graphql.ResponseWriterGetTokenMiddleware(h))
+	h := handler.New(srv)
+	h.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+		InitFunc:              graphql.WebsocketInitFunc,
+	})
+	h.AddTransport(transport.Options{})
+	h.AddTransport(transport.GET{})
+	h.AddTransport(transport.POST{})
+	h.AddTransport(transport.MultipartForm{})
+	h.SetQueryCache(lru.New[*ast.QueryDocument](1000))
+	h.SetErrorPresenter(graphql.ErrorPresenter)
+	h.Use(extension.AutomaticPersistedQuery{
+		Cache: apqCache,
+	})
+	if allowList != nil {
+		h.Use(allowList)
+	} else {
+		h.Use(extension.Introspection{})
+		p := playground.Handler("GraphQL", "/graphql")
+		http.Handle("/playground", p)
+	}
+	h.Use(extension.FixedComplexityLimit(cfg.ComplexityLimit))
+	h.Use(graphql.DepthLimit{Limit: cfg.DepthLimit})
+	http.Handle("/graphql", graphql.ResponseWriterGetTokenMiddleware(proxies, s.LoaderMiddleware(h)))
+	http.HandleFunc("GET /auth/oidc/{provider}/login", s.OIDCLogin(cfg.OIDCAfterLoginURL))
+	http.HandleFunc("GET /auth/oidc/{provider}/callback", s.OIDCCallback(cfg.OIDCAfterLoginURL, proxies))
 
 	err = http.ListenAndServe(":8080", nil)
 	if err != nil {
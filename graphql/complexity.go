@@ -0,0 +1,205 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const (
+	// defaultListSize is how many items a list field without pagination is
+	// expected to return when its cost is estimated, paginated lists left
+	// unbounded return up to maxListSize.
+	defaultListSize = 10
+	maxListSize     = 100
+
+	errDepthLimit = "DEPTH_LIMIT_EXCEEDED"
+)
+
+var (
+	ErrInvalidComplexityWeights = errors.New("complexity weights must look like Type.field=weight")
+)
+
+// ComplexityWeights is the cost of a field on its own keyed by "Type.field",
+// fields that are not listed cost 1.
+type ComplexityWeights map[string]int
+
+// DefaultComplexityWeights charges more for fields that cost a gRPC call.
+var DefaultComplexityWeights = ComplexityWeights{
+	"Query.getSeller":                  5,
+	"Query.getBuyer":                   5,
+	"Query.getSellers":                 5,
+	"Query.getProfileBuyer":            5,
+	"Query.getProfileSeller":           5,
+	"Query.getProducts":                5,
+	"Query.getOrders":                  5,
+	"Query.getFulfillments":            5,
+	"Query.getReturns":                 5,
+	"Query.getStockNotifications":      5,
+	"Query.getNotificationPreferences": 5,
+	"Query.getUsers":                   5,
+	"Query.getOrder":                   5,
+	"AccountBuyer.orders":              10,
+	"AccountBuyer.wishlist":            10,
+	"AccountSeller.products":           5,
+	"Order.account":                    2,
+	"OrderProduct.product":             2,
+	"Product.reviews":                  5,
+}
+
+// ParseComplexityWeights reads weights written as
+// "Type.field=weight,Type.field=weight" on top of the defaults.
+func ParseComplexityWeights(s string) (ComplexityWeights, error) {
+	weights := ComplexityWeights{}
+	for k, v := range DefaultComplexityWeights {
+		weights[k] = v
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		field, value, ok := strings.Cut(pair, "=")
+		if !ok || !strings.Contains(field, ".") {
+			return nil, ErrInvalidComplexityWeights
+		}
+		weight, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || weight < 0 {
+			return nil, ErrInvalidComplexityWeights
+		}
+		weights[strings.TrimSpace(field)] = weight
+	}
+	return weights, nil
+}
+
+type weightedSchema struct {
+	graphql.ExecutableSchema
+	weights ComplexityWeights
+}
+
+// WithComplexityWeights prices every field by its weight, a list field is
+// charged its children once per item it may return.
+func WithComplexityWeights(es graphql.ExecutableSchema, weights ComplexityWeights) graphql.ExecutableSchema {
+	return weightedSchema{ExecutableSchema: es, weights: weights}
+}
+
+func (s weightedSchema) Complexity(ctx context.Context, typeName, fieldName string, childComplexity int, args map[string]any) (int, bool) {
+	weight, ok := s.weights[typeName+"."+fieldName]
+	if !ok {
+		weight = 1
+	}
+
+	def, ok := s.Schema().Types[typeName]
+	if !ok {
+		return weight + childComplexity, true
+	}
+	field := def.Fields.ForName(fieldName)
+	if field == nil || field.Type.Elem == nil {
+		return weight + childComplexity, true
+	}
+
+	return weight + listSize(field, args)*childComplexity, true
+}
+
+// listSize is the take of a paginated list, or the default size.
+func listSize(field *ast.FieldDefinition, args map[string]any) int {
+	if field.Arguments.ForName("pagination") == nil {
+		return defaultListSize
+	}
+	pagination, ok := args["pagination"].(map[string]any)
+	if !ok {
+		return maxListSize
+	}
+
+	var take int
+	switch v := pagination["take"].(type) {
+	case int:
+		take = v
+	case int64:
+		take = int(v)
+	case float64:
+		take = int(v)
+	case interface{ Int64() (int64, error) }:
+		n, _ := v.Int64()
+		take = int(n)
+	}
+	if take <= 0 || take > maxListSize {
+		// the services fall back to their largest page
+		return maxListSize
+	}
+	return take
+}
+
+// DepthLimit rejects operations nesting fields deeper than Limit,
+// introspection fields are not counted.
+type DepthLimit struct {
+	Limit int
+}
+
+var _ interface {
+	graphql.OperationContextMutator
+	graphql.HandlerExtension
+} = DepthLimit{}
+
+func (d DepthLimit) ExtensionName() string {
+	return "DepthLimit"
+}
+
+func (d DepthLimit) Validate(schema graphql.ExecutableSchema) error {
+	if d.Limit <= 0 {
+		return errors.New("DepthLimit.Limit must be positive")
+	}
+	return nil
+}
+
+func (d DepthLimit) MutateOperationContext(ctx context.Context, opCtx *graphql.OperationContext) *gqlerror.Error {
+	op := opCtx.Doc.Operations.ForName(opCtx.OperationName)
+	if op == nil {
+		return nil
+	}
+
+	depth := selectionDepth(op.SelectionSet, map[string]bool{})
+	if depth > d.Limit {
+		err := gqlerror.Errorf("operation has depth %d, which exceeds the limit of %d", depth, d.Limit)
+		errcode.Set(err, errDepthLimit)
+		return err
+	}
+	return nil
+}
+
+func selectionDepth(set ast.SelectionSet, visited map[string]bool) int {
+	depth := 0
+	for _, sel := range set {
+		d := 0
+		switch s := sel.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name, "__") {
+				continue
+			}
+			d = 1 + selectionDepth(s.SelectionSet, visited)
+		case *ast.InlineFragment:
+			d = selectionDepth(s.SelectionSet, visited)
+		case *ast.FragmentSpread:
+			// validation already rejects fragment cycles, this only guards
+			// against walking a spread inside itself
+			if s.Definition == nil || visited[s.Name] {
+				continue
+			}
+			visited[s.Name] = true
+			d = selectionDepth(s.Definition.SelectionSet, visited)
+			delete(visited, s.Name)
+		}
+		if d > depth {
+			depth = d
+		}
+	}
+	return depth
+}
@@ -0,0 +1,202 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/231031/ecom-mcs-grpc/account"
+	"github.com/231031/ecom-mcs-grpc/catalog"
+)
+
+const (
+	loaderWait     = 2 * time.Millisecond
+	loaderMaxBatch = 100
+)
+
+var (
+	loadersCtxKey ctxKey = "loaders"
+	ErrNotLoaded         = errors.New("key not found by loader")
+)
+
+// Loader batches the keys asked for by resolvers running in the same tick
+// into one fetch and caches the results for the rest of the request.
+type Loader[K comparable, V any] struct {
+	ctx   context.Context
+	fetch func(ctx context.Context, keys []K) (map[K]V, error)
+
+	mu    sync.Mutex
+	cache map[K]V
+	batch *loaderBatch[K, V]
+}
+
+type loaderBatch[K comparable, V any] struct {
+	keys   []K
+	once   sync.Once
+	done   chan struct{}
+	values map[K]V
+	err    error
+}
+
+func NewLoader[K comparable, V any](ctx context.Context, fetch func(ctx context.Context, keys []K) (map[K]V, error)) *Loader[K, V] {
+	return &Loader[K, V]{
+		ctx:   ctx,
+		fetch: fetch,
+		cache: map[K]V{},
+	}
+}
+
+func (l *Loader[K, V]) Load(key K) (V, error) {
+	l.mu.Lock()
+	if v, ok := l.cache[key]; ok {
+		l.mu.Unlock()
+		return v, nil
+	}
+
+	b := l.batch
+	if b == nil {
+		b = &loaderBatch[K, V]{done: make(chan struct{})}
+		l.batch = b
+		go func() {
+			time.Sleep(loaderWait)
+			l.dispatch(b)
+		}()
+	}
+	if !containsKey(b.keys, key) {
+		b.keys = append(b.keys, key)
+	}
+	full := len(b.keys) >= loaderMaxBatch
+	l.mu.Unlock()
+
+	if full {
+		go l.dispatch(b)
+	}
+
+	<-b.done
+	if b.err != nil {
+		var zero V
+		return zero, b.err
+	}
+	v, ok := b.values[key]
+	if !ok {
+		return v, ErrNotLoaded
+	}
+	return v, nil
+}
+
+// dispatch runs a batch once, whichever of the timer or a full batch gets
+// there first.
+func (l *Loader[K, V]) dispatch(b *loaderBatch[K, V]) {
+	b.once.Do(func() {
+		l.mu.Lock()
+		if l.batch == b {
+			l.batch = nil
+		}
+		keys := b.keys
+		l.mu.Unlock()
+
+		ctx, cancel := context.WithTimeout(l.ctx, 3*time.Second)
+		defer cancel()
+		b.values, b.err = l.fetch(ctx, keys)
+
+		if b.err == nil {
+			l.mu.Lock()
+			for k, v := range b.values {
+				l.cache[k] = v
+			}
+			l.mu.Unlock()
+		}
+		close(b.done)
+	})
+}
+
+func containsKey[K comparable](keys []K, key K) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Loaders holds the loaders of a single request.
+type Loaders struct {
+	Buyers         *Loader[string, account.Buyer]
+	Products       *Loader[string, catalog.Product]
+	SellerProducts *Loader[string, []catalog.Product]
+}
+
+func (s *Server) newLoaders(ctx context.Context) *Loaders {
+	return &Loaders{
+		Buyers: NewLoader(ctx, func(ctx context.Context, ids []string) (map[string]account.Buyer, error) {
+			buyers, err := s.accountClient.GetAccountBuyers(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+
+			result := map[string]account.Buyer{}
+			for _, b := range buyers {
+				result[b.ID] = b
+			}
+			return result, nil
+		}),
+		Products: NewLoader(ctx, func(ctx context.Context, ids []string) (map[string]catalog.Product, error) {
+			products, err := s.catalogClient.GetProducts(ctx, 0, 0, ids, "", catalog.ProductFilter{})
+			if err != nil {
+				return nil, err
+			}
+
+			result := map[string]catalog.Product{}
+			for _, p := range products {
+				result[p.ID] = p
+			}
+			return result, nil
+		}),
+		SellerProducts: NewLoader(ctx, func(ctx context.Context, sellerIDs []string) (map[string][]catalog.Product, error) {
+			products, err := s.catalogClient.GetProducts(ctx, 0, 0, nil, sellerQuery(sellerIDs), catalog.ProductFilter{})
+			if err != nil {
+				return nil, err
+			}
+
+			result := map[string][]catalog.Product{}
+			for _, id := range sellerIDs {
+				result[id] = []catalog.Product{}
+			}
+			for _, p := range products {
+				result[p.SellerID] = append(result[p.SellerID], p)
+			}
+			return result, nil
+		}),
+	}
+}
+
+// sellerQuery matches the products of any of the sellers in one search.
+func sellerQuery(sellerIDs []string) string {
+	quoted := []string{}
+	for _, id := range sellerIDs {
+		quoted = append(quoted, fmt.Sprintf("%q", id))
+	}
+	return fmt.Sprintf("seller_id:(%s)", strings.Join(quoted, " OR "))
+}
+
+// LoaderMiddleware gives every request its own loaders so results are
+// never shared between users.
+func (s *Server) LoaderMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), loadersCtxKey, s.newLoaders(r.Context()))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// GetLoaders returns the loaders of the request, resolvers reached without
+// the middleware get fresh ones.
+func (s *Server) GetLoaders(ctx context.Context) *Loaders {
+	if loaders, ok := ctx.Value(loadersCtxKey).(*Loaders); ok {
+		return loaders
+	}
+	return s.newLoaders(ctx)
+}
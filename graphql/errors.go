@@ -0,0 +1,84 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+
+	"github.com/231031/ecom-mcs-grpc/pkg/grpcerr"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"google.golang.org/grpc/status"
+)
+
+// gatewayErrorCodes are the codes of the errors raised by the gateway itself.
+var gatewayErrorCodes = []struct {
+	err  error
+	code string
+}{
+	{ErrInvalidID, grpcerr.ReasonValidation},
+	{ErrInvalidParameter, grpcerr.ReasonValidation},
+	{ErrInvalidInfo, grpcerr.ReasonValidation},
+	{ErrUnauthHeader, grpcerr.ReasonUnauthorized},
+	{ErrPrivateOrders, grpcerr.ReasonForbidden},
+	{ErrPrivateWishlist, grpcerr.ReasonForbidden},
+	{ErrNotLoaded, grpcerr.ReasonNotFound},
+}
+
+// ErrorPresenter gives every error a stable extensions.code. Errors from the
+// services keep the message of their status, plus the request fields they
+// are about; internal ones are logged and their message hidden.
+func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+	if _, ok := gqlErr.Extensions["code"]; ok {
+		return gqlErr
+	}
+
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if errors.As(err, &grpcErr) {
+		st := grpcErr.GRPCStatus()
+		code := grpcerr.Reason(st)
+
+		gqlErr.Message = st.Message()
+		switch code {
+		case grpcerr.ReasonInternal:
+			log.Println("internal error", st.Code(), st.Message())
+			gqlErr.Message = "internal error"
+		case grpcerr.ReasonUnavailable:
+			log.Println("service unavailable", st.Code(), st.Message())
+			gqlErr.Message = "service unavailable, try again later"
+		}
+
+		setExtension(gqlErr, "code", code)
+		if violations := grpcerr.Fields(st); len(violations) > 0 {
+			fields := []map[string]string{}
+			for _, v := range violations {
+				fields = append(fields, map[string]string{
+					"field":   v.Field,
+					"message": v.Description,
+				})
+			}
+			setExtension(gqlErr, "fields", fields)
+		}
+		if delay, ok := grpcerr.RetryAfter(st); ok {
+			setExtension(gqlErr, "retry_after", int(math.Ceil(delay.Seconds())))
+		}
+		return gqlErr
+	}
+
+	for _, c := range gatewayErrorCodes {
+		if errors.Is(err, c.err) {
+			setExtension(gqlErr, "code", c.code)
+			break
+		}
+	}
+	return gqlErr
+}
+
+func setExtension(err *gqlerror.Error, key string, value interface{}) {
+	if err.Extensions == nil {
+		err.Extensions = map[string]interface{}{}
+	}
+	err.Extensions[key] = value
+}
@@ -1,10 +1,16 @@
 package graphql
 
 import (
+	"context"
+
 	"github.com/231031/ecom-mcs-grpc/account"
 	"github.com/231031/ecom-mcs-grpc/authentication"
 	"github.com/231031/ecom-mcs-grpc/catalog"
 	"github.com/231031/ecom-mcs-grpc/order"
+	"github.com/231031/ecom-mcs-grpc/pkg/auth"
+	"github.com/231031/ecom-mcs-grpc/pkg/idempotency"
+	"github.com/231031/ecom-mcs-grpc/pkg/validate"
+	"github.com/231031/ecom-mcs-grpc/review"
 	"github.com/99designs/gqlgen/graphql"
 	"google.golang.org/grpc"
 )
@@ -16,45 +22,64 @@ type Server struct {
 	accountClient *account.Client
 	catalogClient *catalog.Client
 	orderClient   *order.Client
+	reviewClient  *review.Client
 }
 
-func NewGraphQLServer(authUrl, accountUrl, catalogUrl, orderUrl string) (*Server, error) {
+func NewGraphQLServer(authUrl, accountUrl, catalogUrl, orderUrl, reviewUrl string) (*Server, error) {
 	metadataOption := grpc.WithUnaryInterceptor(MetadataInterceptor)
+	idempotencyOption := grpc.WithChainUnaryInterceptor(idempotency.UnaryClientInterceptor())
+	// bad input is turned down here with the rules the services enforce
+	validationOption := grpc.WithChainUnaryInterceptor(validate.UnaryClientInterceptor())
 
-	authClient, err := authentication.NewClient(authUrl)
+	authClient, err := authentication.NewClient(authUrl, metadataOption, validationOption, idempotencyOption)
 	if err != nil {
 		authClient.Close()
 		return nil, err
 	}
 
-	accountClient, err := account.NewClient(accountUrl, metadataOption)
+	accountClient, err := account.NewClient(accountUrl, metadataOption, validationOption)
 	if err != nil {
 		authClient.Close()
 		accountClient.Close()
 		return nil, err
 	}
 
-	catalogClient, err := catalog.NewClient(catalogUrl, metadataOption)
+	catalogClient, err := catalog.NewClient(catalogUrl, metadataOption, validationOption, idempotencyOption)
 	if err != nil {
 		accountClient.Close()
 		return nil, err
 	}
 
-	orderClient, err := order.NewClient(orderUrl, metadataOption)
+	orderClient, err := order.NewClient(orderUrl, metadataOption, validationOption, idempotencyOption)
 	if err != nil {
 		accountClient.Close()
 		catalogClient.Close()
 		return nil, err
 	}
 
+	reviewClient, err := review.NewClient(reviewUrl, metadataOption, validationOption)
+	if err != nil {
+		accountClient.Close()
+		catalogClient.Close()
+		orderClient.Close()
+		return nil, err
+	}
+
 	return &Server{
 		authClient,
 		accountClient,
 		catalogClient,
 		orderClient,
+		reviewClient,
 	}, nil
 }
 
+// FetchJWKS gets the signing keys from the authentication service, the
+// gateway verifies access tokens with them.
+func (s *Server) FetchJWKS(ctx context.Context) (*auth.JWKS, error) {
+	return s.authClient.GetJWKS(ctx)
+}
+
 func (s *Server) Mutation() MutationResolver {
 	return &mutationResolver{
 		server: s,
@@ -68,6 +93,42 @@ func (s *Server) Query() QueryResolver {
 
 }
 
+func (s *Server) AccountBuyer() AccountBuyerResolver {
+	return &accountBuyerResolver{
+		server: s,
+	}
+}
+
+func (s *Server) AccountSeller() AccountSellerResolver {
+	return &accountSellerResolver{
+		server: s,
+	}
+}
+
+func (s *Server) Order() OrderResolver {
+	return &orderResolver{
+		server: s,
+	}
+}
+
+func (s *Server) OrderProduct() OrderProductResolver {
+	return &orderProductResolver{
+		server: s,
+	}
+}
+
+func (s *Server) Product() ProductResolver {
+	return &productResolver{
+		server: s,
+	}
+}
+
+func (s *Server) Subscription() SubscriptionResolver {
+	return &subscriptionResolver{
+		server: s,
+	}
+}
+
 func (s *Server) ToExecutablesSchema(m *authMiddlewre) graphql.ExecutableSchema {
 	c := Config{Resolvers: s}
 	c.Directives.HasRole = m.HasRole
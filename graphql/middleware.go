@@ -2,64 +2,83 @@ package graphql
 
 import (
 	"context"
-	"crypto/rsa"
 	"errors"
 	"fmt"
-	"io/ioutil"
+	"log"
 	"slices"
 	"time"
 
+	"net"
 	"net/http"
 	"strings"
 
+	"github.com/231031/ecom-mcs-grpc/pkg/auth"
+	"github.com/231031/ecom-mcs-grpc/pkg/idempotency"
 	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/golang-jwt/jwt"
 	"github.com/vektah/gqlparser/v2/gqlerror"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 )
 
+// UserAuth is the user of a token, acting as Role out of the Roles they
+// hold.
 type UserAuth struct {
-	ID    string `json:"id"`
-	Email string `json:"email"`
-	Role  int32  `json:"role"`
+	ID    string  `json:"id"`
+	Email string  `json:"email"`
+	Role  int32   `json:"role"`
+	Roles []int32 `json:"roles"`
+	// SessionID is the login session the token was issued for
+	SessionID string `json:"-"`
 }
 
 type TokenClaims struct {
-	User UserAuth `json:"user"`
+	User      UserAuth `json:"user"`
+	SessionID string   `json:"sid"`
 	jwt.StandardClaims
 }
 
+// ClientInfo is the device and address a request comes from, kept with the
+// sessions of the user.
+type ClientInfo struct {
+	Device string
+	IP     string
+}
+
 type ctxKey string
 
+// the longest device and address the authentication service keeps
+const (
+	maxDeviceLen = 256
+	maxIPLen     = 64
+)
+
 var (
 	responseWriterKey ctxKey = "response_writer"
+	clientInfoKey     ctxKey = "client_info"
 	userCtxKey        ctxKey = "X-Request-User"
 	ErrUnauthHeader          = errors.New("the user is unauthorization")
 )
 
 type authMiddlewre struct {
-	publicKey *rsa.PublicKey
+	keys        auth.KeySource
+	revocations *RevocationList
 }
 
-func NewAuthMiddleware(path string) *authMiddlewre {
-	pub, err := ioutil.ReadFile(path)
-	if err != nil {
-		fmt.Sprintln("failed to read public pem file", err)
-		return nil
-	}
-	publicKey, err := jwt.ParseRSAPublicKeyFromPEM(pub)
-	if err != nil {
-		fmt.Sprintln("failed to parse public pem to rsa", err)
-		return nil
-	}
-
-	return &authMiddlewre{publicKey: publicKey}
+// NewAuthMiddleware checks the tokens with the keys the authentication
+// service publishes, and against the revocation list when one is given.
+func NewAuthMiddleware(keys auth.KeySource, revocations *RevocationList) *authMiddlewre {
+	return &authMiddlewre{keys: keys, revocations: revocations}
 }
 
-func ResponseWriterGetTokenMiddleware(next http.Handler) http.Handler {
+func ResponseWriterGetTokenMiddleware(proxies TrustedProxies, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := context.WithValue(r.Context(), responseWriterKey, w)
+		ctx = context.WithValue(ctx, clientInfoKey, clientInfoFromRequest(r, proxies))
+		if key := r.Header.Get(idempotency.HeaderKey); key != "" {
+			ctx = idempotency.NewContext(ctx, key)
+		}
 
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
@@ -77,6 +96,16 @@ func ResponseWriterGetTokenMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// WebsocketInitFunc reads the bearer token from the connection_init payload,
+// browsers cannot set headers on a websocket upgrade.
+func WebsocketInitFunc(ctx context.Context, initPayload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+	authHeader := initPayload.Authorization()
+	if strings.HasPrefix(authHeader, "Bearer ") {
+		ctx = context.WithValue(ctx, "token", strings.TrimPrefix(authHeader, "Bearer "))
+	}
+	return ctx, &initPayload, nil
+}
+
 func (m *authMiddlewre) HasRole(ctx context.Context, obj interface{}, next graphql.Resolver, role []RoleType) (interface{}, error) {
 	tokenIn := ctx.Value("token")
 	if tokenIn == nil {
@@ -100,7 +129,7 @@ func (m *authMiddlewre) HasRole(ctx context.Context, obj interface{}, next graph
 	}
 
 	claims, err := m.ValidateToken(tokenStr)
-	if err != nil {
+	if err != nil || claims.Id == "" {
 		return nil, &gqlerror.Error{
 			Message: "Invalid or expired token",
 			Extensions: map[string]interface{}{
@@ -109,8 +138,32 @@ func (m *authMiddlewre) HasRole(ctx context.Context, obj interface{}, next graph
 		}
 	}
 
-	roleUser := MapIntToRole(claims.User.Role)
-	if have := slices.Contains(role, roleUser); !have {
+	if m.revocations != nil {
+		// the services do not read the list, a token that cannot be checked
+		// is refused
+		revoked, err := m.revocations.IsRevoked(ctx, claims.Id, claims.SessionID, time.Unix(claims.ExpiresAt, 0))
+		if err != nil {
+			log.Println("error checking token revocation", err)
+			return nil, &gqlerror.Error{
+				Message: "Unable to check the token, try again later",
+				Extensions: map[string]interface{}{
+					"code": "UNAVAILABLE",
+				},
+			}
+		}
+		if revoked {
+			return nil, &gqlerror.Error{
+				Message: "Token has been revoked",
+				Extensions: map[string]interface{}{
+					"code": "UNAUTHORIZED",
+				},
+			}
+		}
+	}
+
+	// the session only gets what the role it acts as gets, the other roles
+	// the user holds wait for switchRole
+	if !slices.Contains(role, MapIntToRole(claims.User.Role)) {
 		return nil, &gqlerror.Error{
 			Message: "Invalid role for the resource",
 			Extensions: map[string]interface{}{
@@ -119,7 +172,9 @@ func (m *authMiddlewre) HasRole(ctx context.Context, obj interface{}, next graph
 		}
 	}
 
-	ctx = context.WithValue(ctx, userCtxKey, claims.User)
+	user := claims.User
+	user.SessionID = claims.SessionID
+	ctx = context.WithValue(ctx, userCtxKey, user)
 	return next(ctx)
 }
 
@@ -128,7 +183,11 @@ func (m *authMiddlewre) ValidateToken(tokenStr string) (*TokenClaims, error) {
 
 	// extract the claims and verify the signature
 	token, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
-		return m.publicKey, nil
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, auth.ErrUnexpectedSigning
+		}
+		kid, _ := token.Header["kid"].(string)
+		return m.keys.PublicKey(kid)
 	})
 	if err != nil {
 		fmt.Println("failed to parse with claims token", err)
@@ -158,15 +217,102 @@ func GetUserContext(ctx context.Context) (UserAuth, error) {
 	return u, nil
 }
 
+func GetClientInfo(ctx context.Context) ClientInfo {
+	c, _ := ctx.Value(clientInfoKey).(ClientInfo)
+	return c
+}
+
+// TrustedProxies are the proxies in front of the gateway, only the addresses
+// they append to X-Forwarded-For are believed.
+type TrustedProxies []*net.IPNet
+
+var ErrInvalidTrustedProxies = errors.New("trusted proxies must be IP addresses or CIDR ranges")
+
+// ParseTrustedProxies reads addresses and ranges written as
+// "10.0.0.0/8,192.168.1.1".
+func ParseTrustedProxies(s string) (TrustedProxies, error) {
+	proxies := TrustedProxies{}
+	for _, p := range strings.Split(s, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if !strings.Contains(p, "/") {
+			if ip := net.ParseIP(p); ip != nil && ip.To4() != nil {
+				p += "/32"
+			} else {
+				p += "/128"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(p)
+		if err != nil {
+			return nil, ErrInvalidTrustedProxies
+		}
+		proxies = append(proxies, ipNet)
+	}
+	return proxies, nil
+}
+
+func (t TrustedProxies) contains(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, n := range t {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP is the remote address, or when that is a trusted proxy the
+// rightmost address of X-Forwarded-For no trusted proxy appended. The
+// entries left of it are whatever the client sent.
+func (t TrustedProxies) ClientIP(r *http.Request) string {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		ip = host
+	}
+
+	forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0 && t.contains(ip); i-- {
+		hop := strings.TrimSpace(forwarded[i])
+		if net.ParseIP(hop) == nil {
+			break
+		}
+		ip = hop
+	}
+	return ip
+}
+
+// clientInfoFromRequest takes the address of the client as the trusted
+// proxies forwarded it.
+func clientInfoFromRequest(r *http.Request, proxies TrustedProxies) ClientInfo {
+	ip := proxies.ClientIP(r)
+
+	device := r.UserAgent()
+	if len(device) > maxDeviceLen {
+		device = device[:maxDeviceLen]
+	}
+	if len(ip) > maxIPLen {
+		ip = ip[:maxIPLen]
+	}
+	return ClientInfo{
+		Device: device,
+		IP:     ip,
+	}
+}
+
+// MetadataInterceptor forwards the token only once the hasRole directive
+// checked it against the revocation list, the services only verify its
+// signature. The authentication service refuses a token that does not verify,
+// so a stale cookie sent along with a login must not reach it either.
 func MetadataInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
-	user, ok := ctx.Value(userCtxKey).(*UserAuth)
-	if ok {
-		md := metadata.New(map[string]string{
-			"id":    user.ID,
-			"email": user.Email,
-			"role":  fmt.Sprintf("%d", user.Role),
-		})
-		ctx = metadata.NewOutgoingContext(ctx, md)
+	if token, ok := ctx.Value("token").(string); ok && token != "" {
+		if _, err := GetUserContext(ctx); err == nil {
+			ctx = metadata.AppendToOutgoingContext(ctx, auth.MetadataKey, "Bearer "+token)
+		}
 	}
 
 	if _, ok := ctx.Deadline(); !ok {
@@ -24,12 +24,14 @@ type LoginResult interface {
 }
 
 type AccountBuyer struct {
-	Orders    []*Order `json:"orders"`
-	Email     string   `json:"email"`
-	FirstName string   `json:"first_name"`
-	LastName  string   `json:"last_name"`
-	Phone     string   `json:"phone"`
-	Address   string   `json:"address"`
+	ID        string          `json:"id"`
+	Orders    []*Order        `json:"orders"`
+	Wishlist  []*WishlistItem `json:"wishlist"`
+	Email     string          `json:"email"`
+	FirstName string          `json:"first_name"`
+	LastName  string          `json:"last_name"`
+	Phone     string          `json:"phone"`
+	Address   string          `json:"address"`
 }
 
 func (AccountBuyer) IsBaseInfo()               {}
@@ -46,6 +48,7 @@ type AccountBuyerInput struct {
 }
 
 type AccountSeller struct {
+	ID        string     `json:"id"`
 	StoreName string     `json:"store_name"`
 	Products  []*Product `json:"products"`
 	Email     string     `json:"email"`
@@ -77,16 +80,57 @@ type BaseInfoInput struct {
 	Address   string  `json:"address"`
 }
 
+type Fulfillment struct {
+	ID             string          `json:"id"`
+	OrderID        string          `json:"order_id"`
+	SellerID       string          `json:"seller_id"`
+	Status         OrderStatus     `json:"status"`
+	Carrier        string          `json:"carrier"`
+	TrackingNumber string          `json:"tracking_number"`
+	PayoutAmount   float64         `json:"payout_amount"`
+	Products       []*OrderProduct `json:"products"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+}
+
+type FulfillmentInput struct {
+	Status         OrderStatus `json:"status"`
+	Carrier        *string     `json:"carrier,omitempty"`
+	TrackingNumber *string     `json:"tracking_number,omitempty"`
+}
+
+type MfaChallenge struct {
+	MfaToken string `json:"mfa_token"`
+}
+
+func (MfaChallenge) IsLoginResult() {}
+
 type Mutation struct {
 }
 
+type NotificationPreferences struct {
+	AccountCreated bool `json:"account_created"`
+	OrderPlaced    bool `json:"order_placed"`
+	OrderShipped   bool `json:"order_shipped"`
+}
+
+type NotificationPreferencesInput struct {
+	AccountCreated bool `json:"account_created"`
+	OrderPlaced    bool `json:"order_placed"`
+	OrderShipped   bool `json:"order_shipped"`
+}
+
 type Order struct {
-	ID         string          `json:"id"`
-	Account    *AccountBuyer   `json:"account"`
-	Products   []*OrderProduct `json:"products"`
-	TotalPrice float64         `json:"total_price"`
-	CreatedAt  time.Time       `json:"created_at"`
-	Address    string          `json:"address"`
+	ID             string          `json:"id"`
+	Account        *AccountBuyer   `json:"account"`
+	Products       []*OrderProduct `json:"products"`
+	TotalPrice     float64         `json:"total_price"`
+	CreatedAt      time.Time       `json:"created_at"`
+	Address        string          `json:"address"`
+	Status         OrderStatus     `json:"status"`
+	PaymentStatus  PaymentStatus   `json:"payment_status"`
+	RefundedAmount float64         `json:"refunded_amount"`
+	Fulfillments   []*Fulfillment  `json:"fulfillments"`
 }
 
 type OrderInput struct {
@@ -95,8 +139,9 @@ type OrderInput struct {
 }
 
 type OrderProduct struct {
-	Product  *Product `json:"product"`
-	Quantity int      `json:"quantity"`
+	Product        *Product `json:"product"`
+	CurrentProduct *Product `json:"currentProduct,omitempty"`
+	Quantity       int      `json:"quantity"`
 }
 
 type OrderProductInput struct {
@@ -104,25 +149,49 @@ type OrderProductInput struct {
 	Quantity  int    `json:"quantity"`
 }
 
+type OrderReturn struct {
+	ID            string         `json:"id"`
+	OrderID       string         `json:"order_id"`
+	FulfillmentID string         `json:"fulfillment_id"`
+	AccountID     string         `json:"account_id"`
+	SellerID      string         `json:"seller_id"`
+	ProductID     string         `json:"product_id"`
+	Quantity      int            `json:"quantity"`
+	Reason        ReturnReason   `json:"reason"`
+	Note          string         `json:"note"`
+	Status        ReturnStatus   `json:"status"`
+	RefundAmount  float64        `json:"refund_amount"`
+	Timeline      []*ReturnEvent `json:"timeline"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
 type PaginationInput struct {
 	Skip int `json:"skip"`
 	Take int `json:"take"`
 }
 
 type Product struct {
-	ID          string  `json:"id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
-	Quantity    int     `json:"quantity"`
-	SellerID    string  `json:"seller_id"`
+	ID                string    `json:"id"`
+	Name              string    `json:"name"`
+	Description       string    `json:"description"`
+	Price             float64   `json:"price"`
+	Quantity          int       `json:"quantity"`
+	LowStockThreshold int       `json:"low_stock_threshold"`
+	AverageRating     float64   `json:"average_rating"`
+	ReviewCount       int       `json:"review_count"`
+	Unpublished       bool      `json:"unpublished"`
+	ModerationNote    string    `json:"moderation_note"`
+	SellerID          string    `json:"seller_id"`
+	Reviews           []*Review `json:"reviews"`
 }
 
 type ProductInput struct {
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
-	Quantity    int     `json:"quantity"`
+	Name              string  `json:"name"`
+	Description       string  `json:"description"`
+	Price             float64 `json:"price"`
+	Quantity          int     `json:"quantity"`
+	LowStockThreshold *int    `json:"low_stock_threshold,omitempty"`
 }
 
 type Query struct {
@@ -133,21 +202,409 @@ type RefreshToken struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
+type ReturnEvent struct {
+	Status    ReturnStatus `json:"status"`
+	Note      string       `json:"note"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type ReturnInput struct {
+	OrderID   string       `json:"order_id"`
+	ProductID string       `json:"product_id"`
+	Quantity  int          `json:"quantity"`
+	Reason    ReturnReason `json:"reason"`
+	Note      *string      `json:"note,omitempty"`
+}
+
+type Review struct {
+	ID        string    `json:"id"`
+	ProductID string    `json:"product_id"`
+	AccountID string    `json:"account_id"`
+	Rating    int       `json:"rating"`
+	Title     string    `json:"title"`
+	Comment   string    `json:"comment"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ReviewInput struct {
+	ProductID string  `json:"product_id"`
+	Rating    int     `json:"rating"`
+	Title     *string `json:"title,omitempty"`
+	Comment   *string `json:"comment,omitempty"`
+}
+
+type Session struct {
+	ID         string    `json:"id"`
+	Device     string    `json:"device"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	Current    bool      `json:"current"`
+}
+
+type StockLevel struct {
+	ProductID string `json:"product_id"`
+	OnHand    int    `json:"on_hand"`
+	Reserved  int    `json:"reserved"`
+	Available int    `json:"available"`
+}
+
+type StockNotification struct {
+	ID        string                `json:"id"`
+	ProductID string                `json:"product_id"`
+	Kind      StockNotificationKind `json:"kind"`
+	Quantity  int                   `json:"quantity"`
+	Threshold int                   `json:"threshold"`
+	Read      bool                  `json:"read"`
+	CreatedAt time.Time             `json:"created_at"`
+}
+
+type Subscription struct {
+}
+
+type TotpEnrollment struct {
+	Secret        string   `json:"secret"`
+	OtpauthURI    string   `json:"otpauth_uri"`
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type User struct {
+	ID              string     `json:"id"`
+	Email           string     `json:"email"`
+	Role            RoleType   `json:"role"`
+	Roles           []RoleType `json:"roles"`
+	Suspended       bool       `json:"suspended"`
+	SuspendedReason string     `json:"suspended_reason"`
+	TotpEnabled     bool       `json:"totp_enabled"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+func (User) IsLoginResult() {}
+
+type WishlistItem struct {
+	Product      *Product  `json:"product"`
+	SavedPrice   float64   `json:"saved_price"`
+	CurrentPrice float64   `json:"current_price"`
+	InStock      bool      `json:"in_stock"`
+	PriceDropped bool      `json:"price_dropped"`
+	SavedAt      time.Time `json:"saved_at"`
+}
+
+type OrderStatus string
+
+const (
+	OrderStatusPending   OrderStatus = "PENDING"
+	OrderStatusShipped   OrderStatus = "SHIPPED"
+	OrderStatusDelivered OrderStatus = "DELIVERED"
+	OrderStatusCanceled  OrderStatus = "CANCELED"
+)
+
+var AllOrderStatus = []OrderStatus{
+	OrderStatusPending,
+	OrderStatusShipped,
+	OrderStatusDelivered,
+	OrderStatusCanceled,
+}
+
+func (e OrderStatus) IsValid() bool {
+	switch e {
+	case OrderStatusPending, OrderStatusShipped, OrderStatusDelivered, OrderStatusCanceled:
+		return true
+	}
+	return false
+}
+
+func (e OrderStatus) String() string {
+	return string(e)
+}
+
+func (e *OrderStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = OrderStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid OrderStatus", str)
+	}
+	return nil
+}
+
+func (e OrderStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *OrderStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e OrderStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type PaymentStatus string
+
+const (
+	PaymentStatusPending           PaymentStatus = "PENDING"
+	PaymentStatusCompleted         PaymentStatus = "COMPLETED"
+	PaymentStatusFailed            PaymentStatus = "FAILED"
+	PaymentStatusPartiallyRefunded PaymentStatus = "PARTIALLY_REFUNDED"
+	PaymentStatusRefunded          PaymentStatus = "REFUNDED"
+)
+
+var AllPaymentStatus = []PaymentStatus{
+	PaymentStatusPending,
+	PaymentStatusCompleted,
+	PaymentStatusFailed,
+	PaymentStatusPartiallyRefunded,
+	PaymentStatusRefunded,
+}
+
+func (e PaymentStatus) IsValid() bool {
+	switch e {
+	case PaymentStatusPending, PaymentStatusCompleted, PaymentStatusFailed, PaymentStatusPartiallyRefunded, PaymentStatusRefunded:
+		return true
+	}
+	return false
+}
+
+func (e PaymentStatus) String() string {
+	return string(e)
+}
+
+func (e *PaymentStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PaymentStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PaymentStatus", str)
+	}
+	return nil
+}
+
+func (e PaymentStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *PaymentStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e PaymentStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ProductSort string
+
+const (
+	ProductSortRelevance ProductSort = "RELEVANCE"
+	ProductSortRating    ProductSort = "RATING"
+)
+
+var AllProductSort = []ProductSort{
+	ProductSortRelevance,
+	ProductSortRating,
+}
+
+func (e ProductSort) IsValid() bool {
+	switch e {
+	case ProductSortRelevance, ProductSortRating:
+		return true
+	}
+	return false
+}
+
+func (e ProductSort) String() string {
+	return string(e)
+}
+
+func (e *ProductSort) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ProductSort(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ProductSort", str)
+	}
+	return nil
+}
+
+func (e ProductSort) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ProductSort) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ProductSort) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ReturnReason string
+
+const (
+	ReturnReasonDamaged        ReturnReason = "DAMAGED"
+	ReturnReasonWrongItem      ReturnReason = "WRONG_ITEM"
+	ReturnReasonNotAsDescribed ReturnReason = "NOT_AS_DESCRIBED"
+	ReturnReasonNoLongerNeeded ReturnReason = "NO_LONGER_NEEDED"
+	ReturnReasonOther          ReturnReason = "OTHER"
+)
+
+var AllReturnReason = []ReturnReason{
+	ReturnReasonDamaged,
+	ReturnReasonWrongItem,
+	ReturnReasonNotAsDescribed,
+	ReturnReasonNoLongerNeeded,
+	ReturnReasonOther,
+}
+
+func (e ReturnReason) IsValid() bool {
+	switch e {
+	case ReturnReasonDamaged, ReturnReasonWrongItem, ReturnReasonNotAsDescribed, ReturnReasonNoLongerNeeded, ReturnReasonOther:
+		return true
+	}
+	return false
+}
+
+func (e ReturnReason) String() string {
+	return string(e)
+}
+
+func (e *ReturnReason) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ReturnReason(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ReturnReason", str)
+	}
+	return nil
+}
+
+func (e ReturnReason) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ReturnReason) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ReturnReason) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ReturnStatus string
+
+const (
+	ReturnStatusRequested ReturnStatus = "REQUESTED"
+	ReturnStatusApproved  ReturnStatus = "APPROVED"
+	ReturnStatusRejected  ReturnStatus = "REJECTED"
+	ReturnStatusReceived  ReturnStatus = "RECEIVED"
+	ReturnStatusRefunded  ReturnStatus = "REFUNDED"
+)
+
+var AllReturnStatus = []ReturnStatus{
+	ReturnStatusRequested,
+	ReturnStatusApproved,
+	ReturnStatusRejected,
+	ReturnStatusReceived,
+	ReturnStatusRefunded,
+}
+
+func (e ReturnStatus) IsValid() bool {
+	switch e {
+	case ReturnStatusRequested, ReturnStatusApproved, ReturnStatusRejected, ReturnStatusReceived, ReturnStatusRefunded:
+		return true
+	}
+	return false
+}
+
+func (e ReturnStatus) String() string {
+	return string(e)
+}
+
+func (e *ReturnStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ReturnStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ReturnStatus", str)
+	}
+	return nil
+}
+
+func (e ReturnStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ReturnStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ReturnStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
 type RoleType string
 
 const (
 	RoleTypeSeller RoleType = "SELLER"
 	RoleTypeBuyer  RoleType = "BUYER"
+	RoleTypeAdmin  RoleType = "ADMIN"
 )
 
 var AllRoleType = []RoleType{
 	RoleTypeSeller,
 	RoleTypeBuyer,
+	RoleTypeAdmin,
 }
 
 func (e RoleType) IsValid() bool {
 	switch e {
-	case RoleTypeSeller, RoleTypeBuyer:
+	case RoleTypeSeller, RoleTypeBuyer, RoleTypeAdmin:
 		return true
 	}
 	return false
@@ -187,3 +644,60 @@ func (e RoleType) MarshalJSON() ([]byte, error) {
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
 }
+
+type StockNotificationKind string
+
+const (
+	StockNotificationKindLowStock    StockNotificationKind = "LOW_STOCK"
+	StockNotificationKindOutOfStock  StockNotificationKind = "OUT_OF_STOCK"
+	StockNotificationKindBackInStock StockNotificationKind = "BACK_IN_STOCK"
+)
+
+var AllStockNotificationKind = []StockNotificationKind{
+	StockNotificationKindLowStock,
+	StockNotificationKindOutOfStock,
+	StockNotificationKindBackInStock,
+}
+
+func (e StockNotificationKind) IsValid() bool {
+	switch e {
+	case StockNotificationKindLowStock, StockNotificationKindOutOfStock, StockNotificationKindBackInStock:
+		return true
+	}
+	return false
+}
+
+func (e StockNotificationKind) String() string {
+	return string(e)
+}
+
+func (e *StockNotificationKind) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = StockNotificationKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid StockNotificationKind", str)
+	}
+	return nil
+}
+
+func (e StockNotificationKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *StockNotificationKind) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e StockNotificationKind) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
@@ -7,9 +7,12 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/231031/ecom-mcs-grpc/account"
 	"github.com/231031/ecom-mcs-grpc/account/pb"
 	auth_pb "github.com/231031/ecom-mcs-grpc/authentication/pb"
 	"github.com/231031/ecom-mcs-grpc/order"
+	"github.com/231031/ecom-mcs-grpc/pkg/idempotency"
+	"github.com/231031/ecom-mcs-grpc/review"
 	"github.com/vektah/gqlparser/v2/gqlerror"
 )
 
@@ -64,11 +67,11 @@ func (m *mutationResolver) CreateAccountBuyer(ctx context.Context, in AccountBuy
 	}
 
 	return &AccountBuyer{
+		ID:        a.ID,
 		FirstName: a.FirstName,
 		LastName:  a.LastName,
 		Phone:     a.Phone,
 		Address:   a.Address,
-		Orders:    []*Order{},
 	}, nil
 }
 
@@ -98,12 +101,12 @@ func (m *mutationResolver) CreateAccountSeller(ctx context.Context, in AccountSe
 	}
 
 	return &AccountSeller{
+		ID:        a.ID,
 		StoreName: a.StoreName,
 		FirstName: a.FirstName,
 		LastName:  a.LastName,
 		Phone:     a.Phone,
 		Address:   a.Address,
-		Products:  []*Product{},
 	}, nil
 }
 
@@ -132,11 +135,11 @@ func (m *mutationResolver) UpdateAccountBuyer(ctx context.Context, in AccountBuy
 	}
 
 	return &AccountBuyer{
+		ID:        a.Id,
 		FirstName: a.BaseInfo.FirstName,
 		LastName:  a.BaseInfo.LastName,
 		Phone:     a.BaseInfo.Phone,
 		Address:   a.BaseInfo.Address,
-		Orders:    []*Order{},
 	}, nil
 }
 
@@ -166,11 +169,12 @@ func (m *mutationResolver) UpdateAccountSeller(ctx context.Context, in AccountSe
 	}
 
 	return &AccountSeller{
+		ID:        a.Id,
+		StoreName: a.StoreName,
 		FirstName: a.BaseInfo.FirstName,
 		LastName:  a.BaseInfo.LastName,
 		Phone:     a.BaseInfo.Phone,
 		Address:   a.BaseInfo.Address,
-		Products:  []*Product{},
 	}, nil
 }
 
@@ -183,45 +187,69 @@ func (m *mutationResolver) LoginUser(ctx context.Context, email, password string
 	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
+	client := GetClientInfo(ctx)
 	user, err := m.server.authClient.LoginUser(ctx, &auth_pb.LoginUserRequest{
 		Email:    email,
 		Password: password,
+		Device:   client.Device,
+		Ip:       client.IP,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	cookieToken := &http.Cookie{
-		Name:     "token",
-		Value:    user.TokenResponse.GetToken(),
-		Expires:  time.Now().Add(1 * time.Hour),
-		HttpOnly: true,
-		Secure:   true,
-		Path:     "/",
+	// the user has 2FA, the tokens come with verifyMfa
+	if user.GetMfaToken() != "" {
+		return &MfaChallenge{MfaToken: user.GetMfaToken()}, nil
 	}
-	cookieRefreshToken := &http.Cookie{
-		Name:     "refresh_token",
-		Value:    user.TokenResponse.GetRefreshToken(),
-		Expires:  time.Now().Add(240 * time.Hour),
-		HttpOnly: true,
-		Secure:   true,
-		Path:     "/",
+	return loginResult(w, user), nil
+}
+
+func (m *mutationResolver) VerifyMfa(ctx context.Context, mfaToken string, code string) (LoginResult, error) {
+	w, ok := ctx.Value(responseWriterKey).(http.ResponseWriter)
+	if !ok {
+		return nil, &gqlerror.Error{Message: "response writer not found in context"}
 	}
-	http.SetCookie(w, cookieToken)
-	http.SetCookie(w, cookieRefreshToken)
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	client := GetClientInfo(ctx)
+	user, err := m.server.authClient.VerifyMFA(ctx, &auth_pb.VerifyMFARequest{
+		MfaToken: mfaToken,
+		Code:     code,
+		Device:   client.Device,
+		Ip:       client.IP,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return loginResult(w, user), nil
+}
+
+// loginResult sets the token cookies of a completed login and returns the
+// account of the role.
+func loginResult(w http.ResponseWriter, user *auth_pb.LoginUserResponse) LoginResult {
+	setTokenCookies(w, user.GetTokenResponse())
 
 	// access role from context
 	role := MapIntToRole(user.Role)
+	if role == RoleTypeAdmin {
+		return &User{
+			Email: user.Email,
+			Role:  role,
+		}
+	}
 	if role == RoleTypeSeller {
 		return &AccountSeller{
-			Email: email,
-		}, nil
+			Email: user.Email,
+		}
 	}
 
 	// Default: Return an AccountBuyer
 	return &AccountBuyer{
-		Email: email,
-	}, nil
+		Email: user.Email,
+	}
 }
 
 func (m *mutationResolver) RefrehToken(ctx context.Context, token string) (*RefreshToken, error) {
@@ -233,14 +261,276 @@ func (m *mutationResolver) RefrehToken(ctx context.Context, token string) (*Refr
 	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
+	client := GetClientInfo(ctx)
 	in := &auth_pb.RefreshTokenRequest{
 		RefreshToken: token,
+		Device:       client.Device,
+		Ip:           client.IP,
 	}
 	tokenPair, err := m.server.authClient.RefreshTokenUser(ctx, in)
 	if err != nil {
 		return nil, err
 	}
+	setTokenCookies(w, tokenPair)
+
+	return &RefreshToken{}, nil
+}
+
+func (m *mutationResolver) EnrollTotp(ctx context.Context) (*TotpEnrollment, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	userAuth, err := GetUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	enrollment, err := m.server.authClient.EnrollTOTP(ctx, userAuth.ID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return &TotpEnrollment{
+		Secret:        enrollment.GetSecret(),
+		OtpauthURI:    enrollment.GetOtpauthUri(),
+		RecoveryCodes: enrollment.GetRecoveryCodes(),
+	}, nil
+}
+
+func (m *mutationResolver) EnableTotp(ctx context.Context, code string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	userAuth, err := GetUserContext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	err = m.server.authClient.EnableTOTP(ctx, userAuth.ID, code)
+	if err != nil {
+		log.Println(err)
+		return false, err
+	}
+	return true, nil
+}
+
+func (m *mutationResolver) DisableTotp(ctx context.Context, code string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	userAuth, err := GetUserContext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	err = m.server.authClient.DisableTOTP(ctx, userAuth.ID, code)
+	if err != nil {
+		log.Println(err)
+		return false, err
+	}
+	return true, nil
+}
+
+func (m *mutationResolver) VerifyEmail(ctx context.Context, token string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	email, err := m.server.authClient.VerifyEmail(ctx, token)
+	if err != nil {
+		return "", err
+	}
+	return email, nil
+}
+
+func (m *mutationResolver) RequestEmailVerification(ctx context.Context, email string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	err := m.server.authClient.RequestEmailVerification(ctx, email)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
 
+func (m *mutationResolver) RequestPasswordReset(ctx context.Context, email string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	err := m.server.authClient.RequestPasswordReset(ctx, email)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (m *mutationResolver) ResetPassword(ctx context.Context, token string, password string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	err := m.server.authClient.ResetPassword(ctx, token, password)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Logout ends the given session of the user, the one the request is made
+// with when no id is given.
+func (m *mutationResolver) Logout(ctx context.Context, sessionID *string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	userAuth, err := GetUserContext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	id := userAuth.SessionID
+	if sessionID != nil {
+		id = *sessionID
+	}
+	if id == "" {
+		return false, ErrInvalidParameter
+	}
+
+	err = m.server.authClient.Logout(ctx, userAuth.ID, id)
+	if err != nil {
+		log.Println(err)
+		return false, err
+	}
+
+	if id == userAuth.SessionID {
+		clearTokenCookies(ctx)
+	}
+	return true, nil
+}
+
+func (m *mutationResolver) LogoutAllSessions(ctx context.Context) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	userAuth, err := GetUserContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	revoked, err := m.server.authClient.LogoutAllSessions(ctx, userAuth.ID)
+	if err != nil {
+		log.Println(err)
+		return 0, err
+	}
+
+	clearTokenCookies(ctx)
+	return int(revoked), nil
+}
+
+// ChangePassword keeps the session the request is made with, the others are
+// revoked.
+func (m *mutationResolver) ChangePassword(ctx context.Context, currentPassword string, newPassword string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	userAuth, err := GetUserContext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	err = m.server.authClient.ChangePassword(ctx, userAuth.ID, userAuth.SessionID, currentPassword, newPassword)
+	if err != nil {
+		log.Println(err)
+		return false, err
+	}
+	return true, nil
+}
+
+// ChangeEmail sends a verification link to the new email, the email changes
+// once it is opened with verifyEmail.
+func (m *mutationResolver) ChangeEmail(ctx context.Context, email string, password string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	userAuth, err := GetUserContext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	err = m.server.authClient.ChangeEmail(ctx, userAuth.ID, password, email)
+	if err != nil {
+		log.Println(err)
+		return false, err
+	}
+	return true, nil
+}
+
+func (m *mutationResolver) DeleteAccount(ctx context.Context, password string) (*time.Time, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	userAuth, err := GetUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deleteAt, err := m.server.authClient.DeleteUser(ctx, userAuth.ID, password)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	// every session is revoked until the user logs in again
+	clearTokenCookies(ctx)
+	return &deleteAt, nil
+}
+
+// AddRole lets the user hold the role too, the profile of the role is created
+// with createAccountBuyer or createAccountSeller under the same id.
+func (m *mutationResolver) AddRole(ctx context.Context, role RoleType) (*User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	userAuth, err := GetUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := m.server.authClient.AddRole(ctx, userAuth.ID, MapRoleToInt(role))
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return MapUser(u), nil
+}
+
+// SwitchRole makes the session act as another role the user holds, without
+// logging in again.
+func (m *mutationResolver) SwitchRole(ctx context.Context, role RoleType) (LoginResult, error) {
+	w, ok := ctx.Value(responseWriterKey).(http.ResponseWriter)
+	if !ok {
+		return nil, &gqlerror.Error{Message: "response writer not found in context"}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	userAuth, err := GetUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if userAuth.SessionID == "" {
+		return nil, ErrInvalidParameter
+	}
+
+	user, err := m.server.authClient.SwitchRole(ctx, userAuth.ID, userAuth.SessionID, MapRoleToInt(role))
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return loginResult(w, user), nil
+}
+
+func setTokenCookies(w http.ResponseWriter, tokenPair *auth_pb.TokenResponse) {
 	cookieToken := &http.Cookie{
 		Name:     "token",
 		Value:    tokenPair.GetToken(),
@@ -259,8 +549,23 @@ func (m *mutationResolver) RefrehToken(ctx context.Context, token string) (*Refr
 	}
 	http.SetCookie(w, cookieToken)
 	http.SetCookie(w, cookieRefreshToken)
+}
 
-	return &RefreshToken{}, nil
+func clearTokenCookies(ctx context.Context) {
+	w, ok := ctx.Value(responseWriterKey).(http.ResponseWriter)
+	if !ok {
+		return
+	}
+
+	for _, name := range []string{"token", "refresh_token"} {
+		http.SetCookie(w, &http.Cookie{
+			Name:     name,
+			MaxAge:   -1,
+			HttpOnly: true,
+			Secure:   true,
+			Path:     "/",
+		})
+	}
 }
 
 func (m *mutationResolver) CreateProduct(ctx context.Context, in ProductInput) (*Product, error) {
@@ -272,19 +577,25 @@ func (m *mutationResolver) CreateProduct(ctx context.Context, in ProductInput) (
 		return nil, err
 	}
 
-	p, err := m.server.catalogClient.PostProduct(ctx, in.Name, in.Description, userAuth.ID, in.Price, uint32(in.Quantity))
+	threshold := 0
+	if in.LowStockThreshold != nil {
+		threshold = *in.LowStockThreshold
+	}
+
+	p, err := m.server.catalogClient.PostProduct(ctx, in.Name, in.Description, userAuth.ID, in.Price, uint32(in.Quantity), uint32(threshold))
 	if err != nil {
 		return nil, err
 	}
 
 	log.Println(p.SellerID)
 	return &Product{
-		ID:          p.ID,
-		Name:        in.Name,
-		Description: in.Description,
-		Price:       in.Price,
-		Quantity:    in.Quantity,
-		SellerID:    p.SellerID,
+		ID:                p.ID,
+		Name:              in.Name,
+		Description:       in.Description,
+		Price:             in.Price,
+		Quantity:          in.Quantity,
+		LowStockThreshold: threshold,
+		SellerID:          p.SellerID,
 	}, nil
 }
 
@@ -292,18 +603,24 @@ func (m *mutationResolver) UpdateProduct(ctx context.Context, in ProductInput, i
 	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
+	threshold := 0
+	if in.LowStockThreshold != nil {
+		threshold = *in.LowStockThreshold
+	}
+
 	// edit function update product
-	_, err := m.server.catalogClient.UpdateProduct(ctx, id, in.Name, in.Description, in.Price, uint32(in.Quantity))
+	_, err := m.server.catalogClient.UpdateProduct(ctx, id, in.Name, in.Description, in.Price, uint32(in.Quantity), uint32(threshold))
 	if err != nil {
 		return nil, err
 	}
 
 	return &Product{
-		ID:          id,
-		Name:        in.Name,
-		Description: in.Description,
-		Price:       in.Price,
-		Quantity:    in.Quantity,
+		ID:                id,
+		Name:              in.Name,
+		Description:       in.Description,
+		Price:             in.Price,
+		Quantity:          in.Quantity,
+		LowStockThreshold: threshold,
 	}, nil
 }
 
@@ -313,10 +630,16 @@ func (m *mutationResolver) DeleteProduct(ctx context.Context, id string) (string
 	return id, nil
 }
 
-func (m *mutationResolver) CreateOrder(ctx context.Context, in OrderInput) (*Order, error) {
+func (m *mutationResolver) CreateOrder(ctx context.Context, in OrderInput, idempotencyKey *string) (*Order, error) {
 	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
+	// the argument serves clients that cannot set the header, a retry with
+	// the same key gets the order placed the first time
+	if idempotencyKey != nil && *idempotencyKey != "" {
+		ctx = idempotency.NewContext(ctx, *idempotencyKey)
+	}
+
 	var products []order.OrderedProduct
 	for _, p := range in.Products {
 		if p.Quantity <= 0 {
@@ -339,11 +662,7 @@ func (m *mutationResolver) CreateOrder(ctx context.Context, in OrderInput) (*Ord
 		return nil, err
 	}
 
-	return &Order{
-		ID:         order.ID,
-		TotalPrice: order.TotalPrice,
-		CreatedAt:  order.CreatedAt,
-	}, nil
+	return MapOrder(*order), nil
 }
 
 func (m *mutationResolver) DeleteOrder(ctx context.Context, id string) (string, error) {
@@ -351,3 +670,380 @@ func (m *mutationResolver) DeleteOrder(ctx context.Context, id string) (string,
 	// defer cancel()
 	return id, nil
 }
+
+func (m *mutationResolver) UpdateFulfillment(ctx context.Context, id string, in FulfillmentInput) (*Fulfillment, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	userAuth, err := GetUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	carrier, trackingNumber := "", ""
+	if in.Carrier != nil {
+		carrier = *in.Carrier
+	}
+	if in.TrackingNumber != nil {
+		trackingNumber = *in.TrackingNumber
+	}
+
+	f, err := m.server.orderClient.UpdateFulfillment(ctx, id, userAuth.ID, MapOrderStatusToInt(in.Status), carrier, trackingNumber)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return MapFulfillments([]order.Fulfillment{*f})[0], nil
+}
+
+func (m *mutationResolver) RequestReturn(ctx context.Context, in ReturnInput) (*OrderReturn, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	if in.Quantity <= 0 {
+		return nil, ErrInvalidParameter
+	}
+
+	userAuth, err := GetUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	note := ""
+	if in.Note != nil {
+		note = *in.Note
+	}
+
+	ret, err := m.server.orderClient.RequestReturn(ctx, userAuth.ID, in.OrderID, in.ProductID, uint32(in.Quantity), MapReturnReasonToInt(in.Reason), note)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return MapReturns([]order.Return{*ret})[0], nil
+}
+
+func (m *mutationResolver) ReviewReturn(ctx context.Context, id string, approve bool, note *string) (*OrderReturn, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	userAuth, err := GetUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	noteVal := ""
+	if note != nil {
+		noteVal = *note
+	}
+
+	ret, err := m.server.orderClient.ReviewReturn(ctx, id, userAuth.ID, approve, noteVal)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return MapReturns([]order.Return{*ret})[0], nil
+}
+
+func (m *mutationResolver) ReceiveReturn(ctx context.Context, id string, note *string) (*OrderReturn, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	userAuth, err := GetUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	noteVal := ""
+	if note != nil {
+		noteVal = *note
+	}
+
+	ret, err := m.server.orderClient.ReceiveReturn(ctx, id, userAuth.ID, noteVal)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return MapReturns([]order.Return{*ret})[0], nil
+}
+
+func (m *mutationResolver) RefundReturn(ctx context.Context, id string, amount *float64, note *string) (*OrderReturn, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	userAuth, err := GetUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// no amount refunds the returned items in full
+	amountVal := 0.0
+	if amount != nil {
+		if *amount <= 0 {
+			return nil, ErrInvalidParameter
+		}
+		amountVal = *amount
+	}
+	noteVal := ""
+	if note != nil {
+		noteVal = *note
+	}
+
+	ret, err := m.server.orderClient.RefundReturn(ctx, id, userAuth.ID, amountVal, noteVal)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return MapReturns([]order.Return{*ret})[0], nil
+}
+
+func (m *mutationResolver) SubscribeBackInStock(ctx context.Context, productID string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	userAuth, err := GetUserContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	err = m.server.catalogClient.SubscribeBackInStock(ctx, productID, userAuth.ID)
+	if err != nil {
+		log.Println(err)
+		return "", err
+	}
+
+	return productID, nil
+}
+
+func (m *mutationResolver) UnsubscribeBackInStock(ctx context.Context, productID string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	userAuth, err := GetUserContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	err = m.server.catalogClient.UnsubscribeBackInStock(ctx, productID, userAuth.ID)
+	if err != nil {
+		log.Println(err)
+		return "", err
+	}
+
+	return productID, nil
+}
+
+func (m *mutationResolver) MarkStockNotificationsRead(ctx context.Context, ids []string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	userAuth, err := GetUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	marked, err := m.server.catalogClient.MarkStockNotificationsRead(ctx, userAuth.ID, ids)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return marked, nil
+}
+
+func (m *mutationResolver) UpdateNotificationPreferences(ctx context.Context, in NotificationPreferencesInput) (*NotificationPreferences, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	userAuth, err := GetUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := m.server.accountClient.UpdateNotificationPreferences(ctx, account.NotificationPreference{
+		AccountID:      userAuth.ID,
+		AccountCreated: in.AccountCreated,
+		OrderPlaced:    in.OrderPlaced,
+		OrderShipped:   in.OrderShipped,
+	})
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return &NotificationPreferences{
+		AccountCreated: p.AccountCreated,
+		OrderPlaced:    p.OrderPlaced,
+		OrderShipped:   p.OrderShipped,
+	}, nil
+}
+
+func (m *mutationResolver) PostReview(ctx context.Context, in ReviewInput) (*Review, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	userAuth, err := GetUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	title, comment := "", ""
+	if in.Title != nil {
+		title = *in.Title
+	}
+	if in.Comment != nil {
+		comment = *in.Comment
+	}
+
+	rv, err := m.server.reviewClient.PostReview(ctx, in.ProductID, userAuth.ID, uint32(in.Rating), title, comment)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return MapReviews([]review.Review{*rv})[0], nil
+}
+
+func (m *mutationResolver) DeleteReview(ctx context.Context, id string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	userAuth, err := GetUserContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	rv, err := m.server.reviewClient.DeleteReview(ctx, id, userAuth.ID)
+	if err != nil {
+		log.Println(err)
+		return "", err
+	}
+
+	return rv.ID, nil
+}
+
+func (m *mutationResolver) AddToWishlist(ctx context.Context, productID string) (*WishlistItem, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	userAuth, err := GetUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := m.server.catalogClient.GetProduct(ctx, productID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	item, err := m.server.accountClient.AddWishlistItem(ctx, userAuth.ID, p.ID, p.Price)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return MapWishlistItem(item.SavedPrice, item.CreatedAt, *p), nil
+}
+
+func (m *mutationResolver) RemoveFromWishlist(ctx context.Context, productID string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	userAuth, err := GetUserContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	err = m.server.accountClient.RemoveWishlistItem(ctx, userAuth.ID, productID)
+	if err != nil {
+		log.Println(err)
+		return "", err
+	}
+
+	return productID, nil
+}
+
+func (m *mutationResolver) AdjustStock(ctx context.Context, productID string, delta int, note *string) (*StockLevel, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	noteVal := ""
+	if note != nil {
+		noteVal = *note
+	}
+
+	l, err := m.server.catalogClient.AdjustStock(ctx, productID, int32(delta), noteVal)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return &StockLevel{
+		ProductID: l.ProductID,
+		OnHand:    int(l.OnHand),
+		Reserved:  int(l.Reserved),
+		Available: int(l.Available()),
+	}, nil
+}
+
+func (m *mutationResolver) SuspendUser(ctx context.Context, id string, reason string) (*User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	u, err := m.server.authClient.SetUserSuspended(ctx, id, true, reason)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return MapUser(u), nil
+}
+
+func (m *mutationResolver) ReactivateUser(ctx context.Context, id string) (*User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	u, err := m.server.authClient.SetUserSuspended(ctx, id, false, "")
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return MapUser(u), nil
+}
+
+func (m *mutationResolver) UnlockUser(ctx context.Context, id string) (*User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	u, err := m.server.authClient.UnlockUser(ctx, id)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return MapUser(u), nil
+}
+
+func (m *mutationResolver) ModerateProduct(ctx context.Context, id string, unpublished bool, note *string) (*Product, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	noteVal := ""
+	if note != nil {
+		noteVal = *note
+	}
+
+	p, err := m.server.catalogClient.ModerateProduct(ctx, id, unpublished, noteVal)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return MapProduct(*p), nil
+}
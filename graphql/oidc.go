@@ -0,0 +1,122 @@
+package graphql
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	auth_pb "github.com/231031/ecom-mcs-grpc/authentication/pb"
+	"github.com/231031/ecom-mcs-grpc/pkg/grpcerr"
+	"google.golang.org/grpc/status"
+)
+
+// The OIDC login runs in the browser, outside of GraphQL. The login endpoint
+// redirects to the provider, which redirects back to the callback with the
+// code. The state is kept in a cookie too, so a callback only completes a
+// login started in the same browser.
+const (
+	oidcStateCookie = "oidc_state"
+	oidcCookiePath  = "/auth/oidc/"
+)
+
+// OIDCLogin handles GET /auth/oidc/{provider}/login, a login that cannot
+// start goes back to redirectURL with the error.
+func (s *Server) OIDCLogin(redirectURL string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		resp, err := s.authClient.StartOIDCLogin(ctx, r.PathValue("provider"))
+		if err != nil {
+			log.Println(err)
+			redirectAfterLogin(w, r, redirectURL, "error", oidcErrorReason(err))
+			return
+		}
+
+		http.SetCookie(w, &http.Cookie{
+			Name:     oidcStateCookie,
+			Value:    resp.GetState(),
+			MaxAge:   int((10 * time.Minute).Seconds()),
+			HttpOnly: true,
+			Secure:   true,
+			// the provider redirects back with a top level GET
+			SameSite: http.SameSiteLaxMode,
+			Path:     oidcCookiePath,
+		})
+		http.Redirect(w, r, resp.GetAuthorizationUrl(), http.StatusFound)
+	}
+}
+
+// OIDCCallback handles GET /auth/oidc/{provider}/callback. It sets the token
+// cookies loginUser sets and sends the browser to redirectURL, with the
+// mfa_token when the user has 2FA, or the error when the login failed.
+func (s *Server) OIDCCallback(redirectURL string, proxies TrustedProxies) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		http.SetCookie(w, &http.Cookie{
+			Name:     oidcStateCookie,
+			MaxAge:   -1,
+			HttpOnly: true,
+			Secure:   true,
+			Path:     oidcCookiePath,
+		})
+
+		// the user cancelled or the provider refused
+		if e := query.Get("error"); e != "" {
+			redirectAfterLogin(w, r, redirectURL, "error", e)
+			return
+		}
+
+		cookie, err := r.Cookie(oidcStateCookie)
+		state := query.Get("state")
+		if err != nil || state == "" || cookie.Value != state {
+			redirectAfterLogin(w, r, redirectURL, "error", grpcerr.ReasonValidation)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		defer cancel()
+
+		client := clientInfoFromRequest(r, proxies)
+		user, err := s.authClient.CompleteOIDCLogin(ctx, &auth_pb.CompleteOIDCLoginRequest{
+			Provider: r.PathValue("provider"),
+			Code:     query.Get("code"),
+			State:    state,
+			Device:   client.Device,
+			Ip:       client.IP,
+		})
+		if err != nil {
+			log.Println(err)
+			redirectAfterLogin(w, r, redirectURL, "error", oidcErrorReason(err))
+			return
+		}
+
+		if user.GetMfaToken() != "" {
+			redirectAfterLogin(w, r, redirectURL, "mfa_token", user.GetMfaToken())
+			return
+		}
+		setTokenCookies(w, user.GetTokenResponse())
+		http.Redirect(w, r, redirectURL, http.StatusFound)
+	}
+}
+
+func redirectAfterLogin(w http.ResponseWriter, r *http.Request, redirectURL string, key string, value string) {
+	sep := "?"
+	if strings.Contains(redirectURL, "?") {
+		sep = "&"
+	}
+	http.Redirect(w, r, redirectURL+sep+url.Values{key: {value}}.Encode(), http.StatusFound)
+}
+
+// oidcErrorReason is the reason the frontend is told, as the extension of a
+// GraphQL error would carry it.
+func oidcErrorReason(err error) string {
+	st, ok := status.FromError(err)
+	if !ok {
+		return grpcerr.ReasonInternal
+	}
+	return grpcerr.Reason(st)
+}
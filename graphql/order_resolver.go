@@ -0,0 +1,46 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"log"
+)
+
+type orderResolver struct {
+	server *Server
+}
+
+func (r *orderResolver) Account(ctx context.Context, obj *Order) (*AccountBuyer, error) {
+	a, err := r.server.GetLoaders(ctx).Buyers.Load(obj.Account.ID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return &AccountBuyer{
+		ID:        a.ID,
+		FirstName: a.FirstName,
+		LastName:  a.LastName,
+		Phone:     a.Phone,
+		Address:   a.Address,
+	}, nil
+}
+
+type orderProductResolver struct {
+	server *Server
+}
+
+// CurrentProduct is the catalog entry of the ordered product as it is now,
+// null once the product left the catalog.
+func (r *orderProductResolver) CurrentProduct(ctx context.Context, obj *OrderProduct) (*Product, error) {
+	p, err := r.server.GetLoaders(ctx).Products.Load(obj.Product.ID)
+	if errors.Is(err, ErrNotLoaded) {
+		return nil, nil
+	}
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return MapProduct(p), nil
+}
@@ -0,0 +1,124 @@
+package graphql
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"os"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/redis/go-redis/v9"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const errQueryNotAllowed = "PERSISTED_QUERY_NOT_ALLOWED"
+
+// RedisCache shares persisted queries between gateway replicas.
+type RedisCache struct {
+	client *redis.Client
+	prefix string
+	ttl    time.Duration
+}
+
+var _ graphql.Cache[string] = (*RedisCache)(nil)
+
+func NewRedisCache(addr, password, prefix string, ttl time.Duration) (*RedisCache, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       0,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, err
+	}
+
+	return &RedisCache{client: client, prefix: prefix, ttl: ttl}, nil
+}
+
+func (c *RedisCache) Get(ctx context.Context, key string) (string, bool) {
+	value, err := c.client.Get(ctx, c.prefix+key).Result()
+	if err != nil {
+		if err != redis.Nil {
+			log.Println("error getting persisted query", err)
+		}
+		return "", false
+	}
+	return value, true
+}
+
+func (c *RedisCache) Add(ctx context.Context, key string, value string) {
+	if err := c.client.Set(ctx, c.prefix+key, value, c.ttl).Err(); err != nil {
+		log.Println("error adding persisted query", err)
+	}
+}
+
+// AllowList only lets through the queries of a manifest built with the
+// clients, a JSON object of sha256 hash to query. Used as the APQ cache it
+// also serves those queries by hash.
+type AllowList struct {
+	queries map[string]string
+}
+
+var _ interface {
+	graphql.Cache[string]
+	graphql.OperationParameterMutator
+	graphql.HandlerExtension
+} = (*AllowList)(nil)
+
+func LoadAllowList(path string) (*AllowList, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	queries := map[string]string{}
+	if err := json.Unmarshal(data, &queries); err != nil {
+		return nil, err
+	}
+
+	// key the queries by their real hash so a wrong manifest entry cannot
+	// let another query through
+	allowList := &AllowList{queries: map[string]string{}}
+	for _, query := range queries {
+		allowList.queries[queryHash(query)] = query
+	}
+	return allowList, nil
+}
+
+func (a *AllowList) Get(ctx context.Context, key string) (string, bool) {
+	query, ok := a.queries[key]
+	return query, ok
+}
+
+// Add does nothing, the allow list only changes with a new manifest.
+func (a *AllowList) Add(ctx context.Context, key string, value string) {}
+
+func (a *AllowList) ExtensionName() string {
+	return "AllowList"
+}
+
+func (a *AllowList) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (a *AllowList) MutateOperationParameters(ctx context.Context, rawParams *graphql.RawParams) *gqlerror.Error {
+	if _, ok := a.queries[queryHash(rawParams.Query)]; ok {
+		return nil
+	}
+
+	err := gqlerror.Errorf("query is not in the allow list")
+	errcode.Set(err, errQueryNotAllowed)
+	return err
+}
+
+func queryHash(query string) string {
+	b := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(b[:])
+}
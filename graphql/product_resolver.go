@@ -0,0 +1,25 @@
+package graphql
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+type productResolver struct {
+	server *Server
+}
+
+func (r *productResolver) Reviews(ctx context.Context, obj *Product, pagination *PaginationInput) ([]*Review, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	skip, take := pagination.bounds()
+	reviews, err := r.server.reviewClient.GetProductReviews(ctx, obj.ID, skip, take)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return MapReviews(reviews), nil
+}
@@ -5,6 +5,10 @@ import (
 	"errors"
 	"log"
 	"time"
+
+	auth_pb "github.com/231031/ecom-mcs-grpc/authentication/pb"
+	"github.com/231031/ecom-mcs-grpc/catalog"
+	"github.com/231031/ecom-mcs-grpc/order"
 )
 
 var (
@@ -31,6 +35,7 @@ func (r *queryResolver) GetProfileBuyer(ctx context.Context) (*AccountBuyer, err
 	}
 
 	return &AccountBuyer{
+		ID:        a.ID,
 		FirstName: a.FirstName,
 		LastName:  a.LastName,
 		Phone:     a.Phone,
@@ -54,6 +59,7 @@ func (r *queryResolver) GetProfileSeller(ctx context.Context) (*AccountSeller, e
 	}
 
 	return &AccountSeller{
+		ID:        a.ID,
 		StoreName: a.StoreName,
 		FirstName: a.FirstName,
 		LastName:  a.LastName,
@@ -66,14 +72,21 @@ func (r *queryResolver) GetBuyer(ctx context.Context, id string) (*AccountBuyer,
 	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
+	userAuth, err := GetUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// sellers only see the buyers that ordered from them
 	if id != "" {
-		a, err := r.server.accountClient.GetAccountBuyerByID(ctx, id)
+		a, err := r.server.orderClient.GetBuyerForSeller(ctx, userAuth.ID, id)
 		if err != nil {
 			log.Println(err)
 			return nil, err
 		}
 
 		return &AccountBuyer{
+			ID:        a.ID,
 			FirstName: a.FirstName,
 			LastName:  a.LastName,
 			Phone:     a.Phone,
@@ -96,6 +109,7 @@ func (r *queryResolver) GetSeller(ctx context.Context, id string) (*AccountSelle
 		}
 
 		return &AccountSeller{
+			ID:        a.ID,
 			StoreName: a.StoreName,
 			FirstName: a.FirstName,
 			LastName:  a.LastName,
@@ -129,6 +143,7 @@ func (r *queryResolver) GetSellers(ctx context.Context, pagination *PaginationIn
 		var sellers = []*AccountSeller{}
 		for _, a := range accounts {
 			sellers = append(sellers, &AccountSeller{
+				ID:        a.ID,
 				StoreName: a.StoreName,
 				FirstName: a.FirstName,
 				LastName:  a.LastName,
@@ -142,7 +157,7 @@ func (r *queryResolver) GetSellers(ctx context.Context, pagination *PaginationIn
 	return nil, ErrInvalidID
 }
 
-func (r *queryResolver) GetProducts(ctx context.Context, pagination *PaginationInput, query *string, id *string) ([]*Product, error) {
+func (r *queryResolver) GetProducts(ctx context.Context, pagination *PaginationInput, query *string, id *string, minRating *float64, sort *ProductSort) ([]*Product, error) {
 	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
 	defer cancel()
 
@@ -157,10 +172,15 @@ func (r *queryResolver) GetProducts(ctx context.Context, pagination *PaginationI
 			return nil, err
 		}
 		return []*Product{{
-			ID:          p.ID,
-			Name:        p.Name,
-			Description: p.Description,
-			Price:       p.Price,
+			ID:                p.ID,
+			Name:              p.Name,
+			Description:       p.Description,
+			Price:             p.Price,
+			Quantity:          int(p.Quantity),
+			LowStockThreshold: int(p.LowStockThreshold),
+			AverageRating:     p.AverageRating,
+			ReviewCount:       int(p.ReviewCount),
+			SellerID:          p.SellerID,
 		}}, nil
 	}
 
@@ -168,7 +188,13 @@ func (r *queryResolver) GetProducts(ctx context.Context, pagination *PaginationI
 	if pagination != nil {
 		skip, take = pagination.bounds()
 	}
-	productList, err := r.server.catalogClient.GetProducts(ctx, skip, take, nil, q)
+	filter := catalog.ProductFilter{
+		SortByRating: sort != nil && *sort == ProductSortRating,
+	}
+	if minRating != nil {
+		filter.MinRating = *minRating
+	}
+	productList, err := r.server.catalogClient.GetProducts(ctx, skip, take, nil, q, filter)
 	if err != nil {
 		log.Println(err)
 		return nil, err
@@ -178,10 +204,15 @@ func (r *queryResolver) GetProducts(ctx context.Context, pagination *PaginationI
 	for _, p := range productList {
 		products = append(products,
 			&Product{
-				ID:          p.ID,
-				Name:        p.Name,
-				Description: p.Description,
-				Price:       p.Price,
+				ID:                p.ID,
+				Name:              p.Name,
+				Description:       p.Description,
+				Price:             p.Price,
+				Quantity:          int(p.Quantity),
+				LowStockThreshold: int(p.LowStockThreshold),
+				AverageRating:     p.AverageRating,
+				ReviewCount:       int(p.ReviewCount),
+				SellerID:          p.SellerID,
 			},
 		)
 	}
@@ -204,29 +235,173 @@ func (r *queryResolver) GetOrders(ctx context.Context, id *string) ([]*Order, er
 
 	orders := []*Order{}
 	for _, o := range orderList {
-		var products []*OrderProduct
-		for _, p := range o.Products {
-			products = append(products, &OrderProduct{
-				Product: &Product{
-					ID:          p.ID,
-					Name:        p.Name,
-					Description: p.Description,
-					Price:       p.Price,
-				},
-				Quantity: int(p.Quantity),
-			})
-		}
-
-		orders = append(orders, &Order{
-			ID:         o.ID,
-			TotalPrice: o.TotalPrice,
-			CreatedAt:  o.CreatedAt,
-			Products:   products,
-		})
+		orders = append(orders, MapOrder(o))
 	}
 	return orders, nil
 }
 
+func (r *queryResolver) GetFulfillments(ctx context.Context, pagination *PaginationInput) ([]*Fulfillment, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	userAuth, err := GetUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	skip, take := pagination.bounds()
+	fulfillments, err := r.server.orderClient.GetFulfillmentsForSeller(ctx, userAuth.ID, skip, take)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return MapFulfillments(fulfillments), nil
+}
+
+func (r *queryResolver) GetReturns(ctx context.Context, pagination *PaginationInput) ([]*OrderReturn, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	userAuth, err := GetUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var returns []order.Return
+	if MapIntToRole(userAuth.Role) == RoleTypeSeller {
+		skip, take := pagination.bounds()
+		returns, err = r.server.orderClient.GetReturnsForSeller(ctx, userAuth.ID, skip, take)
+	} else {
+		returns, err = r.server.orderClient.GetReturnsForAccount(ctx, userAuth.ID)
+	}
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return MapReturns(returns), nil
+}
+
+func (r *queryResolver) GetStockNotifications(ctx context.Context, unreadOnly *bool, pagination *PaginationInput) ([]*StockNotification, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	userAuth, err := GetUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	skip, take := pagination.bounds()
+	notifications, err := r.server.catalogClient.GetStockNotifications(ctx, userAuth.ID, unreadOnly != nil && *unreadOnly, skip, take)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return MapStockNotifications(notifications), nil
+}
+
+func (r *queryResolver) GetNotificationPreferences(ctx context.Context) (*NotificationPreferences, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	userAuth, err := GetUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := r.server.accountClient.GetNotificationPreferences(ctx, userAuth.ID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return &NotificationPreferences{
+		AccountCreated: p.AccountCreated,
+		OrderPlaced:    p.OrderPlaced,
+		OrderShipped:   p.OrderShipped,
+	}, nil
+}
+
+func (r *queryResolver) GetSessions(ctx context.Context) ([]*Session, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	userAuth, err := GetUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := r.server.authClient.ListSessions(ctx, userAuth.ID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return MapSessions(sessions, userAuth.SessionID), nil
+}
+
+// GetMe returns the user of the token, with the roles they can switch to.
+func (r *queryResolver) GetMe(ctx context.Context) (*User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	userAuth, err := GetUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := r.server.authClient.GetUser(ctx, userAuth.ID)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return MapUser(u), nil
+}
+
+func (r *queryResolver) GetUsers(ctx context.Context, query *string, roles []RoleType, suspendedOnly *bool, pagination *PaginationInput) ([]*User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	in := &auth_pb.ListUsersRequest{}
+	if query != nil {
+		in.Query = *query
+	}
+	for _, role := range roles {
+		in.Roles = append(in.Roles, MapRoleToInt(role))
+	}
+	if suspendedOnly != nil {
+		in.SuspendedOnly = *suspendedOnly
+	}
+	in.Skip, in.Take = pagination.bounds()
+
+	users, err := r.server.authClient.ListUsers(ctx, in)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	result := []*User{}
+	for _, u := range users {
+		result = append(result, MapUser(u))
+	}
+	return result, nil
+}
+
+func (r *queryResolver) GetOrder(ctx context.Context, id string) (*Order, error) {
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	o, err := r.server.orderClient.GetOrder(ctx, id)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+
+	return MapOrder(*o), nil
+}
+
 func (p *PaginationInput) bounds() (uint64, uint64) {
 	skipVal := uint64(0)
 	takeVal := uint64(0)
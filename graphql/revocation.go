@@ -0,0 +1,105 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// the denylist entries the authentication service writes, tokens were denied
+// one by one before whole sessions were
+const (
+	revokedTokenPrefix   = "revoked_token:"
+	revokedSessionPrefix = "revoked_session:"
+)
+
+// how long a cached answer stands in for Redis while it is unreachable,
+// after that the tokens are refused until it is back
+const revocationMaxStale = time.Minute
+
+// RevocationList reads the sessions the authentication service denied on
+// logout and suspension. Answers are cached so most requests do not reach
+// Redis, a revoked token stays revoked until it expires, a valid one is asked
+// again after the cache ttl. Only the gateway reads the list, the services
+// verify the signature of the token and nothing else.
+type RevocationList struct {
+	client *redis.Client
+	ttl    time.Duration
+
+	mu        sync.Mutex
+	entries   map[string]revocationEntry
+	lastSweep time.Time
+}
+
+type revocationEntry struct {
+	revoked   bool
+	checkedAt time.Time
+	expiresAt time.Time
+}
+
+func NewRevocationList(addr, password string, ttl time.Duration) (*RevocationList, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       0,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, err
+	}
+
+	return &RevocationList{
+		client:  client,
+		ttl:     ttl,
+		entries: map[string]revocationEntry{},
+	}, nil
+}
+
+// IsRevoked tells whether the token with the id, or the session it was issued
+// for, was revoked. expiresAt is when the token expires by itself. While
+// Redis is unreachable the last answer of the token is used for
+// revocationMaxStale, an error is returned after that.
+func (l *RevocationList) IsRevoked(ctx context.Context, tokenID, sessionID string, expiresAt time.Time) (bool, error) {
+	now := time.Now()
+
+	l.mu.Lock()
+	entry, ok := l.entries[tokenID]
+	l.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.revoked, nil
+	}
+
+	keys := []string{revokedTokenPrefix + tokenID}
+	if sessionID != "" {
+		keys = append(keys, revokedSessionPrefix+sessionID)
+	}
+	n, err := l.client.Exists(ctx, keys...).Result()
+	if err != nil {
+		if ok && now.Sub(entry.checkedAt) < revocationMaxStale {
+			return entry.revoked, nil
+		}
+		return false, err
+	}
+
+	entry = revocationEntry{revoked: n > 0, checkedAt: now, expiresAt: now.Add(l.ttl)}
+	if entry.revoked {
+		entry.expiresAt = expiresAt
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries[tokenID] = entry
+	if now.Sub(l.lastSweep) > l.ttl {
+		for id, e := range l.entries {
+			if now.After(e.expiresAt) && now.Sub(e.checkedAt) > revocationMaxStale {
+				delete(l.entries, id)
+			}
+		}
+		l.lastSweep = now
+	}
+	return entry.revoked, nil
+}
@@ -0,0 +1,65 @@
+package graphql
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// notificationPollInterval is how often a subscription looks for new stock
+// notifications, catalog has no push channel yet.
+const notificationPollInterval = 5 * time.Second
+
+type subscriptionResolver struct {
+	server *Server
+}
+
+func (r *subscriptionResolver) StockNotifications(ctx context.Context) (<-chan *StockNotification, error) {
+	userAuth, err := GetUserContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan *StockNotification, 1)
+	go func() {
+		defer close(ch)
+
+		since := time.Now().UTC()
+		ticker := time.NewTicker(notificationPollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			pollCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+			notifications, err := r.server.catalogClient.GetStockNotifications(pollCtx, userAuth.ID, true, 0, 0)
+			cancel()
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+
+			// notifications come newest first, send them in the order they happened
+			mapped := MapStockNotifications(notifications)
+			for i := len(mapped) - 1; i >= 0; i-- {
+				n := mapped[i]
+				if !n.CreatedAt.After(since) {
+					continue
+				}
+
+				select {
+				case ch <- n:
+					since = n.CreatedAt
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return ch, nil
+}
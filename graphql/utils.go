@@ -1,7 +1,14 @@
 package graphql
 
 import (
+	"log"
+	"time"
+
 	"github.com/231031/ecom-mcs-grpc/account/pb"
+	auth_pb "github.com/231031/ecom-mcs-grpc/authentication/pb"
+	"github.com/231031/ecom-mcs-grpc/catalog"
+	"github.com/231031/ecom-mcs-grpc/order"
+	"github.com/231031/ecom-mcs-grpc/review"
 )
 
 type SelectionType string
@@ -64,6 +71,7 @@ func MapRoleToInt(role RoleType) int32 {
 	mapRole := map[RoleType]int32{
 		RoleTypeBuyer:  0,
 		RoleTypeSeller: 1,
+		RoleTypeAdmin:  2,
 	}
 
 	return mapRole[role]
@@ -73,6 +81,268 @@ func MapIntToRole(roleNum int32) RoleType {
 	mapRole := map[int32]RoleType{
 		0: RoleTypeBuyer,
 		1: RoleTypeSeller,
+		2: RoleTypeAdmin,
 	}
 	return mapRole[roleNum]
 }
+
+func MapOrderStatusToInt(status OrderStatus) int32 {
+	mapStatus := map[OrderStatus]int32{
+		OrderStatusPending:   order.OrderStatusPending,
+		OrderStatusShipped:   order.OrderStatusShipped,
+		OrderStatusDelivered: order.OrderStatusDelivered,
+		OrderStatusCanceled:  order.OrderStatusCanceled,
+	}
+
+	return mapStatus[status]
+}
+
+func MapIntToOrderStatus(statusNum int32) OrderStatus {
+	mapStatus := map[int32]OrderStatus{
+		order.OrderStatusPending:   OrderStatusPending,
+		order.OrderStatusShipped:   OrderStatusShipped,
+		order.OrderStatusDelivered: OrderStatusDelivered,
+		order.OrderStatusCanceled:  OrderStatusCanceled,
+	}
+	return mapStatus[statusNum]
+}
+
+func MapIntToPaymentStatus(statusNum int32) PaymentStatus {
+	mapStatus := map[int32]PaymentStatus{
+		order.PaymentStatusPending:           PaymentStatusPending,
+		order.PaymentStatusCompleted:         PaymentStatusCompleted,
+		order.PaymentStatusFailed:            PaymentStatusFailed,
+		order.PaymentStatusPartiallyRefunded: PaymentStatusPartiallyRefunded,
+		order.PaymentStatusRefunded:          PaymentStatusRefunded,
+	}
+	return mapStatus[statusNum]
+}
+
+func MapReturnReasonToInt(reason ReturnReason) int32 {
+	mapReason := map[ReturnReason]int32{
+		ReturnReasonDamaged:        order.ReturnReasonDamaged,
+		ReturnReasonWrongItem:      order.ReturnReasonWrongItem,
+		ReturnReasonNotAsDescribed: order.ReturnReasonNotAsDescribed,
+		ReturnReasonNoLongerNeeded: order.ReturnReasonNoLongerNeeded,
+		ReturnReasonOther:          order.ReturnReasonOther,
+	}
+
+	return mapReason[reason]
+}
+
+func MapIntToReturnReason(reasonNum int32) ReturnReason {
+	mapReason := map[int32]ReturnReason{
+		order.ReturnReasonDamaged:        ReturnReasonDamaged,
+		order.ReturnReasonWrongItem:      ReturnReasonWrongItem,
+		order.ReturnReasonNotAsDescribed: ReturnReasonNotAsDescribed,
+		order.ReturnReasonNoLongerNeeded: ReturnReasonNoLongerNeeded,
+		order.ReturnReasonOther:          ReturnReasonOther,
+	}
+	return mapReason[reasonNum]
+}
+
+func MapIntToReturnStatus(statusNum int32) ReturnStatus {
+	mapStatus := map[int32]ReturnStatus{
+		order.ReturnStatusRequested: ReturnStatusRequested,
+		order.ReturnStatusApproved:  ReturnStatusApproved,
+		order.ReturnStatusRejected:  ReturnStatusRejected,
+		order.ReturnStatusReceived:  ReturnStatusReceived,
+		order.ReturnStatusRefunded:  ReturnStatusRefunded,
+	}
+	return mapStatus[statusNum]
+}
+
+func MapProduct(p catalog.Product) *Product {
+	return &Product{
+		ID:                p.ID,
+		Name:              p.Name,
+		Description:       p.Description,
+		Price:             p.Price,
+		Quantity:          int(p.Quantity),
+		LowStockThreshold: int(p.LowStockThreshold),
+		AverageRating:     p.AverageRating,
+		ReviewCount:       int(p.ReviewCount),
+		SellerID:          p.SellerID,
+		Unpublished:       p.Unpublished,
+		ModerationNote:    p.ModerationNote,
+	}
+}
+
+func MapUser(u *auth_pb.User) *User {
+	user := &User{
+		ID:              u.Id,
+		Email:           u.Email,
+		Role:            MapIntToRole(u.Role),
+		Roles:           []RoleType{},
+		Suspended:       u.Suspended,
+		SuspendedReason: u.SuspendedReason,
+		TotpEnabled:     u.TotpEnabled,
+	}
+	for _, r := range u.Roles {
+		user.Roles = append(user.Roles, MapIntToRole(r))
+	}
+	if err := user.CreatedAt.UnmarshalBinary(u.CreatedAt); err != nil {
+		log.Println("error unmarshalling timestamp", err)
+	}
+	return user
+}
+
+// MapSessions marks the session the request is made with as the current one.
+func MapSessions(sessions []*auth_pb.Session, currentID string) []*Session {
+	result := []*Session{}
+	for _, s := range sessions {
+		session := &Session{
+			ID:      s.Id,
+			Device:  s.Device,
+			IP:      s.Ip,
+			Current: s.Id == currentID,
+		}
+		if err := session.CreatedAt.UnmarshalBinary(s.CreatedAt); err != nil {
+			log.Println("error unmarshalling timestamp", err)
+		}
+		if err := session.LastUsedAt.UnmarshalBinary(s.LastUsedAt); err != nil {
+			log.Println("error unmarshalling timestamp", err)
+		}
+		result = append(result, session)
+	}
+	return result
+}
+
+func MapOrder(o order.Order) *Order {
+	return &Order{
+		ID:             o.ID,
+		Account:        &AccountBuyer{ID: o.AccountID},
+		TotalPrice:     o.TotalPrice,
+		CreatedAt:      o.CreatedAt,
+		Status:         MapIntToOrderStatus(o.Status),
+		PaymentStatus:  MapIntToPaymentStatus(o.PaymentStatus),
+		RefundedAmount: o.RefundedAmount,
+		Products:       MapOrderedProducts(o.Products),
+		Fulfillments:   MapFulfillments(o.Fulfillments),
+	}
+}
+
+// MapOrderedProducts keeps the name and price the product was ordered at, the
+// current catalog entry is resolved separately as currentProduct.
+func MapOrderedProducts(products []order.OrderedProduct) []*OrderProduct {
+	orderProducts := []*OrderProduct{}
+	for _, p := range products {
+		orderProducts = append(orderProducts, &OrderProduct{
+			Product: &Product{
+				ID:          p.ID,
+				Name:        p.Name,
+				Description: p.Description,
+				Price:       p.Price,
+				SellerID:    p.SellerID,
+			},
+			Quantity: int(p.Quantity),
+		})
+	}
+	return orderProducts
+}
+
+func MapFulfillments(fulfillments []order.Fulfillment) []*Fulfillment {
+	result := []*Fulfillment{}
+	for _, f := range fulfillments {
+		result = append(result, &Fulfillment{
+			ID:             f.ID,
+			OrderID:        f.OrderID,
+			SellerID:       f.SellerID,
+			Status:         MapIntToOrderStatus(f.Status),
+			Carrier:        f.Carrier,
+			TrackingNumber: f.TrackingNumber,
+			PayoutAmount:   f.PayoutAmount,
+			Products:       MapOrderedProducts(f.Products),
+			CreatedAt:      f.CreatedAt,
+			UpdatedAt:      f.UpdatedAt,
+		})
+	}
+	return result
+}
+
+func MapReturns(returns []order.Return) []*OrderReturn {
+	result := []*OrderReturn{}
+	for _, ret := range returns {
+		timeline := []*ReturnEvent{}
+		for _, e := range ret.Timeline {
+			timeline = append(timeline, &ReturnEvent{
+				Status:    MapIntToReturnStatus(e.Status),
+				Note:      e.Note,
+				CreatedAt: e.CreatedAt,
+			})
+		}
+
+		result = append(result, &OrderReturn{
+			ID:            ret.ID,
+			OrderID:       ret.OrderID,
+			FulfillmentID: ret.FulfillmentID,
+			AccountID:     ret.AccountID,
+			SellerID:      ret.SellerID,
+			ProductID:     ret.ProductID,
+			Quantity:      int(ret.Quantity),
+			Reason:        MapIntToReturnReason(ret.Reason),
+			Note:          ret.Note,
+			Status:        MapIntToReturnStatus(ret.Status),
+			RefundAmount:  ret.RefundAmount,
+			Timeline:      timeline,
+			CreatedAt:     ret.CreatedAt,
+			UpdatedAt:     ret.UpdatedAt,
+		})
+	}
+	return result
+}
+
+func MapIntToStockNotificationKind(kindNum int32) StockNotificationKind {
+	mapKind := map[int32]StockNotificationKind{
+		catalog.NotificationLowStock:    StockNotificationKindLowStock,
+		catalog.NotificationOutOfStock:  StockNotificationKindOutOfStock,
+		catalog.NotificationBackInStock: StockNotificationKindBackInStock,
+	}
+	return mapKind[kindNum]
+}
+
+func MapStockNotifications(notifications []catalog.StockNotification) []*StockNotification {
+	result := []*StockNotification{}
+	for _, n := range notifications {
+		result = append(result, &StockNotification{
+			ID:        n.ID,
+			ProductID: n.ProductID,
+			Kind:      MapIntToStockNotificationKind(n.Kind),
+			Quantity:  int(n.Quantity),
+			Threshold: int(n.Threshold),
+			Read:      n.ReadAt != nil,
+			CreatedAt: n.CreatedAt,
+		})
+	}
+	return result
+}
+
+func MapReviews(reviews []review.Review) []*Review {
+	result := []*Review{}
+	for _, rv := range reviews {
+		result = append(result, &Review{
+			ID:        rv.ID,
+			ProductID: rv.ProductID,
+			AccountID: rv.AccountID,
+			Rating:    int(rv.Rating),
+			Title:     rv.Title,
+			Comment:   rv.Comment,
+			CreatedAt: rv.CreatedAt,
+			UpdatedAt: rv.UpdatedAt,
+		})
+	}
+	return result
+}
+
+// MapWishlistItem resolves a saved product against its current catalog
+// entry.
+func MapWishlistItem(savedPrice float64, savedAt time.Time, p catalog.Product) *WishlistItem {
+	return &WishlistItem{
+		Product:      MapProduct(p),
+		SavedPrice:   savedPrice,
+		CurrentPrice: p.Price,
+		InStock:      p.Quantity > 0,
+		PriceDropped: p.Price < savedPrice,
+		SavedAt:      savedAt,
+	}
+}
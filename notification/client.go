@@ -0,0 +1,110 @@
+package notification
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/231031/ecom-mcs-grpc/notification/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type Client struct {
+	conn    *grpc.ClientConn
+	service pb.NotificationServiceClient
+}
+
+func NewClient(url string, opts ...grpc.DialOption) (*Client, error) {
+	defaultOpts := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}
+	finalOpts := append(defaultOpts, opts...)
+
+	conn, err := grpc.NewClient(url, finalOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	service := pb.NewNotificationServiceClient(conn)
+	return &Client{
+		conn:    conn,
+		service: service,
+	}, nil
+}
+
+func (c *Client) Close() {
+	c.conn.Close()
+}
+
+// Notify sends the event to the account, email can be left empty once the
+// service has seen the account's address. A nil delivery means the account
+// turned the event off.
+func (c *Client) Notify(ctx context.Context, event int32, accountID, email string, data map[string]string) (*Delivery, error) {
+	r, err := c.service.Notify(ctx, &pb.NotifyRequest{
+		Event:     event,
+		AccountId: accountID,
+		Email:     email,
+		Data:      data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if r.Delivery == nil {
+		return nil, nil
+	}
+
+	d := deliveryFromProto(r.Delivery)
+	return &d, nil
+}
+
+func (c *Client) GetDeliveries(ctx context.Context, accountID string, skip uint64, take uint64) ([]Delivery, error) {
+	r, err := c.service.GetDeliveries(ctx, &pb.GetDeliveriesRequest{
+		AccountId: accountID,
+		Skip:      skip,
+		Take:      take,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := []Delivery{}
+	for _, d := range r.Deliveries {
+		deliveries = append(deliveries, deliveryFromProto(d))
+	}
+	return deliveries, nil
+}
+
+func (c *Client) EraseAccount(ctx context.Context, accountID string) error {
+	_, err := c.service.EraseAccount(ctx, &pb.EraseAccountRequest{AccountId: accountID})
+	return err
+}
+
+func deliveryFromProto(d *pb.Delivery) Delivery {
+	delivery := Delivery{
+		ID:        d.Id,
+		AccountID: d.AccountId,
+		Event:     d.Event,
+		Recipient: d.Recipient,
+		Subject:   d.Subject,
+		Status:    d.Status,
+		Attempts:  d.Attempts,
+		LastError: d.LastError,
+	}
+
+	if err := delivery.NextAttemptAt.UnmarshalBinary(d.NextAttemptAt); err != nil {
+		log.Println("error unmarshal timestamp", err)
+	}
+	if err := delivery.CreatedAt.UnmarshalBinary(d.CreatedAt); err != nil {
+		log.Println("error unmarshal timestamp", err)
+	}
+	if len(d.SentAt) > 0 {
+		sentAt := time.Time{}
+		if err := sentAt.UnmarshalBinary(d.SentAt); err != nil {
+			log.Println("error unmarshal timestamp", err)
+		}
+		delivery.SentAt = &sentAt
+	}
+
+	return delivery
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/231031/ecom-mcs-grpc/notification"
+	"github.com/231031/ecom-mcs-grpc/pkg/auth"
+	"github.com/kelseyhightower/envconfig"
+	"github.com/tinrab/retry"
+)
+
+type Config struct {
+	DatabaseURL  string `envconfig:"DATABASE_URL"`
+	AccountURL   string `envconfig:"ACCOUNT_SERVICE_URL"`
+	Transport    string `envconfig:"NOTIFIER_TRANSPORT" default:"log"`
+	LogPath      string `envconfig:"NOTIFIER_LOG_PATH"`
+	SMTPHost     string `envconfig:"SMTP_HOST"`
+	SMTPPort     int    `envconfig:"SMTP_PORT" default:"1025"`
+	SMTPUsername string `envconfig:"SMTP_USERNAME"`
+	SMTPPassword string `envconfig:"SMTP_PASSWORD"`
+	SMTPFrom     string `envconfig:"SMTP_FROM" default:"no-reply@ecom.local"`
+
+	PublicKeyPath      string `envconfig:"FILE_PUB_PATH"`
+	JWKSURL            string `envconfig:"JWKS_URL"`
+	InternalAuthSecret string `envconfig:"INTERNAL_AUTH_SECRET"`
+}
+
+func main() {
+	var cfg Config
+	err := envconfig.Process("", &cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var r notification.Repository
+	retry.ForeverSleep(2*time.Second, func(_ int) (err error) {
+		r, err = notification.NewPostgresRepository(cfg.DatabaseURL)
+		if err != nil {
+			log.Println(err)
+		}
+		return
+	})
+	defer r.Close()
+
+	var notifier notification.Notifier
+	switch cfg.Transport {
+	case "smtp":
+		notifier = notification.NewSMTPNotifier(notification.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+		})
+	case "log":
+		var w io.Writer = os.Stdout
+		if cfg.LogPath != "" {
+			f, err := os.OpenFile(cfg.LogPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer f.Close()
+			w = f
+		}
+		notifier = notification.NewLogNotifier(w)
+	default:
+		log.Fatalf("unknown notifier transport %q", cfg.Transport)
+	}
+
+	templates, err := notification.NewTemplates()
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Println("Listening on port")
+
+	keys, err := auth.NewKeySource(cfg.JWKSURL, cfg.PublicKeyPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	authn, err := auth.NewAuthenticator("notification", keys, cfg.InternalAuthSecret)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	s := notification.NewService(r, notifier, templates)
+	go notification.RunRetryWorker(context.Background(), s, 30*time.Second)
+	log.Fatal(notification.ListenGRPC(s, authn, cfg.AccountURL, 50005))
+}
@@ -0,0 +1,133 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/smtp"
+	"net/textproto"
+	"sync"
+	"time"
+)
+
+// Message is a rendered notification ready to hand to a transport.
+type Message struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+}
+
+// Notifier delivers a message, implementations are the transports the
+// service can be configured with.
+type Notifier interface {
+	Send(ctx context.Context, m Message) error
+}
+
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+type smtpNotifier struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewSMTPNotifier sends mail through an SMTP relay, a local mail catcher
+// without authentication works as well when no username is set.
+func NewSMTPNotifier(cfg SMTPConfig) Notifier {
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+
+	return &smtpNotifier{
+		addr: fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		from: cfg.From,
+		auth: auth,
+	}
+}
+
+func (n *smtpNotifier) Send(ctx context.Context, m Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=utf-8", m.Text},
+		{"text/html; charset=utf-8", m.HTML},
+	}
+	for _, part := range parts {
+		pw, err := w.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return err
+		}
+
+		qw := quotedprintable.NewWriter(pw)
+		if _, err := qw.Write([]byte(part.content)); err != nil {
+			return err
+		}
+		if err := qw.Close(); err != nil {
+			return err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", n.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", m.To)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", w.Boundary())
+	msg.Write(body.Bytes())
+
+	return smtp.SendMail(n.addr, n.auth, n.from, []string{m.To}, msg.Bytes())
+}
+
+type logNotifier struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewLogNotifier writes every message as a JSON line to w instead of sending
+// it, for development and for environments without a mail relay.
+func NewLogNotifier(w io.Writer) Notifier {
+	return &logNotifier{w: w}
+}
+
+func (n *logNotifier) Send(ctx context.Context, m Message) error {
+	line, err := json.Marshal(struct {
+		Message
+		SentAt time.Time `json:"sent_at"`
+	}{m, time.Now().UTC()})
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	_, err = n.w.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,23 @@
+package pb
+
+import "github.com/231031/ecom-mcs-grpc/pkg/validate"
+
+// Validation rules of the notification requests, enforced by the service
+// through the validate interceptor.
+
+// maxEvent is the last event constant of the notification package.
+const maxEvent = 4
+
+func (m *NotifyRequest) Validate() error {
+	return validate.Check(
+		validate.Between("event", m.GetEvent(), 0, maxEvent),
+		validate.Required("account_id", m.GetAccountId()),
+		validate.Email("email", m.GetEmail()),
+	)
+}
+
+func (m *GetDeliveriesRequest) Validate() error {
+	return validate.Check(
+		validate.Required("account_id", m.GetAccountId()),
+	)
+}
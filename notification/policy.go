@@ -0,0 +1,17 @@
+package notification
+
+import (
+	"github.com/231031/ecom-mcs-grpc/notification/pb"
+	"github.com/231031/ecom-mcs-grpc/pkg/auth"
+)
+
+// policies says who may call each method of the service.
+var policies = auth.Policies{
+	pb.NotificationService_Notify_FullMethodName: auth.Internal(),
+	pb.NotificationService_GetDeliveries_FullMethodName: auth.OwnedBy(func(r *pb.GetDeliveriesRequest) string {
+		return r.GetAccountId()
+	}, auth.RoleBuyer, auth.RoleSeller),
+
+	// the authentication service erases the account once its deletion is due
+	pb.NotificationService_EraseAccount_FullMethodName: auth.Internal(),
+}
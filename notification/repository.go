@@ -0,0 +1,197 @@
+package notification
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	_ "github.com/lib/pq"
+)
+
+type Repository interface {
+	Close()
+	PutRecipient(ctx context.Context, accountID, email string) error
+	GetRecipient(ctx context.Context, accountID string) (string, error)
+	DeleteAccount(ctx context.Context, accountID string) error
+
+	PutDelivery(ctx context.Context, d Delivery) error
+	UpdateDelivery(ctx context.Context, d Delivery) error
+	ClaimDueDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]Delivery, error)
+	GetDeliveries(ctx context.Context, accountID string, skip uint64, take uint64) ([]Delivery, error)
+}
+
+type postgresRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresRepository(url string) (Repository, error) {
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Ping()
+	if err != nil {
+		return nil, err
+	}
+	return &postgresRepository{db}, nil
+}
+
+func (r *postgresRepository) Close() {
+	r.db.Close()
+}
+
+func (r *postgresRepository) PutRecipient(ctx context.Context, accountID, email string) error {
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO recipients(account_id, email, updated_at)
+		VALUES($1, $2, NOW())
+		ON CONFLICT (account_id) DO UPDATE SET email = EXCLUDED.email, updated_at = EXCLUDED.updated_at`,
+		accountID, email,
+	)
+	return err
+}
+
+func (r *postgresRepository) GetRecipient(ctx context.Context, accountID string) (string, error) {
+	var email string
+	err := r.db.QueryRowContext(
+		ctx,
+		"SELECT email FROM recipients WHERE account_id = $1",
+		accountID,
+	).Scan(&email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrRecipientNotFound
+		}
+		return "", err
+	}
+	return email, nil
+}
+
+// DeleteAccount deletes the recipient and the mails sent to the account,
+// pending ones included.
+func (r *postgresRepository) DeleteAccount(ctx context.Context, accountID string) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	if _, err = tx.ExecContext(ctx, "DELETE FROM deliveries WHERE account_id = $1", accountID); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, "DELETE FROM recipients WHERE account_id = $1", accountID)
+	return err
+}
+
+func (r *postgresRepository) PutDelivery(ctx context.Context, d Delivery) error {
+	_, err := r.db.ExecContext(
+		ctx,
+		`INSERT INTO deliveries(id, account_id, event, recipient, subject, text_body, html_body, status, attempts, last_error, next_attempt_at, created_at)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		d.ID, d.AccountID, d.Event, d.Recipient, d.Subject, d.Text, d.HTML, d.Status, d.Attempts, d.LastError, d.NextAttemptAt, d.CreatedAt,
+	)
+	return err
+}
+
+func (r *postgresRepository) UpdateDelivery(ctx context.Context, d Delivery) error {
+	_, err := r.db.ExecContext(
+		ctx,
+		`UPDATE deliveries
+		SET status = $2, attempts = $3, last_error = $4, next_attempt_at = $5, sent_at = $6
+		WHERE id = $1`,
+		d.ID, d.Status, d.Attempts, d.LastError, d.NextAttemptAt, d.SentAt,
+	)
+	return err
+}
+
+// ClaimDueDeliveries takes the pending deliveries due at now and pushes their
+// next attempt to leaseUntil, so concurrent workers never pick the same one.
+func (r *postgresRepository) ClaimDueDeliveries(ctx context.Context, now, leaseUntil time.Time, limit int) ([]Delivery, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`UPDATE deliveries SET next_attempt_at = $3
+		WHERE id IN (
+			SELECT id FROM deliveries
+			WHERE status = $1 AND next_attempt_at <= $2
+			ORDER BY next_attempt_at
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+deliveryColumns,
+		DeliveryPending, now, leaseUntil, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanDeliveries(rows)
+}
+
+func (r *postgresRepository) GetDeliveries(ctx context.Context, accountID string, skip uint64, take uint64) ([]Delivery, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT `+deliveryColumns+`
+		FROM deliveries
+		WHERE account_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3`,
+		accountID, take, skip,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanDeliveries(rows)
+}
+
+const deliveryColumns = `id,
+		account_id,
+		event,
+		recipient,
+		subject,
+		text_body,
+		html_body,
+		status,
+		attempts,
+		last_error,
+		next_attempt_at,
+		created_at,
+		sent_at`
+
+func scanDeliveries(rows *sql.Rows) ([]Delivery, error) {
+	deliveries := []Delivery{}
+	for rows.Next() {
+		d := Delivery{}
+		if err := rows.Scan(
+			&d.ID,
+			&d.AccountID,
+			&d.Event,
+			&d.Recipient,
+			&d.Subject,
+			&d.Text,
+			&d.HTML,
+			&d.Status,
+			&d.Attempts,
+			&d.LastError,
+			&d.NextAttemptAt,
+			&d.CreatedAt,
+			&d.SentAt,
+		); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, rows.Err()
+}
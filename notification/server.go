@@ -0,0 +1,151 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+
+	"github.com/231031/ecom-mcs-grpc/account"
+	"github.com/231031/ecom-mcs-grpc/notification/pb"
+	"github.com/231031/ecom-mcs-grpc/pkg/auth"
+	"github.com/231031/ecom-mcs-grpc/pkg/grpcerr"
+	"github.com/231031/ecom-mcs-grpc/pkg/validate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+var errorMappings = []grpcerr.Mapping{
+	grpcerr.Validation(ErrUnknownEvent, "event"),
+	grpcerr.NotFound(ErrRecipientNotFound).OnField("email"),
+}
+
+type grpcServer struct {
+	service       Service
+	accountClient *account.Client
+	pb.UnimplementedNotificationServiceServer
+}
+
+func ListenGRPC(s Service, authn *auth.Authenticator, accountURL string, port int) error {
+	accountClient, err := account.NewClient(accountURL, authn.DialOption())
+	if err != nil {
+		return err
+	}
+
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		accountClient.Close()
+		return err
+	}
+
+	serv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			grpcerr.UnaryServerInterceptor(errorMappings...),
+			authn.UnaryServerInterceptor(policies),
+			validate.UnaryServerInterceptor(),
+		),
+	)
+	pb.RegisterNotificationServiceServer(
+		serv,
+		&grpcServer{
+			service:       s,
+			accountClient: accountClient,
+		},
+	)
+	reflection.Register(serv)
+	return serv.Serve(lis)
+}
+
+func (s *grpcServer) Notify(ctx context.Context, r *pb.NotifyRequest) (*pb.NotifyResponse, error) {
+	// the security mails go out before the account exists and cannot be
+	// turned off
+	if r.Event != EventEmailVerification && r.Event != EventPasswordReset {
+		// the recipient is rarely the user the event happened for
+		preferences, err := s.accountClient.GetNotificationPreferences(auth.WithoutUser(ctx), r.AccountId)
+		if err != nil {
+			log.Println("error getting notification preferences", err)
+			return nil, err
+		}
+		if !wantsEvent(preferences, r.Event) {
+			return &pb.NotifyResponse{}, nil
+		}
+	}
+
+	d, err := s.service.Notify(ctx, r.Event, r.AccountId, r.Email, r.Data)
+	if err != nil {
+		log.Println("error notifying", err)
+		return nil, err
+	}
+
+	return &pb.NotifyResponse{
+		Delivery: deliveryToProto(*d),
+	}, nil
+}
+
+func (s *grpcServer) GetDeliveries(ctx context.Context, r *pb.GetDeliveriesRequest) (*pb.GetDeliveriesResponse, error) {
+	deliveries, err := s.service.GetDeliveries(ctx, r.AccountId, r.Skip, r.Take)
+	if err != nil {
+		log.Println("error getting deliveries", err)
+		return nil, err
+	}
+
+	deliveriesProto := []*pb.Delivery{}
+	for _, d := range deliveries {
+		deliveriesProto = append(deliveriesProto, deliveryToProto(d))
+	}
+	return &pb.GetDeliveriesResponse{
+		Deliveries: deliveriesProto,
+	}, nil
+}
+
+func (s *grpcServer) EraseAccount(ctx context.Context, r *pb.EraseAccountRequest) (*pb.EraseAccountResponse, error) {
+	if err := s.service.EraseAccount(ctx, r.AccountId); err != nil {
+		log.Println("error erasing deliveries of account", err)
+		return nil, err
+	}
+
+	return &pb.EraseAccountResponse{}, nil
+}
+
+func wantsEvent(p *account.NotificationPreference, event int32) bool {
+	switch event {
+	case EventAccountCreated:
+		return p.AccountCreated
+	case EventOrderPlaced:
+		return p.OrderPlaced
+	case EventOrderShipped:
+		return p.OrderShipped
+	}
+	return true
+}
+
+func deliveryToProto(d Delivery) *pb.Delivery {
+	deliveryProto := &pb.Delivery{
+		Id:        d.ID,
+		AccountId: d.AccountID,
+		Event:     d.Event,
+		Recipient: d.Recipient,
+		Subject:   d.Subject,
+		Status:    d.Status,
+		Attempts:  d.Attempts,
+		LastError: d.LastError,
+	}
+
+	var err error
+	deliveryProto.NextAttemptAt, err = d.NextAttemptAt.MarshalBinary()
+	if err != nil {
+		log.Println("error marshal timestamp", err)
+	}
+	deliveryProto.CreatedAt, err = d.CreatedAt.MarshalBinary()
+	if err != nil {
+		log.Println("error marshal timestamp", err)
+	}
+	if d.SentAt != nil {
+		deliveryProto.SentAt, err = d.SentAt.MarshalBinary()
+		if err != nil {
+			log.Println("error marshal timestamp", err)
+		}
+	}
+
+	return deliveryProto
+}
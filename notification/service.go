@@ -0,0 +1,200 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/segmentio/ksuid"
+)
+
+const (
+	EventAccountCreated = 0
+	EventOrderPlaced    = 1
+	EventOrderShipped   = 2
+	// the security mails, sent whatever the preferences of the account
+	EventEmailVerification = 3
+	EventPasswordReset     = 4
+)
+
+const (
+	DeliveryPending = 0
+	DeliverySent    = 1
+	DeliveryFailed  = 2
+)
+
+const (
+	// MaxDeliveryAttempts is how often a delivery is tried before it is
+	// marked failed and left in the log.
+	MaxDeliveryAttempts = 5
+
+	retryBaseDelay = time.Minute
+	// deliveryLease keeps a delivery away from other workers while one of
+	// them is sending it.
+	deliveryLease = 2 * time.Minute
+)
+
+var (
+	ErrRecipientNotFound = errors.New("no email address known for the account")
+)
+
+// Delivery is one notification sent, or still to be sent, to one recipient.
+type Delivery struct {
+	ID            string     `json:"id"`
+	AccountID     string     `json:"account_id"`
+	Event         int32      `json:"event"`
+	Recipient     string     `json:"recipient"`
+	Subject       string     `json:"subject"`
+	Text          string     `json:"text"`
+	HTML          string     `json:"html"`
+	Status        int32      `json:"status"`
+	Attempts      uint32     `json:"attempts"`
+	LastError     string     `json:"last_error"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	SentAt        *time.Time `json:"sent_at"`
+}
+
+type Service interface {
+	Notify(ctx context.Context, event int32, accountID, email string, data map[string]string) (*Delivery, error)
+	RetryDeliveries(ctx context.Context) (int, error)
+	GetDeliveries(ctx context.Context, accountID string, skip uint64, take uint64) ([]Delivery, error)
+	EraseAccount(ctx context.Context, accountID string) error
+}
+
+type notificationService struct {
+	repository Repository
+	notifier   Notifier
+	templates  *Templates
+}
+
+func NewService(r Repository, n Notifier, t *Templates) Service {
+	return &notificationService{repository: r, notifier: n, templates: t}
+}
+
+// Notify renders the event for the account and sends it. The address is
+// remembered when given, so later events only need the account id.
+func (s *notificationService) Notify(ctx context.Context, event int32, accountID, email string, data map[string]string) (*Delivery, error) {
+	var err error
+	if email != "" {
+		err = s.repository.PutRecipient(ctx, accountID, email)
+	} else {
+		email, err = s.repository.GetRecipient(ctx, accountID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := s.templates.Render(event, TemplateData{
+		AccountID: accountID,
+		Email:     email,
+		Data:      data,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	d := &Delivery{
+		ID:            ksuid.New().String(),
+		AccountID:     accountID,
+		Event:         event,
+		Recipient:     email,
+		Subject:       msg.Subject,
+		Text:          msg.Text,
+		HTML:          msg.HTML,
+		Status:        DeliveryPending,
+		NextAttemptAt: now.Add(deliveryLease),
+		CreatedAt:     now,
+	}
+	if err := s.repository.PutDelivery(ctx, *d); err != nil {
+		return nil, err
+	}
+
+	s.attempt(ctx, d)
+	return d, nil
+}
+
+// RetryDeliveries sends the pending deliveries that are due again and
+// returns how many went out.
+func (s *notificationService) RetryDeliveries(ctx context.Context) (int, error) {
+	now := time.Now().UTC()
+	deliveries, err := s.repository.ClaimDueDeliveries(ctx, now, now.Add(deliveryLease), 100)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i := range deliveries {
+		s.attempt(ctx, &deliveries[i])
+		if deliveries[i].Status == DeliverySent {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+func (s *notificationService) GetDeliveries(ctx context.Context, accountID string, skip uint64, take uint64) ([]Delivery, error) {
+	if take > 100 || (skip == 0 && take == 0) {
+		take = 100
+	}
+	return s.repository.GetDeliveries(ctx, accountID, skip, take)
+}
+
+func (s *notificationService) EraseAccount(ctx context.Context, accountID string) error {
+	return s.repository.DeleteAccount(ctx, accountID)
+}
+
+// attempt sends d once and records the outcome, a failure is retried with an
+// exponential backoff until MaxDeliveryAttempts is reached.
+func (s *notificationService) attempt(ctx context.Context, d *Delivery) {
+	err := s.notifier.Send(ctx, Message{
+		To:      d.Recipient,
+		Subject: d.Subject,
+		Text:    d.Text,
+		HTML:    d.HTML,
+	})
+
+	now := time.Now().UTC()
+	d.Attempts++
+	if err == nil {
+		d.Status = DeliverySent
+		d.LastError = ""
+		d.SentAt = &now
+	} else {
+		log.Println("error sending notification", d.ID, err)
+		d.LastError = err.Error()
+		if d.Attempts >= MaxDeliveryAttempts {
+			d.Status = DeliveryFailed
+		} else {
+			d.NextAttemptAt = now.Add(retryBaseDelay << (d.Attempts - 1))
+		}
+	}
+
+	if err := s.repository.UpdateDelivery(ctx, *d); err != nil {
+		log.Println("error updating delivery", d.ID, err)
+	}
+}
+
+// RunRetryWorker retries due deliveries every interval until the context is
+// done.
+func RunRetryWorker(ctx context.Context, s Service, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sent, err := s.RetryDeliveries(ctx)
+			if err != nil {
+				log.Println("error retrying deliveries", err)
+			}
+			if sent > 0 {
+				log.Println("retried deliveries sent", sent)
+			}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package notification
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	htmltemplate "html/template"
+	"text/template"
+)
+
+var (
+	ErrUnknownEvent = errors.New("unknown notification event")
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// eventTemplates names the template files of every event, each has a .txt
+// file defining "subject" and "body" and a .html file defining "body".
+var eventTemplates = map[int32]string{
+	EventAccountCreated: "account_created",
+	EventOrderPlaced:    "order_placed",
+	EventOrderShipped:   "order_shipped",
+
+	EventEmailVerification: "verify_email",
+	EventPasswordReset:     "password_reset",
+}
+
+// TemplateData is what the templates of an event are executed with.
+type TemplateData struct {
+	AccountID string
+	Email     string
+	Data      map[string]string
+}
+
+type eventTemplate struct {
+	text *template.Template
+	html *htmltemplate.Template
+}
+
+type Templates struct {
+	events map[int32]eventTemplate
+}
+
+func NewTemplates() (*Templates, error) {
+	t := &Templates{events: map[int32]eventTemplate{}}
+	for event, name := range eventTemplates {
+		// a value the caller did not send renders empty instead of "<no value>"
+		text, err := template.New(name+".txt").Option("missingkey=zero").ParseFS(templateFS, "templates/"+name+".txt")
+		if err != nil {
+			return nil, err
+		}
+		html, err := htmltemplate.New(name+".html").Option("missingkey=zero").ParseFS(templateFS, "templates/"+name+".html")
+		if err != nil {
+			return nil, err
+		}
+		t.events[event] = eventTemplate{text: text, html: html}
+	}
+	return t, nil
+}
+
+func (t *Templates) Render(event int32, data TemplateData) (*Message, error) {
+	et, ok := t.events[event]
+	if !ok {
+		return nil, ErrUnknownEvent
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := et.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, err
+	}
+	if err := et.text.ExecuteTemplate(&text, "body", data); err != nil {
+		return nil, err
+	}
+	if err := et.html.ExecuteTemplate(&html, "body", data); err != nil {
+		return nil, err
+	}
+
+	return &Message{
+		To:      data.Email,
+		Subject: subject.String(),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS recipients (
+    account_id VARCHAR(27) PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS deliveries (
+    id VARCHAR(27) PRIMARY KEY,
+    account_id VARCHAR(27) NOT NULL,
+    event INT NOT NULL,
+    recipient VARCHAR(255) NOT NULL,
+    subject TEXT NOT NULL,
+    text_body TEXT NOT NULL,
+    html_body TEXT NOT NULL,
+    status INT NOT NULL DEFAULT 0,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    sent_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS deliveries_account_id_idx ON deliveries (account_id, created_at);
+CREATE INDEX IF NOT EXISTS deliveries_pending_idx ON deliveries (next_attempt_at) WHERE status = 0;
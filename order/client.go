@@ -5,6 +5,7 @@ import (
 	"log"
 	"time"
 
+	"github.com/231031/ecom-mcs-grpc/account"
 	"github.com/231031/ecom-mcs-grpc/order/pb"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -54,11 +55,27 @@ func (c *Client) PostOrder(ctx context.Context, accountID string, products []Ord
 		return nil, err
 	}
 
+	orderedProducts := []OrderedProduct{}
+	for _, p := range orderProto.Order.Products {
+		orderedProducts = append(orderedProducts, OrderedProduct{
+			ID:          p.Id,
+			Name:        p.Name,
+			Description: p.Description,
+			Price:       p.Price,
+			Quantity:    p.Quantity,
+			SellerID:    p.SellerId,
+		})
+	}
+
 	order := &Order{
-		ID:         orderProto.Order.Id,
-		TotalPrice: orderProto.Order.TotalPrice,
-		AccountID:  orderProto.Order.AccountId,
-		Products:   products,
+		ID:             orderProto.Order.Id,
+		TotalPrice:     orderProto.Order.TotalPrice,
+		AccountID:      orderProto.Order.AccountId,
+		Status:         orderProto.Order.Status,
+		PaymentStatus:  orderProto.Order.PaymentStatus,
+		RefundedAmount: orderProto.Order.RefundedAmount,
+		Products:       orderedProducts,
+		Fulfillments:   fulfillmentsFromProto(orderProto.Order.Fulfillments),
 	}
 
 	createdAt := time.Time{}
@@ -83,33 +100,295 @@ func (c *Client) GetOrdersForAccount(ctx context.Context, accountID string) ([]O
 
 	orders := []Order{}
 	for _, op := range ordersProto.Orders {
-		createdAt := time.Time{}
-		err = createdAt.UnmarshalBinary(op.CreatedAt)
-		if err != nil {
-			log.Println("error unmarshalling timestamp", err)
-		}
+		orders = append(orders, orderFromProto(op))
+	}
 
-		order := Order{
-			ID:         op.Id,
-			AccountID:  op.AccountId,
-			TotalPrice: op.TotalPrice,
-			CreatedAt:  createdAt,
-		}
+	return orders, nil
+}
 
-		products := []OrderedProduct{}
-		for _, p := range op.Products {
-			products = append(products, OrderedProduct{
-				ID:          p.Id,
-				Name:        p.Name,
-				Description: p.Description,
-				Price:       p.Price,
-				Quantity:    p.Quantity,
-			})
-		}
+func (c *Client) GetOrder(ctx context.Context, id string) (*Order, error) {
+	r, err := c.service.GetOrder(ctx, &pb.GetOrderRequest{Id: id})
+	if err != nil {
+		log.Println("error getting order", err)
+		return nil, err
+	}
 
-		order.Products = products
-		orders = append(orders, order)
+	order := orderFromProto(r.Order)
+	return &order, nil
+}
+
+func orderFromProto(op *pb.Order) Order {
+	createdAt := time.Time{}
+	err := createdAt.UnmarshalBinary(op.CreatedAt)
+	if err != nil {
+		log.Println("error unmarshalling timestamp", err)
 	}
 
-	return orders, nil
+	order := Order{
+		ID:             op.Id,
+		AccountID:      op.AccountId,
+		TotalPrice:     op.TotalPrice,
+		Status:         op.Status,
+		PaymentStatus:  op.PaymentStatus,
+		RefundedAmount: op.RefundedAmount,
+		CreatedAt:      createdAt,
+		Fulfillments:   fulfillmentsFromProto(op.Fulfillments),
+	}
+
+	products := []OrderedProduct{}
+	for _, p := range op.Products {
+		products = append(products, OrderedProduct{
+			ID:          p.Id,
+			Name:        p.Name,
+			Description: p.Description,
+			Price:       p.Price,
+			Quantity:    p.Quantity,
+			SellerID:    p.SellerId,
+		})
+	}
+
+	order.Products = products
+	return order
+}
+
+func (c *Client) GetFulfillmentsForSeller(ctx context.Context, sellerID string, skip uint64, take uint64) ([]Fulfillment, error) {
+	r, err := c.service.GetFulfillmentsForSeller(ctx, &pb.GetFulfillmentsForSellerRequest{
+		SellerId: sellerID,
+		Skip:     skip,
+		Take:     take,
+	})
+	if err != nil {
+		log.Println("error getting fulfillments", err)
+		return nil, err
+	}
+
+	return fulfillmentsFromProto(r.Fulfillments), nil
+}
+
+func (c *Client) UpdateFulfillment(ctx context.Context, id, sellerID string, status int32, carrier, trackingNumber string) (*Fulfillment, error) {
+	r, err := c.service.UpdateFulfillment(ctx, &pb.UpdateFulfillmentRequest{
+		Id:             id,
+		SellerId:       sellerID,
+		Status:         status,
+		Carrier:        carrier,
+		TrackingNumber: trackingNumber,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	f := fulfillmentFromProto(r.Fulfillment)
+	return &f, nil
+}
+
+func (c *Client) RequestReturn(ctx context.Context, accountID, orderID, productID string, quantity uint32, reason int32, note string) (*Return, error) {
+	r, err := c.service.RequestReturn(ctx, &pb.RequestReturnRequest{
+		AccountId: accountID,
+		OrderId:   orderID,
+		ProductId: productID,
+		Quantity:  quantity,
+		Reason:    reason,
+		Note:      note,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ret := returnFromProto(r.OrderReturn)
+	return &ret, nil
+}
+
+func (c *Client) ReviewReturn(ctx context.Context, id, sellerID string, approve bool, note string) (*Return, error) {
+	r, err := c.service.ReviewReturn(ctx, &pb.ReviewReturnRequest{
+		Id:       id,
+		SellerId: sellerID,
+		Approve:  approve,
+		Note:     note,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ret := returnFromProto(r.OrderReturn)
+	return &ret, nil
+}
+
+func (c *Client) ReceiveReturn(ctx context.Context, id, sellerID string, note string) (*Return, error) {
+	r, err := c.service.ReceiveReturn(ctx, &pb.ReceiveReturnRequest{
+		Id:       id,
+		SellerId: sellerID,
+		Note:     note,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ret := returnFromProto(r.OrderReturn)
+	return &ret, nil
+}
+
+func (c *Client) RefundReturn(ctx context.Context, id, sellerID string, amount float64, note string) (*Return, error) {
+	r, err := c.service.RefundReturn(ctx, &pb.RefundReturnRequest{
+		Id:       id,
+		SellerId: sellerID,
+		Amount:   amount,
+		Note:     note,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ret := returnFromProto(r.OrderReturn)
+	return &ret, nil
+}
+
+// HasDeliveredProduct reports whether the account received the product, with
+// the order it came in.
+func (c *Client) GetBuyerForSeller(ctx context.Context, sellerID, accountID string) (*account.Buyer, error) {
+	r, err := c.service.GetBuyerForSeller(ctx, &pb.GetBuyerForSellerRequest{
+		SellerId:  sellerID,
+		AccountId: accountID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &account.Buyer{
+		ID: r.AccountId,
+		BaseInfo: account.BaseInfo{
+			FirstName: r.FirstName,
+			LastName:  r.LastName,
+			Phone:     r.Phone,
+			Address:   r.Address,
+		},
+	}, nil
+}
+
+func (c *Client) EraseAccount(ctx context.Context, accountID string) error {
+	_, err := c.service.EraseAccount(ctx, &pb.EraseAccountRequest{AccountId: accountID})
+	return err
+}
+
+func (c *Client) HasDeliveredProduct(ctx context.Context, accountID, productID string) (bool, string, error) {
+	r, err := c.service.HasDeliveredProduct(ctx, &pb.HasDeliveredProductRequest{
+		AccountId: accountID,
+		ProductId: productID,
+	})
+	if err != nil {
+		return false, "", err
+	}
+
+	return r.Delivered, r.OrderId, nil
+}
+
+func (c *Client) GetReturnsForAccount(ctx context.Context, accountID string) ([]Return, error) {
+	r, err := c.service.GetReturnsForAccount(ctx, &pb.GetReturnsForAccountRequest{
+		AccountId: accountID,
+	})
+	if err != nil {
+		log.Println("error getting returns", err)
+		return nil, err
+	}
+
+	return returnsFromProto(r.Returns), nil
+}
+
+func (c *Client) GetReturnsForSeller(ctx context.Context, sellerID string, skip uint64, take uint64) ([]Return, error) {
+	r, err := c.service.GetReturnsForSeller(ctx, &pb.GetReturnsForSellerRequest{
+		SellerId: sellerID,
+		Skip:     skip,
+		Take:     take,
+	})
+	if err != nil {
+		log.Println("error getting returns", err)
+		return nil, err
+	}
+
+	return returnsFromProto(r.Returns), nil
+}
+
+func fulfillmentFromProto(fp *pb.Fulfillment) Fulfillment {
+	f := Fulfillment{
+		ID:             fp.Id,
+		OrderID:        fp.OrderId,
+		SellerID:       fp.SellerId,
+		Status:         fp.Status,
+		Carrier:        fp.Carrier,
+		TrackingNumber: fp.TrackingNumber,
+		PayoutAmount:   fp.PayoutAmount,
+		Products:       []OrderedProduct{},
+	}
+
+	if err := f.CreatedAt.UnmarshalBinary(fp.CreatedAt); err != nil {
+		log.Println("error unmarshalling timestamp", err)
+	}
+	if err := f.UpdatedAt.UnmarshalBinary(fp.UpdatedAt); err != nil {
+		log.Println("error unmarshalling timestamp", err)
+	}
+
+	for _, p := range fp.Products {
+		f.Products = append(f.Products, OrderedProduct{
+			ID:          p.Id,
+			Name:        p.Name,
+			Description: p.Description,
+			Price:       p.Price,
+			Quantity:    p.Quantity,
+			SellerID:    p.SellerId,
+		})
+	}
+
+	return f
+}
+
+func fulfillmentsFromProto(fulfillmentsProto []*pb.Fulfillment) []Fulfillment {
+	fulfillments := []Fulfillment{}
+	for _, fp := range fulfillmentsProto {
+		fulfillments = append(fulfillments, fulfillmentFromProto(fp))
+	}
+	return fulfillments
+}
+
+func returnFromProto(rp *pb.OrderReturn) Return {
+	ret := Return{
+		ID:            rp.Id,
+		OrderID:       rp.OrderId,
+		FulfillmentID: rp.FulfillmentId,
+		AccountID:     rp.AccountId,
+		SellerID:      rp.SellerId,
+		ProductID:     rp.ProductId,
+		Quantity:      rp.Quantity,
+		Reason:        rp.Reason,
+		Note:          rp.Note,
+		Status:        rp.Status,
+		RefundAmount:  rp.RefundAmount,
+		Timeline:      []ReturnEvent{},
+	}
+
+	if err := ret.CreatedAt.UnmarshalBinary(rp.CreatedAt); err != nil {
+		log.Println("error unmarshalling timestamp", err)
+	}
+	if err := ret.UpdatedAt.UnmarshalBinary(rp.UpdatedAt); err != nil {
+		log.Println("error unmarshalling timestamp", err)
+	}
+
+	for _, e := range rp.Timeline {
+		event := ReturnEvent{
+			Status: e.Status,
+			Note:   e.Note,
+		}
+		if err := event.CreatedAt.UnmarshalBinary(e.CreatedAt); err != nil {
+			log.Println("error unmarshalling timestamp", err)
+		}
+		ret.Timeline = append(ret.Timeline, event)
+	}
+
+	return ret
+}
+
+func returnsFromProto(returnsProto []*pb.OrderReturn) []Return {
+	returns := []Return{}
+	for _, rp := range returnsProto {
+		returns = append(returns, returnFromProto(rp))
+	}
+	return returns
 }
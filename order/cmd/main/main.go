@@ -5,14 +5,22 @@ import (
 	"time"
 
 	"github.com/231031/ecom-mcs-grpc/order"
+	"github.com/231031/ecom-mcs-grpc/pkg/audit"
+	"github.com/231031/ecom-mcs-grpc/pkg/auth"
+	"github.com/231031/ecom-mcs-grpc/pkg/idempotency"
 	"github.com/kelseyhightower/envconfig"
 	"github.com/tinrab/retry"
 )
 
 type Config struct {
-	DatabaseURL string `envconfig:"DATABASE_URL"`
-	AccountURL  string `envconfig:"ACCOUNT_SERVICE_URL"`
-	CatalogURL  string `envconfig:"CATALOG_SERVICE_URL"`
+	DatabaseURL     string `envconfig:"DATABASE_URL"`
+	AccountURL      string `envconfig:"ACCOUNT_SERVICE_URL"`
+	CatalogURL      string `envconfig:"CATALOG_SERVICE_URL"`
+	NotificationURL string `envconfig:"NOTIFICATION_SERVICE_URL"`
+
+	PublicKeyPath      string `envconfig:"FILE_PUB_PATH"`
+	JWKSURL            string `envconfig:"JWKS_URL"`
+	InternalAuthSecret string `envconfig:"INTERNAL_AUTH_SECRET"`
 }
 
 func main() {
@@ -31,8 +39,29 @@ func main() {
 		return
 	})
 	defer r.Close()
+
+	idempotencyStore, err := idempotency.NewPostgresStore(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer idempotencyStore.Close()
+
+	auditStore, err := audit.NewPostgresStore(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer auditStore.Close()
 	log.Println("Listening on port")
 
+	keys, err := auth.NewKeySource(cfg.JWKSURL, cfg.PublicKeyPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	authn, err := auth.NewAuthenticator("order", keys, cfg.InternalAuthSecret)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	s := order.NewService(r)
-	log.Fatal(order.ListenGRPC(s, cfg.AccountURL, cfg.CatalogURL, 50003))
+	log.Fatal(order.ListenGRPC(s, authn, idempotencyStore, auditStore, cfg.AccountURL, cfg.CatalogURL, cfg.NotificationURL, 50003))
 }
@@ -1,9 +1,11 @@
 package order
 
 const (
-	PaymentStatusPending   = 0
-	PaymentStatusCompleted = 1
-	PaymentStatusFailed    = 2
+	PaymentStatusPending           = 0
+	PaymentStatusCompleted         = 1
+	PaymentStatusFailed            = 2
+	PaymentStatusPartiallyRefunded = 3
+	PaymentStatusRefunded          = 4
 )
 
 const (
@@ -12,3 +14,19 @@ const (
 	OrderStatusDelivered = 2
 	OrderStatusCanceled  = 3
 )
+
+const (
+	ReturnReasonDamaged        = 0
+	ReturnReasonWrongItem      = 1
+	ReturnReasonNotAsDescribed = 2
+	ReturnReasonNoLongerNeeded = 3
+	ReturnReasonOther          = 4
+)
+
+const (
+	ReturnStatusRequested = 0
+	ReturnStatusApproved  = 1
+	ReturnStatusRejected  = 2
+	ReturnStatusReceived  = 3
+	ReturnStatusRefunded  = 4
+)
@@ -1,7 +1,7 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.36.4
-// 	protoc        v5.29.2
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.33.0
 // source: order.proto
 
 package pb
@@ -22,14 +22,18 @@ const (
 )
 
 type Order struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	CreatedAt     []byte                 `protobuf:"bytes,2,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
-	AccountId     string                 `protobuf:"bytes,3,opt,name=accountId,proto3" json:"accountId,omitempty"`
-	TotalPrice    float64                `protobuf:"fixed64,4,opt,name=totalPrice,proto3" json:"totalPrice,omitempty"`
-	Products      []*Order_OrderProduct  `protobuf:"bytes,5,rep,name=products,proto3" json:"products,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	CreatedAt      []byte                 `protobuf:"bytes,2,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	AccountId      string                 `protobuf:"bytes,3,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	TotalPrice     float64                `protobuf:"fixed64,4,opt,name=totalPrice,proto3" json:"totalPrice,omitempty"`
+	Products       []*Order_OrderProduct  `protobuf:"bytes,5,rep,name=products,proto3" json:"products,omitempty"`
+	Status         int32                  `protobuf:"varint,6,opt,name=status,proto3" json:"status,omitempty"`
+	Fulfillments   []*Fulfillment         `protobuf:"bytes,7,rep,name=fulfillments,proto3" json:"fulfillments,omitempty"`
+	PaymentStatus  int32                  `protobuf:"varint,8,opt,name=paymentStatus,proto3" json:"paymentStatus,omitempty"`
+	RefundedAmount float64                `protobuf:"fixed64,9,opt,name=refundedAmount,proto3" json:"refundedAmount,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *Order) Reset() {
@@ -97,6 +101,150 @@ func (x *Order) GetProducts() []*Order_OrderProduct {
 	return nil
 }
 
+func (x *Order) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *Order) GetFulfillments() []*Fulfillment {
+	if x != nil {
+		return x.Fulfillments
+	}
+	return nil
+}
+
+func (x *Order) GetPaymentStatus() int32 {
+	if x != nil {
+		return x.PaymentStatus
+	}
+	return 0
+}
+
+func (x *Order) GetRefundedAmount() float64 {
+	if x != nil {
+		return x.RefundedAmount
+	}
+	return 0
+}
+
+type Fulfillment struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrderId        string                 `protobuf:"bytes,2,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	SellerId       string                 `protobuf:"bytes,3,opt,name=sellerId,proto3" json:"sellerId,omitempty"`
+	Status         int32                  `protobuf:"varint,4,opt,name=status,proto3" json:"status,omitempty"`
+	Carrier        string                 `protobuf:"bytes,5,opt,name=carrier,proto3" json:"carrier,omitempty"`
+	TrackingNumber string                 `protobuf:"bytes,6,opt,name=trackingNumber,proto3" json:"trackingNumber,omitempty"`
+	PayoutAmount   float64                `protobuf:"fixed64,7,opt,name=payoutAmount,proto3" json:"payoutAmount,omitempty"`
+	Products       []*Order_OrderProduct  `protobuf:"bytes,8,rep,name=products,proto3" json:"products,omitempty"`
+	CreatedAt      []byte                 `protobuf:"bytes,9,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	UpdatedAt      []byte                 `protobuf:"bytes,10,opt,name=updatedAt,proto3" json:"updatedAt,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Fulfillment) Reset() {
+	*x = Fulfillment{}
+	mi := &file_order_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Fulfillment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Fulfillment) ProtoMessage() {}
+
+func (x *Fulfillment) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Fulfillment.ProtoReflect.Descriptor instead.
+func (*Fulfillment) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Fulfillment) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Fulfillment) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *Fulfillment) GetSellerId() string {
+	if x != nil {
+		return x.SellerId
+	}
+	return ""
+}
+
+func (x *Fulfillment) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *Fulfillment) GetCarrier() string {
+	if x != nil {
+		return x.Carrier
+	}
+	return ""
+}
+
+func (x *Fulfillment) GetTrackingNumber() string {
+	if x != nil {
+		return x.TrackingNumber
+	}
+	return ""
+}
+
+func (x *Fulfillment) GetPayoutAmount() float64 {
+	if x != nil {
+		return x.PayoutAmount
+	}
+	return 0
+}
+
+func (x *Fulfillment) GetProducts() []*Order_OrderProduct {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+func (x *Fulfillment) GetCreatedAt() []byte {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Fulfillment) GetUpdatedAt() []byte {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
 type PostOrderRequest struct {
 	state         protoimpl.MessageState           `protogen:"open.v1"`
 	AccountId     string                           `protobuf:"bytes,1,opt,name=accountId,proto3" json:"accountId,omitempty"`
@@ -107,7 +255,7 @@ type PostOrderRequest struct {
 
 func (x *PostOrderRequest) Reset() {
 	*x = PostOrderRequest{}
-	mi := &file_order_proto_msgTypes[1]
+	mi := &file_order_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -119,7 +267,7 @@ func (x *PostOrderRequest) String() string {
 func (*PostOrderRequest) ProtoMessage() {}
 
 func (x *PostOrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[1]
+	mi := &file_order_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -132,7 +280,7 @@ func (x *PostOrderRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PostOrderRequest.ProtoReflect.Descriptor instead.
 func (*PostOrderRequest) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{1}
+	return file_order_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *PostOrderRequest) GetAccountId() string {
@@ -158,7 +306,7 @@ type PostOrderResponse struct {
 
 func (x *PostOrderResponse) Reset() {
 	*x = PostOrderResponse{}
-	mi := &file_order_proto_msgTypes[2]
+	mi := &file_order_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -170,7 +318,7 @@ func (x *PostOrderResponse) String() string {
 func (*PostOrderResponse) ProtoMessage() {}
 
 func (x *PostOrderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[2]
+	mi := &file_order_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -183,7 +331,7 @@ func (x *PostOrderResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PostOrderResponse.ProtoReflect.Descriptor instead.
 func (*PostOrderResponse) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{2}
+	return file_order_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *PostOrderResponse) GetOrder() *Order {
@@ -202,7 +350,7 @@ type GetOrderRequest struct {
 
 func (x *GetOrderRequest) Reset() {
 	*x = GetOrderRequest{}
-	mi := &file_order_proto_msgTypes[3]
+	mi := &file_order_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -214,7 +362,7 @@ func (x *GetOrderRequest) String() string {
 func (*GetOrderRequest) ProtoMessage() {}
 
 func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[3]
+	mi := &file_order_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -227,7 +375,7 @@ func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetOrderRequest.ProtoReflect.Descriptor instead.
 func (*GetOrderRequest) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{3}
+	return file_order_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *GetOrderRequest) GetId() string {
@@ -246,7 +394,7 @@ type GetOrderResponse struct {
 
 func (x *GetOrderResponse) Reset() {
 	*x = GetOrderResponse{}
-	mi := &file_order_proto_msgTypes[4]
+	mi := &file_order_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -258,7 +406,7 @@ func (x *GetOrderResponse) String() string {
 func (*GetOrderResponse) ProtoMessage() {}
 
 func (x *GetOrderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[4]
+	mi := &file_order_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -271,7 +419,7 @@ func (x *GetOrderResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetOrderResponse.ProtoReflect.Descriptor instead.
 func (*GetOrderResponse) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{4}
+	return file_order_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GetOrderResponse) GetOrder() *Order {
@@ -290,7 +438,7 @@ type GetOrderForAccountRequest struct {
 
 func (x *GetOrderForAccountRequest) Reset() {
 	*x = GetOrderForAccountRequest{}
-	mi := &file_order_proto_msgTypes[5]
+	mi := &file_order_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -302,7 +450,7 @@ func (x *GetOrderForAccountRequest) String() string {
 func (*GetOrderForAccountRequest) ProtoMessage() {}
 
 func (x *GetOrderForAccountRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[5]
+	mi := &file_order_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -315,7 +463,7 @@ func (x *GetOrderForAccountRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetOrderForAccountRequest.ProtoReflect.Descriptor instead.
 func (*GetOrderForAccountRequest) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{5}
+	return file_order_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *GetOrderForAccountRequest) GetAccountId() string {
@@ -334,7 +482,7 @@ type GetOrderForAccountResponse struct {
 
 func (x *GetOrderForAccountResponse) Reset() {
 	*x = GetOrderForAccountResponse{}
-	mi := &file_order_proto_msgTypes[6]
+	mi := &file_order_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -346,7 +494,7 @@ func (x *GetOrderForAccountResponse) String() string {
 func (*GetOrderForAccountResponse) ProtoMessage() {}
 
 func (x *GetOrderForAccountResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[6]
+	mi := &file_order_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -359,7 +507,7 @@ func (x *GetOrderForAccountResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetOrderForAccountResponse.ProtoReflect.Descriptor instead.
 func (*GetOrderForAccountResponse) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{6}
+	return file_order_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *GetOrderForAccountResponse) GetOrders() []*Order {
@@ -369,32 +517,30 @@ func (x *GetOrderForAccountResponse) GetOrders() []*Order {
 	return nil
 }
 
-type Order_OrderProduct struct {
+type GetFulfillmentsForSellerRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	Price         float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
-	Quantity      uint32                 `protobuf:"varint,5,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	SellerId      string                 `protobuf:"bytes,1,opt,name=sellerId,proto3" json:"sellerId,omitempty"`
+	Skip          uint64                 `protobuf:"varint,2,opt,name=skip,proto3" json:"skip,omitempty"`
+	Take          uint64                 `protobuf:"varint,3,opt,name=take,proto3" json:"take,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Order_OrderProduct) Reset() {
-	*x = Order_OrderProduct{}
-	mi := &file_order_proto_msgTypes[7]
+func (x *GetFulfillmentsForSellerRequest) Reset() {
+	*x = GetFulfillmentsForSellerRequest{}
+	mi := &file_order_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Order_OrderProduct) String() string {
+func (x *GetFulfillmentsForSellerRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Order_OrderProduct) ProtoMessage() {}
+func (*GetFulfillmentsForSellerRequest) ProtoMessage() {}
 
-func (x *Order_OrderProduct) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[7]
+func (x *GetFulfillmentsForSellerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -405,69 +551,174 @@ func (x *Order_OrderProduct) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Order_OrderProduct.ProtoReflect.Descriptor instead.
-func (*Order_OrderProduct) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{0, 0}
+// Deprecated: Use GetFulfillmentsForSellerRequest.ProtoReflect.Descriptor instead.
+func (*GetFulfillmentsForSellerRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *Order_OrderProduct) GetId() string {
+func (x *GetFulfillmentsForSellerRequest) GetSellerId() string {
 	if x != nil {
-		return x.Id
+		return x.SellerId
 	}
 	return ""
 }
 
-func (x *Order_OrderProduct) GetName() string {
+func (x *GetFulfillmentsForSellerRequest) GetSkip() uint64 {
 	if x != nil {
-		return x.Name
+		return x.Skip
+	}
+	return 0
+}
+
+func (x *GetFulfillmentsForSellerRequest) GetTake() uint64 {
+	if x != nil {
+		return x.Take
+	}
+	return 0
+}
+
+type GetFulfillmentsForSellerResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Fulfillments  []*Fulfillment         `protobuf:"bytes,1,rep,name=fulfillments,proto3" json:"fulfillments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFulfillmentsForSellerResponse) Reset() {
+	*x = GetFulfillmentsForSellerResponse{}
+	mi := &file_order_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFulfillmentsForSellerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFulfillmentsForSellerResponse) ProtoMessage() {}
+
+func (x *GetFulfillmentsForSellerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFulfillmentsForSellerResponse.ProtoReflect.Descriptor instead.
+func (*GetFulfillmentsForSellerResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetFulfillmentsForSellerResponse) GetFulfillments() []*Fulfillment {
+	if x != nil {
+		return x.Fulfillments
+	}
+	return nil
+}
+
+type UpdateFulfillmentRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	SellerId       string                 `protobuf:"bytes,2,opt,name=sellerId,proto3" json:"sellerId,omitempty"`
+	Status         int32                  `protobuf:"varint,3,opt,name=status,proto3" json:"status,omitempty"`
+	Carrier        string                 `protobuf:"bytes,4,opt,name=carrier,proto3" json:"carrier,omitempty"`
+	TrackingNumber string                 `protobuf:"bytes,5,opt,name=trackingNumber,proto3" json:"trackingNumber,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *UpdateFulfillmentRequest) Reset() {
+	*x = UpdateFulfillmentRequest{}
+	mi := &file_order_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateFulfillmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateFulfillmentRequest) ProtoMessage() {}
+
+func (x *UpdateFulfillmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateFulfillmentRequest.ProtoReflect.Descriptor instead.
+func (*UpdateFulfillmentRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *UpdateFulfillmentRequest) GetId() string {
+	if x != nil {
+		return x.Id
 	}
 	return ""
 }
 
-func (x *Order_OrderProduct) GetDescription() string {
+func (x *UpdateFulfillmentRequest) GetSellerId() string {
 	if x != nil {
-		return x.Description
+		return x.SellerId
 	}
 	return ""
 }
 
-func (x *Order_OrderProduct) GetPrice() float64 {
+func (x *UpdateFulfillmentRequest) GetStatus() int32 {
 	if x != nil {
-		return x.Price
+		return x.Status
 	}
 	return 0
 }
 
-func (x *Order_OrderProduct) GetQuantity() uint32 {
+func (x *UpdateFulfillmentRequest) GetCarrier() string {
 	if x != nil {
-		return x.Quantity
+		return x.Carrier
 	}
-	return 0
+	return ""
 }
 
-type PostOrderRequest_OrderProduct struct {
+func (x *UpdateFulfillmentRequest) GetTrackingNumber() string {
+	if x != nil {
+		return x.TrackingNumber
+	}
+	return ""
+}
+
+type UpdateFulfillmentResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProductId     string                 `protobuf:"bytes,1,opt,name=productId,proto3" json:"productId,omitempty"`
-	Quantity      uint32                 `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Fulfillment   *Fulfillment           `protobuf:"bytes,1,opt,name=fulfillment,proto3" json:"fulfillment,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PostOrderRequest_OrderProduct) Reset() {
-	*x = PostOrderRequest_OrderProduct{}
-	mi := &file_order_proto_msgTypes[8]
+func (x *UpdateFulfillmentResponse) Reset() {
+	*x = UpdateFulfillmentResponse{}
+	mi := &file_order_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PostOrderRequest_OrderProduct) String() string {
+func (x *UpdateFulfillmentResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PostOrderRequest_OrderProduct) ProtoMessage() {}
+func (*UpdateFulfillmentResponse) ProtoMessage() {}
 
-func (x *PostOrderRequest_OrderProduct) ProtoReflect() protoreflect.Message {
-	mi := &file_order_proto_msgTypes[8]
+func (x *UpdateFulfillmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -478,91 +729,1296 @@ func (x *PostOrderRequest_OrderProduct) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PostOrderRequest_OrderProduct.ProtoReflect.Descriptor instead.
-func (*PostOrderRequest_OrderProduct) Descriptor() ([]byte, []int) {
-	return file_order_proto_rawDescGZIP(), []int{1, 0}
+// Deprecated: Use UpdateFulfillmentResponse.ProtoReflect.Descriptor instead.
+func (*UpdateFulfillmentResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *PostOrderRequest_OrderProduct) GetProductId() string {
+func (x *UpdateFulfillmentResponse) GetFulfillment() *Fulfillment {
 	if x != nil {
-		return x.ProductId
+		return x.Fulfillment
 	}
-	return ""
+	return nil
 }
 
-func (x *PostOrderRequest_OrderProduct) GetQuantity() uint32 {
-	if x != nil {
-		return x.Quantity
-	}
-	return 0
+type OrderReturn struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrderId       string                 `protobuf:"bytes,2,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	FulfillmentId string                 `protobuf:"bytes,3,opt,name=fulfillmentId,proto3" json:"fulfillmentId,omitempty"`
+	AccountId     string                 `protobuf:"bytes,4,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	SellerId      string                 `protobuf:"bytes,5,opt,name=sellerId,proto3" json:"sellerId,omitempty"`
+	ProductId     string                 `protobuf:"bytes,6,opt,name=productId,proto3" json:"productId,omitempty"`
+	Quantity      uint32                 `protobuf:"varint,7,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Reason        int32                  `protobuf:"varint,8,opt,name=reason,proto3" json:"reason,omitempty"`
+	Note          string                 `protobuf:"bytes,9,opt,name=note,proto3" json:"note,omitempty"`
+	Status        int32                  `protobuf:"varint,10,opt,name=status,proto3" json:"status,omitempty"`
+	RefundAmount  float64                `protobuf:"fixed64,11,opt,name=refundAmount,proto3" json:"refundAmount,omitempty"`
+	Timeline      []*OrderReturn_Event   `protobuf:"bytes,12,rep,name=timeline,proto3" json:"timeline,omitempty"`
+	CreatedAt     []byte                 `protobuf:"bytes,13,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	UpdatedAt     []byte                 `protobuf:"bytes,14,opt,name=updatedAt,proto3" json:"updatedAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderReturn) Reset() {
+	*x = OrderReturn{}
+	mi := &file_order_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderReturn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderReturn) ProtoMessage() {}
+
+func (x *OrderReturn) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderReturn.ProtoReflect.Descriptor instead.
+func (*OrderReturn) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *OrderReturn) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *OrderReturn) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *OrderReturn) GetFulfillmentId() string {
+	if x != nil {
+		return x.FulfillmentId
+	}
+	return ""
+}
+
+func (x *OrderReturn) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *OrderReturn) GetSellerId() string {
+	if x != nil {
+		return x.SellerId
+	}
+	return ""
+}
+
+func (x *OrderReturn) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *OrderReturn) GetQuantity() uint32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *OrderReturn) GetReason() int32 {
+	if x != nil {
+		return x.Reason
+	}
+	return 0
+}
+
+func (x *OrderReturn) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *OrderReturn) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *OrderReturn) GetRefundAmount() float64 {
+	if x != nil {
+		return x.RefundAmount
+	}
+	return 0
+}
+
+func (x *OrderReturn) GetTimeline() []*OrderReturn_Event {
+	if x != nil {
+		return x.Timeline
+	}
+	return nil
+}
+
+func (x *OrderReturn) GetCreatedAt() []byte {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *OrderReturn) GetUpdatedAt() []byte {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type RequestReturnRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	OrderId       string                 `protobuf:"bytes,2,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	ProductId     string                 `protobuf:"bytes,3,opt,name=productId,proto3" json:"productId,omitempty"`
+	Quantity      uint32                 `protobuf:"varint,4,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Reason        int32                  `protobuf:"varint,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	Note          string                 `protobuf:"bytes,6,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestReturnRequest) Reset() {
+	*x = RequestReturnRequest{}
+	mi := &file_order_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestReturnRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestReturnRequest) ProtoMessage() {}
+
+func (x *RequestReturnRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestReturnRequest.ProtoReflect.Descriptor instead.
+func (*RequestReturnRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RequestReturnRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *RequestReturnRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *RequestReturnRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *RequestReturnRequest) GetQuantity() uint32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *RequestReturnRequest) GetReason() int32 {
+	if x != nil {
+		return x.Reason
+	}
+	return 0
+}
+
+func (x *RequestReturnRequest) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+type ReviewReturnRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	SellerId      string                 `protobuf:"bytes,2,opt,name=sellerId,proto3" json:"sellerId,omitempty"`
+	Approve       bool                   `protobuf:"varint,3,opt,name=approve,proto3" json:"approve,omitempty"`
+	Note          string                 `protobuf:"bytes,4,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReviewReturnRequest) Reset() {
+	*x = ReviewReturnRequest{}
+	mi := &file_order_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReviewReturnRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewReturnRequest) ProtoMessage() {}
+
+func (x *ReviewReturnRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewReturnRequest.ProtoReflect.Descriptor instead.
+func (*ReviewReturnRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ReviewReturnRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ReviewReturnRequest) GetSellerId() string {
+	if x != nil {
+		return x.SellerId
+	}
+	return ""
+}
+
+func (x *ReviewReturnRequest) GetApprove() bool {
+	if x != nil {
+		return x.Approve
+	}
+	return false
+}
+
+func (x *ReviewReturnRequest) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+type ReceiveReturnRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	SellerId      string                 `protobuf:"bytes,2,opt,name=sellerId,proto3" json:"sellerId,omitempty"`
+	Note          string                 `protobuf:"bytes,3,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReceiveReturnRequest) Reset() {
+	*x = ReceiveReturnRequest{}
+	mi := &file_order_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReceiveReturnRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReceiveReturnRequest) ProtoMessage() {}
+
+func (x *ReceiveReturnRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReceiveReturnRequest.ProtoReflect.Descriptor instead.
+func (*ReceiveReturnRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ReceiveReturnRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ReceiveReturnRequest) GetSellerId() string {
+	if x != nil {
+		return x.SellerId
+	}
+	return ""
+}
+
+func (x *ReceiveReturnRequest) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+type RefundReturnRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	SellerId      string                 `protobuf:"bytes,2,opt,name=sellerId,proto3" json:"sellerId,omitempty"`
+	Amount        float64                `protobuf:"fixed64,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Note          string                 `protobuf:"bytes,4,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefundReturnRequest) Reset() {
+	*x = RefundReturnRequest{}
+	mi := &file_order_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefundReturnRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefundReturnRequest) ProtoMessage() {}
+
+func (x *RefundReturnRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefundReturnRequest.ProtoReflect.Descriptor instead.
+func (*RefundReturnRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *RefundReturnRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RefundReturnRequest) GetSellerId() string {
+	if x != nil {
+		return x.SellerId
+	}
+	return ""
+}
+
+func (x *RefundReturnRequest) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *RefundReturnRequest) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+type OrderReturnResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderReturn   *OrderReturn           `protobuf:"bytes,1,opt,name=orderReturn,proto3" json:"orderReturn,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderReturnResponse) Reset() {
+	*x = OrderReturnResponse{}
+	mi := &file_order_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderReturnResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderReturnResponse) ProtoMessage() {}
+
+func (x *OrderReturnResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderReturnResponse.ProtoReflect.Descriptor instead.
+func (*OrderReturnResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *OrderReturnResponse) GetOrderReturn() *OrderReturn {
+	if x != nil {
+		return x.OrderReturn
+	}
+	return nil
+}
+
+type GetReturnsForAccountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetReturnsForAccountRequest) Reset() {
+	*x = GetReturnsForAccountRequest{}
+	mi := &file_order_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReturnsForAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReturnsForAccountRequest) ProtoMessage() {}
+
+func (x *GetReturnsForAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReturnsForAccountRequest.ProtoReflect.Descriptor instead.
+func (*GetReturnsForAccountRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetReturnsForAccountRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+type GetReturnsForSellerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SellerId      string                 `protobuf:"bytes,1,opt,name=sellerId,proto3" json:"sellerId,omitempty"`
+	Skip          uint64                 `protobuf:"varint,2,opt,name=skip,proto3" json:"skip,omitempty"`
+	Take          uint64                 `protobuf:"varint,3,opt,name=take,proto3" json:"take,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetReturnsForSellerRequest) Reset() {
+	*x = GetReturnsForSellerRequest{}
+	mi := &file_order_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReturnsForSellerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReturnsForSellerRequest) ProtoMessage() {}
+
+func (x *GetReturnsForSellerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReturnsForSellerRequest.ProtoReflect.Descriptor instead.
+func (*GetReturnsForSellerRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetReturnsForSellerRequest) GetSellerId() string {
+	if x != nil {
+		return x.SellerId
+	}
+	return ""
+}
+
+func (x *GetReturnsForSellerRequest) GetSkip() uint64 {
+	if x != nil {
+		return x.Skip
+	}
+	return 0
+}
+
+func (x *GetReturnsForSellerRequest) GetTake() uint64 {
+	if x != nil {
+		return x.Take
+	}
+	return 0
+}
+
+type GetReturnsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Returns       []*OrderReturn         `protobuf:"bytes,1,rep,name=returns,proto3" json:"returns,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetReturnsResponse) Reset() {
+	*x = GetReturnsResponse{}
+	mi := &file_order_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReturnsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReturnsResponse) ProtoMessage() {}
+
+func (x *GetReturnsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReturnsResponse.ProtoReflect.Descriptor instead.
+func (*GetReturnsResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetReturnsResponse) GetReturns() []*OrderReturn {
+	if x != nil {
+		return x.Returns
+	}
+	return nil
+}
+
+type HasDeliveredProductRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	ProductId     string                 `protobuf:"bytes,2,opt,name=productId,proto3" json:"productId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HasDeliveredProductRequest) Reset() {
+	*x = HasDeliveredProductRequest{}
+	mi := &file_order_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HasDeliveredProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HasDeliveredProductRequest) ProtoMessage() {}
+
+func (x *HasDeliveredProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HasDeliveredProductRequest.ProtoReflect.Descriptor instead.
+func (*HasDeliveredProductRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *HasDeliveredProductRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *HasDeliveredProductRequest) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+type HasDeliveredProductResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Delivered     bool                   `protobuf:"varint,1,opt,name=delivered,proto3" json:"delivered,omitempty"`
+	OrderId       string                 `protobuf:"bytes,2,opt,name=orderId,proto3" json:"orderId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HasDeliveredProductResponse) Reset() {
+	*x = HasDeliveredProductResponse{}
+	mi := &file_order_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HasDeliveredProductResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HasDeliveredProductResponse) ProtoMessage() {}
+
+func (x *HasDeliveredProductResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HasDeliveredProductResponse.ProtoReflect.Descriptor instead.
+func (*HasDeliveredProductResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *HasDeliveredProductResponse) GetDelivered() bool {
+	if x != nil {
+		return x.Delivered
+	}
+	return false
+}
+
+func (x *HasDeliveredProductResponse) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type GetBuyerForSellerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SellerId      string                 `protobuf:"bytes,1,opt,name=sellerId,proto3" json:"sellerId,omitempty"`
+	AccountId     string                 `protobuf:"bytes,2,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBuyerForSellerRequest) Reset() {
+	*x = GetBuyerForSellerRequest{}
+	mi := &file_order_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBuyerForSellerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBuyerForSellerRequest) ProtoMessage() {}
+
+func (x *GetBuyerForSellerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBuyerForSellerRequest.ProtoReflect.Descriptor instead.
+func (*GetBuyerForSellerRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetBuyerForSellerRequest) GetSellerId() string {
+	if x != nil {
+		return x.SellerId
+	}
+	return ""
+}
+
+func (x *GetBuyerForSellerRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+type GetBuyerForSellerResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	FirstName     string                 `protobuf:"bytes,2,opt,name=firstName,proto3" json:"firstName,omitempty"`
+	LastName      string                 `protobuf:"bytes,3,opt,name=lastName,proto3" json:"lastName,omitempty"`
+	Phone         string                 `protobuf:"bytes,4,opt,name=phone,proto3" json:"phone,omitempty"`
+	Address       string                 `protobuf:"bytes,5,opt,name=address,proto3" json:"address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBuyerForSellerResponse) Reset() {
+	*x = GetBuyerForSellerResponse{}
+	mi := &file_order_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBuyerForSellerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBuyerForSellerResponse) ProtoMessage() {}
+
+func (x *GetBuyerForSellerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBuyerForSellerResponse.ProtoReflect.Descriptor instead.
+func (*GetBuyerForSellerResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetBuyerForSellerResponse) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *GetBuyerForSellerResponse) GetFirstName() string {
+	if x != nil {
+		return x.FirstName
+	}
+	return ""
+}
+
+func (x *GetBuyerForSellerResponse) GetLastName() string {
+	if x != nil {
+		return x.LastName
+	}
+	return ""
+}
+
+func (x *GetBuyerForSellerResponse) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *GetBuyerForSellerResponse) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+type EraseAccountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=accountId,proto3" json:"accountId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EraseAccountRequest) Reset() {
+	*x = EraseAccountRequest{}
+	mi := &file_order_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EraseAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EraseAccountRequest) ProtoMessage() {}
+
+func (x *EraseAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EraseAccountRequest.ProtoReflect.Descriptor instead.
+func (*EraseAccountRequest) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *EraseAccountRequest) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+type EraseAccountResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EraseAccountResponse) Reset() {
+	*x = EraseAccountResponse{}
+	mi := &file_order_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EraseAccountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EraseAccountResponse) ProtoMessage() {}
+
+func (x *EraseAccountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EraseAccountResponse.ProtoReflect.Descriptor instead.
+func (*EraseAccountResponse) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{26}
+}
+
+type Order_OrderProduct struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Price         float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
+	Quantity      uint32                 `protobuf:"varint,5,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	SellerId      string                 `protobuf:"bytes,6,opt,name=seller_id,json=sellerId,proto3" json:"seller_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Order_OrderProduct) Reset() {
+	*x = Order_OrderProduct{}
+	mi := &file_order_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Order_OrderProduct) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Order_OrderProduct) ProtoMessage() {}
+
+func (x *Order_OrderProduct) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Order_OrderProduct.ProtoReflect.Descriptor instead.
+func (*Order_OrderProduct) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{0, 0}
+}
+
+func (x *Order_OrderProduct) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Order_OrderProduct) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Order_OrderProduct) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Order_OrderProduct) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Order_OrderProduct) GetQuantity() uint32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *Order_OrderProduct) GetSellerId() string {
+	if x != nil {
+		return x.SellerId
+	}
+	return ""
+}
+
+type PostOrderRequest_OrderProduct struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=productId,proto3" json:"productId,omitempty"`
+	Quantity      uint32                 `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PostOrderRequest_OrderProduct) Reset() {
+	*x = PostOrderRequest_OrderProduct{}
+	mi := &file_order_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PostOrderRequest_OrderProduct) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PostOrderRequest_OrderProduct) ProtoMessage() {}
+
+func (x *PostOrderRequest_OrderProduct) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PostOrderRequest_OrderProduct.ProtoReflect.Descriptor instead.
+func (*PostOrderRequest_OrderProduct) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{2, 0}
+}
+
+func (x *PostOrderRequest_OrderProduct) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *PostOrderRequest_OrderProduct) GetQuantity() uint32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type OrderReturn_Event struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        int32                  `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
+	Note          string                 `protobuf:"bytes,2,opt,name=note,proto3" json:"note,omitempty"`
+	CreatedAt     []byte                 `protobuf:"bytes,3,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderReturn_Event) Reset() {
+	*x = OrderReturn_Event{}
+	mi := &file_order_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderReturn_Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderReturn_Event) ProtoMessage() {}
+
+func (x *OrderReturn_Event) ProtoReflect() protoreflect.Message {
+	mi := &file_order_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderReturn_Event.ProtoReflect.Descriptor instead.
+func (*OrderReturn_Event) Descriptor() ([]byte, []int) {
+	return file_order_proto_rawDescGZIP(), []int{12, 0}
+}
+
+func (x *OrderReturn_Event) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *OrderReturn_Event) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+func (x *OrderReturn_Event) GetCreatedAt() []byte {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
 }
 
 var File_order_proto protoreflect.FileDescriptor
 
-var file_order_proto_rawDesc = string([]byte{
-	0x0a, 0x0b, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x22, 0xb3, 0x02, 0x0a, 0x05, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x0e,
-	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x1c,
-	0x0a, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x1c, 0x0a, 0x09,
-	0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x09, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x74, 0x6f,
-	0x74, 0x61, 0x6c, 0x50, 0x72, 0x69, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a,
-	0x74, 0x6f, 0x74, 0x61, 0x6c, 0x50, 0x72, 0x69, 0x63, 0x65, 0x12, 0x35, 0x0a, 0x08, 0x70, 0x72,
-	0x6f, 0x64, 0x75, 0x63, 0x74, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72,
-	0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74,
-	0x73, 0x1a, 0x86, 0x01, 0x0a, 0x0c, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x64, 0x75,
-	0x63, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
-	0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69,
-	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73,
-	0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x72, 0x69, 0x63,
-	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x70, 0x72, 0x69, 0x63, 0x65, 0x12, 0x1a,
-	0x0a, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d,
-	0x52, 0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x22, 0xbc, 0x01, 0x0a, 0x10, 0x50,
-	0x6f, 0x73, 0x74, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x1c, 0x0a, 0x09, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x09, 0x61, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x40, 0x0a,
-	0x08, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x24, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x6f, 0x73, 0x74, 0x4f, 0x72, 0x64, 0x65,
-	0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x50, 0x72,
-	0x6f, 0x64, 0x75, 0x63, 0x74, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x73, 0x1a,
-	0x48, 0x0a, 0x0c, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x12,
-	0x1c, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x49, 0x64, 0x12, 0x1a, 0x0a,
-	0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52,
-	0x08, 0x71, 0x75, 0x61, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x22, 0x37, 0x0a, 0x11, 0x50, 0x6f, 0x73,
-	0x74, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x22,
-	0x0a, 0x05, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x52, 0x05, 0x6f, 0x72, 0x64,
-	0x65, 0x72, 0x22, 0x21, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x36, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x4f, 0x72, 0x64, 0x65,
-	0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x22, 0x0a, 0x05, 0x6f, 0x72, 0x64,
-	0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x2e, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x52, 0x05, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x22, 0x39, 0x0a,
-	0x19, 0x47, 0x65, 0x74, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x46, 0x6f, 0x72, 0x41, 0x63, 0x63, 0x6f,
-	0x75, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x61, 0x63,
-	0x63, 0x6f, 0x75, 0x6e, 0x74, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x61,
-	0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x49, 0x64, 0x22, 0x42, 0x0a, 0x1a, 0x47, 0x65, 0x74, 0x4f,
-	0x72, 0x64, 0x65, 0x72, 0x46, 0x6f, 0x72, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x24, 0x0a, 0x06, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x73,
-	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x4f,
-	0x72, 0x64, 0x65, 0x72, 0x52, 0x06, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x73, 0x32, 0xae, 0x01, 0x0a,
-	0x0c, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x40, 0x0a,
-	0x09, 0x50, 0x6f, 0x73, 0x74, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x17, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x2e, 0x50, 0x6f, 0x73, 0x74, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x50, 0x6f, 0x73, 0x74,
-	0x4f, 0x72, 0x64, 0x65, 0x72, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12,
-	0x5c, 0x0a, 0x13, 0x47, 0x65, 0x74, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x73, 0x46, 0x6f, 0x72, 0x41,
-	0x63, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x20, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2e, 0x47,
-	0x65, 0x74, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x46, 0x6f, 0x72, 0x41, 0x63, 0x63, 0x6f, 0x75, 0x6e,
-	0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x21, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x2e, 0x47, 0x65, 0x74, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x46, 0x6f, 0x72, 0x41, 0x63, 0x63, 0x6f,
-	0x75, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x06, 0x5a,
-	0x04, 0x2e, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
-})
+const file_order_proto_rawDesc = "" +
+	"\n" +
+	"\vorder.proto\x12\x05proto\"\xee\x03\n" +
+	"\x05Order\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1c\n" +
+	"\tcreatedAt\x18\x02 \x01(\fR\tcreatedAt\x12\x1c\n" +
+	"\taccountId\x18\x03 \x01(\tR\taccountId\x12\x1e\n" +
+	"\n" +
+	"totalPrice\x18\x04 \x01(\x01R\n" +
+	"totalPrice\x125\n" +
+	"\bproducts\x18\x05 \x03(\v2\x19.proto.Order.OrderProductR\bproducts\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\x05R\x06status\x126\n" +
+	"\ffulfillments\x18\a \x03(\v2\x12.proto.FulfillmentR\ffulfillments\x12$\n" +
+	"\rpaymentStatus\x18\b \x01(\x05R\rpaymentStatus\x12&\n" +
+	"\x0erefundedAmount\x18\t \x01(\x01R\x0erefundedAmount\x1a\xa3\x01\n" +
+	"\fOrderProduct\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x14\n" +
+	"\x05price\x18\x04 \x01(\x01R\x05price\x12\x1a\n" +
+	"\bquantity\x18\x05 \x01(\rR\bquantity\x12\x1b\n" +
+	"\tseller_id\x18\x06 \x01(\tR\bsellerId\"\xc4\x02\n" +
+	"\vFulfillment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\aorderId\x18\x02 \x01(\tR\aorderId\x12\x1a\n" +
+	"\bsellerId\x18\x03 \x01(\tR\bsellerId\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\x05R\x06status\x12\x18\n" +
+	"\acarrier\x18\x05 \x01(\tR\acarrier\x12&\n" +
+	"\x0etrackingNumber\x18\x06 \x01(\tR\x0etrackingNumber\x12\"\n" +
+	"\fpayoutAmount\x18\a \x01(\x01R\fpayoutAmount\x125\n" +
+	"\bproducts\x18\b \x03(\v2\x19.proto.Order.OrderProductR\bproducts\x12\x1c\n" +
+	"\tcreatedAt\x18\t \x01(\fR\tcreatedAt\x12\x1c\n" +
+	"\tupdatedAt\x18\n" +
+	" \x01(\fR\tupdatedAt\"\xbc\x01\n" +
+	"\x10PostOrderRequest\x12\x1c\n" +
+	"\taccountId\x18\x01 \x01(\tR\taccountId\x12@\n" +
+	"\bproducts\x18\x02 \x03(\v2$.proto.PostOrderRequest.OrderProductR\bproducts\x1aH\n" +
+	"\fOrderProduct\x12\x1c\n" +
+	"\tproductId\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\rR\bquantity\"7\n" +
+	"\x11PostOrderResponse\x12\"\n" +
+	"\x05order\x18\x01 \x01(\v2\f.proto.OrderR\x05order\"!\n" +
+	"\x0fGetOrderRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"6\n" +
+	"\x10GetOrderResponse\x12\"\n" +
+	"\x05order\x18\x01 \x01(\v2\f.proto.OrderR\x05order\"9\n" +
+	"\x19GetOrderForAccountRequest\x12\x1c\n" +
+	"\taccountId\x18\x01 \x01(\tR\taccountId\"B\n" +
+	"\x1aGetOrderForAccountResponse\x12$\n" +
+	"\x06orders\x18\x01 \x03(\v2\f.proto.OrderR\x06orders\"e\n" +
+	"\x1fGetFulfillmentsForSellerRequest\x12\x1a\n" +
+	"\bsellerId\x18\x01 \x01(\tR\bsellerId\x12\x12\n" +
+	"\x04skip\x18\x02 \x01(\x04R\x04skip\x12\x12\n" +
+	"\x04take\x18\x03 \x01(\x04R\x04take\"Z\n" +
+	" GetFulfillmentsForSellerResponse\x126\n" +
+	"\ffulfillments\x18\x01 \x03(\v2\x12.proto.FulfillmentR\ffulfillments\"\xa0\x01\n" +
+	"\x18UpdateFulfillmentRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\bsellerId\x18\x02 \x01(\tR\bsellerId\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\x05R\x06status\x12\x18\n" +
+	"\acarrier\x18\x04 \x01(\tR\acarrier\x12&\n" +
+	"\x0etrackingNumber\x18\x05 \x01(\tR\x0etrackingNumber\"Q\n" +
+	"\x19UpdateFulfillmentResponse\x124\n" +
+	"\vfulfillment\x18\x01 \x01(\v2\x12.proto.FulfillmentR\vfulfillment\"\xfe\x03\n" +
+	"\vOrderReturn\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\aorderId\x18\x02 \x01(\tR\aorderId\x12$\n" +
+	"\rfulfillmentId\x18\x03 \x01(\tR\rfulfillmentId\x12\x1c\n" +
+	"\taccountId\x18\x04 \x01(\tR\taccountId\x12\x1a\n" +
+	"\bsellerId\x18\x05 \x01(\tR\bsellerId\x12\x1c\n" +
+	"\tproductId\x18\x06 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\a \x01(\rR\bquantity\x12\x16\n" +
+	"\x06reason\x18\b \x01(\x05R\x06reason\x12\x12\n" +
+	"\x04note\x18\t \x01(\tR\x04note\x12\x16\n" +
+	"\x06status\x18\n" +
+	" \x01(\x05R\x06status\x12\"\n" +
+	"\frefundAmount\x18\v \x01(\x01R\frefundAmount\x124\n" +
+	"\btimeline\x18\f \x03(\v2\x18.proto.OrderReturn.EventR\btimeline\x12\x1c\n" +
+	"\tcreatedAt\x18\r \x01(\fR\tcreatedAt\x12\x1c\n" +
+	"\tupdatedAt\x18\x0e \x01(\fR\tupdatedAt\x1aQ\n" +
+	"\x05Event\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\x05R\x06status\x12\x12\n" +
+	"\x04note\x18\x02 \x01(\tR\x04note\x12\x1c\n" +
+	"\tcreatedAt\x18\x03 \x01(\fR\tcreatedAt\"\xb4\x01\n" +
+	"\x14RequestReturnRequest\x12\x1c\n" +
+	"\taccountId\x18\x01 \x01(\tR\taccountId\x12\x18\n" +
+	"\aorderId\x18\x02 \x01(\tR\aorderId\x12\x1c\n" +
+	"\tproductId\x18\x03 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x04 \x01(\rR\bquantity\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\x05R\x06reason\x12\x12\n" +
+	"\x04note\x18\x06 \x01(\tR\x04note\"o\n" +
+	"\x13ReviewReturnRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\bsellerId\x18\x02 \x01(\tR\bsellerId\x12\x18\n" +
+	"\aapprove\x18\x03 \x01(\bR\aapprove\x12\x12\n" +
+	"\x04note\x18\x04 \x01(\tR\x04note\"V\n" +
+	"\x14ReceiveReturnRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\bsellerId\x18\x02 \x01(\tR\bsellerId\x12\x12\n" +
+	"\x04note\x18\x03 \x01(\tR\x04note\"m\n" +
+	"\x13RefundReturnRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\bsellerId\x18\x02 \x01(\tR\bsellerId\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x01R\x06amount\x12\x12\n" +
+	"\x04note\x18\x04 \x01(\tR\x04note\"K\n" +
+	"\x13OrderReturnResponse\x124\n" +
+	"\vorderReturn\x18\x01 \x01(\v2\x12.proto.OrderReturnR\vorderReturn\";\n" +
+	"\x1bGetReturnsForAccountRequest\x12\x1c\n" +
+	"\taccountId\x18\x01 \x01(\tR\taccountId\"`\n" +
+	"\x1aGetReturnsForSellerRequest\x12\x1a\n" +
+	"\bsellerId\x18\x01 \x01(\tR\bsellerId\x12\x12\n" +
+	"\x04skip\x18\x02 \x01(\x04R\x04skip\x12\x12\n" +
+	"\x04take\x18\x03 \x01(\x04R\x04take\"B\n" +
+	"\x12GetReturnsResponse\x12,\n" +
+	"\areturns\x18\x01 \x03(\v2\x12.proto.OrderReturnR\areturns\"X\n" +
+	"\x1aHasDeliveredProductRequest\x12\x1c\n" +
+	"\taccountId\x18\x01 \x01(\tR\taccountId\x12\x1c\n" +
+	"\tproductId\x18\x02 \x01(\tR\tproductId\"U\n" +
+	"\x1bHasDeliveredProductResponse\x12\x1c\n" +
+	"\tdelivered\x18\x01 \x01(\bR\tdelivered\x12\x18\n" +
+	"\aorderId\x18\x02 \x01(\tR\aorderId\"T\n" +
+	"\x18GetBuyerForSellerRequest\x12\x1a\n" +
+	"\bsellerId\x18\x01 \x01(\tR\bsellerId\x12\x1c\n" +
+	"\taccountId\x18\x02 \x01(\tR\taccountId\"\xa3\x01\n" +
+	"\x19GetBuyerForSellerResponse\x12\x1c\n" +
+	"\taccountId\x18\x01 \x01(\tR\taccountId\x12\x1c\n" +
+	"\tfirstName\x18\x02 \x01(\tR\tfirstName\x12\x1a\n" +
+	"\blastName\x18\x03 \x01(\tR\blastName\x12\x14\n" +
+	"\x05phone\x18\x04 \x01(\tR\x05phone\x12\x18\n" +
+	"\aaddress\x18\x05 \x01(\tR\aaddress\"3\n" +
+	"\x13EraseAccountRequest\x12\x1c\n" +
+	"\taccountId\x18\x01 \x01(\tR\taccountId\"\x16\n" +
+	"\x14EraseAccountResponse2\x97\t\n" +
+	"\fOrderService\x12@\n" +
+	"\tPostOrder\x12\x17.proto.PostOrderRequest\x1a\x18.proto.PostOrderResponse\"\x00\x12=\n" +
+	"\bGetOrder\x12\x16.proto.GetOrderRequest\x1a\x17.proto.GetOrderResponse\"\x00\x12\\\n" +
+	"\x13GetOrdersForAccount\x12 .proto.GetOrderForAccountRequest\x1a!.proto.GetOrderForAccountResponse\"\x00\x12^\n" +
+	"\x13HasDeliveredProduct\x12!.proto.HasDeliveredProductRequest\x1a\".proto.HasDeliveredProductResponse\"\x00\x12m\n" +
+	"\x18GetFulfillmentsForSeller\x12&.proto.GetFulfillmentsForSellerRequest\x1a'.proto.GetFulfillmentsForSellerResponse\"\x00\x12X\n" +
+	"\x11UpdateFulfillment\x12\x1f.proto.UpdateFulfillmentRequest\x1a .proto.UpdateFulfillmentResponse\"\x00\x12X\n" +
+	"\x11GetBuyerForSeller\x12\x1f.proto.GetBuyerForSellerRequest\x1a .proto.GetBuyerForSellerResponse\"\x00\x12J\n" +
+	"\rRequestReturn\x12\x1b.proto.RequestReturnRequest\x1a\x1a.proto.OrderReturnResponse\"\x00\x12H\n" +
+	"\fReviewReturn\x12\x1a.proto.ReviewReturnRequest\x1a\x1a.proto.OrderReturnResponse\"\x00\x12J\n" +
+	"\rReceiveReturn\x12\x1b.proto.ReceiveReturnRequest\x1a\x1a.proto.OrderReturnResponse\"\x00\x12H\n" +
+	"\fRefundReturn\x12\x1a.proto.RefundReturnRequest\x1a\x1a.proto.OrderReturnResponse\"\x00\x12W\n" +
+	"\x14GetReturnsForAccount\x12\".proto.GetReturnsForAccountRequest\x1a\x19.proto.GetReturnsResponse\"\x00\x12U\n" +
+	"\x13GetReturnsForSeller\x12!.proto.GetReturnsForSellerRequest\x1a\x19.proto.GetReturnsResponse\"\x00\x12I\n" +
+	"\fEraseAccount\x12\x1a.proto.EraseAccountRequest\x1a\x1b.proto.EraseAccountResponse\"\x00B\x06Z\x04./pbb\x06proto3"
 
 var (
 	file_order_proto_rawDescOnce sync.Once
@@ -576,33 +2032,85 @@ func file_order_proto_rawDescGZIP() []byte {
 	return file_order_proto_rawDescData
 }
 
-var file_order_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_order_proto_msgTypes = make([]protoimpl.MessageInfo, 30)
 var file_order_proto_goTypes = []any{
-	(*Order)(nil),                         // 0: proto.Order
-	(*PostOrderRequest)(nil),              // 1: proto.PostOrderRequest
-	(*PostOrderResponse)(nil),             // 2: proto.PostOrderResponse
-	(*GetOrderRequest)(nil),               // 3: proto.GetOrderRequest
-	(*GetOrderResponse)(nil),              // 4: proto.GetOrderResponse
-	(*GetOrderForAccountRequest)(nil),     // 5: proto.GetOrderForAccountRequest
-	(*GetOrderForAccountResponse)(nil),    // 6: proto.GetOrderForAccountResponse
-	(*Order_OrderProduct)(nil),            // 7: proto.Order.OrderProduct
-	(*PostOrderRequest_OrderProduct)(nil), // 8: proto.PostOrderRequest.OrderProduct
+	(*Order)(nil),                            // 0: proto.Order
+	(*Fulfillment)(nil),                      // 1: proto.Fulfillment
+	(*PostOrderRequest)(nil),                 // 2: proto.PostOrderRequest
+	(*PostOrderResponse)(nil),                // 3: proto.PostOrderResponse
+	(*GetOrderRequest)(nil),                  // 4: proto.GetOrderRequest
+	(*GetOrderResponse)(nil),                 // 5: proto.GetOrderResponse
+	(*GetOrderForAccountRequest)(nil),        // 6: proto.GetOrderForAccountRequest
+	(*GetOrderForAccountResponse)(nil),       // 7: proto.GetOrderForAccountResponse
+	(*GetFulfillmentsForSellerRequest)(nil),  // 8: proto.GetFulfillmentsForSellerRequest
+	(*GetFulfillmentsForSellerResponse)(nil), // 9: proto.GetFulfillmentsForSellerResponse
+	(*UpdateFulfillmentRequest)(nil),         // 10: proto.UpdateFulfillmentRequest
+	(*UpdateFulfillmentResponse)(nil),        // 11: proto.UpdateFulfillmentResponse
+	(*OrderReturn)(nil),                      // 12: proto.OrderReturn
+	(*RequestReturnRequest)(nil),             // 13: proto.RequestReturnRequest
+	(*ReviewReturnRequest)(nil),              // 14: proto.ReviewReturnRequest
+	(*ReceiveReturnRequest)(nil),             // 15: proto.ReceiveReturnRequest
+	(*RefundReturnRequest)(nil),              // 16: proto.RefundReturnRequest
+	(*OrderReturnResponse)(nil),              // 17: proto.OrderReturnResponse
+	(*GetReturnsForAccountRequest)(nil),      // 18: proto.GetReturnsForAccountRequest
+	(*GetReturnsForSellerRequest)(nil),       // 19: proto.GetReturnsForSellerRequest
+	(*GetReturnsResponse)(nil),               // 20: proto.GetReturnsResponse
+	(*HasDeliveredProductRequest)(nil),       // 21: proto.HasDeliveredProductRequest
+	(*HasDeliveredProductResponse)(nil),      // 22: proto.HasDeliveredProductResponse
+	(*GetBuyerForSellerRequest)(nil),         // 23: proto.GetBuyerForSellerRequest
+	(*GetBuyerForSellerResponse)(nil),        // 24: proto.GetBuyerForSellerResponse
+	(*EraseAccountRequest)(nil),              // 25: proto.EraseAccountRequest
+	(*EraseAccountResponse)(nil),             // 26: proto.EraseAccountResponse
+	(*Order_OrderProduct)(nil),               // 27: proto.Order.OrderProduct
+	(*PostOrderRequest_OrderProduct)(nil),    // 28: proto.PostOrderRequest.OrderProduct
+	(*OrderReturn_Event)(nil),                // 29: proto.OrderReturn.Event
 }
 var file_order_proto_depIdxs = []int32{
-	7, // 0: proto.Order.products:type_name -> proto.Order.OrderProduct
-	8, // 1: proto.PostOrderRequest.products:type_name -> proto.PostOrderRequest.OrderProduct
-	0, // 2: proto.PostOrderResponse.order:type_name -> proto.Order
-	0, // 3: proto.GetOrderResponse.order:type_name -> proto.Order
-	0, // 4: proto.GetOrderForAccountResponse.orders:type_name -> proto.Order
-	1, // 5: proto.OrderService.PostOrder:input_type -> proto.PostOrderRequest
-	5, // 6: proto.OrderService.GetOrdersForAccount:input_type -> proto.GetOrderForAccountRequest
-	2, // 7: proto.OrderService.PostOrder:output_type -> proto.PostOrderResponse
-	6, // 8: proto.OrderService.GetOrdersForAccount:output_type -> proto.GetOrderForAccountResponse
-	7, // [7:9] is the sub-list for method output_type
-	5, // [5:7] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	27, // 0: proto.Order.products:type_name -> proto.Order.OrderProduct
+	1,  // 1: proto.Order.fulfillments:type_name -> proto.Fulfillment
+	27, // 2: proto.Fulfillment.products:type_name -> proto.Order.OrderProduct
+	28, // 3: proto.PostOrderRequest.products:type_name -> proto.PostOrderRequest.OrderProduct
+	0,  // 4: proto.PostOrderResponse.order:type_name -> proto.Order
+	0,  // 5: proto.GetOrderResponse.order:type_name -> proto.Order
+	0,  // 6: proto.GetOrderForAccountResponse.orders:type_name -> proto.Order
+	1,  // 7: proto.GetFulfillmentsForSellerResponse.fulfillments:type_name -> proto.Fulfillment
+	1,  // 8: proto.UpdateFulfillmentResponse.fulfillment:type_name -> proto.Fulfillment
+	29, // 9: proto.OrderReturn.timeline:type_name -> proto.OrderReturn.Event
+	12, // 10: proto.OrderReturnResponse.orderReturn:type_name -> proto.OrderReturn
+	12, // 11: proto.GetReturnsResponse.returns:type_name -> proto.OrderReturn
+	2,  // 12: proto.OrderService.PostOrder:input_type -> proto.PostOrderRequest
+	4,  // 13: proto.OrderService.GetOrder:input_type -> proto.GetOrderRequest
+	6,  // 14: proto.OrderService.GetOrdersForAccount:input_type -> proto.GetOrderForAccountRequest
+	21, // 15: proto.OrderService.HasDeliveredProduct:input_type -> proto.HasDeliveredProductRequest
+	8,  // 16: proto.OrderService.GetFulfillmentsForSeller:input_type -> proto.GetFulfillmentsForSellerRequest
+	10, // 17: proto.OrderService.UpdateFulfillment:input_type -> proto.UpdateFulfillmentRequest
+	23, // 18: proto.OrderService.GetBuyerForSeller:input_type -> proto.GetBuyerForSellerRequest
+	13, // 19: proto.OrderService.RequestReturn:input_type -> proto.RequestReturnRequest
+	14, // 20: proto.OrderService.ReviewReturn:input_type -> proto.ReviewReturnRequest
+	15, // 21: proto.OrderService.ReceiveReturn:input_type -> proto.ReceiveReturnRequest
+	16, // 22: proto.OrderService.RefundReturn:input_type -> proto.RefundReturnRequest
+	18, // 23: proto.OrderService.GetReturnsForAccount:input_type -> proto.GetReturnsForAccountRequest
+	19, // 24: proto.OrderService.GetReturnsForSeller:input_type -> proto.GetReturnsForSellerRequest
+	25, // 25: proto.OrderService.EraseAccount:input_type -> proto.EraseAccountRequest
+	3,  // 26: proto.OrderService.PostOrder:output_type -> proto.PostOrderResponse
+	5,  // 27: proto.OrderService.GetOrder:output_type -> proto.GetOrderResponse
+	7,  // 28: proto.OrderService.GetOrdersForAccount:output_type -> proto.GetOrderForAccountResponse
+	22, // 29: proto.OrderService.HasDeliveredProduct:output_type -> proto.HasDeliveredProductResponse
+	9,  // 30: proto.OrderService.GetFulfillmentsForSeller:output_type -> proto.GetFulfillmentsForSellerResponse
+	11, // 31: proto.OrderService.UpdateFulfillment:output_type -> proto.UpdateFulfillmentResponse
+	24, // 32: proto.OrderService.GetBuyerForSeller:output_type -> proto.GetBuyerForSellerResponse
+	17, // 33: proto.OrderService.RequestReturn:output_type -> proto.OrderReturnResponse
+	17, // 34: proto.OrderService.ReviewReturn:output_type -> proto.OrderReturnResponse
+	17, // 35: proto.OrderService.ReceiveReturn:output_type -> proto.OrderReturnResponse
+	17, // 36: proto.OrderService.RefundReturn:output_type -> proto.OrderReturnResponse
+	20, // 37: proto.OrderService.GetReturnsForAccount:output_type -> proto.GetReturnsResponse
+	20, // 38: proto.OrderService.GetReturnsForSeller:output_type -> proto.GetReturnsResponse
+	26, // 39: proto.OrderService.EraseAccount:output_type -> proto.EraseAccountResponse
+	26, // [26:40] is the sub-list for method output_type
+	12, // [12:26] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
 }
 
 func init() { file_order_proto_init() }
@@ -616,7 +2124,7 @@ func file_order_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_order_proto_rawDesc), len(file_order_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   9,
+			NumMessages:   30,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -1,7 +1,7 @@
 // Code generated by protoc-gen-go-grpc. DO NOT EDIT.
 // versions:
 // - protoc-gen-go-grpc v1.5.1
-// - protoc             v5.29.2
+// - protoc             v6.33.0
 // source: order.proto
 
 package pb
@@ -19,8 +19,20 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	OrderService_PostOrder_FullMethodName           = "/proto.OrderService/PostOrder"
-	OrderService_GetOrdersForAccount_FullMethodName = "/proto.OrderService/GetOrdersForAccount"
+	OrderService_PostOrder_FullMethodName                = "/proto.OrderService/PostOrder"
+	OrderService_GetOrder_FullMethodName                 = "/proto.OrderService/GetOrder"
+	OrderService_GetOrdersForAccount_FullMethodName      = "/proto.OrderService/GetOrdersForAccount"
+	OrderService_HasDeliveredProduct_FullMethodName      = "/proto.OrderService/HasDeliveredProduct"
+	OrderService_GetFulfillmentsForSeller_FullMethodName = "/proto.OrderService/GetFulfillmentsForSeller"
+	OrderService_UpdateFulfillment_FullMethodName        = "/proto.OrderService/UpdateFulfillment"
+	OrderService_GetBuyerForSeller_FullMethodName        = "/proto.OrderService/GetBuyerForSeller"
+	OrderService_RequestReturn_FullMethodName            = "/proto.OrderService/RequestReturn"
+	OrderService_ReviewReturn_FullMethodName             = "/proto.OrderService/ReviewReturn"
+	OrderService_ReceiveReturn_FullMethodName            = "/proto.OrderService/ReceiveReturn"
+	OrderService_RefundReturn_FullMethodName             = "/proto.OrderService/RefundReturn"
+	OrderService_GetReturnsForAccount_FullMethodName     = "/proto.OrderService/GetReturnsForAccount"
+	OrderService_GetReturnsForSeller_FullMethodName      = "/proto.OrderService/GetReturnsForSeller"
+	OrderService_EraseAccount_FullMethodName             = "/proto.OrderService/EraseAccount"
 )
 
 // OrderServiceClient is the client API for OrderService service.
@@ -28,8 +40,19 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type OrderServiceClient interface {
 	PostOrder(ctx context.Context, in *PostOrderRequest, opts ...grpc.CallOption) (*PostOrderResponse, error)
-	// rpc GetOrder(GetOrderRequest) returns (GetOrderResponse) {}
+	GetOrder(ctx context.Context, in *GetOrderRequest, opts ...grpc.CallOption) (*GetOrderResponse, error)
 	GetOrdersForAccount(ctx context.Context, in *GetOrderForAccountRequest, opts ...grpc.CallOption) (*GetOrderForAccountResponse, error)
+	HasDeliveredProduct(ctx context.Context, in *HasDeliveredProductRequest, opts ...grpc.CallOption) (*HasDeliveredProductResponse, error)
+	GetFulfillmentsForSeller(ctx context.Context, in *GetFulfillmentsForSellerRequest, opts ...grpc.CallOption) (*GetFulfillmentsForSellerResponse, error)
+	UpdateFulfillment(ctx context.Context, in *UpdateFulfillmentRequest, opts ...grpc.CallOption) (*UpdateFulfillmentResponse, error)
+	GetBuyerForSeller(ctx context.Context, in *GetBuyerForSellerRequest, opts ...grpc.CallOption) (*GetBuyerForSellerResponse, error)
+	RequestReturn(ctx context.Context, in *RequestReturnRequest, opts ...grpc.CallOption) (*OrderReturnResponse, error)
+	ReviewReturn(ctx context.Context, in *ReviewReturnRequest, opts ...grpc.CallOption) (*OrderReturnResponse, error)
+	ReceiveReturn(ctx context.Context, in *ReceiveReturnRequest, opts ...grpc.CallOption) (*OrderReturnResponse, error)
+	RefundReturn(ctx context.Context, in *RefundReturnRequest, opts ...grpc.CallOption) (*OrderReturnResponse, error)
+	GetReturnsForAccount(ctx context.Context, in *GetReturnsForAccountRequest, opts ...grpc.CallOption) (*GetReturnsResponse, error)
+	GetReturnsForSeller(ctx context.Context, in *GetReturnsForSellerRequest, opts ...grpc.CallOption) (*GetReturnsResponse, error)
+	EraseAccount(ctx context.Context, in *EraseAccountRequest, opts ...grpc.CallOption) (*EraseAccountResponse, error)
 }
 
 type orderServiceClient struct {
@@ -50,6 +73,16 @@ func (c *orderServiceClient) PostOrder(ctx context.Context, in *PostOrderRequest
 	return out, nil
 }
 
+func (c *orderServiceClient) GetOrder(ctx context.Context, in *GetOrderRequest, opts ...grpc.CallOption) (*GetOrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetOrderResponse)
+	err := c.cc.Invoke(ctx, OrderService_GetOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *orderServiceClient) GetOrdersForAccount(ctx context.Context, in *GetOrderForAccountRequest, opts ...grpc.CallOption) (*GetOrderForAccountResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetOrderForAccountResponse)
@@ -60,13 +93,134 @@ func (c *orderServiceClient) GetOrdersForAccount(ctx context.Context, in *GetOrd
 	return out, nil
 }
 
+func (c *orderServiceClient) HasDeliveredProduct(ctx context.Context, in *HasDeliveredProductRequest, opts ...grpc.CallOption) (*HasDeliveredProductResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HasDeliveredProductResponse)
+	err := c.cc.Invoke(ctx, OrderService_HasDeliveredProduct_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) GetFulfillmentsForSeller(ctx context.Context, in *GetFulfillmentsForSellerRequest, opts ...grpc.CallOption) (*GetFulfillmentsForSellerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetFulfillmentsForSellerResponse)
+	err := c.cc.Invoke(ctx, OrderService_GetFulfillmentsForSeller_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) UpdateFulfillment(ctx context.Context, in *UpdateFulfillmentRequest, opts ...grpc.CallOption) (*UpdateFulfillmentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateFulfillmentResponse)
+	err := c.cc.Invoke(ctx, OrderService_UpdateFulfillment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) GetBuyerForSeller(ctx context.Context, in *GetBuyerForSellerRequest, opts ...grpc.CallOption) (*GetBuyerForSellerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetBuyerForSellerResponse)
+	err := c.cc.Invoke(ctx, OrderService_GetBuyerForSeller_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) RequestReturn(ctx context.Context, in *RequestReturnRequest, opts ...grpc.CallOption) (*OrderReturnResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderReturnResponse)
+	err := c.cc.Invoke(ctx, OrderService_RequestReturn_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) ReviewReturn(ctx context.Context, in *ReviewReturnRequest, opts ...grpc.CallOption) (*OrderReturnResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderReturnResponse)
+	err := c.cc.Invoke(ctx, OrderService_ReviewReturn_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) ReceiveReturn(ctx context.Context, in *ReceiveReturnRequest, opts ...grpc.CallOption) (*OrderReturnResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderReturnResponse)
+	err := c.cc.Invoke(ctx, OrderService_ReceiveReturn_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) RefundReturn(ctx context.Context, in *RefundReturnRequest, opts ...grpc.CallOption) (*OrderReturnResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OrderReturnResponse)
+	err := c.cc.Invoke(ctx, OrderService_RefundReturn_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) GetReturnsForAccount(ctx context.Context, in *GetReturnsForAccountRequest, opts ...grpc.CallOption) (*GetReturnsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetReturnsResponse)
+	err := c.cc.Invoke(ctx, OrderService_GetReturnsForAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) GetReturnsForSeller(ctx context.Context, in *GetReturnsForSellerRequest, opts ...grpc.CallOption) (*GetReturnsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetReturnsResponse)
+	err := c.cc.Invoke(ctx, OrderService_GetReturnsForSeller_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) EraseAccount(ctx context.Context, in *EraseAccountRequest, opts ...grpc.CallOption) (*EraseAccountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EraseAccountResponse)
+	err := c.cc.Invoke(ctx, OrderService_EraseAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // OrderServiceServer is the server API for OrderService service.
 // All implementations must embed UnimplementedOrderServiceServer
 // for forward compatibility.
 type OrderServiceServer interface {
 	PostOrder(context.Context, *PostOrderRequest) (*PostOrderResponse, error)
-	// rpc GetOrder(GetOrderRequest) returns (GetOrderResponse) {}
+	GetOrder(context.Context, *GetOrderRequest) (*GetOrderResponse, error)
 	GetOrdersForAccount(context.Context, *GetOrderForAccountRequest) (*GetOrderForAccountResponse, error)
+	HasDeliveredProduct(context.Context, *HasDeliveredProductRequest) (*HasDeliveredProductResponse, error)
+	GetFulfillmentsForSeller(context.Context, *GetFulfillmentsForSellerRequest) (*GetFulfillmentsForSellerResponse, error)
+	UpdateFulfillment(context.Context, *UpdateFulfillmentRequest) (*UpdateFulfillmentResponse, error)
+	GetBuyerForSeller(context.Context, *GetBuyerForSellerRequest) (*GetBuyerForSellerResponse, error)
+	RequestReturn(context.Context, *RequestReturnRequest) (*OrderReturnResponse, error)
+	ReviewReturn(context.Context, *ReviewReturnRequest) (*OrderReturnResponse, error)
+	ReceiveReturn(context.Context, *ReceiveReturnRequest) (*OrderReturnResponse, error)
+	RefundReturn(context.Context, *RefundReturnRequest) (*OrderReturnResponse, error)
+	GetReturnsForAccount(context.Context, *GetReturnsForAccountRequest) (*GetReturnsResponse, error)
+	GetReturnsForSeller(context.Context, *GetReturnsForSellerRequest) (*GetReturnsResponse, error)
+	EraseAccount(context.Context, *EraseAccountRequest) (*EraseAccountResponse, error)
 	mustEmbedUnimplementedOrderServiceServer()
 }
 
@@ -80,9 +234,45 @@ type UnimplementedOrderServiceServer struct{}
 func (UnimplementedOrderServiceServer) PostOrder(context.Context, *PostOrderRequest) (*PostOrderResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PostOrder not implemented")
 }
+func (UnimplementedOrderServiceServer) GetOrder(context.Context, *GetOrderRequest) (*GetOrderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOrder not implemented")
+}
 func (UnimplementedOrderServiceServer) GetOrdersForAccount(context.Context, *GetOrderForAccountRequest) (*GetOrderForAccountResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetOrdersForAccount not implemented")
 }
+func (UnimplementedOrderServiceServer) HasDeliveredProduct(context.Context, *HasDeliveredProductRequest) (*HasDeliveredProductResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HasDeliveredProduct not implemented")
+}
+func (UnimplementedOrderServiceServer) GetFulfillmentsForSeller(context.Context, *GetFulfillmentsForSellerRequest) (*GetFulfillmentsForSellerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFulfillmentsForSeller not implemented")
+}
+func (UnimplementedOrderServiceServer) UpdateFulfillment(context.Context, *UpdateFulfillmentRequest) (*UpdateFulfillmentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateFulfillment not implemented")
+}
+func (UnimplementedOrderServiceServer) GetBuyerForSeller(context.Context, *GetBuyerForSellerRequest) (*GetBuyerForSellerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBuyerForSeller not implemented")
+}
+func (UnimplementedOrderServiceServer) RequestReturn(context.Context, *RequestReturnRequest) (*OrderReturnResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestReturn not implemented")
+}
+func (UnimplementedOrderServiceServer) ReviewReturn(context.Context, *ReviewReturnRequest) (*OrderReturnResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReviewReturn not implemented")
+}
+func (UnimplementedOrderServiceServer) ReceiveReturn(context.Context, *ReceiveReturnRequest) (*OrderReturnResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReceiveReturn not implemented")
+}
+func (UnimplementedOrderServiceServer) RefundReturn(context.Context, *RefundReturnRequest) (*OrderReturnResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefundReturn not implemented")
+}
+func (UnimplementedOrderServiceServer) GetReturnsForAccount(context.Context, *GetReturnsForAccountRequest) (*GetReturnsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReturnsForAccount not implemented")
+}
+func (UnimplementedOrderServiceServer) GetReturnsForSeller(context.Context, *GetReturnsForSellerRequest) (*GetReturnsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReturnsForSeller not implemented")
+}
+func (UnimplementedOrderServiceServer) EraseAccount(context.Context, *EraseAccountRequest) (*EraseAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EraseAccount not implemented")
+}
 func (UnimplementedOrderServiceServer) mustEmbedUnimplementedOrderServiceServer() {}
 func (UnimplementedOrderServiceServer) testEmbeddedByValue()                      {}
 
@@ -122,6 +312,24 @@ func _OrderService_PostOrder_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrderService_GetOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).GetOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_GetOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).GetOrder(ctx, req.(*GetOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _OrderService_GetOrdersForAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetOrderForAccountRequest)
 	if err := dec(in); err != nil {
@@ -140,6 +348,204 @@ func _OrderService_GetOrdersForAccount_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrderService_HasDeliveredProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HasDeliveredProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).HasDeliveredProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_HasDeliveredProduct_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).HasDeliveredProduct(ctx, req.(*HasDeliveredProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_GetFulfillmentsForSeller_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFulfillmentsForSellerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).GetFulfillmentsForSeller(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_GetFulfillmentsForSeller_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).GetFulfillmentsForSeller(ctx, req.(*GetFulfillmentsForSellerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_UpdateFulfillment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateFulfillmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).UpdateFulfillment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_UpdateFulfillment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).UpdateFulfillment(ctx, req.(*UpdateFulfillmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_GetBuyerForSeller_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBuyerForSellerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).GetBuyerForSeller(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_GetBuyerForSeller_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).GetBuyerForSeller(ctx, req.(*GetBuyerForSellerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_RequestReturn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestReturnRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).RequestReturn(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_RequestReturn_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).RequestReturn(ctx, req.(*RequestReturnRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_ReviewReturn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReviewReturnRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ReviewReturn(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ReviewReturn_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ReviewReturn(ctx, req.(*ReviewReturnRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_ReceiveReturn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReceiveReturnRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ReceiveReturn(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ReceiveReturn_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ReceiveReturn(ctx, req.(*ReceiveReturnRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_RefundReturn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefundReturnRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).RefundReturn(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_RefundReturn_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).RefundReturn(ctx, req.(*RefundReturnRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_GetReturnsForAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReturnsForAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).GetReturnsForAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_GetReturnsForAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).GetReturnsForAccount(ctx, req.(*GetReturnsForAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_GetReturnsForSeller_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReturnsForSellerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).GetReturnsForSeller(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_GetReturnsForSeller_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).GetReturnsForSeller(ctx, req.(*GetReturnsForSellerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_EraseAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EraseAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).EraseAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_EraseAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).EraseAccount(ctx, req.(*EraseAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // OrderService_ServiceDesc is the grpc.ServiceDesc for OrderService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -151,10 +557,58 @@ var OrderService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "PostOrder",
 			Handler:    _OrderService_PostOrder_Handler,
 		},
+		{
+			MethodName: "GetOrder",
+			Handler:    _OrderService_GetOrder_Handler,
+		},
 		{
 			MethodName: "GetOrdersForAccount",
 			Handler:    _OrderService_GetOrdersForAccount_Handler,
 		},
+		{
+			MethodName: "HasDeliveredProduct",
+			Handler:    _OrderService_HasDeliveredProduct_Handler,
+		},
+		{
+			MethodName: "GetFulfillmentsForSeller",
+			Handler:    _OrderService_GetFulfillmentsForSeller_Handler,
+		},
+		{
+			MethodName: "UpdateFulfillment",
+			Handler:    _OrderService_UpdateFulfillment_Handler,
+		},
+		{
+			MethodName: "GetBuyerForSeller",
+			Handler:    _OrderService_GetBuyerForSeller_Handler,
+		},
+		{
+			MethodName: "RequestReturn",
+			Handler:    _OrderService_RequestReturn_Handler,
+		},
+		{
+			MethodName: "ReviewReturn",
+			Handler:    _OrderService_ReviewReturn_Handler,
+		},
+		{
+			MethodName: "ReceiveReturn",
+			Handler:    _OrderService_ReceiveReturn_Handler,
+		},
+		{
+			MethodName: "RefundReturn",
+			Handler:    _OrderService_RefundReturn_Handler,
+		},
+		{
+			MethodName: "GetReturnsForAccount",
+			Handler:    _OrderService_GetReturnsForAccount_Handler,
+		},
+		{
+			MethodName: "GetReturnsForSeller",
+			Handler:    _OrderService_GetReturnsForSeller_Handler,
+		},
+		{
+			MethodName: "EraseAccount",
+			Handler:    _OrderService_EraseAccount_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "order.proto",
@@ -0,0 +1,115 @@
+package pb
+
+import "github.com/231031/ecom-mcs-grpc/pkg/validate"
+
+// Validation rules of the order requests, enforced by the service and the
+// gateway through the validate interceptors.
+
+const (
+	maxNoteLen     = 500
+	maxCarrierLen  = 100
+	maxTrackingLen = 100
+
+	// the bounds of the status and reason constants of the order package
+	maxOrderStatus  = 3
+	maxReturnReason = 4
+)
+
+func (m *PostOrderRequest_OrderProduct) Validate() error {
+	return validate.Check(
+		validate.Required("productId", m.GetProductId()),
+		validate.Positive("quantity", m.GetQuantity()),
+	)
+}
+
+func (m *PostOrderRequest) Validate() error {
+	return validate.Check(
+		validate.Required("accountId", m.GetAccountId()),
+		validate.MinItems("products", len(m.GetProducts()), 1),
+		validate.Each("products", m.GetProducts()),
+	)
+}
+
+func (m *GetOrderRequest) Validate() error {
+	return validate.Check(
+		validate.Required("id", m.GetId()),
+	)
+}
+
+func (m *GetOrderForAccountRequest) Validate() error {
+	return validate.Check(
+		validate.Required("accountId", m.GetAccountId()),
+	)
+}
+
+func (m *HasDeliveredProductRequest) Validate() error {
+	return validate.Check(
+		validate.Required("accountId", m.GetAccountId()),
+		validate.Required("productId", m.GetProductId()),
+	)
+}
+
+func (m *GetFulfillmentsForSellerRequest) Validate() error {
+	return validate.Check(
+		validate.Required("sellerId", m.GetSellerId()),
+	)
+}
+
+func (m *UpdateFulfillmentRequest) Validate() error {
+	return validate.Check(
+		validate.Required("id", m.GetId()),
+		validate.Required("sellerId", m.GetSellerId()),
+		validate.Between("status", m.GetStatus(), 0, maxOrderStatus),
+		validate.MaxLen("carrier", m.GetCarrier(), maxCarrierLen),
+		validate.MaxLen("trackingNumber", m.GetTrackingNumber(), maxTrackingLen),
+	)
+}
+
+func (m *RequestReturnRequest) Validate() error {
+	return validate.Check(
+		validate.Required("accountId", m.GetAccountId()),
+		validate.Required("orderId", m.GetOrderId()),
+		validate.Required("productId", m.GetProductId()),
+		validate.Positive("quantity", m.GetQuantity()),
+		validate.Between("reason", m.GetReason(), 0, maxReturnReason),
+		validate.MaxLen("note", m.GetNote(), maxNoteLen),
+	)
+}
+
+func (m *ReviewReturnRequest) Validate() error {
+	return validate.Check(
+		validate.Required("id", m.GetId()),
+		validate.Required("sellerId", m.GetSellerId()),
+		validate.MaxLen("note", m.GetNote(), maxNoteLen),
+	)
+}
+
+func (m *ReceiveReturnRequest) Validate() error {
+	return validate.Check(
+		validate.Required("id", m.GetId()),
+		validate.Required("sellerId", m.GetSellerId()),
+		validate.MaxLen("note", m.GetNote(), maxNoteLen),
+	)
+}
+
+// Validate checks a refund, a zero amount refunds the full returned value.
+func (m *RefundReturnRequest) Validate() error {
+	return validate.Check(
+		validate.Required("id", m.GetId()),
+		validate.Required("sellerId", m.GetSellerId()),
+		validate.NonNegative("amount", m.GetAmount()),
+		validate.MaxLen("note", m.GetNote(), maxNoteLen),
+	)
+}
+
+func (m *GetReturnsForAccountRequest) Validate() error {
+	return validate.Check(
+		validate.Required("accountId", m.GetAccountId()),
+	)
+}
+
+func (m *GetReturnsForSellerRequest) Validate() error {
+	return validate.Check(
+		validate.Required("sellerId", m.GetSellerId()),
+	)
+}
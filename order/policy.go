@@ -0,0 +1,52 @@
+package order
+
+import (
+	"github.com/231031/ecom-mcs-grpc/order/pb"
+	"github.com/231031/ecom-mcs-grpc/pkg/auth"
+)
+
+// policies says who may call each method of the service.
+var policies = auth.Policies{
+	pb.OrderService_PostOrder_FullMethodName: auth.OwnedBy(func(r *pb.PostOrderRequest) string {
+		return r.GetAccountId()
+	}, auth.RoleBuyer),
+	pb.OrderService_GetOrder_FullMethodName: auth.Roles(auth.RoleAdmin),
+	pb.OrderService_GetOrdersForAccount_FullMethodName: auth.OwnedBy(func(r *pb.GetOrderForAccountRequest) string {
+		return r.GetAccountId()
+	}, auth.RoleBuyer),
+	pb.OrderService_HasDeliveredProduct_FullMethodName: auth.OwnedBy(func(r *pb.HasDeliveredProductRequest) string {
+		return r.GetAccountId()
+	}, auth.RoleBuyer),
+
+	pb.OrderService_GetFulfillmentsForSeller_FullMethodName: auth.OwnedBy(func(r *pb.GetFulfillmentsForSellerRequest) string {
+		return r.GetSellerId()
+	}, auth.RoleSeller),
+	pb.OrderService_UpdateFulfillment_FullMethodName: auth.OwnedBy(func(r *pb.UpdateFulfillmentRequest) string {
+		return r.GetSellerId()
+	}, auth.RoleSeller),
+	pb.OrderService_GetBuyerForSeller_FullMethodName: auth.OwnedBy(func(r *pb.GetBuyerForSellerRequest) string {
+		return r.GetSellerId()
+	}, auth.RoleSeller),
+
+	pb.OrderService_RequestReturn_FullMethodName: auth.OwnedBy(func(r *pb.RequestReturnRequest) string {
+		return r.GetAccountId()
+	}, auth.RoleBuyer),
+	pb.OrderService_ReviewReturn_FullMethodName: auth.OwnedBy(func(r *pb.ReviewReturnRequest) string {
+		return r.GetSellerId()
+	}, auth.RoleSeller),
+	pb.OrderService_ReceiveReturn_FullMethodName: auth.OwnedBy(func(r *pb.ReceiveReturnRequest) string {
+		return r.GetSellerId()
+	}, auth.RoleSeller),
+	pb.OrderService_RefundReturn_FullMethodName: auth.OwnedBy(func(r *pb.RefundReturnRequest) string {
+		return r.GetSellerId()
+	}, auth.RoleSeller),
+	pb.OrderService_GetReturnsForAccount_FullMethodName: auth.OwnedBy(func(r *pb.GetReturnsForAccountRequest) string {
+		return r.GetAccountId()
+	}, auth.RoleBuyer),
+	pb.OrderService_GetReturnsForSeller_FullMethodName: auth.OwnedBy(func(r *pb.GetReturnsForSellerRequest) string {
+		return r.GetSellerId()
+	}, auth.RoleSeller),
+
+	// the authentication service erases the account once its deletion is due
+	pb.OrderService_EraseAccount_FullMethodName: auth.Internal(),
+}
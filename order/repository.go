@@ -3,6 +3,7 @@ package order
 import (
 	"context"
 	"database/sql"
+	"errors"
 
 	"github.com/lib/pq"
 )
@@ -10,7 +11,24 @@ import (
 type Repository interface {
 	Close()
 	PutOrder(ctx context.Context, o Order) error
+	DeleteOrder(ctx context.Context, id string) error
+	GetOrderByID(ctx context.Context, id string) (*Order, error)
 	GetOrdersForAccount(ctx context.Context, accountID string) ([]Order, error)
+	GetDeliveredOrderID(ctx context.Context, accountID, productID string) (string, error)
+
+	GetFulfillmentByID(ctx context.Context, id string) (*Fulfillment, error)
+	GetFulfillmentsForSeller(ctx context.Context, sellerID string, skip uint64, take uint64) ([]Fulfillment, error)
+	UpdateFulfillment(ctx context.Context, id string, update func(f *Fulfillment) error) (*Fulfillment, error)
+	HasSoldTo(ctx context.Context, sellerID, accountID string) (bool, error)
+
+	GetOrderLine(ctx context.Context, orderID, productID string) (*OrderLine, error)
+	PutReturn(ctx context.Context, ret Return) error
+	GetReturnByID(ctx context.Context, id string) (*Return, error)
+	GetReturnsForAccount(ctx context.Context, accountID string) ([]Return, error)
+	GetReturnsForSeller(ctx context.Context, sellerID string, skip uint64, take uint64) ([]Return, error)
+	UpdateReturn(ctx context.Context, id string, note string, update func(ret *Return) error) (*Return, error)
+
+	AnonymizeAccount(ctx context.Context, accountID string) error
 }
 
 type postgresRepository struct {
@@ -57,13 +75,17 @@ func (r *postgresRepository) PutOrder(ctx context.Context, o Order) error {
 		return err
 	}
 
-	stmt, err := tx.PrepareContext(ctx, pq.CopyIn("order_products", "order_id", "product_id", "quantity"))
+	// snapshot the product as it was sold, so history does not follow catalog edits
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn(
+		"order_products",
+		"order_id", "product_id", "quantity", "name", "description", "price", "seller_id",
+	))
 	if err != nil {
 		return err
 	}
 
 	for _, p := range o.Products {
-		_, err = stmt.ExecContext(ctx, o.ID, p.ID, p.Quantity)
+		_, err = stmt.ExecContext(ctx, o.ID, p.ID, p.Quantity, p.Name, p.Description, p.Price, p.SellerID)
 		if err != nil {
 			return err
 		}
@@ -75,9 +97,52 @@ func (r *postgresRepository) PutOrder(ctx context.Context, o Order) error {
 	}
 	stmt.Close()
 
+	for _, f := range o.Fulfillments {
+		_, err = tx.ExecContext(
+			ctx,
+			`INSERT INTO order_fulfillments(id, order_id, seller_id, status, payout_amount, created_at, updated_at)
+			VALUES($1, $2, $3, $4, $5, $6, $7)`,
+			f.ID, f.OrderID, f.SellerID, f.Status, f.PayoutAmount, f.CreatedAt, f.UpdatedAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// DeleteOrder takes back an order that was never confirmed, its products and
+// fulfillments go with it.
+func (r *postgresRepository) DeleteOrder(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM orders WHERE id = $1", id)
+	return err
+}
+
+// GetOrderByID loads the orders of the account the order belongs to, so the
+// order comes with its fulfillments the same way as in the account history.
+func (r *postgresRepository) GetOrderByID(ctx context.Context, id string) (*Order, error) {
+	var accountID string
+	err := r.db.QueryRowContext(ctx, "SELECT account_id FROM orders WHERE id = $1", id).Scan(&accountID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, err
+	}
+
+	orders, err := r.GetOrdersForAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range orders {
+		if o.ID == id {
+			return &o, nil
+		}
+	}
+	return nil, ErrOrderNotFound
+}
+
 func (r *postgresRepository) GetOrdersForAccount(ctx context.Context, accountID string) ([]Order, error) {
 	rows, err := r.db.QueryContext(
 		ctx,
@@ -86,8 +151,15 @@ func (r *postgresRepository) GetOrdersForAccount(ctx context.Context, accountID
 		o.created_at,
 		o.account_id,
 		o.total_price::money::numeric::float8,
+		o.status,
+		o.payment_status,
+		o.refunded_amount::money::numeric::float8,
 		op.product_id,
-		op.quantity
+		op.quantity,
+		op.name,
+		op.description,
+		op.price::money::numeric::float8,
+		op.seller_id
 		FROM orders o JOIN order_products op ON (o.id = op.order_id)
 		WHERE o.account_id = $1
 		ORDER BY o.id`,
@@ -111,39 +183,50 @@ func (r *postgresRepository) GetOrdersForAccount(ctx context.Context, accountID
 			&order.CreatedAt,
 			&order.AccountID,
 			&order.TotalPrice,
+			&order.Status,
+			&order.PaymentStatus,
+			&order.RefundedAmount,
 			&orderedProduct.ID,
 			&orderedProduct.Quantity,
+			&orderedProduct.Name,
+			&orderedProduct.Description,
+			&orderedProduct.Price,
+			&orderedProduct.SellerID,
 		); err != nil {
 			return nil, err
 		}
 		// Scan order
 		if lastOrder.ID != "" && lastOrder.ID != order.ID {
 			newOrder := Order{
-				ID:         lastOrder.ID,
-				AccountID:  lastOrder.AccountID,
-				CreatedAt:  lastOrder.CreatedAt,
-				TotalPrice: lastOrder.TotalPrice,
-				Products:   products,
+				ID:             lastOrder.ID,
+				AccountID:      lastOrder.AccountID,
+				CreatedAt:      lastOrder.CreatedAt,
+				TotalPrice:     lastOrder.TotalPrice,
+				Status:         lastOrder.Status,
+				PaymentStatus:  lastOrder.PaymentStatus,
+				RefundedAmount: lastOrder.RefundedAmount,
+				Products:       products,
 			}
 			orders = append(orders, newOrder)
+			products = []OrderedProduct{}
 		}
 		// Scan products
-		products = append(products, OrderedProduct{
-			ID:       orderedProduct.ID,
-			Quantity: orderedProduct.Quantity,
-		})
+		products = append(products, *orderedProduct)
 
 		*lastOrder = *order
 	}
 
 	// Add last order (or first :D)
-	if lastOrder.ID != "" && lastOrder.ID != order.ID {
+	if lastOrder.ID != "" {
 		newOrder := Order{
-			ID:         lastOrder.ID,
-			AccountID:  lastOrder.AccountID,
-			CreatedAt:  lastOrder.CreatedAt,
-			TotalPrice: lastOrder.TotalPrice,
-			Products:   products,
+			ID:             lastOrder.ID,
+			AccountID:      lastOrder.AccountID,
+			CreatedAt:      lastOrder.CreatedAt,
+			TotalPrice:     lastOrder.TotalPrice,
+			Status:         lastOrder.Status,
+			PaymentStatus:  lastOrder.PaymentStatus,
+			RefundedAmount: lastOrder.RefundedAmount,
+			Products:       products,
 		}
 		orders = append(orders, newOrder)
 	}
@@ -152,5 +235,348 @@ func (r *postgresRepository) GetOrdersForAccount(ctx context.Context, accountID
 		return nil, err
 	}
 
+	fulfillments, err := r.getFulfillmentsForAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range orders {
+		for _, f := range fulfillments[orders[i].ID] {
+			for _, p := range orders[i].Products {
+				if p.SellerID == f.SellerID {
+					f.Products = append(f.Products, p)
+				}
+			}
+			orders[i].Fulfillments = append(orders[i].Fulfillments, f)
+		}
+	}
+
 	return orders, nil
 }
+
+func (r *postgresRepository) GetDeliveredOrderID(ctx context.Context, accountID, productID string) (string, error) {
+	var orderID string
+	err := r.db.QueryRowContext(
+		ctx,
+		`SELECT o.id
+		FROM orders o
+		JOIN order_products op ON (op.order_id = o.id)
+		JOIN order_fulfillments f ON (f.order_id = op.order_id AND f.seller_id = op.seller_id)
+		WHERE o.account_id = $1 AND op.product_id = $2 AND f.status = $3
+		ORDER BY o.created_at DESC
+		LIMIT 1`,
+		accountID, productID, OrderStatusDelivered,
+	).Scan(&orderID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return orderID, nil
+}
+
+func (r *postgresRepository) GetFulfillmentByID(ctx context.Context, id string) (*Fulfillment, error) {
+	row := r.db.QueryRowContext(
+		ctx,
+		`SELECT `+fulfillmentColumns+`
+		FROM order_fulfillments f
+		WHERE f.id = $1`,
+		id,
+	)
+
+	f := &Fulfillment{}
+	if err := scanFulfillment(row, f); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrFulfillmentNotFound
+		}
+		return nil, err
+	}
+
+	products, err := r.getSellerProducts(ctx, f.SellerID, []string{f.OrderID})
+	if err != nil {
+		return nil, err
+	}
+	f.Products = products[f.OrderID]
+
+	return f, nil
+}
+
+func (r *postgresRepository) GetFulfillmentsForSeller(ctx context.Context, sellerID string, skip uint64, take uint64) ([]Fulfillment, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT `+fulfillmentColumns+`
+		FROM order_fulfillments f
+		WHERE f.seller_id = $1
+		ORDER BY f.created_at DESC
+		LIMIT $2 OFFSET $3`,
+		sellerID, take, skip,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	fulfillments := []Fulfillment{}
+	orderIDs := []string{}
+	for rows.Next() {
+		f := Fulfillment{}
+		if err = scanFulfillment(rows, &f); err != nil {
+			return nil, err
+		}
+		fulfillments = append(fulfillments, f)
+		orderIDs = append(orderIDs, f.OrderID)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	products, err := r.getSellerProducts(ctx, sellerID, orderIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range fulfillments {
+		fulfillments[i].Products = products[fulfillments[i].OrderID]
+	}
+
+	return fulfillments, nil
+}
+
+// UpdateFulfillment locks the fulfillment, lets update check and change it
+// and writes it back in the same transaction, so concurrent updates are
+// checked one after the other. An error of update rolls back.
+func (r *postgresRepository) UpdateFulfillment(ctx context.Context, id string, update func(f *Fulfillment) error) (*Fulfillment, error) {
+	f, err := r.updateFulfillment(ctx, id, update)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := r.getSellerProducts(ctx, f.SellerID, []string{f.OrderID})
+	if err != nil {
+		return nil, err
+	}
+	f.Products = products[f.OrderID]
+	return f, nil
+}
+
+func (r *postgresRepository) updateFulfillment(ctx context.Context, id string, update func(f *Fulfillment) error) (f *Fulfillment, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	row := tx.QueryRowContext(
+		ctx,
+		`SELECT `+fulfillmentColumns+`
+		FROM order_fulfillments f
+		WHERE f.id = $1
+		FOR UPDATE`,
+		id,
+	)
+	f = &Fulfillment{}
+	if err = scanFulfillment(row, f); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = ErrFulfillmentNotFound
+		}
+		return nil, err
+	}
+	if err = update(f); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		`UPDATE order_fulfillments
+		SET status = $2, carrier = $3, tracking_number = $4, updated_at = $5
+		WHERE id = $1`,
+		f.ID, f.Status, f.Carrier, f.TrackingNumber, f.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// keep the buyer-facing order status in line with its fulfillments
+	rows, err := tx.QueryContext(ctx, "SELECT status FROM order_fulfillments WHERE order_id = $1", f.OrderID)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := []int32{}
+	for rows.Next() {
+		var st int32
+		if err = rows.Scan(&st); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		statuses = append(statuses, st)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"UPDATE orders SET status = $2, updated_at = $3 WHERE id = $1",
+		f.OrderID, deriveOrderStatus(statuses), f.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// HasSoldTo tells whether the account ever ordered from the seller.
+func (r *postgresRepository) HasSoldTo(ctx context.Context, sellerID, accountID string) (bool, error) {
+	var sold bool
+	err := r.db.QueryRowContext(
+		ctx,
+		`SELECT EXISTS(
+			SELECT 1
+			FROM order_fulfillments f
+			JOIN orders o ON (o.id = f.order_id)
+			WHERE f.seller_id = $1 AND o.account_id = $2
+		)`,
+		sellerID, accountID,
+	).Scan(&sold)
+	return sold, err
+}
+
+const fulfillmentColumns = `f.id,
+		f.order_id,
+		(SELECT o.account_id FROM orders o WHERE o.id = f.order_id),
+		f.seller_id,
+		f.status,
+		f.carrier,
+		f.tracking_number,
+		f.payout_amount::money::numeric::float8,
+		f.created_at,
+		f.updated_at`
+
+// AnonymizeAccount detaches the orders and returns of a deleted account from
+// it. The sellers keep them for their books, without the buyer and without
+// what the buyer wrote.
+func (r *postgresRepository) AnonymizeAccount(ctx context.Context, accountID string) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	_, err = tx.ExecContext(
+		ctx,
+		`UPDATE order_return_events SET note = ''
+		WHERE status = $2 AND return_id IN (SELECT id FROM order_returns WHERE account_id = $1)`,
+		accountID, ReturnStatusRequested,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE order_returns SET account_id = '', note = '' WHERE account_id = $1", accountID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE orders SET account_id = '' WHERE account_id = $1", accountID)
+	return err
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanFulfillment(row scanner, f *Fulfillment) error {
+	return row.Scan(
+		&f.ID,
+		&f.OrderID,
+		&f.AccountID,
+		&f.SellerID,
+		&f.Status,
+		&f.Carrier,
+		&f.TrackingNumber,
+		&f.PayoutAmount,
+		&f.CreatedAt,
+		&f.UpdatedAt,
+	)
+}
+
+func (r *postgresRepository) getFulfillmentsForAccount(ctx context.Context, accountID string) (map[string][]Fulfillment, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT `+fulfillmentColumns+`
+		FROM order_fulfillments f JOIN orders o ON (o.id = f.order_id)
+		WHERE o.account_id = $1
+		ORDER BY f.order_id`,
+		accountID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	fulfillments := map[string][]Fulfillment{}
+	for rows.Next() {
+		f := Fulfillment{}
+		if err = scanFulfillment(rows, &f); err != nil {
+			return nil, err
+		}
+		fulfillments[f.OrderID] = append(fulfillments[f.OrderID], f)
+	}
+
+	return fulfillments, rows.Err()
+}
+
+// getSellerProducts returns the seller's lines of the given orders keyed by order id.
+func (r *postgresRepository) getSellerProducts(ctx context.Context, sellerID string, orderIDs []string) (map[string][]OrderedProduct, error) {
+	products := map[string][]OrderedProduct{}
+	if len(orderIDs) == 0 {
+		return products, nil
+	}
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT
+		order_id,
+		product_id,
+		quantity,
+		name,
+		description,
+		price::money::numeric::float8,
+		seller_id
+		FROM order_products
+		WHERE seller_id = $1 AND order_id = ANY($2)`,
+		sellerID, pq.Array(orderIDs),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var orderID string
+		p := OrderedProduct{}
+		if err = rows.Scan(&orderID, &p.ID, &p.Quantity, &p.Name, &p.Description, &p.Price, &p.SellerID); err != nil {
+			return nil, err
+		}
+		products[orderID] = append(products[orderID], p)
+	}
+
+	return products, rows.Err()
+}
@@ -0,0 +1,358 @@
+package order
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const returnColumns = `r.id,
+		r.order_id,
+		r.fulfillment_id,
+		r.account_id,
+		r.seller_id,
+		r.product_id,
+		r.quantity,
+		r.reason,
+		r.note,
+		r.status,
+		r.refund_amount::money::numeric::float8,
+		r.created_at,
+		r.updated_at`
+
+func scanReturn(row scanner, ret *Return) error {
+	return row.Scan(
+		&ret.ID,
+		&ret.OrderID,
+		&ret.FulfillmentID,
+		&ret.AccountID,
+		&ret.SellerID,
+		&ret.ProductID,
+		&ret.Quantity,
+		&ret.Reason,
+		&ret.Note,
+		&ret.Status,
+		&ret.RefundAmount,
+		&ret.CreatedAt,
+		&ret.UpdatedAt,
+	)
+}
+
+func (r *postgresRepository) GetOrderLine(ctx context.Context, orderID, productID string) (*OrderLine, error) {
+	line := &OrderLine{}
+	err := r.db.QueryRowContext(
+		ctx,
+		`SELECT
+		o.id,
+		o.account_id,
+		f.id,
+		f.status,
+		op.product_id,
+		op.quantity,
+		op.name,
+		op.description,
+		op.price::money::numeric::float8,
+		op.seller_id,
+		COALESCE((
+			SELECT SUM(r.quantity) FROM order_returns r
+			WHERE r.order_id = op.order_id AND r.product_id = op.product_id AND r.status <> $3
+		), 0)
+		FROM order_products op
+		JOIN orders o ON (o.id = op.order_id)
+		JOIN order_fulfillments f ON (f.order_id = op.order_id AND f.seller_id = op.seller_id)
+		WHERE op.order_id = $1 AND op.product_id = $2`,
+		orderID, productID, ReturnStatusRejected,
+	).Scan(
+		&line.OrderID,
+		&line.AccountID,
+		&line.FulfillmentID,
+		&line.FulfillmentStatus,
+		&line.Product.ID,
+		&line.Product.Quantity,
+		&line.Product.Name,
+		&line.Product.Description,
+		&line.Product.Price,
+		&line.Product.SellerID,
+		&line.ReturnedQuantity,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrOrderLineNotFound
+		}
+		return nil, err
+	}
+
+	return line, nil
+}
+
+func (r *postgresRepository) PutReturn(ctx context.Context, ret Return) (err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	// the order line is locked so that parallel requests count each other's
+	// returns against the ordered quantity
+	var ordered, returned uint32
+	err = tx.QueryRowContext(
+		ctx,
+		`SELECT quantity FROM order_products
+		WHERE order_id = $1 AND product_id = $2
+		FOR UPDATE`,
+		ret.OrderID, ret.ProductID,
+	).Scan(&ordered)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = ErrOrderLineNotFound
+		}
+		return err
+	}
+	err = tx.QueryRowContext(
+		ctx,
+		`SELECT COALESCE(SUM(quantity), 0) FROM order_returns
+		WHERE order_id = $1 AND product_id = $2 AND status <> $3`,
+		ret.OrderID, ret.ProductID, ReturnStatusRejected,
+	).Scan(&returned)
+	if err != nil {
+		return err
+	}
+	if returned+ret.Quantity > ordered {
+		return ErrInvalidReturnQuantity
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		`INSERT INTO order_returns(
+			id, order_id, fulfillment_id, account_id, seller_id, product_id,
+			quantity, reason, note, status, refund_amount, created_at, updated_at
+		) VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
+		ret.ID, ret.OrderID, ret.FulfillmentID, ret.AccountID, ret.SellerID, ret.ProductID,
+		ret.Quantity, ret.Reason, ret.Note, ret.Status, ret.RefundAmount, ret.CreatedAt, ret.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return insertReturnEvent(ctx, tx, ret.ID, ret.Status, ret.Note, ret.CreatedAt)
+}
+
+func (r *postgresRepository) GetReturnByID(ctx context.Context, id string) (*Return, error) {
+	row := r.db.QueryRowContext(
+		ctx,
+		`SELECT `+returnColumns+`
+		FROM order_returns r
+		WHERE r.id = $1`,
+		id,
+	)
+
+	ret := &Return{}
+	if err := scanReturn(row, ret); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrReturnNotFound
+		}
+		return nil, err
+	}
+
+	timelines, err := r.getReturnTimelines(ctx, []string{ret.ID})
+	if err != nil {
+		return nil, err
+	}
+	ret.Timeline = timelines[ret.ID]
+
+	return ret, nil
+}
+
+func (r *postgresRepository) GetReturnsForAccount(ctx context.Context, accountID string) ([]Return, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT `+returnColumns+`
+		FROM order_returns r
+		WHERE r.account_id = $1
+		ORDER BY r.created_at DESC`,
+		accountID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.collectReturns(ctx, rows)
+}
+
+func (r *postgresRepository) GetReturnsForSeller(ctx context.Context, sellerID string, skip uint64, take uint64) ([]Return, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT `+returnColumns+`
+		FROM order_returns r
+		WHERE r.seller_id = $1
+		ORDER BY r.created_at DESC
+		LIMIT $2 OFFSET $3`,
+		sellerID, take, skip,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return r.collectReturns(ctx, rows)
+}
+
+// UpdateReturn locks the return, lets update check and change it and writes
+// it back in the same transaction, so concurrent moves are checked one after
+// the other. A return moved to refunded credits its amount to the order. An
+// error of update rolls back.
+func (r *postgresRepository) UpdateReturn(ctx context.Context, id string, note string, update func(ret *Return) error) (*Return, error) {
+	ret, err := r.updateReturn(ctx, id, note, update)
+	if err != nil {
+		return nil, err
+	}
+
+	timelines, err := r.getReturnTimelines(ctx, []string{ret.ID})
+	if err != nil {
+		return nil, err
+	}
+	ret.Timeline = timelines[ret.ID]
+	return ret, nil
+}
+
+func (r *postgresRepository) updateReturn(ctx context.Context, id string, note string, update func(ret *Return) error) (ret *Return, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	row := tx.QueryRowContext(
+		ctx,
+		`SELECT `+returnColumns+`
+		FROM order_returns r
+		WHERE r.id = $1
+		FOR UPDATE`,
+		id,
+	)
+	ret = &Return{}
+	if err = scanReturn(row, ret); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = ErrReturnNotFound
+		}
+		return nil, err
+	}
+	if err = update(ret); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"UPDATE order_returns SET status = $2, refund_amount = $3, updated_at = $4 WHERE id = $1",
+		ret.ID, ret.Status, ret.RefundAmount, ret.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = insertReturnEvent(ctx, tx, ret.ID, ret.Status, note, ret.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if ret.Status != ReturnStatusRefunded {
+		return ret, nil
+	}
+
+	// the order is refunded in full once refunds reach its total price
+	_, err = tx.ExecContext(
+		ctx,
+		`UPDATE orders SET
+		refunded_amount = refunded_amount + $2,
+		payment_status = CASE WHEN refunded_amount + $2 >= total_price THEN $3 ELSE $4 END,
+		updated_at = $5
+		WHERE id = $1`,
+		ret.OrderID, ret.RefundAmount, PaymentStatusRefunded, PaymentStatusPartiallyRefunded, ret.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (r *postgresRepository) collectReturns(ctx context.Context, rows *sql.Rows) ([]Return, error) {
+	returns := []Return{}
+	ids := []string{}
+	for rows.Next() {
+		ret := Return{}
+		if err := scanReturn(rows, &ret); err != nil {
+			return nil, err
+		}
+		returns = append(returns, ret)
+		ids = append(ids, ret.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	timelines, err := r.getReturnTimelines(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range returns {
+		returns[i].Timeline = timelines[returns[i].ID]
+	}
+
+	return returns, nil
+}
+
+func (r *postgresRepository) getReturnTimelines(ctx context.Context, ids []string) (map[string][]ReturnEvent, error) {
+	timelines := map[string][]ReturnEvent{}
+	if len(ids) == 0 {
+		return timelines, nil
+	}
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT return_id, status, note, created_at
+		FROM order_return_events
+		WHERE return_id = ANY($1)
+		ORDER BY created_at`,
+		pq.Array(ids),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var returnID string
+		e := ReturnEvent{}
+		if err = rows.Scan(&returnID, &e.Status, &e.Note, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		timelines[returnID] = append(timelines[returnID], e)
+	}
+
+	return timelines, rows.Err()
+}
+
+func insertReturnEvent(ctx context.Context, tx *sql.Tx, returnID string, status int32, note string, at time.Time) error {
+	_, err := tx.ExecContext(
+		ctx,
+		"INSERT INTO order_return_events(return_id, status, note, created_at) VALUES($1, $2, $3, $4)",
+		returnID, status, note, at,
+	)
+	return err
+}
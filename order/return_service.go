@@ -0,0 +1,183 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/segmentio/ksuid"
+)
+
+var (
+	ErrReturnNotFound          = errors.New("return not found")
+	ErrOrderLineNotFound       = errors.New("order line not found")
+	ErrLineNotDelivered        = errors.New("order line is not delivered")
+	ErrInvalidReturnQuantity   = errors.New("return quantity exceeds returnable quantity")
+	ErrInvalidReturnReason     = errors.New("invalid return reason")
+	ErrInvalidReturnTransition = errors.New("invalid return status transition")
+	ErrInvalidRefundAmount     = errors.New("refund amount exceeds the returned value")
+)
+
+// Return is a buyer's request to send back part of a delivered order line.
+type Return struct {
+	ID            string        `json:"id"`
+	OrderID       string        `json:"order_id"`
+	FulfillmentID string        `json:"fulfillment_id"`
+	AccountID     string        `json:"account_id"`
+	SellerID      string        `json:"seller_id"`
+	ProductID     string        `json:"product_id"`
+	Quantity      uint32        `json:"quantity"`
+	Reason        int32         `json:"reason"`
+	Note          string        `json:"note"`
+	Status        int32         `json:"status"`
+	RefundAmount  float64       `json:"refund_amount"`
+	Timeline      []ReturnEvent `json:"timeline"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+type ReturnEvent struct {
+	Status    int32     `json:"status"`
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OrderLine is an ordered product together with what is needed to decide
+// whether it can still be returned.
+type OrderLine struct {
+	OrderID           string
+	AccountID         string
+	FulfillmentID     string
+	FulfillmentStatus int32
+	Product           OrderedProduct
+	ReturnedQuantity  uint32
+}
+
+func (s *orderService) RequestReturn(ctx context.Context, accountID, orderID, productID string, quantity uint32, reason int32, note string) (*Return, error) {
+	if reason < ReturnReasonDamaged || reason > ReturnReasonOther {
+		return nil, ErrInvalidReturnReason
+	}
+
+	line, err := s.repository.GetOrderLine(ctx, orderID, productID)
+	if err != nil {
+		return nil, err
+	}
+	if line.AccountID != accountID {
+		return nil, ErrOrderLineNotFound
+	}
+	if line.FulfillmentStatus != OrderStatusDelivered {
+		return nil, ErrLineNotDelivered
+	}
+	if quantity == 0 || line.ReturnedQuantity+quantity > line.Product.Quantity {
+		return nil, ErrInvalidReturnQuantity
+	}
+
+	now := time.Now().UTC()
+	ret := &Return{
+		ID:            ksuid.New().String(),
+		OrderID:       orderID,
+		FulfillmentID: line.FulfillmentID,
+		AccountID:     accountID,
+		SellerID:      line.Product.SellerID,
+		ProductID:     productID,
+		Quantity:      quantity,
+		Reason:        reason,
+		Note:          note,
+		Status:        ReturnStatusRequested,
+		RefundAmount:  line.Product.Price * float64(quantity),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	ret.Timeline = []ReturnEvent{{Status: ret.Status, Note: note, CreatedAt: now}}
+
+	if err := s.repository.PutReturn(ctx, *ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (s *orderService) ReviewReturn(ctx context.Context, id, sellerID string, approve bool, note string) (*Return, error) {
+	status := int32(ReturnStatusRejected)
+	if approve {
+		status = ReturnStatusApproved
+	}
+	return s.moveReturn(ctx, id, sellerID, ReturnStatusRequested, status, note)
+}
+
+// ReceiveReturn calls restock with the approved return before saving it as
+// received, the return stays approved until the restock succeeds so that it
+// can be received again.
+func (s *orderService) ReceiveReturn(ctx context.Context, id, sellerID string, note string, restock func(ret *Return) error) (*Return, error) {
+	ret, err := s.getSellerReturn(ctx, id, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	if ret.Status != ReturnStatusApproved {
+		return nil, ErrInvalidReturnTransition
+	}
+	if err := restock(ret); err != nil {
+		return nil, err
+	}
+	return s.moveReturn(ctx, id, sellerID, ReturnStatusApproved, ReturnStatusReceived, note)
+}
+
+func (s *orderService) RefundReturn(ctx context.Context, id, sellerID string, amount float64, note string) (*Return, error) {
+	return s.repository.UpdateReturn(ctx, id, note, func(ret *Return) error {
+		if ret.SellerID != sellerID {
+			return ErrReturnNotFound
+		}
+		if ret.Status != ReturnStatusReceived {
+			return ErrInvalidReturnTransition
+		}
+
+		// a zero amount refunds the full value of the returned items
+		if amount < 0 || amount > ret.RefundAmount {
+			return ErrInvalidRefundAmount
+		}
+		if amount == 0 {
+			amount = ret.RefundAmount
+		}
+
+		ret.Status = ReturnStatusRefunded
+		ret.RefundAmount = amount
+		ret.UpdatedAt = time.Now().UTC()
+		return nil
+	})
+}
+
+func (s *orderService) GetReturnsForAccount(ctx context.Context, accountID string) ([]Return, error) {
+	return s.repository.GetReturnsForAccount(ctx, accountID)
+}
+
+func (s *orderService) GetReturnsForSeller(ctx context.Context, sellerID string, skip uint64, take uint64) ([]Return, error) {
+	if take > 100 || (skip == 0 && take == 0) {
+		take = 100
+	}
+	return s.repository.GetReturnsForSeller(ctx, sellerID, skip, take)
+}
+
+func (s *orderService) moveReturn(ctx context.Context, id, sellerID string, from, to int32, note string) (*Return, error) {
+	return s.repository.UpdateReturn(ctx, id, note, func(ret *Return) error {
+		if ret.SellerID != sellerID {
+			return ErrReturnNotFound
+		}
+		if ret.Status != from {
+			return ErrInvalidReturnTransition
+		}
+
+		ret.Status = to
+		ret.UpdatedAt = time.Now().UTC()
+		return nil
+	})
+}
+
+func (s *orderService) getSellerReturn(ctx context.Context, id, sellerID string) (*Return, error) {
+	ret, err := s.repository.GetReturnByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if ret.SellerID != sellerID {
+		return nil, ErrReturnNotFound
+	}
+	return ret, nil
+}
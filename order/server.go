@@ -6,35 +6,76 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"strconv"
+	"time"
 
 	"github.com/231031/ecom-mcs-grpc/account"
 	"github.com/231031/ecom-mcs-grpc/catalog"
+	"github.com/231031/ecom-mcs-grpc/notification"
 	"github.com/231031/ecom-mcs-grpc/order/pb"
+	"github.com/231031/ecom-mcs-grpc/pkg/audit"
+	"github.com/231031/ecom-mcs-grpc/pkg/auth"
+	"github.com/231031/ecom-mcs-grpc/pkg/grpcerr"
+	"github.com/231031/ecom-mcs-grpc/pkg/idempotency"
+	"github.com/231031/ecom-mcs-grpc/pkg/validate"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 )
 
 var (
-	ErrInvalidOrder   = errors.New("failed to create order")
-	ErrInvalidAccount = errors.New("account not found")
+	ErrInvalidOrder     = errors.New("failed to create order")
+	ErrInvalidAccount   = errors.New("account not found")
+	ErrProductsNotFound = errors.New("products not found")
+	ErrOrderNotFound    = errors.New("order not found")
+	ErrBuyerNotFound    = errors.New("buyer not found")
 )
 
+// how often placing an order tries to turn its reservation into a sale
+const (
+	commitAttempts = 3
+	commitBackoff  = 200 * time.Millisecond
+)
+
+var errorMappings = []grpcerr.Mapping{
+	grpcerr.NotFound(ErrInvalidAccount),
+	grpcerr.NotFound(ErrProductsNotFound).OnField("products"),
+	grpcerr.NotFound(ErrOrderNotFound),
+	grpcerr.NotFound(ErrBuyerNotFound),
+	grpcerr.NotFound(ErrFulfillmentNotFound),
+	grpcerr.Conflict(ErrInvalidStatusTransition),
+	grpcerr.NotFound(ErrReturnNotFound),
+	grpcerr.NotFound(ErrOrderLineNotFound),
+	grpcerr.Conflict(ErrLineNotDelivered),
+	grpcerr.Validation(ErrInvalidReturnQuantity, "quantity"),
+	grpcerr.Validation(ErrInvalidReturnReason, "reason"),
+	grpcerr.Conflict(ErrInvalidReturnTransition),
+	grpcerr.Validation(ErrInvalidRefundAmount, "amount"),
+}
+
 type grpcServer struct {
-	service       Service
-	accountClient *account.Client
-	catalogClient *catalog.Client
+	service            Service
+	accountClient      *account.Client
+	catalogClient      *catalog.Client
+	notificationClient *notification.Client
 	pb.UnimplementedOrderServiceServer
 }
 
-func ListenGRPC(s Service, accountURL, catalogURL string, port int) error {
-	accountClient, err := account.NewClient(accountURL)
+func ListenGRPC(s Service, authn *auth.Authenticator, idempotencyStore idempotency.Store, auditStore audit.Store, accountURL, catalogURL, notificationURL string, port int) error {
+	accountClient, err := account.NewClient(accountURL, authn.DialOption())
+	if err != nil {
+		return err
+	}
+
+	catalogClient, err := catalog.NewClient(catalogURL, authn.DialOption())
 	if err != nil {
+		accountClient.Close()
 		return err
 	}
 
-	catalogClient, err := catalog.NewClient(catalogURL)
+	notificationClient, err := notification.NewClient(notificationURL, authn.DialOption())
 	if err != nil {
 		accountClient.Close()
+		catalogClient.Close()
 		return err
 	}
 
@@ -42,14 +83,31 @@ func ListenGRPC(s Service, accountURL, catalogURL string, port int) error {
 	if err != nil {
 		accountClient.Close()
 		catalogClient.Close()
+		notificationClient.Close()
 		return err
 	}
 
-	serve := grpc.NewServer()
+	serve := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			grpcerr.UnaryServerInterceptor(errorMappings...),
+			authn.UnaryServerInterceptor(policies),
+			validate.UnaryServerInterceptor(),
+			idempotency.UnaryServerInterceptor(
+				idempotencyStore,
+				idempotency.DefaultTTL,
+				pb.OrderService_PostOrder_FullMethodName,
+			),
+			audit.UnaryServerInterceptor(
+				auditStore,
+				pb.OrderService_GetOrder_FullMethodName,
+			),
+		),
+	)
 	grpcServiceServer := &grpcServer{
-		service:       s,
-		accountClient: accountClient,
-		catalogClient: catalogClient,
+		service:            s,
+		accountClient:      accountClient,
+		catalogClient:      catalogClient,
+		notificationClient: notificationClient,
 	}
 
 	pb.RegisterOrderServiceServer(serve, grpcServiceServer)
@@ -71,19 +129,27 @@ func (s *grpcServer) PostOrder(ctx context.Context, r *pb.PostOrderRequest) (*pb
 		}
 	}
 
-	products, err := s.catalogClient.GetProducts(ctx, 0, 0, productIDs, "")
+	products, err := s.catalogClient.GetProducts(ctx, 0, 0, productIDs, "", catalog.ProductFilter{})
 	if err != nil {
 		log.Println("error getting product", err)
 		return nil, err
 	}
 
+	// unpublished products are still returned by id but cannot be ordered
+	orderable := []catalog.Product{}
+	for _, p := range products {
+		if !p.Unpublished {
+			orderable = append(orderable, p)
+		}
+	}
+	products = orderable
+
 	if len(products) != len(productIDs) {
 		notFound := len(productIDs) - len(products)
-		return nil, fmt.Errorf("%d products not found", notFound)
+		return nil, fmt.Errorf("%d %w", notFound, ErrProductsNotFound)
 	}
 
-	ids := []string{}
-	quantity := []uint32{}
+	items := []catalog.ReservationItem{}
 	orderProducts := []OrderedProduct{}
 	for _, p := range products {
 		product := OrderedProduct{
@@ -92,30 +158,60 @@ func (s *grpcServer) PostOrder(ctx context.Context, r *pb.PostOrderRequest) (*pb
 			Description: p.Description,
 			Price:       p.Price,
 			Quantity:    0,
+			SellerID:    p.SellerID,
 		}
 		for _, rp := range r.Products {
 			if product.ID == rp.ProductId {
 				product.Quantity = rp.Quantity
-
-				ids = append(ids, p.ID)
-				quantity = append(quantity, p.Quantity-rp.Quantity)
+				items = append(items, catalog.ReservationItem{
+					ProductID: p.ID,
+					Quantity:  rp.Quantity,
+				})
 				break
 			}
 		}
 		orderProducts = append(orderProducts, product)
 	}
 
+	// hold the stock while the order is written, so it cannot be sold twice
+	reservation, err := s.catalogClient.ReserveStock(ctx, items, 0)
+	if err != nil {
+		log.Println("error reserving stock", err)
+		return nil, err
+	}
+
 	order, err := s.service.PostOrder(ctx, r.AccountId, orderProducts)
 	if err != nil {
 		log.Println(err)
+		if _, err := s.catalogClient.ReleaseReservation(ctx, reservation.ID); err != nil {
+			log.Println("error releasing reservation", err)
+		}
 		return nil, ErrInvalidOrder
 	}
 
-	// update quantity of products
-	_, err = s.catalogClient.UpdateQuantity(ctx, ids, quantity)
-	if err != nil {
-		return nil, err
+	// the order only stands once its stock is sold, otherwise it is taken back
+	if err := s.commitReservation(ctx, reservation.ID, order.ID); err != nil {
+		log.Println("error committing reservation", err)
+
+		ctx := context.WithoutCancel(ctx)
+		if _, err := s.catalogClient.ReleaseReservation(ctx, reservation.ID); err != nil {
+			log.Println("error releasing reservation", err)
+		}
+		if err := s.service.DeleteOrder(ctx, order.ID); err != nil {
+			log.Println("error deleting order", err)
+		}
+		return nil, ErrInvalidOrder
+	}
+
+	itemCount := uint32(0)
+	for _, p := range order.Products {
+		itemCount += p.Quantity
 	}
+	s.notify(notification.EventOrderPlaced, order.AccountID, map[string]string{
+		"order_id":    order.ID,
+		"item_count":  strconv.FormatUint(uint64(itemCount), 10),
+		"total_price": strconv.FormatFloat(order.TotalPrice, 'f', 2, 64),
+	})
 
 	productsPsroto := []*pb.Order_OrderProduct{}
 	for _, p := range order.Products {
@@ -125,6 +221,7 @@ func (s *grpcServer) PostOrder(ctx context.Context, r *pb.PostOrderRequest) (*pb
 			Description: p.Description,
 			Price:       p.Price,
 			Quantity:    p.Quantity,
+			SellerId:    p.SellerID,
 		})
 	}
 
@@ -135,11 +232,15 @@ func (s *grpcServer) PostOrder(ctx context.Context, r *pb.PostOrderRequest) (*pb
 	}
 
 	orderProto := &pb.Order{
-		Id:         order.ID,
-		CreatedAt:  createdAtBin,
-		Products:   productsPsroto,
-		AccountId:  order.AccountID,
-		TotalPrice: order.TotalPrice,
+		Id:             order.ID,
+		CreatedAt:      createdAtBin,
+		Products:       productsPsroto,
+		AccountId:      order.AccountID,
+		TotalPrice:     order.TotalPrice,
+		Status:         order.Status,
+		PaymentStatus:  order.PaymentStatus,
+		RefundedAmount: order.RefundedAmount,
+		Fulfillments:   fulfillmentsToProto(order.Fulfillments),
 	}
 
 	return &pb.PostOrderResponse{
@@ -147,6 +248,24 @@ func (s *grpcServer) PostOrder(ctx context.Context, r *pb.PostOrderRequest) (*pb
 	}, nil
 }
 
+// commitReservation retries the commit a few times before giving up, a commit
+// repeated with the same reference is answered like the first one.
+func (s *grpcServer) commitReservation(ctx context.Context, id, reference string) (err error) {
+	for attempt := 0; attempt < commitAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(time.Duration(attempt) * commitBackoff):
+			}
+		}
+		if _, err = s.catalogClient.CommitReservation(ctx, id, reference); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
 func (s *grpcServer) GetOrdersForAccount(ctx context.Context, r *pb.GetOrderForAccountRequest) (*pb.GetOrderForAccountResponse, error) {
 	_, err := s.accountClient.GetAccountBuyerByID(ctx, r.AccountId)
 	if err != nil {
@@ -160,57 +279,322 @@ func (s *grpcServer) GetOrdersForAccount(ctx context.Context, r *pb.GetOrderForA
 		return nil, err
 	}
 
-	productIDsMap := map[string]bool{}
+	ordersProto := []*pb.Order{}
 	for _, o := range orders {
-		for _, p := range o.Products {
-			productIDsMap[p.ID] = true
+		ordersProto = append(ordersProto, orderToProto(o))
+	}
+
+	return &pb.GetOrderForAccountResponse{
+		Orders: ordersProto,
+	}, nil
+
+}
+
+// GetOrder looks any order up by its id, it backs the order view of the
+// back office.
+func (s *grpcServer) GetOrder(ctx context.Context, r *pb.GetOrderRequest) (*pb.GetOrderResponse, error) {
+	o, err := s.service.GetOrder(ctx, r.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetOrderResponse{Order: orderToProto(*o)}, nil
+}
+
+func orderToProto(o Order) *pb.Order {
+	order := &pb.Order{
+		Id:             o.ID,
+		AccountId:      o.AccountID,
+		TotalPrice:     o.TotalPrice,
+		Status:         o.Status,
+		PaymentStatus:  o.PaymentStatus,
+		RefundedAmount: o.RefundedAmount,
+		Products:       []*pb.Order_OrderProduct{},
+		Fulfillments:   fulfillmentsToProto(o.Fulfillments),
+	}
+
+	var err error
+	order.CreatedAt, err = o.CreatedAt.MarshalBinary()
+	if err != nil {
+		log.Println("error marshal timestamp", err)
+	}
+
+	// line items are served from the snapshot taken at purchase time
+	for _, p := range o.Products {
+		order.Products = append(order.Products, &pb.Order_OrderProduct{
+			Id:          p.ID,
+			Name:        p.Name,
+			Description: p.Description,
+			Price:       p.Price,
+			Quantity:    p.Quantity,
+			SellerId:    p.SellerID,
+		})
+	}
+	return order
+}
+
+func (s *grpcServer) HasDeliveredProduct(ctx context.Context, r *pb.HasDeliveredProductRequest) (*pb.HasDeliveredProductResponse, error) {
+	orderID, err := s.service.GetDeliveredOrderID(ctx, r.AccountId, r.ProductId)
+	if err != nil {
+		log.Println("error getting delivered order", err)
+		return nil, err
+	}
+
+	return &pb.HasDeliveredProductResponse{
+		Delivered: orderID != "",
+		OrderId:   orderID,
+	}, nil
+}
+
+// GetBuyerForSeller shows a buyer to a seller they ordered from, the account
+// service only shows buyers to themselves.
+func (s *grpcServer) GetBuyerForSeller(ctx context.Context, r *pb.GetBuyerForSellerRequest) (*pb.GetBuyerForSellerResponse, error) {
+	sold, err := s.service.HasSoldTo(ctx, r.SellerId, r.AccountId)
+	if err != nil {
+		log.Println("error checking orders of buyer", err)
+		return nil, err
+	}
+	if !sold {
+		return nil, ErrBuyerNotFound
+	}
+
+	// the seller may not read the buyer from the account service, the order
+	// service does once they sold to them
+	a, err := s.accountClient.GetAccountBuyerByID(auth.WithoutUser(ctx), r.AccountId)
+	if err != nil {
+		log.Println("error getting account", err)
+		return nil, ErrBuyerNotFound
+	}
+
+	return &pb.GetBuyerForSellerResponse{
+		AccountId: a.ID,
+		FirstName: a.FirstName,
+		LastName:  a.LastName,
+		Phone:     a.Phone,
+		Address:   a.Address,
+	}, nil
+}
+
+func (s *grpcServer) GetFulfillmentsForSeller(ctx context.Context, r *pb.GetFulfillmentsForSellerRequest) (*pb.GetFulfillmentsForSellerResponse, error) {
+	_, err := s.accountClient.GetAccountSellerByID(ctx, r.SellerId)
+	if err != nil {
+		log.Println("error getting account", err)
+		return nil, ErrInvalidAccount
+	}
+
+	fulfillments, err := s.service.GetFulfillmentsForSeller(ctx, r.SellerId, r.Skip, r.Take)
+	if err != nil {
+		log.Println("error getting fulfillments", err)
+		return nil, err
+	}
+
+	return &pb.GetFulfillmentsForSellerResponse{
+		Fulfillments: fulfillmentsToProto(fulfillments),
+	}, nil
+}
+
+func (s *grpcServer) UpdateFulfillment(ctx context.Context, r *pb.UpdateFulfillmentRequest) (*pb.UpdateFulfillmentResponse, error) {
+	f, err := s.service.UpdateFulfillment(ctx, r.Id, r.SellerId, r.Status, r.Carrier, r.TrackingNumber)
+	if err != nil {
+		log.Println("error updating fulfillment", err)
+		return nil, err
+	}
+
+	// a seller sending tracking details with the shipped status is when the
+	// buyer hears about it, a bare status change is not worth an email
+	if r.Status == OrderStatusShipped && r.TrackingNumber != "" {
+		s.notify(notification.EventOrderShipped, f.AccountID, map[string]string{
+			"order_id":        f.OrderID,
+			"carrier":         f.Carrier,
+			"tracking_number": f.TrackingNumber,
+		})
+	}
+
+	return &pb.UpdateFulfillmentResponse{
+		Fulfillment: fulfillmentToProto(*f),
+	}, nil
+}
+
+func (s *grpcServer) RequestReturn(ctx context.Context, r *pb.RequestReturnRequest) (*pb.OrderReturnResponse, error) {
+	ret, err := s.service.RequestReturn(ctx, r.AccountId, r.OrderId, r.ProductId, r.Quantity, r.Reason, r.Note)
+	if err != nil {
+		log.Println("error requesting return", err)
+		return nil, err
+	}
+
+	return &pb.OrderReturnResponse{OrderReturn: returnToProto(*ret)}, nil
+}
+
+func (s *grpcServer) ReviewReturn(ctx context.Context, r *pb.ReviewReturnRequest) (*pb.OrderReturnResponse, error) {
+	ret, err := s.service.ReviewReturn(ctx, r.Id, r.SellerId, r.Approve, r.Note)
+	if err != nil {
+		log.Println("error reviewing return", err)
+		return nil, err
+	}
+
+	return &pb.OrderReturnResponse{OrderReturn: returnToProto(*ret)}, nil
+}
+
+func (s *grpcServer) ReceiveReturn(ctx context.Context, r *pb.ReceiveReturnRequest) (*pb.OrderReturnResponse, error) {
+	// put the returned items back on sale, the reference keeps a retry from
+	// restocking them twice
+	ret, err := s.service.ReceiveReturn(ctx, r.Id, r.SellerId, r.Note, func(ret *Return) error {
+		_, err := s.catalogClient.Restock(ctx, ret.ProductID, ret.Quantity, "return "+ret.ID, "returned items")
+		if err != nil {
+			log.Println("error restocking product", err)
 		}
+		return err
+	})
+	if err != nil {
+		log.Println("error receiving return", err)
+		return nil, err
 	}
 
-	productIDs := []string{}
-	for id := range productIDsMap {
-		productIDs = append(productIDs, id)
+	return &pb.OrderReturnResponse{OrderReturn: returnToProto(*ret)}, nil
+}
+
+func (s *grpcServer) RefundReturn(ctx context.Context, r *pb.RefundReturnRequest) (*pb.OrderReturnResponse, error) {
+	ret, err := s.service.RefundReturn(ctx, r.Id, r.SellerId, r.Amount, r.Note)
+	if err != nil {
+		log.Println("error refunding return", err)
+		return nil, err
 	}
-	products, err := s.catalogClient.GetProducts(ctx, 0, 0, productIDs, "")
+
+	return &pb.OrderReturnResponse{OrderReturn: returnToProto(*ret)}, nil
+}
+
+func (s *grpcServer) GetReturnsForAccount(ctx context.Context, r *pb.GetReturnsForAccountRequest) (*pb.GetReturnsResponse, error) {
+	returns, err := s.service.GetReturnsForAccount(ctx, r.AccountId)
 	if err != nil {
-		log.Println("error getting products", err)
+		log.Println("error getting returns", err)
 		return nil, err
 	}
 
-	ordersProto := []*pb.Order{}
-	for _, o := range orders {
-		order := &pb.Order{
-			Id:         o.ID,
-			AccountId:  o.AccountID,
-			TotalPrice: o.TotalPrice,
-			Products:   []*pb.Order_OrderProduct{},
+	return &pb.GetReturnsResponse{Returns: returnsToProto(returns)}, nil
+}
+
+func (s *grpcServer) GetReturnsForSeller(ctx context.Context, r *pb.GetReturnsForSellerRequest) (*pb.GetReturnsResponse, error) {
+	returns, err := s.service.GetReturnsForSeller(ctx, r.SellerId, r.Skip, r.Take)
+	if err != nil {
+		log.Println("error getting returns", err)
+		return nil, err
+	}
+
+	return &pb.GetReturnsResponse{Returns: returnsToProto(returns)}, nil
+}
+
+func (s *grpcServer) EraseAccount(ctx context.Context, r *pb.EraseAccountRequest) (*pb.EraseAccountResponse, error) {
+	if err := s.service.EraseAccount(ctx, r.AccountId); err != nil {
+		log.Println("error erasing orders of account", err)
+		return nil, err
+	}
+
+	return &pb.EraseAccountResponse{}, nil
+}
+
+func fulfillmentToProto(f Fulfillment) *pb.Fulfillment {
+	products := []*pb.Order_OrderProduct{}
+	for _, p := range f.Products {
+		products = append(products, &pb.Order_OrderProduct{
+			Id:          p.ID,
+			Name:        p.Name,
+			Description: p.Description,
+			Price:       p.Price,
+			Quantity:    p.Quantity,
+			SellerId:    p.SellerID,
+		})
+	}
+
+	fulfillment := &pb.Fulfillment{
+		Id:             f.ID,
+		OrderId:        f.OrderID,
+		SellerId:       f.SellerID,
+		Status:         f.Status,
+		Carrier:        f.Carrier,
+		TrackingNumber: f.TrackingNumber,
+		PayoutAmount:   f.PayoutAmount,
+		Products:       products,
+	}
+
+	var err error
+	fulfillment.CreatedAt, err = f.CreatedAt.MarshalBinary()
+	if err != nil {
+		log.Println("error marshal timestamp", err)
+	}
+	fulfillment.UpdatedAt, err = f.UpdatedAt.MarshalBinary()
+	if err != nil {
+		log.Println("error marshal timestamp", err)
+	}
+
+	return fulfillment
+}
+
+func fulfillmentsToProto(fulfillments []Fulfillment) []*pb.Fulfillment {
+	fulfillmentsProto := []*pb.Fulfillment{}
+	for _, f := range fulfillments {
+		fulfillmentsProto = append(fulfillmentsProto, fulfillmentToProto(f))
+	}
+	return fulfillmentsProto
+}
+
+func returnToProto(ret Return) *pb.OrderReturn {
+	returnProto := &pb.OrderReturn{
+		Id:            ret.ID,
+		OrderId:       ret.OrderID,
+		FulfillmentId: ret.FulfillmentID,
+		AccountId:     ret.AccountID,
+		SellerId:      ret.SellerID,
+		ProductId:     ret.ProductID,
+		Quantity:      ret.Quantity,
+		Reason:        ret.Reason,
+		Note:          ret.Note,
+		Status:        ret.Status,
+		RefundAmount:  ret.RefundAmount,
+		Timeline:      []*pb.OrderReturn_Event{},
+	}
+
+	var err error
+	returnProto.CreatedAt, err = ret.CreatedAt.MarshalBinary()
+	if err != nil {
+		log.Println("error marshal timestamp", err)
+	}
+	returnProto.UpdatedAt, err = ret.UpdatedAt.MarshalBinary()
+	if err != nil {
+		log.Println("error marshal timestamp", err)
+	}
+
+	for _, e := range ret.Timeline {
+		event := &pb.OrderReturn_Event{
+			Status: e.Status,
+			Note:   e.Note,
 		}
-		order.CreatedAt, err = o.CreatedAt.MarshalBinary()
+		event.CreatedAt, err = e.CreatedAt.MarshalBinary()
 		if err != nil {
 			log.Println("error marshal timestamp", err)
 		}
+		returnProto.Timeline = append(returnProto.Timeline, event)
+	}
 
-		for _, p := range o.Products {
-			for _, queryP := range products {
-				if queryP.ID == p.ID {
-					product := &pb.Order_OrderProduct{
-						Id:          queryP.ID,
-						Name:        queryP.Name,
-						Description: queryP.Description,
-						Price:       queryP.Price,
-						Quantity:    p.Quantity,
-					}
-					order.Products = append(order.Products, product)
-					break
-				}
-			}
-		}
+	return returnProto
+}
 
-		ordersProto = append(ordersProto, order)
+func returnsToProto(returns []Return) []*pb.OrderReturn {
+	returnsProto := []*pb.OrderReturn{}
+	for _, ret := range returns {
+		returnsProto = append(returnsProto, returnToProto(ret))
 	}
+	return returnsProto
+}
 
-	return &pb.GetOrderForAccountResponse{
-		Orders: ordersProto,
-	}, nil
+// notify tells the buyer about an order event without holding up the
+// request, the notification service retries failed deliveries on its side.
+func (s *grpcServer) notify(event int32, accountID string, data map[string]string) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
 
+		if _, err := s.notificationClient.Notify(ctx, event, accountID, "", data); err != nil {
+			log.Println("error sending notification", err)
+		}
+	}()
 }
@@ -2,14 +2,36 @@ package order
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/segmentio/ksuid"
 )
 
+var (
+	ErrFulfillmentNotFound     = errors.New("fulfillment not found")
+	ErrInvalidStatusTransition = errors.New("invalid fulfillment status transition")
+)
+
 type Service interface {
 	PostOrder(ctx context.Context, accountID string, products []OrderedProduct) (*Order, error)
+	DeleteOrder(ctx context.Context, id string) error
+	GetOrder(ctx context.Context, id string) (*Order, error)
 	GetOrdersForAccount(ctx context.Context, accountID string) ([]Order, error)
+	GetDeliveredOrderID(ctx context.Context, accountID, productID string) (string, error)
+
+	GetFulfillmentsForSeller(ctx context.Context, sellerID string, skip uint64, take uint64) ([]Fulfillment, error)
+	UpdateFulfillment(ctx context.Context, id, sellerID string, status int32, carrier, trackingNumber string) (*Fulfillment, error)
+	HasSoldTo(ctx context.Context, sellerID, accountID string) (bool, error)
+
+	RequestReturn(ctx context.Context, accountID, orderID, productID string, quantity uint32, reason int32, note string) (*Return, error)
+	ReviewReturn(ctx context.Context, id, sellerID string, approve bool, note string) (*Return, error)
+	ReceiveReturn(ctx context.Context, id, sellerID string, note string, restock func(ret *Return) error) (*Return, error)
+	RefundReturn(ctx context.Context, id, sellerID string, amount float64, note string) (*Return, error)
+	GetReturnsForAccount(ctx context.Context, accountID string) ([]Return, error)
+	GetReturnsForSeller(ctx context.Context, sellerID string, skip uint64, take uint64) ([]Return, error)
+
+	EraseAccount(ctx context.Context, accountID string) error
 }
 
 type OrderedProduct struct {
@@ -18,14 +40,34 @@ type OrderedProduct struct {
 	Description string  `json:"description"`
 	Price       float64 `json:"price"`
 	Quantity    uint32  `json:"quantity"`
+	SellerID    string  `json:"seller_id"`
 }
 
 type Order struct {
-	ID         string           `json:"id"`
-	CreatedAt  time.Time        `json:"created_at"`
-	TotalPrice float64          `json:"total_price"`
-	AccountID  string           `json:"account_id"`
-	Products   []OrderedProduct `json:"products"`
+	ID             string           `json:"id"`
+	CreatedAt      time.Time        `json:"created_at"`
+	TotalPrice     float64          `json:"total_price"`
+	AccountID      string           `json:"account_id"`
+	Status         int32            `json:"status"`
+	PaymentStatus  int32            `json:"payment_status"`
+	RefundedAmount float64          `json:"refunded_amount"`
+	Products       []OrderedProduct `json:"products"`
+	Fulfillments   []Fulfillment    `json:"fulfillments"`
+}
+
+// Fulfillment is the part of an order shipped and paid out to a single seller.
+type Fulfillment struct {
+	ID             string           `json:"id"`
+	OrderID        string           `json:"order_id"`
+	AccountID      string           `json:"account_id"`
+	SellerID       string           `json:"seller_id"`
+	Status         int32            `json:"status"`
+	Carrier        string           `json:"carrier"`
+	TrackingNumber string           `json:"tracking_number"`
+	PayoutAmount   float64          `json:"payout_amount"`
+	Products       []OrderedProduct `json:"products"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
 }
 
 type orderService struct {
@@ -41,6 +83,7 @@ func (s *orderService) PostOrder(ctx context.Context, accountID string, products
 		ID:        ksuid.New().String(),
 		CreatedAt: time.Now().UTC(),
 		AccountID: accountID,
+		Status:    OrderStatusPending,
 		Products:  products,
 	}
 
@@ -48,6 +91,8 @@ func (s *orderService) PostOrder(ctx context.Context, accountID string, products
 	for _, p := range products {
 		o.TotalPrice += p.Price * float64(p.Quantity)
 	}
+	o.Fulfillments = splitFulfillments(o)
+
 	err := s.repository.PutOrder(ctx, *o)
 	if err != nil {
 		return nil, err
@@ -55,6 +100,127 @@ func (s *orderService) PostOrder(ctx context.Context, accountID string, products
 	return o, nil
 }
 
+func (s *orderService) EraseAccount(ctx context.Context, accountID string) error {
+	return s.repository.AnonymizeAccount(ctx, accountID)
+}
+
+func (s *orderService) DeleteOrder(ctx context.Context, id string) error {
+	return s.repository.DeleteOrder(ctx, id)
+}
+
+func (s *orderService) GetOrder(ctx context.Context, id string) (*Order, error) {
+	return s.repository.GetOrderByID(ctx, id)
+}
+
 func (s *orderService) GetOrdersForAccount(ctx context.Context, accountID string) ([]Order, error) {
 	return s.repository.GetOrdersForAccount(ctx, accountID)
 }
+
+// GetDeliveredOrderID returns the latest order in which the product reached
+// the account, empty when it never did.
+func (s *orderService) GetDeliveredOrderID(ctx context.Context, accountID, productID string) (string, error) {
+	return s.repository.GetDeliveredOrderID(ctx, accountID, productID)
+}
+
+func (s *orderService) HasSoldTo(ctx context.Context, sellerID, accountID string) (bool, error) {
+	return s.repository.HasSoldTo(ctx, sellerID, accountID)
+}
+
+func (s *orderService) GetFulfillmentsForSeller(ctx context.Context, sellerID string, skip uint64, take uint64) ([]Fulfillment, error) {
+	if take > 100 || (skip == 0 && take == 0) {
+		take = 100
+	}
+	return s.repository.GetFulfillmentsForSeller(ctx, sellerID, skip, take)
+}
+
+func (s *orderService) UpdateFulfillment(ctx context.Context, id, sellerID string, status int32, carrier, trackingNumber string) (*Fulfillment, error) {
+	// the transition is checked against the row locked for the update
+	return s.repository.UpdateFulfillment(ctx, id, func(f *Fulfillment) error {
+		if f.SellerID != sellerID {
+			return ErrFulfillmentNotFound
+		}
+
+		if status != f.Status && !canTransition(f.Status, status) {
+			return ErrInvalidStatusTransition
+		}
+		if status == OrderStatusShipped && trackingNumber == "" && f.TrackingNumber == "" {
+			return ErrInvalidStatusTransition
+		}
+
+		f.Status = status
+		if carrier != "" {
+			f.Carrier = carrier
+		}
+		if trackingNumber != "" {
+			f.TrackingNumber = trackingNumber
+		}
+		f.UpdatedAt = time.Now().UTC()
+		return nil
+	})
+}
+
+// splitFulfillments groups the order lines by seller, one fulfillment per seller.
+func splitFulfillments(o *Order) []Fulfillment {
+	fulfillments := []Fulfillment{}
+	index := map[string]int{}
+	for _, p := range o.Products {
+		i, ok := index[p.SellerID]
+		if !ok {
+			fulfillments = append(fulfillments, Fulfillment{
+				ID:        ksuid.New().String(),
+				OrderID:   o.ID,
+				AccountID: o.AccountID,
+				SellerID:  p.SellerID,
+				Status:    OrderStatusPending,
+				Products:  []OrderedProduct{},
+				CreatedAt: o.CreatedAt,
+				UpdatedAt: o.CreatedAt,
+			})
+			i = len(fulfillments) - 1
+			index[p.SellerID] = i
+		}
+
+		fulfillments[i].Products = append(fulfillments[i].Products, p)
+		fulfillments[i].PayoutAmount += p.Price * float64(p.Quantity)
+	}
+	return fulfillments
+}
+
+func canTransition(from, to int32) bool {
+	switch from {
+	case OrderStatusPending:
+		return to == OrderStatusShipped || to == OrderStatusCanceled
+	case OrderStatusShipped:
+		return to == OrderStatusDelivered
+	}
+	return false
+}
+
+// deriveOrderStatus rolls the fulfillment statuses up into the order status.
+func deriveOrderStatus(statuses []int32) int32 {
+	if len(statuses) == 0 {
+		return OrderStatusPending
+	}
+
+	canceled, delivered, shipped := 0, 0, 0
+	for _, st := range statuses {
+		switch st {
+		case OrderStatusCanceled:
+			canceled++
+		case OrderStatusDelivered:
+			delivered++
+		case OrderStatusShipped:
+			shipped++
+		}
+	}
+
+	switch {
+	case canceled == len(statuses):
+		return OrderStatusCanceled
+	case delivered > 0 && delivered+canceled == len(statuses):
+		return OrderStatusDelivered
+	case shipped > 0 || delivered > 0:
+		return OrderStatusShipped
+	}
+	return OrderStatusPending
+}
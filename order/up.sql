@@ -4,21 +4,90 @@ CREATE TABLE IF NOT EXISTS orders (
     total_price NUMERIC(19, 2) NOT NULL,
     payment_status INT NOT NULL DEFAULT 0,
     payment_id VARCHAR(127),
-    `status` INT NOT NULL DEFAULT 0,
+    refunded_amount NUMERIC(19, 2) NOT NULL DEFAULT 0,
+    status INT NOT NULL DEFAULT 0,
     created_at TIMESTAMP WITH TIME ZONE NOT NULL,
-    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL
 );
 
 CREATE TABLE IF NOT EXISTS order_products (
     order_id VARCHAR(27) NOT NULL,
     product_id CHAR(27),
     quantity INT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    price NUMERIC(19, 2) NOT NULL,
+    seller_id VARCHAR(27) NOT NULL,
     PRIMARY KEY (order_id, product_id),
     FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE
 );
 
+CREATE TABLE IF NOT EXISTS order_fulfillments (
+    id VARCHAR(27) PRIMARY KEY,
+    order_id VARCHAR(27) NOT NULL,
+    seller_id VARCHAR(27) NOT NULL,
+    status INT NOT NULL DEFAULT 0,
+    carrier VARCHAR(64) NOT NULL DEFAULT '',
+    tracking_number VARCHAR(127) NOT NULL DEFAULT '',
+    payout_amount NUMERIC(19, 2) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    UNIQUE (order_id, seller_id),
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS order_fulfillments_seller_id_idx ON order_fulfillments (seller_id);
+
+CREATE TABLE IF NOT EXISTS order_returns (
+    id VARCHAR(27) PRIMARY KEY,
+    order_id VARCHAR(27) NOT NULL,
+    fulfillment_id VARCHAR(27) NOT NULL,
+    account_id VARCHAR(27) NOT NULL,
+    seller_id VARCHAR(27) NOT NULL,
+    product_id CHAR(27) NOT NULL,
+    quantity INT NOT NULL,
+    reason INT NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    status INT NOT NULL DEFAULT 0,
+    refund_amount NUMERIC(19, 2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+    FOREIGN KEY (order_id) REFERENCES orders(id) ON DELETE CASCADE,
+    FOREIGN KEY (fulfillment_id) REFERENCES order_fulfillments(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS order_returns_account_id_idx ON order_returns (account_id);
+CREATE INDEX IF NOT EXISTS order_returns_seller_id_idx ON order_returns (seller_id);
+
+CREATE TABLE IF NOT EXISTS order_return_events (
+    return_id VARCHAR(27) NOT NULL,
+    status INT NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    FOREIGN KEY (return_id) REFERENCES order_returns(id) ON DELETE CASCADE
+);
+
 CREATE TABLE IF NOT EXISTS product_sellers (
     product_id VARCHAR(27) UNIQUE NOT NULL,
     seller_id VARCHAR(27) NOT NULL,
     PRIMARY KEY (product_id, seller_id)
-);
\ No newline at end of file
+);
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    key VARCHAR(255) PRIMARY KEY,
+    request_hash CHAR(64) NOT NULL,
+    response BYTEA,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    id VARCHAR(27) PRIMARY KEY,
+    actor_id VARCHAR(27) NOT NULL,
+    actor_email VARCHAR(127) NOT NULL,
+    method VARCHAR(255) NOT NULL,
+    request TEXT NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_actor_id_idx ON audit_log (actor_id, created_at);
@@ -0,0 +1,70 @@
+package audit
+
+import (
+	"context"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/231031/ecom-mcs-grpc/pkg/auth"
+	"github.com/segmentio/ksuid"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Entry is one back-office action: who did it, the method and request, and
+// the error it ended with, empty when it succeeded.
+type Entry struct {
+	ID         string
+	ActorID    string
+	ActorEmail string
+	Method     string
+	Request    string
+	Error      string
+	CreatedAt  time.Time
+}
+
+type Store interface {
+	Close()
+	Record(ctx context.Context, e Entry) error
+}
+
+// UnaryServerInterceptor records the calls admins make to the given methods,
+// whether they succeed or not. It runs after the authentication interceptor,
+// which puts the identity of the caller in the context.
+func UnaryServerInterceptor(store Store, methods ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !slices.Contains(methods, info.FullMethod) {
+			return handler(ctx, req)
+		}
+		id, ok := auth.FromContext(ctx)
+		if !ok || !id.IsUser() || !id.HasRole(auth.RoleAdmin) {
+			return handler(ctx, req)
+		}
+
+		resp, err := handler(ctx, req)
+
+		e := Entry{
+			ID:         ksuid.New().String(),
+			ActorID:    id.UserID,
+			ActorEmail: id.Email,
+			Method:     info.FullMethod,
+			CreatedAt:  time.Now().UTC(),
+		}
+		if m, ok := req.(proto.Message); ok {
+			if b, mErr := protojson.Marshal(m); mErr == nil {
+				e.Request = string(b)
+			}
+		}
+		if err != nil {
+			e.Error = err.Error()
+		}
+
+		// the action already happened, a lost record must not turn it into an error
+		if rErr := store.Record(context.WithoutCancel(ctx), e); rErr != nil {
+			log.Println("error recording audit entry", info.FullMethod, rErr)
+		}
+		return resp, err
+	}
+}
@@ -0,0 +1,40 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+
+	_ "github.com/lib/pq"
+)
+
+// postgresStore keeps entries in the audit_log table of the service database.
+type postgresStore struct {
+	db *sql.DB
+}
+
+func NewPostgresStore(url string) (Store, error) {
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Ping()
+	if err != nil {
+		return nil, err
+	}
+	return &postgresStore{db}, nil
+}
+
+func (s *postgresStore) Close() {
+	s.db.Close()
+}
+
+func (s *postgresStore) Record(ctx context.Context, e Entry) error {
+	_, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO audit_log(id, actor_id, actor_email, method, request, error, created_at)
+		VALUES($1, $2, $3, $4, $5, $6, $7)`,
+		e.ID, e.ActorID, e.ActorEmail, e.Method, e.Request, e.Error, e.CreatedAt,
+	)
+	return err
+}
@@ -0,0 +1,285 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/231031/ecom-mcs-grpc/pkg/grpcerr"
+	"github.com/golang-jwt/jwt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// MetadataKey carries the access token of the user, as "Bearer <token>".
+const MetadataKey = "authorization"
+
+// The signed internal header a service sends when it calls another one.
+const (
+	ServiceKey   = "x-internal-service"
+	TimestampKey = "x-internal-timestamp"
+	SignatureKey = "x-internal-signature"
+)
+
+// maxClockSkew bounds how old a signed internal header can be, so a captured
+// one cannot be replayed later.
+const maxClockSkew = 5 * time.Minute
+
+var (
+	ErrUnauthenticated   = errors.New("missing or invalid credentials")
+	ErrForbidden         = errors.New("not allowed to call this method")
+	ErrMissingSecret     = errors.New("internal auth secret is not set")
+	ErrInvalidSignature  = errors.New("invalid internal signature")
+	ErrExpiredSignature  = errors.New("internal signature is expired")
+	ErrUnexpectedSigning = errors.New("unexpected token signing method")
+)
+
+type Role int32
+
+// The roles of the authentication service.
+const (
+	RoleBuyer  Role = 0
+	RoleSeller Role = 1
+	RoleAdmin  Role = 2
+)
+
+// Identity is who is calling. A call from another service has Service set,
+// and the user it acts for when the user token was forwarded. A user holds
+// Roles and acts as Role, one of them, the policies only allow Role.
+type Identity struct {
+	UserID  string
+	Email   string
+	Role    Role
+	Roles   []Role
+	Service string
+
+	token string
+}
+
+func (i Identity) IsUser() bool {
+	return i.UserID != ""
+}
+
+// HasRole tells whether the user acts as one of the roles, holding another
+// one is not enough until the session switches to it.
+func (i Identity) HasRole(roles ...Role) bool {
+	return slices.Contains(roles, i.Role)
+}
+
+func (i Identity) IsService() bool {
+	return i.Service != ""
+}
+
+type ctxKey struct{}
+
+func NewContext(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+func FromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(ctxKey{}).(Identity)
+	return id, ok
+}
+
+// WithoutUser drops the user the calls are made for, the service makes the
+// next calls to the others on its own. It is for reads the user may not make
+// themselves once the service checked they are entitled to the result.
+func WithoutUser(ctx context.Context) context.Context {
+	id, ok := FromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	return NewContext(ctx, Identity{Service: id.Service})
+}
+
+// UserID returns the id of the user calling, empty for anonymous calls and
+// services acting on their own.
+func UserID(ctx context.Context) string {
+	id, _ := FromContext(ctx)
+	return id.UserID
+}
+
+type tokenClaims struct {
+	User struct {
+		ID    string  `json:"id"`
+		Email string  `json:"email"`
+		Role  int32   `json:"role"`
+		Roles []int32 `json:"roles"`
+	} `json:"user"`
+	jwt.StandardClaims
+}
+
+// Authenticator checks the identity of incoming calls and signs the calls a
+// service makes to the others.
+type Authenticator struct {
+	service string
+	keys    KeySource
+	secret  []byte
+}
+
+func NewAuthenticator(service string, keys KeySource, internalSecret string) (*Authenticator, error) {
+	if internalSecret == "" {
+		return nil, ErrMissingSecret
+	}
+
+	return &Authenticator{
+		service: service,
+		keys:    keys,
+		secret:  []byte(internalSecret),
+	}, nil
+}
+
+// UnaryServerInterceptor identifies the caller, checks it against the policy
+// of the method and puts the identity in the context of the handler. Methods
+// without a policy are refused. A service calling for a user is held to the
+// policy like the user, only the calls a service makes on its own skip it.
+func (a *Authenticator) UnaryServerInterceptor(policies Policies) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		policy, ok := policies[info.FullMethod]
+		if !ok {
+			return nil, grpcerr.Forbidden(ErrForbidden).Status(ErrForbidden).Err()
+		}
+
+		id, err := a.identify(ctx, info.FullMethod)
+		if err != nil {
+			return nil, grpcerr.Unauthorized(ErrUnauthenticated).Status(ErrUnauthenticated).Err()
+		}
+
+		if !policy.public {
+			if id == nil {
+				return nil, grpcerr.Unauthorized(ErrUnauthenticated).Status(ErrUnauthenticated).Err()
+			}
+			if id.IsUser() && !policy.allow(*id, req) {
+				return nil, grpcerr.Forbidden(ErrForbidden).Status(ErrForbidden).Err()
+			}
+		}
+
+		if id != nil {
+			ctx = NewContext(ctx, *id)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// UnaryClientInterceptor signs the calls to the other services, and forwards
+// the token of the user the call is made for.
+func (a *Authenticator) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		pairs := []string{
+			ServiceKey, a.service,
+			TimestampKey, timestamp,
+			SignatureKey, a.sign(a.service, timestamp, method),
+		}
+		if id, ok := FromContext(ctx); ok && id.token != "" {
+			pairs = append(pairs, MetadataKey, "Bearer "+id.token)
+		}
+
+		ctx = metadata.AppendToOutgoingContext(ctx, pairs...)
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// DialOption installs UnaryClientInterceptor on a client.
+func (a *Authenticator) DialOption() grpc.DialOption {
+	return grpc.WithChainUnaryInterceptor(a.UnaryClientInterceptor())
+}
+
+// identify returns the identity of the caller, nil for an anonymous call.
+// Credentials that are sent but do not verify are an error.
+func (a *Authenticator) identify(ctx context.Context, method string) (*Identity, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	id := &Identity{}
+
+	if service := first(md, ServiceKey); service != "" {
+		if err := a.verifySignature(service, first(md, TimestampKey), first(md, SignatureKey), method); err != nil {
+			return nil, err
+		}
+		id.Service = service
+	}
+
+	if header := first(md, MetadataKey); header != "" {
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok {
+			return nil, ErrUnauthenticated
+		}
+		claims, err := a.verifyToken(token)
+		if err != nil {
+			return nil, err
+		}
+		id.UserID = claims.User.ID
+		id.Email = claims.User.Email
+		id.Role = Role(claims.User.Role)
+		id.Roles = []Role{id.Role}
+		// tokens issued before users held several roles only carry one
+		if len(claims.User.Roles) > 0 {
+			id.Roles = id.Roles[:0]
+			for _, r := range claims.User.Roles {
+				id.Roles = append(id.Roles, Role(r))
+			}
+		}
+		id.token = token
+	}
+
+	if !id.IsService() && !id.IsUser() {
+		return nil, nil
+	}
+	return id, nil
+}
+
+func (a *Authenticator) verifyToken(tokenStr string) (*tokenClaims, error) {
+	claims := &tokenClaims{}
+	token, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, ErrUnexpectedSigning
+		}
+		kid, _ := token.Header["kid"].(string)
+		return a.keys.PublicKey(kid)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid || claims.User.ID == "" {
+		return nil, ErrUnauthenticated
+	}
+	return claims, nil
+}
+
+func (a *Authenticator) verifySignature(service, timestamp, signature, method string) error {
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	age := time.Since(time.Unix(sec, 0))
+	if age > maxClockSkew || age < -maxClockSkew {
+		return ErrExpiredSignature
+	}
+
+	expected := a.sign(service, timestamp, method)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// sign binds the signature to the method, so it cannot be reused to call
+// another one.
+func (a *Authenticator) sign(service, timestamp, method string) string {
+	mac := hmac.New(sha256.New, a.secret)
+	mac.Write([]byte(service + "\n" + timestamp + "\n" + method))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func first(md metadata.MD, key string) string {
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
@@ -0,0 +1,230 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+var (
+	ErrUnknownKey = errors.New("unknown signing key")
+	ErrInvalidJWK = errors.New("invalid json web key")
+)
+
+const (
+	// DefaultKeySetTTL is how long a fetched key set is used before it is
+	// fetched again. The authentication service publishes a new key longer
+	// than this before signing with it.
+	DefaultKeySetTTL = 5 * time.Minute
+
+	// minKeySetRefresh bounds how often the key set is fetched, whatever the
+	// kids of the tokens coming in.
+	minKeySetRefresh = 30 * time.Second
+)
+
+// KeySource finds the public key a token was signed with from its kid.
+type KeySource interface {
+	PublicKey(kid string) (*rsa.PublicKey, error)
+}
+
+// JWK is an RSA public key in the JSON Web Key format of RFC 7517.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// JWKS is the key set the authentication service publishes.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+func NewJWK(kid string, key *rsa.PublicKey) JWK {
+	return JWK{
+		Kty: "RSA",
+		Kid: kid,
+		Use: "sig",
+		Alg: "RS256",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func (k JWK) PublicKey() (*rsa.PublicKey, error) {
+	if k.Kty != "RSA" || k.Kid == "" {
+		return nil, ErrInvalidJWK
+	}
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, ErrInvalidJWK
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil || len(e) == 0 || len(e) > 4 {
+		return nil, ErrInvalidJWK
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}, nil
+}
+
+// FetchFunc gets the current key set.
+type FetchFunc func(ctx context.Context) (*JWKS, error)
+
+// FetchHTTP gets the key set from the jwks.json endpoint at url.
+func FetchHTTP(url string) FetchFunc {
+	return func(ctx context.Context) (*JWKS, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("fetching key set: %s", resp.Status)
+		}
+		set := &JWKS{}
+		if err := json.NewDecoder(resp.Body).Decode(set); err != nil {
+			return nil, err
+		}
+		return set, nil
+	}
+}
+
+// KeySet caches a fetched key set. It is fetched again once the ttl passed,
+// or early for a kid it does not know, a key that was just rotated in. The
+// fetch runs without the lock, one at a time: a stale key is served in the
+// meantime and only the callers of an unknown kid wait for it.
+type KeySet struct {
+	fetch FetchFunc
+	ttl   time.Duration
+
+	mu          sync.Mutex
+	keys        map[string]*rsa.PublicKey
+	fetchedAt   time.Time
+	attemptedAt time.Time
+	// refreshing is closed once the fetch in flight is done, nil when none is
+	refreshing chan struct{}
+	refreshErr error
+}
+
+func NewKeySet(fetch FetchFunc, ttl time.Duration) *KeySet {
+	return &KeySet{fetch: fetch, ttl: ttl}
+}
+
+func (s *KeySet) PublicKey(kid string) (*rsa.PublicKey, error) {
+	s.mu.Lock()
+	key, ok := s.keys[kid]
+	stale := time.Since(s.fetchedAt) > s.ttl
+	done := s.refreshing
+	if (!ok || stale) && done == nil && time.Since(s.attemptedAt) > minKeySetRefresh {
+		s.attemptedAt = time.Now()
+		done = make(chan struct{})
+		s.refreshing = done
+		go s.refresh(done)
+	}
+	s.mu.Unlock()
+
+	if ok {
+		return key, nil
+	}
+	if done == nil {
+		return nil, ErrUnknownKey
+	}
+
+	<-done
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key, ok = s.keys[kid]
+	if !ok {
+		// keep serving the keys we have while the endpoint is down
+		if s.keys == nil && s.refreshErr != nil {
+			return nil, s.refreshErr
+		}
+		return nil, ErrUnknownKey
+	}
+	return key, nil
+}
+
+func (s *KeySet) refresh(done chan struct{}) {
+	defer close(done)
+
+	keys, err := s.fetchKeys()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refreshing = nil
+	s.refreshErr = err
+	if err == nil {
+		s.keys = keys
+		s.fetchedAt = time.Now()
+	}
+}
+
+func (s *KeySet) fetchKeys() (map[string]*rsa.PublicKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	set, err := s.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, k := range set.Keys {
+		pub, err := k.PublicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+	return keys, nil
+}
+
+// staticKey is a single key read from a PEM file, used for every kid.
+type staticKey struct {
+	key *rsa.PublicKey
+}
+
+func LoadPublicKey(path string) (KeySource, error) {
+	pub, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := jwt.ParseRSAPublicKeyFromPEM(pub)
+	if err != nil {
+		return nil, err
+	}
+	return &staticKey{key}, nil
+}
+
+func (k *staticKey) PublicKey(kid string) (*rsa.PublicKey, error) {
+	return k.key, nil
+}
+
+// NewKeySource fetches the keys from the JWKS endpoint when there is one,
+// and falls back on the PEM file of a single key.
+func NewKeySource(jwksURL, publicKeyPath string) (KeySource, error) {
+	if jwksURL != "" {
+		return NewKeySet(FetchHTTP(jwksURL), DefaultKeySetTTL), nil
+	}
+	return LoadPublicKey(publicKeyPath)
+}
@@ -0,0 +1,80 @@
+package auth
+
+// Policy says who may call a method. Other services calling on their own are
+// trusted with every method, a service calling for a user only gets what the
+// user would.
+type Policy struct {
+	public bool
+	allow  func(id Identity, req interface{}) bool
+}
+
+// Policies maps the full method names of a service to their policy.
+type Policies map[string]Policy
+
+// Public lets anyone call the method, signed in or not.
+func Public() Policy {
+	return Policy{public: true}
+}
+
+// Internal only lets other services call the method, for themselves or for
+// a user.
+func Internal() Policy {
+	return Policy{allow: func(id Identity, req interface{}) bool {
+		return id.IsService()
+	}}
+}
+
+// Authenticated lets any signed in user call the method.
+func Authenticated() Policy {
+	return Policy{allow: func(id Identity, req interface{}) bool {
+		return id.IsUser()
+	}}
+}
+
+// Roles lets the users holding one of the roles call the method.
+func Roles(roles ...Role) Policy {
+	return Policy{allow: func(id Identity, req interface{}) bool {
+		return id.IsUser() && id.HasRole(roles...)
+	}}
+}
+
+// OwnedBy lets a user holding one of the roles call the method on their own
+// records, owner returns the account id the request is about.
+func OwnedBy[T any](owner func(req T) string, roles ...Role) Policy {
+	return Policy{allow: func(id Identity, req interface{}) bool {
+		r, ok := req.(T)
+		if !ok || !id.IsUser() || !id.HasRole(roles...) {
+			return false
+		}
+		return owner(r) == id.UserID
+	}}
+}
+
+// OwnedByAll is OwnedBy for requests about several accounts, the user must
+// own every one of them.
+func OwnedByAll[T any](owners func(req T) []string, roles ...Role) Policy {
+	return Policy{allow: func(id Identity, req interface{}) bool {
+		r, ok := req.(T)
+		if !ok || !id.IsUser() || !id.HasRole(roles...) {
+			return false
+		}
+		for _, owner := range owners(r) {
+			if owner != id.UserID {
+				return false
+			}
+		}
+		return true
+	}}
+}
+
+// AnyOf lets the call through when one of the policies does.
+func AnyOf(policies ...Policy) Policy {
+	return Policy{allow: func(id Identity, req interface{}) bool {
+		for _, p := range policies {
+			if p.public || p.allow(id, req) {
+				return true
+			}
+		}
+		return false
+	}}
+}
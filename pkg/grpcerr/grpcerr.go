@@ -0,0 +1,265 @@
+package grpcerr
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Domain is set on every ErrorInfo detail the services attach.
+const Domain = "ecom-mcs-grpc"
+
+// Stable reasons sent as ErrorInfo.Reason, the gateway exposes them as the
+// extensions.code of a GraphQL error so clients can switch on them.
+const (
+	ReasonNotFound     = "NOT_FOUND"
+	ReasonConflict     = "CONFLICT"
+	ReasonOutOfStock   = "OUT_OF_STOCK"
+	ReasonValidation   = "VALIDATION"
+	ReasonUnauthorized = "UNAUTHORIZED"
+	ReasonForbidden    = "FORBIDDEN"
+	ReasonRateLimited  = "RATE_LIMITED"
+	ReasonUnavailable  = "UNAVAILABLE"
+	ReasonInternal     = "INTERNAL"
+)
+
+var errInternal = errors.New("internal error")
+
+// Mapping ties a domain error to the status code and reason it is sent with.
+// Field is the request field the error is about, if there is one.
+type Mapping struct {
+	Err    error
+	Code   codes.Code
+	Reason string
+	Field  string
+}
+
+func NotFound(err error) Mapping {
+	return Mapping{Err: err, Code: codes.NotFound, Reason: ReasonNotFound}
+}
+
+func Conflict(err error) Mapping {
+	return Mapping{Err: err, Code: codes.FailedPrecondition, Reason: ReasonConflict}
+}
+
+func AlreadyExists(err error) Mapping {
+	return Mapping{Err: err, Code: codes.AlreadyExists, Reason: ReasonConflict}
+}
+
+func OutOfStock(err error) Mapping {
+	return Mapping{Err: err, Code: codes.FailedPrecondition, Reason: ReasonOutOfStock}
+}
+
+func Validation(err error, field string) Mapping {
+	return Mapping{Err: err, Code: codes.InvalidArgument, Reason: ReasonValidation, Field: field}
+}
+
+func Unauthorized(err error) Mapping {
+	return Mapping{Err: err, Code: codes.Unauthenticated, Reason: ReasonUnauthorized}
+}
+
+func Forbidden(err error) Mapping {
+	return Mapping{Err: err, Code: codes.PermissionDenied, Reason: ReasonForbidden}
+}
+
+func RateLimited(err error) Mapping {
+	return Mapping{Err: err, Code: codes.ResourceExhausted, Reason: ReasonRateLimited}
+}
+
+// OnField returns the mapping for errors about the given request field.
+func (m Mapping) OnField(field string) Mapping {
+	m.Field = field
+	return m
+}
+
+type fieldError struct {
+	err   error
+	field string
+}
+
+func (e *fieldError) Error() string { return e.err.Error() }
+func (e *fieldError) Unwrap() error { return e.err }
+
+// WithField marks err as being about the given request field, it takes over
+// the field of the matching mapping.
+func WithField(err error, field string) error {
+	if err == nil {
+		return nil
+	}
+	return &fieldError{err: err, field: field}
+}
+
+type retryError struct {
+	err   error
+	delay time.Duration
+}
+
+func (e *retryError) Error() string { return e.err.Error() }
+func (e *retryError) Unwrap() error { return e.err }
+
+// WithRetryAfter tells the caller how long to wait before trying again, it is
+// sent as a RetryInfo detail.
+func WithRetryAfter(err error, delay time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	return &retryError{err: err, delay: delay}
+}
+
+// Status builds the status of err with the ErrorInfo, the BadRequest detail
+// when the field is known and the RetryInfo one when the delay is.
+func (m Mapping) Status(err error) *status.Status {
+	field := m.Field
+	var fe *fieldError
+	if errors.As(err, &fe) {
+		field = fe.field
+	}
+
+	st := status.New(m.Code, err.Error())
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: m.Reason, Domain: Domain}}
+	if field != "" {
+		details = append(details, &errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{
+				{Field: field, Description: err.Error()},
+			},
+		})
+	}
+	var re *retryError
+	if errors.As(err, &re) {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(re.delay)})
+	}
+
+	withDetails, errDetails := st.WithDetails(details...)
+	if errDetails != nil {
+		log.Println("error adding status details", errDetails)
+		return st
+	}
+	return withDetails
+}
+
+// InvalidArgument builds the status of a request failing validation on the
+// given fields.
+func InvalidArgument(message string, violations []FieldViolation) *status.Status {
+	br := &errdetails.BadRequest{}
+	for _, v := range violations {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+		})
+	}
+
+	st := status.New(codes.InvalidArgument, message)
+	withDetails, err := st.WithDetails(&errdetails.ErrorInfo{Reason: ReasonValidation, Domain: Domain}, br)
+	if err != nil {
+		log.Println("error adding status details", err)
+		return st
+	}
+	return withDetails
+}
+
+// ToStatus converts err into a status error. Errors that already are one,
+// like those passed on from another service, are kept as they are, unmapped
+// errors are logged and hidden behind an internal error.
+func ToStatus(err error, mappings []Mapping) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	for _, m := range mappings {
+		if errors.Is(err, m.Err) {
+			return m.Status(err).Err()
+		}
+	}
+
+	var fe *fieldError
+	if errors.As(err, &fe) {
+		return Validation(fe.err, fe.field).Status(err).Err()
+	}
+
+	log.Println("unmapped error", err)
+	return Mapping{Code: codes.Internal, Reason: ReasonInternal}.Status(errInternal).Err()
+}
+
+// UnaryServerInterceptor maps the errors returned by the handlers with the
+// given mappings, the first one matching with errors.Is wins.
+func UnaryServerInterceptor(mappings ...Mapping) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return nil, ToStatus(err, mappings)
+		}
+		return resp, nil
+	}
+}
+
+// Reason returns the stable reason of a status error, taken from its
+// ErrorInfo or derived from the code when it has none.
+func Reason(st *status.Status) string {
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok && info.Reason != "" {
+			return info.Reason
+		}
+	}
+
+	switch st.Code() {
+	case codes.NotFound:
+		return ReasonNotFound
+	case codes.AlreadyExists, codes.Aborted, codes.FailedPrecondition:
+		return ReasonConflict
+	case codes.InvalidArgument, codes.OutOfRange:
+		return ReasonValidation
+	case codes.Unauthenticated:
+		return ReasonUnauthorized
+	case codes.PermissionDenied:
+		return ReasonForbidden
+	case codes.ResourceExhausted:
+		return ReasonRateLimited
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return ReasonUnavailable
+	default:
+		return ReasonInternal
+	}
+}
+
+// FieldViolation is a request field an error is about.
+type FieldViolation struct {
+	Field       string
+	Description string
+}
+
+// Fields returns the field violations carried by a status error.
+func Fields(st *status.Status) []FieldViolation {
+	fields := []FieldViolation{}
+	for _, d := range st.Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			for _, v := range br.GetFieldViolations() {
+				fields = append(fields, FieldViolation{Field: v.GetField(), Description: v.GetDescription()})
+			}
+		}
+	}
+	return fields
+}
+
+// RetryAfter returns the delay of the RetryInfo detail of a status error.
+func RetryAfter(st *status.Status) (time.Duration, bool) {
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.RetryInfo); ok && info.GetRetryDelay() != nil {
+			return info.GetRetryDelay().AsDuration(), true
+		}
+	}
+	return 0, false
+}
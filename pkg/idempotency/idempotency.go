@@ -0,0 +1,193 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/231031/ecom-mcs-grpc/pkg/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// MetadataKey is the gRPC metadata key a caller puts the idempotency key in.
+const MetadataKey = "idempotency-key"
+
+// HeaderKey is the HTTP header the gateway reads the idempotency key from.
+const HeaderKey = "Idempotency-Key"
+
+// DefaultTTL is how long a completed response is replayed for.
+const DefaultTTL = 24 * time.Hour
+
+// lockTimeout bounds how long a claimed key stays in progress, so a crash
+// in the middle of a call does not block retries until the record expires.
+const lockTimeout = time.Minute
+
+var (
+	ErrKeyInProgress = status.Error(codes.Aborted, "a request with this idempotency key is in progress")
+	ErrKeyMismatch   = status.Error(codes.InvalidArgument, "idempotency key was used with a different request")
+)
+
+// Record is what a store keeps for a key. Response is nil while the first
+// call holding the key is still running.
+type Record struct {
+	RequestHash string `json:"request_hash"`
+	Response    []byte `json:"response"`
+}
+
+type Store interface {
+	Close()
+	// Reserve claims the key for a new call and returns nil, or returns the
+	// record of the call that already holds it.
+	Reserve(ctx context.Context, key, requestHash string, lock time.Duration) (*Record, error)
+	Complete(ctx context.Context, key string, response []byte, ttl time.Duration) error
+	Release(ctx context.Context, key string) error
+}
+
+type ctxKey struct{}
+
+func NewContext(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, key)
+}
+
+func FromContext(ctx context.Context) string {
+	key, _ := ctx.Value(ctxKey{}).(string)
+	return key
+}
+
+// UnaryClientInterceptor forwards the idempotency key found in the context.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if key := FromContext(ctx); key != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, MetadataKey, key)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// UnaryServerInterceptor makes the given methods idempotent. A call carrying
+// a key that already succeeded gets the stored response back without running
+// the handler again; failed calls release the key so they can be retried.
+// Keys are kept per method and per caller.
+func UnaryServerInterceptor(store Store, ttl time.Duration, methods ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !slices.Contains(methods, info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		key := keyFromIncoming(ctx)
+		if key == "" {
+			return handler(ctx, req)
+		}
+		key = info.FullMethod + ":" + caller(ctx) + ":" + key
+
+		requestHash, err := hashRequest(info.FullMethod, req)
+		if err != nil {
+			return nil, err
+		}
+
+		rec, err := store.Reserve(ctx, key, requestHash, lockTimeout)
+		if err != nil {
+			return nil, err
+		}
+		if rec != nil {
+			return replay(rec, requestHash)
+		}
+
+		resp, err := handler(ctx, req)
+		if err != nil {
+			if errRelease := store.Release(context.WithoutCancel(ctx), key); errRelease != nil {
+				log.Println("error releasing idempotency key", errRelease)
+			}
+			return nil, err
+		}
+
+		respBytes, err := marshalResponse(resp)
+		if err != nil {
+			log.Println("error marshal idempotent response", err)
+			return resp, nil
+		}
+		if err := store.Complete(context.WithoutCancel(ctx), key, respBytes, ttl); err != nil {
+			log.Println("error storing idempotent response", err)
+		}
+
+		return resp, nil
+	}
+}
+
+// caller scopes the keys to who is calling, the same key sent by two callers
+// stands for two different calls. The auth interceptor has to run first.
+func caller(ctx context.Context) string {
+	id, _ := auth.FromContext(ctx)
+	switch {
+	case id.IsUser():
+		return "user:" + id.UserID
+	case id.IsService():
+		return "service:" + id.Service
+	}
+	return "anonymous"
+}
+
+func keyFromIncoming(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(MetadataKey)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func hashRequest(method string, req interface{}) (string, error) {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return "", errors.New("idempotent request is not a proto message")
+	}
+
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(append([]byte(method), b...))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// marshalResponse wraps the response in an Any so the replay knows its type.
+func marshalResponse(resp interface{}) ([]byte, error) {
+	msg, ok := resp.(proto.Message)
+	if !ok {
+		return nil, errors.New("idempotent response is not a proto message")
+	}
+
+	wrapped, err := anypb.New(msg)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(wrapped)
+}
+
+func replay(rec *Record, requestHash string) (interface{}, error) {
+	if rec.RequestHash != requestHash {
+		return nil, ErrKeyMismatch
+	}
+	if rec.Response == nil {
+		return nil, ErrKeyInProgress
+	}
+
+	wrapped := &anypb.Any{}
+	if err := proto.Unmarshal(rec.Response, wrapped); err != nil {
+		return nil, err
+	}
+	return wrapped.UnmarshalNew()
+}
@@ -0,0 +1,88 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	_ "github.com/lib/pq"
+)
+
+// postgresStore keeps records in the idempotency_keys table of the service database.
+type postgresStore struct {
+	db *sql.DB
+}
+
+func NewPostgresStore(url string) (Store, error) {
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Ping()
+	if err != nil {
+		return nil, err
+	}
+	return &postgresStore{db}, nil
+}
+
+func (s *postgresStore) Close() {
+	s.db.Close()
+}
+
+func (s *postgresStore) Reserve(ctx context.Context, key, requestHash string, lock time.Duration) (*Record, error) {
+	now := time.Now().UTC()
+
+	// take the key over when it is free or the record behind it has expired
+	res, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO idempotency_keys(key, request_hash, response, expires_at)
+		VALUES($1, $2, NULL, $3)
+		ON CONFLICT (key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash, response = NULL, expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at < $4`,
+		key, requestHash, now.Add(lock), now,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	claimed, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if claimed == 1 {
+		return nil, nil
+	}
+
+	rec := &Record{}
+	err = s.db.QueryRowContext(
+		ctx,
+		"SELECT request_hash, response FROM idempotency_keys WHERE key = $1",
+		key,
+	).Scan(&rec.RequestHash, &rec.Response)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// released between the insert and the select
+			return s.Reserve(ctx, key, requestHash, lock)
+		}
+		return nil, err
+	}
+
+	return rec, nil
+}
+
+func (s *postgresStore) Complete(ctx context.Context, key string, response []byte, ttl time.Duration) error {
+	_, err := s.db.ExecContext(
+		ctx,
+		"UPDATE idempotency_keys SET response = $2, expires_at = $3 WHERE key = $1",
+		key, response, time.Now().UTC().Add(ttl),
+	)
+	return err
+}
+
+func (s *postgresStore) Release(ctx context.Context, key string) error {
+	_, err := s.db.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE key = $1", key)
+	return err
+}
@@ -0,0 +1,77 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const redisKeyPrefix = "idempotency:"
+
+type redisStore struct {
+	client *redis.Client
+}
+
+func NewRedisStore(client *redis.Client) Store {
+	return &redisStore{client: client}
+}
+
+// Close leaves the client open, it is shared with the service repository.
+func (s *redisStore) Close() {
+}
+
+func (s *redisStore) Reserve(ctx context.Context, key, requestHash string, lock time.Duration) (*Record, error) {
+	recJson, err := json.Marshal(Record{RequestHash: requestHash})
+	if err != nil {
+		return nil, err
+	}
+
+	claimed, err := s.client.SetNX(ctx, redisKeyPrefix+key, recJson, lock).Result()
+	if err != nil {
+		return nil, err
+	}
+	if claimed {
+		return nil, nil
+	}
+
+	stored, err := s.client.Get(ctx, redisKeyPrefix+key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			// expired or released between the two calls
+			return s.Reserve(ctx, key, requestHash, lock)
+		}
+		return nil, err
+	}
+
+	rec := &Record{}
+	if err := json.Unmarshal(stored, rec); err != nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+func (s *redisStore) Complete(ctx context.Context, key string, response []byte, ttl time.Duration) error {
+	stored, err := s.client.Get(ctx, redisKeyPrefix+key).Bytes()
+	if err != nil {
+		return err
+	}
+
+	rec := Record{}
+	if err := json.Unmarshal(stored, &rec); err != nil {
+		return err
+	}
+	rec.Response = response
+
+	recJson, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, redisKeyPrefix+key, recJson, ttl).Err()
+}
+
+func (s *redisStore) Release(ctx context.Context, key string) error {
+	return s.client.Del(ctx, redisKeyPrefix+key).Err()
+}
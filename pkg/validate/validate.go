@@ -0,0 +1,255 @@
+package validate
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/231031/ecom-mcs-grpc/pkg/grpcerr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+var phonePattern = regexp.MustCompile(`^\+?[0-9][0-9 ()-]{5,19}$`)
+
+// Validator is implemented by the request messages that carry rules, next
+// to the generated code of each service.
+type Validator interface {
+	Validate() error
+}
+
+// Error lists every field of a request breaking a rule. It is a gRPC status
+// error, so it reaches the client as InvalidArgument with the violations.
+type Error struct {
+	Violations []grpcerr.FieldViolation
+}
+
+func (e *Error) Error() string {
+	msgs := []string{}
+	for _, v := range e.Violations {
+		msgs = append(msgs, v.Field+": "+v.Description)
+	}
+	return "invalid request, " + strings.Join(msgs, "; ")
+}
+
+func (e *Error) GRPCStatus() *status.Status {
+	return grpcerr.InvalidArgument(e.Error(), e.Violations)
+}
+
+func (e *Error) add(field, description string) {
+	e.Violations = append(e.Violations, grpcerr.FieldViolation{Field: field, Description: description})
+}
+
+// Rule checks one field and records what is wrong with it.
+type Rule func(e *Error)
+
+// Check runs the rules and returns an *Error when any of them fails.
+func Check(rules ...Rule) error {
+	e := &Error{}
+	for _, rule := range rules {
+		rule(e)
+	}
+	if len(e.Violations) == 0 {
+		return nil
+	}
+	return e
+}
+
+func Required(field, value string) Rule {
+	return func(e *Error) {
+		if strings.TrimSpace(value) == "" {
+			e.add(field, "must not be empty")
+		}
+	}
+}
+
+func MinLen(field, value string, min int) Rule {
+	return func(e *Error) {
+		if utf8.RuneCountInString(value) < min {
+			e.add(field, fmt.Sprintf("must be at least %d characters", min))
+		}
+	}
+}
+
+func MaxLen(field, value string, max int) Rule {
+	return func(e *Error) {
+		if utf8.RuneCountInString(value) > max {
+			e.add(field, fmt.Sprintf("must be at most %d characters", max))
+		}
+	}
+}
+
+// Email checks the address format, an empty value is left to Required.
+func Email(field, value string) Rule {
+	return func(e *Error) {
+		if value == "" {
+			return
+		}
+		addr, err := mail.ParseAddress(value)
+		if err != nil || addr.Address != value {
+			e.add(field, "must be a valid email address")
+		}
+	}
+}
+
+// Phone checks the number format, an empty value is left to Required.
+func Phone(field, value string) Rule {
+	return func(e *Error) {
+		if value == "" {
+			return
+		}
+		if !phonePattern.MatchString(value) {
+			e.add(field, "must be a valid phone number")
+		}
+	}
+}
+
+func Positive[T cmp.Ordered](field string, value T) Rule {
+	return func(e *Error) {
+		var zero T
+		if value <= zero {
+			e.add(field, "must be greater than 0")
+		}
+	}
+}
+
+func NonNegative[T cmp.Ordered](field string, value T) Rule {
+	return func(e *Error) {
+		var zero T
+		if value < zero {
+			e.add(field, "must not be negative")
+		}
+	}
+}
+
+func NonZero[T comparable](field string, value T) Rule {
+	return func(e *Error) {
+		var zero T
+		if value == zero {
+			e.add(field, "must not be zero")
+		}
+	}
+}
+
+func Between[T cmp.Ordered](field string, value, min, max T) Rule {
+	return func(e *Error) {
+		if value < min || value > max {
+			e.add(field, fmt.Sprintf("must be between %v and %v", min, max))
+		}
+	}
+}
+
+func MinItems(field string, n, min int) Rule {
+	return func(e *Error) {
+		if n < min {
+			e.add(field, fmt.Sprintf("must have at least %d items", min))
+		}
+	}
+}
+
+func MaxItems(field string, n, max int) Rule {
+	return func(e *Error) {
+		if n > max {
+			e.add(field, fmt.Sprintf("must have at most %d items", max))
+		}
+	}
+}
+
+// SameLen checks that two repeated fields pair up one to one.
+func SameLen(field, other string, n, m int) Rule {
+	return func(e *Error) {
+		if n != m {
+			e.add(field, fmt.Sprintf("must have as many items as %s", other))
+		}
+	}
+}
+
+// Present checks that a message field is set.
+func Present(field string, set bool) Rule {
+	return func(e *Error) {
+		if !set {
+			e.add(field, "is required")
+		}
+	}
+}
+
+// Nested runs the rules of a message field, its violations are reported
+// under field.
+func Nested(field string, v Validator) Rule {
+	return func(e *Error) {
+		nest(e, field, v)
+	}
+}
+
+// Each runs the rules of every message of a repeated field, reported under
+// field[i].
+func Each[T Validator](field string, items []T) Rule {
+	return func(e *Error) {
+		for i, item := range items {
+			nest(e, fmt.Sprintf("%s[%d]", field, i), item)
+		}
+	}
+}
+
+// EachRequired checks that no value of a repeated string field is empty.
+func EachRequired(field string, values []string) Rule {
+	return func(e *Error) {
+		for i, value := range values {
+			Required(fmt.Sprintf("%s[%d]", field, i), value)(e)
+		}
+	}
+}
+
+// EachBetween checks that every value of a repeated field is in range.
+func EachBetween[T cmp.Ordered](field string, values []T, min, max T) Rule {
+	return func(e *Error) {
+		for i, value := range values {
+			Between(fmt.Sprintf("%s[%d]", field, i), value, min, max)(e)
+		}
+	}
+}
+
+func nest(e *Error, field string, v Validator) {
+	err := v.Validate()
+	if err == nil {
+		return
+	}
+	inner, ok := err.(*Error)
+	if !ok {
+		e.add(field, err.Error())
+		return
+	}
+	for _, violation := range inner.Violations {
+		e.add(field+"."+violation.Field, violation.Description)
+	}
+}
+
+// UnaryServerInterceptor rejects requests breaking their rules before they
+// reach the handler.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if v, ok := req.(Validator); ok {
+			if err := v.Validate(); err != nil {
+				return nil, err
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// UnaryClientInterceptor runs the same rules on the caller side, so the
+// gateway answers bad input without a round trip to the service.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if v, ok := req.(Validator); ok {
+			if err := v.Validate(); err != nil {
+				return err
+			}
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
@@ -0,0 +1,108 @@
+package review
+
+import (
+	"context"
+	"log"
+
+	"github.com/231031/ecom-mcs-grpc/review/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type Client struct {
+	conn    *grpc.ClientConn
+	service pb.ReviewServiceClient
+}
+
+func NewClient(url string, opts ...grpc.DialOption) (*Client, error) {
+	defaultOpts := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}
+	finalOpts := append(defaultOpts, opts...)
+
+	conn, err := grpc.NewClient(url, finalOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	service := pb.NewReviewServiceClient(conn)
+	return &Client{
+		conn:    conn,
+		service: service,
+	}, nil
+}
+
+func (c *Client) Close() {
+	c.conn.Close()
+}
+
+func (c *Client) PostReview(ctx context.Context, productID, accountID string, rating uint32, title, comment string) (*Review, error) {
+	r, err := c.service.PostReview(ctx, &pb.PostReviewRequest{
+		ProductId: productID,
+		AccountId: accountID,
+		Rating:    rating,
+		Title:     title,
+		Comment:   comment,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rv := reviewFromProto(r.Review)
+	return &rv, nil
+}
+
+func (c *Client) GetProductReviews(ctx context.Context, productID string, skip uint64, take uint64) ([]Review, error) {
+	r, err := c.service.GetProductReviews(ctx, &pb.GetProductReviewsRequest{
+		ProductId: productID,
+		Skip:      skip,
+		Take:      take,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reviews := []Review{}
+	for _, rv := range r.Reviews {
+		reviews = append(reviews, reviewFromProto(rv))
+	}
+	return reviews, nil
+}
+
+func (c *Client) DeleteReview(ctx context.Context, id, accountID string) (*Review, error) {
+	r, err := c.service.DeleteReview(ctx, &pb.DeleteReviewRequest{
+		Id:        id,
+		AccountId: accountID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rv := reviewFromProto(r.Review)
+	return &rv, nil
+}
+
+func (c *Client) EraseAccount(ctx context.Context, accountID string) error {
+	_, err := c.service.EraseAccount(ctx, &pb.EraseAccountRequest{AccountId: accountID})
+	return err
+}
+
+func reviewFromProto(r *pb.Review) Review {
+	rv := Review{
+		ID:        r.Id,
+		ProductID: r.ProductId,
+		AccountID: r.AccountId,
+		OrderID:   r.OrderId,
+		Rating:    r.Rating,
+		Title:     r.Title,
+		Comment:   r.Comment,
+	}
+
+	if err := rv.CreatedAt.UnmarshalBinary(r.CreatedAt); err != nil {
+		log.Println("error unmarshal timestamp", err)
+	}
+	if err := rv.UpdatedAt.UnmarshalBinary(r.UpdatedAt); err != nil {
+		log.Println("error unmarshal timestamp", err)
+	}
+	return rv
+}
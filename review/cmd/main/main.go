@@ -0,0 +1,52 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"github.com/231031/ecom-mcs-grpc/pkg/auth"
+	"github.com/231031/ecom-mcs-grpc/review"
+	"github.com/kelseyhightower/envconfig"
+	"github.com/tinrab/retry"
+)
+
+type Config struct {
+	DatabaseURL string `envconfig:"DATABASE_URL"`
+	OrderURL    string `envconfig:"ORDER_SERVICE_URL"`
+	CatalogURL  string `envconfig:"CATALOG_SERVICE_URL"`
+
+	PublicKeyPath      string `envconfig:"FILE_PUB_PATH"`
+	JWKSURL            string `envconfig:"JWKS_URL"`
+	InternalAuthSecret string `envconfig:"INTERNAL_AUTH_SECRET"`
+}
+
+func main() {
+	var cfg Config
+	err := envconfig.Process("", &cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var r review.Repository
+	retry.ForeverSleep(2*time.Second, func(_ int) (err error) {
+		r, err = review.NewPostgresRepository(cfg.DatabaseURL)
+		if err != nil {
+			log.Println(err)
+		}
+		return
+	})
+	defer r.Close()
+	log.Println("Listening on port")
+
+	keys, err := auth.NewKeySource(cfg.JWKSURL, cfg.PublicKeyPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	authn, err := auth.NewAuthenticator("review", keys, cfg.InternalAuthSecret)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	s := review.NewService(r)
+	log.Fatal(review.ListenGRPC(s, authn, cfg.OrderURL, cfg.CatalogURL, 50006))
+}
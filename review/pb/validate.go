@@ -0,0 +1,34 @@
+package pb
+
+import "github.com/231031/ecom-mcs-grpc/pkg/validate"
+
+// Validation rules of the review requests, enforced by the service and the
+// gateway through the validate interceptors.
+
+const (
+	maxTitleLen   = 200
+	maxCommentLen = 5000
+)
+
+func (m *PostReviewRequest) Validate() error {
+	return validate.Check(
+		validate.Required("product_id", m.GetProductId()),
+		validate.Required("account_id", m.GetAccountId()),
+		validate.Between("rating", m.GetRating(), 1, 5),
+		validate.MaxLen("title", m.GetTitle(), maxTitleLen),
+		validate.MaxLen("comment", m.GetComment(), maxCommentLen),
+	)
+}
+
+func (m *GetProductReviewsRequest) Validate() error {
+	return validate.Check(
+		validate.Required("product_id", m.GetProductId()),
+	)
+}
+
+func (m *DeleteReviewRequest) Validate() error {
+	return validate.Check(
+		validate.Required("id", m.GetId()),
+		validate.Required("account_id", m.GetAccountId()),
+	)
+}
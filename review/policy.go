@@ -0,0 +1,20 @@
+package review
+
+import (
+	"github.com/231031/ecom-mcs-grpc/pkg/auth"
+	"github.com/231031/ecom-mcs-grpc/review/pb"
+)
+
+// policies says who may call each method of the service.
+var policies = auth.Policies{
+	pb.ReviewService_PostReview_FullMethodName: auth.OwnedBy(func(r *pb.PostReviewRequest) string {
+		return r.GetAccountId()
+	}, auth.RoleBuyer),
+	pb.ReviewService_GetProductReviews_FullMethodName: auth.Public(),
+	pb.ReviewService_DeleteReview_FullMethodName: auth.OwnedBy(func(r *pb.DeleteReviewRequest) string {
+		return r.GetAccountId()
+	}, auth.RoleBuyer),
+
+	// the authentication service erases the account once its deletion is due
+	pb.ReviewService_EraseAccount_FullMethodName: auth.Internal(),
+}
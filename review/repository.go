@@ -0,0 +1,227 @@
+package review
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	_ "github.com/lib/pq"
+)
+
+type Repository interface {
+	Close()
+	PutReview(ctx context.Context, rv Review) (*Review, *Rating, error)
+	GetProductReviews(ctx context.Context, productID string, skip uint64, take uint64) ([]Review, error)
+	DeleteReview(ctx context.Context, id, accountID string) (*Review, *Rating, error)
+	DeleteAccountReviews(ctx context.Context, accountID string) ([]Rating, error)
+}
+
+type postgresRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresRepository(url string) (Repository, error) {
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Ping()
+	if err != nil {
+		return nil, err
+	}
+	return &postgresRepository{db}, nil
+}
+
+func (r *postgresRepository) Close() {
+	r.db.Close()
+}
+
+// PutReview writes the review, replacing the account's earlier review of the
+// product, and returns the product rating including it.
+func (r *postgresRepository) PutReview(ctx context.Context, rv Review) (stored *Review, rating *Rating, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	stored = &Review{}
+	err = scanReview(tx.QueryRowContext(
+		ctx,
+		`INSERT INTO reviews(id, product_id, account_id, order_id, rating, title, comment, created_at, updated_at)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (product_id, account_id) DO UPDATE SET
+			order_id = EXCLUDED.order_id,
+			rating = EXCLUDED.rating,
+			title = EXCLUDED.title,
+			comment = EXCLUDED.comment,
+			updated_at = EXCLUDED.updated_at
+		RETURNING `+reviewColumns,
+		rv.ID, rv.ProductID, rv.AccountID, rv.OrderID, rv.Rating, rv.Title, rv.Comment, rv.CreatedAt, rv.UpdatedAt,
+	), stored)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rating, err = getRating(ctx, tx, rv.ProductID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return stored, rating, nil
+}
+
+func (r *postgresRepository) GetProductReviews(ctx context.Context, productID string, skip uint64, take uint64) ([]Review, error) {
+	rows, err := r.db.QueryContext(
+		ctx,
+		`SELECT `+reviewColumns+`
+		FROM reviews
+		WHERE product_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3`,
+		productID, take, skip,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reviews := []Review{}
+	for rows.Next() {
+		rv := Review{}
+		if err = scanReview(rows, &rv); err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, rv)
+	}
+
+	return reviews, rows.Err()
+}
+
+func (r *postgresRepository) DeleteReview(ctx context.Context, id, accountID string) (deleted *Review, rating *Rating, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	deleted = &Review{}
+	err = scanReview(tx.QueryRowContext(
+		ctx,
+		"DELETE FROM reviews WHERE id = $1 AND account_id = $2 RETURNING "+reviewColumns,
+		id, accountID,
+	), deleted)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, ErrReviewNotFound
+		}
+		return nil, nil, err
+	}
+
+	rating, err = getRating(ctx, tx, deleted.ProductID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return deleted, rating, nil
+}
+
+// DeleteAccountReviews deletes every review of the account and returns the
+// ratings of the products they were about.
+func (r *postgresRepository) DeleteAccountReviews(ctx context.Context, accountID string) (ratings []Rating, err error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	rows, err := tx.QueryContext(ctx, "DELETE FROM reviews WHERE account_id = $1 RETURNING product_id", accountID)
+	if err != nil {
+		return nil, err
+	}
+	productIDs := []string{}
+	for rows.Next() {
+		var productID string
+		if err = rows.Scan(&productID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		productIDs = append(productIDs, productID)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	ratings = []Rating{}
+	for _, productID := range productIDs {
+		var rating *Rating
+		rating, err = getRating(ctx, tx, productID)
+		if err != nil {
+			return nil, err
+		}
+		ratings = append(ratings, *rating)
+	}
+	return ratings, nil
+}
+
+func getRating(ctx context.Context, tx *sql.Tx, productID string) (*Rating, error) {
+	rating := &Rating{ProductID: productID}
+	err := tx.QueryRowContext(
+		ctx,
+		"SELECT COALESCE(AVG(rating), 0)::float8, COUNT(*) FROM reviews WHERE product_id = $1",
+		productID,
+	).Scan(&rating.Average, &rating.Count)
+	if err != nil {
+		return nil, err
+	}
+	return rating, nil
+}
+
+const reviewColumns = `id,
+		product_id,
+		account_id,
+		order_id,
+		rating,
+		title,
+		comment,
+		created_at,
+		updated_at`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanReview(row scanner, rv *Review) error {
+	return row.Scan(
+		&rv.ID,
+		&rv.ProductID,
+		&rv.AccountID,
+		&rv.OrderID,
+		&rv.Rating,
+		&rv.Title,
+		&rv.Comment,
+		&rv.CreatedAt,
+		&rv.UpdatedAt,
+	)
+}
@@ -0,0 +1,164 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+
+	"github.com/231031/ecom-mcs-grpc/catalog"
+	"github.com/231031/ecom-mcs-grpc/order"
+	"github.com/231031/ecom-mcs-grpc/pkg/auth"
+	"github.com/231031/ecom-mcs-grpc/pkg/grpcerr"
+	"github.com/231031/ecom-mcs-grpc/pkg/validate"
+	"github.com/231031/ecom-mcs-grpc/review/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+var (
+	ErrNotDelivered = errors.New("only a delivered product can be reviewed")
+)
+
+var errorMappings = []grpcerr.Mapping{
+	grpcerr.Conflict(ErrNotDelivered),
+	grpcerr.Validation(ErrInvalidRating, "rating"),
+	grpcerr.NotFound(ErrReviewNotFound),
+}
+
+type grpcServer struct {
+	service       Service
+	orderClient   *order.Client
+	catalogClient *catalog.Client
+	pb.UnimplementedReviewServiceServer
+}
+
+func ListenGRPC(s Service, authn *auth.Authenticator, orderURL, catalogURL string, port int) error {
+	orderClient, err := order.NewClient(orderURL, authn.DialOption())
+	if err != nil {
+		return err
+	}
+
+	catalogClient, err := catalog.NewClient(catalogURL, authn.DialOption())
+	if err != nil {
+		orderClient.Close()
+		return err
+	}
+
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		orderClient.Close()
+		catalogClient.Close()
+		return err
+	}
+
+	serv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			grpcerr.UnaryServerInterceptor(errorMappings...),
+			authn.UnaryServerInterceptor(policies),
+			validate.UnaryServerInterceptor(),
+		),
+	)
+	pb.RegisterReviewServiceServer(
+		serv,
+		&grpcServer{
+			service:       s,
+			orderClient:   orderClient,
+			catalogClient: catalogClient,
+		},
+	)
+	reflection.Register(serv)
+	return serv.Serve(lis)
+}
+
+func (s *grpcServer) PostReview(ctx context.Context, r *pb.PostReviewRequest) (*pb.ReviewResponse, error) {
+	delivered, orderID, err := s.orderClient.HasDeliveredProduct(ctx, r.AccountId, r.ProductId)
+	if err != nil {
+		log.Println("error checking delivered orders", err)
+		return nil, err
+	}
+	if !delivered {
+		return nil, ErrNotDelivered
+	}
+
+	rv, rating, err := s.service.PostReview(ctx, orderID, r.ProductId, r.AccountId, r.Rating, r.Title, r.Comment)
+	if err != nil {
+		log.Println("error posting review", err)
+		return nil, err
+	}
+	s.updateRating(ctx, rating)
+
+	return &pb.ReviewResponse{Review: reviewToProto(*rv)}, nil
+}
+
+func (s *grpcServer) GetProductReviews(ctx context.Context, r *pb.GetProductReviewsRequest) (*pb.GetReviewsResponse, error) {
+	reviews, err := s.service.GetProductReviews(ctx, r.ProductId, r.Skip, r.Take)
+	if err != nil {
+		log.Println("error getting reviews", err)
+		return nil, err
+	}
+
+	reviewsProto := []*pb.Review{}
+	for _, rv := range reviews {
+		reviewsProto = append(reviewsProto, reviewToProto(rv))
+	}
+	return &pb.GetReviewsResponse{Reviews: reviewsProto}, nil
+}
+
+func (s *grpcServer) DeleteReview(ctx context.Context, r *pb.DeleteReviewRequest) (*pb.ReviewResponse, error) {
+	rv, rating, err := s.service.DeleteReview(ctx, r.Id, r.AccountId)
+	if err != nil {
+		log.Println("error deleting review", err)
+		return nil, err
+	}
+	s.updateRating(ctx, rating)
+
+	return &pb.ReviewResponse{Review: reviewToProto(*rv)}, nil
+}
+
+func (s *grpcServer) EraseAccount(ctx context.Context, r *pb.EraseAccountRequest) (*pb.EraseAccountResponse, error) {
+	ratings, err := s.service.EraseAccount(ctx, r.AccountId)
+	if err != nil {
+		log.Println("error erasing reviews of account", err)
+		return nil, err
+	}
+	for i := range ratings {
+		s.updateRating(ctx, &ratings[i])
+	}
+
+	return &pb.EraseAccountResponse{}, nil
+}
+
+// updateRating writes the aggregate back to the product document, the review
+// itself is already stored so a failure here is only logged.
+func (s *grpcServer) updateRating(ctx context.Context, rating *Rating) {
+	err := s.catalogClient.UpdateRating(ctx, rating.ProductID, rating.Average, rating.Count)
+	if err != nil {
+		log.Println("error updating product rating", err)
+	}
+}
+
+func reviewToProto(rv Review) *pb.Review {
+	reviewProto := &pb.Review{
+		Id:        rv.ID,
+		ProductId: rv.ProductID,
+		AccountId: rv.AccountID,
+		OrderId:   rv.OrderID,
+		Rating:    rv.Rating,
+		Title:     rv.Title,
+		Comment:   rv.Comment,
+	}
+
+	var err error
+	reviewProto.CreatedAt, err = rv.CreatedAt.MarshalBinary()
+	if err != nil {
+		log.Println("error marshal timestamp", err)
+	}
+	reviewProto.UpdatedAt, err = rv.UpdatedAt.MarshalBinary()
+	if err != nil {
+		log.Println("error marshal timestamp", err)
+	}
+
+	return reviewProto
+}
@@ -0,0 +1,93 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/segmentio/ksuid"
+)
+
+const (
+	MinRating = 1
+	MaxRating = 5
+)
+
+var (
+	ErrInvalidRating  = errors.New("rating must be between 1 and 5")
+	ErrReviewNotFound = errors.New("review not found")
+)
+
+// Review is what a buyer thinks of a product they received, an account has
+// at most one review per product and posting again replaces it.
+type Review struct {
+	ID        string    `json:"id"`
+	ProductID string    `json:"product_id"`
+	AccountID string    `json:"account_id"`
+	OrderID   string    `json:"order_id"`
+	Rating    uint32    `json:"rating"`
+	Title     string    `json:"title"`
+	Comment   string    `json:"comment"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Rating is the aggregate of all reviews of a product.
+type Rating struct {
+	ProductID string  `json:"product_id"`
+	Average   float64 `json:"average"`
+	Count     uint32  `json:"count"`
+}
+
+type Service interface {
+	PostReview(ctx context.Context, orderID, productID, accountID string, rating uint32, title, comment string) (*Review, *Rating, error)
+	GetProductReviews(ctx context.Context, productID string, skip uint64, take uint64) ([]Review, error)
+	DeleteReview(ctx context.Context, id, accountID string) (*Review, *Rating, error)
+	EraseAccount(ctx context.Context, accountID string) ([]Rating, error)
+}
+
+type reviewService struct {
+	repository Repository
+}
+
+func NewService(r Repository) Service {
+	return &reviewService{repository: r}
+}
+
+func (s *reviewService) PostReview(ctx context.Context, orderID, productID, accountID string, rating uint32, title, comment string) (*Review, *Rating, error) {
+	if rating < MinRating || rating > MaxRating {
+		return nil, nil, ErrInvalidRating
+	}
+
+	now := time.Now().UTC()
+	rv := Review{
+		ID:        ksuid.New().String(),
+		ProductID: productID,
+		AccountID: accountID,
+		OrderID:   orderID,
+		Rating:    rating,
+		Title:     strings.TrimSpace(title),
+		Comment:   strings.TrimSpace(comment),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	return s.repository.PutReview(ctx, rv)
+}
+
+func (s *reviewService) GetProductReviews(ctx context.Context, productID string, skip uint64, take uint64) ([]Review, error) {
+	if take > 100 || (skip == 0 && take == 0) {
+		take = 100
+	}
+	return s.repository.GetProductReviews(ctx, productID, skip, take)
+}
+
+func (s *reviewService) DeleteReview(ctx context.Context, id, accountID string) (*Review, *Rating, error) {
+	return s.repository.DeleteReview(ctx, id, accountID)
+}
+
+// EraseAccount deletes the reviews of a deleted account, the ratings of the
+// products they were about change with them.
+func (s *reviewService) EraseAccount(ctx context.Context, accountID string) ([]Rating, error) {
+	return s.repository.DeleteAccountReviews(ctx, accountID)
+}
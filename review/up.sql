@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS reviews (
+    id VARCHAR(27) PRIMARY KEY,
+    product_id VARCHAR(27) NOT NULL,
+    account_id VARCHAR(27) NOT NULL,
+    order_id VARCHAR(27) NOT NULL,
+    rating INT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    title VARCHAR(255) NOT NULL DEFAULT '',
+    comment TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    UNIQUE (product_id, account_id)
+);
+
+CREATE INDEX IF NOT EXISTS reviews_product_id_idx ON reviews (product_id, created_at);